@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/account"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// accountUsagePeriod is how far back `account stats` reads the transcripts
+const accountUsagePeriod = 7 * 24 * time.Hour
+
+// accountCommands maps the `account` subcommands to their entry points
+var accountCommands = map[string]func(args []string) int{
+	"stats": runAccountStats,
+}
+
+// accountUsageJSON is one account of the `account stats --json` output
+type accountUsageJSON struct {
+	Name         string          `json:"name"`
+	ConfigDir    string          `json:"configDir"`
+	Window       usagePeriodJSON `json:"window"` // The rolling usage window (5 hours)
+	Week         usagePeriodJSON `json:"week"`
+	LastUsed     *time.Time      `json:"lastUsed,omitempty"`
+	LimitReached *time.Time      `json:"limitReached,omitempty"`
+}
+
+// usagePeriodJSON is the use of an account in one period
+type usagePeriodJSON struct {
+	Messages int `json:"messages"`
+	Tokens   int `json:"tokens"`
+}
+
+// runAccount implements `claude-launcher account <COMMAND>`
+func runAccount(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	commands := strings.Join(slices.Sorted(maps.Keys(accountCommands)), ", ")
+	if len(args) == 0 {
+		printer.Error("Usage: claude-launcher account <COMMAND> (available: %s)\n", commands)
+		return exitError
+	}
+	cmd, ok := accountCommands[args[0]]
+	if !ok {
+		printer.Error("Unknown account command %q (available: %s)\n", args[0], commands)
+		return exitError
+	}
+	return cmd(args[1:])
+}
+
+// runAccountStats implements `claude-launcher account stats`: how much each account was used
+// recently, read from its Claude Code transcripts, to pick one before hitting a limit
+func runAccountStats(args []string) int {
+	fs := flag.NewFlagSet("account stats", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if _, ok := loadConfig(printer); !ok {
+		return exitConfig
+	}
+	accountCfg, err := account.LoadAccountConfig()
+	if err != nil {
+		printer.Error("Failed to load account config: %v\n", err)
+		return exitError
+	}
+	var accounts []account.Account
+	if accountCfg != nil {
+		accounts = accountCfg.Accounts
+	}
+
+	now := time.Now()
+	usages := make([]ui.AccountUsage, 0, len(accounts))
+	out := make([]accountUsageJSON, 0, len(accounts))
+	for _, acc := range accounts {
+		week, err := launcher.ReadUsage(acc.ConfigDir, now.Add(-accountUsagePeriod))
+		if err != nil {
+			printer.Error("%v\n", err)
+			return exitError
+		}
+		window, err := launcher.ReadUsage(acc.ConfigDir, now.Add(-launcher.UsageWindow))
+		if err != nil {
+			printer.Error("%v\n", err)
+			return exitError
+		}
+
+		usages = append(usages, ui.AccountUsage{
+			Name:         acc.Name,
+			WindowTokens: window.Total(),
+			WeekTokens:   week.Total(),
+			LastUsed:     week.LastUsed,
+			LimitReached: week.LimitReached,
+		})
+		out = append(out, accountUsageJSON{
+			Name:         acc.Name,
+			ConfigDir:    acc.ConfigDir,
+			Window:       usagePeriodJSON{Messages: window.Messages, Tokens: window.Total()},
+			Week:         usagePeriodJSON{Messages: week.Messages, Tokens: week.Total()},
+			LastUsed:     timeOrNil(week.LastUsed),
+			LimitReached: timeOrNil(week.LimitReached),
+		})
+	}
+
+	if jsonOutput {
+		return printJSON(out)
+	}
+	ui.NewPrinter(os.Stdout).ShowAccountUsage(usages, now)
+	return exitSuccess
+}
+
+// timeOrNil returns nil for the zero time, so that it is left out of JSON output
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
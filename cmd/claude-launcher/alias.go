@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/log"
+)
+
+// runAlias runs the user-defined alias name with args appended as claude arguments, and
+// reports whether name is an alias. Built-in commands are dispatched first, so they
+// cannot be overridden. When the config cannot be loaded nothing is an alias and the
+// launch reports the error.
+func runAlias(name string, args []string) (int, bool) {
+	if strings.HasPrefix(name, "-") {
+		return 0, false
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return 0, false
+	}
+	alias, ok := cfg.Aliases[name]
+	if !ok {
+		return 0, false
+	}
+
+	log.Debug("alias expanded", "alias", name)
+	launchProfile = name
+	return launch(profileArgs(alias.Profile(), args)), true
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/23prime/claude-launcher/internal/detach"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/notify"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// launchDetached starts Claude under a background session server, which records the launch
+// and sends the notifications when claude exits
+func launchDetached(l *launcher.Launcher, opts launcher.LaunchOptions, accountName string, notifier *notify.Notifier, printer *ui.Printer) int {
+	store, err := state.NewStore()
+	if err != nil {
+		printer.Error("Failed to open state directory: %v\n", err)
+		return exitError
+	}
+
+	cmd, err := l.Prepare(opts)
+	if err != nil {
+		printer.Error("Failed to launch Claude: %v\n", err)
+		return exitError
+	}
+
+	spec := &detach.Spec{Command: cmd, Account: accountName, Record: launcher.NewRecord(opts), Notifier: notifier}
+	if l.Tool == nil || l.Tool == launcher.Claude {
+		spec.UsageDir = &opts.ConfigDir
+	}
+	id, err := detach.Start(store, spec)
+	if err != nil {
+		cmd.Cleanup()
+		printer.Error("Failed to launch Claude: %v\n", err)
+		return exitError
+	}
+
+	printer.ShowSessionDetached(id)
+	return exitSuccess
+}
+
+// runAttach implements `claude-launcher attach [ID]`
+func runAttach(args []string) int {
+	fs := flag.NewFlagSet("attach", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	store, err := state.NewStore()
+	if err != nil {
+		printer.Error("Failed to open state directory: %v\n", err)
+		return exitError
+	}
+
+	inst, err := findDetachedInstance(store, fs.Arg(0))
+	if err != nil {
+		printer.Error("Failed to find session: %v\n", err)
+		return exitError
+	}
+	if inst == nil {
+		printer.ShowNoDetachedSession(fs.Arg(0))
+		return exitError
+	}
+
+	printer.ShowAttaching(inst.ID)
+	ended, err := detach.Attach(inst.Socket, os.Stdin, os.Stdout)
+	if err != nil {
+		printer.Error("Failed to attach: %v\n", err)
+		return exitError
+	}
+
+	if ended {
+		printer.ShowSessionEnded(inst.ID)
+	} else {
+		printer.ShowSessionDetached(inst.ID)
+	}
+
+	return exitSuccess
+}
+
+// findDetachedInstance returns the detached session with id.
+// With an empty id, the only detached session is returned (nil if there are zero or several).
+func findDetachedInstance(store *state.Store, id string) (*state.Instance, error) {
+	if id != "" {
+		inst, err := store.FindInstance(id)
+		if err != nil || inst == nil || inst.Socket == "" {
+			return nil, err
+		}
+		return inst, nil
+	}
+
+	instances, err := store.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *state.Instance
+	for i := range instances {
+		if instances[i].Socket == "" {
+			continue
+		}
+		if found != nil {
+			return nil, nil
+		}
+		found = &instances[i]
+	}
+
+	return found, nil
+}
+
+// runServeDetached is the hidden entry point of the background session server
+func runServeDetached(args []string) int {
+	if len(args) != 1 {
+		return exitError
+	}
+
+	store, err := state.NewStore()
+	if err != nil {
+		return exitError
+	}
+
+	if err := detach.Serve(store, args[0]); err != nil {
+		return exitError
+	}
+
+	return exitSuccess
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"flag"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/security"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// bookmarkPrefix marks a bookmark name given instead of a command: `claude-launcher @api`
+const bookmarkPrefix = "@"
+
+// bookmarkCommands maps the `bookmark` subcommands to their entry points
+var bookmarkCommands = map[string]func(args []string) int{
+	"add":    runBookmarkAdd,
+	"remove": runBookmarkRemove,
+	"list":   runBookmarkList,
+}
+
+// runBookmark implements `claude-launcher bookmark <COMMAND>`
+func runBookmark(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	commands := strings.Join(slices.Sorted(maps.Keys(bookmarkCommands)), ", ")
+	if len(args) == 0 {
+		printer.Error("Usage: claude-launcher bookmark <COMMAND> (available: %s)\n", commands)
+		return exitError
+	}
+	cmd, ok := bookmarkCommands[args[0]]
+	if !ok {
+		printer.Error("Unknown bookmark command %q (available: %s)\n", args[0], commands)
+		return exitError
+	}
+	return cmd(args[1:])
+}
+
+// bookmarkAddFlags holds the options of `bookmark add`
+type bookmarkAddFlags struct {
+	accountName, preset string
+}
+
+// newBookmarkAddFlags defines the options of `bookmark add`
+func newBookmarkAddFlags() (*flag.FlagSet, *bookmarkAddFlags) {
+	fs := flag.NewFlagSet("bookmark add", flag.ContinueOnError)
+	f := &bookmarkAddFlags{}
+	fs.StringVar(&f.accountName, "account", "", "Account `NAME` the bookmark launches with")
+	fs.StringVar(&f.accountName, "a", "", "Account name the bookmark launches with (shorthand)")
+	fs.StringVar(&f.preset, "preset", "", "Permission preset (`NAME`) the bookmark launches with")
+	return fs, f
+}
+
+// runBookmarkAdd implements `claude-launcher bookmark add <NAME> [PATH] [OPTIONS]`
+func runBookmarkAdd(args []string) int {
+	fs, f := newBookmarkAddFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	// Accept the options after the name and the path as well
+	var positional []string
+	for fs.NArg() > 0 {
+		positional = append(positional, fs.Arg(0))
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return exitError
+		}
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if len(positional) == 0 || len(positional) > 2 {
+		printer.Error("Usage: claude-launcher bookmark add <NAME> [PATH] [-a ACCOUNT] [--preset NAME]\n")
+		return exitError
+	}
+	name := positional[0]
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	path := "."
+	if len(positional) == 2 {
+		path = positional[1]
+	}
+	expanded, err := config.ExpandPath(path)
+	if err == nil {
+		expanded, err = filepath.Abs(expanded)
+	}
+	if err != nil {
+		printer.Error("Failed to resolve %s: %v\n", path, err)
+		return exitError
+	}
+	if info, err := os.Stat(expanded); err != nil || !info.IsDir() {
+		printer.Error("✗ %s is not a directory\n", expanded)
+		return exitError
+	}
+	if _, err := cfg.PermissionsFor(nil, f.preset); err != nil {
+		printer.Error("%v\n", err)
+		return exitError
+	}
+
+	if err := config.SetBookmark("", name, config.Bookmark{Path: expanded, Account: f.accountName, Preset: f.preset}); err != nil {
+		printer.Error("Failed to add bookmark: %v\n", err)
+		return exitError
+	}
+	printer.Success("✓ Added bookmark @%s for %s\n", name, expanded)
+
+	// The launch would be refused; saying so now saves a surprise later
+	if allowed, _ := security.NewDirectoryChecker(cfg.AllowedDirs).IsAllowed(expanded); !allowed { //nolint:errcheck // unresolvable directories are not allowed
+		printer.Warning("⚠ %s is not an allowed directory: add it to allowedDirs before launching the bookmark\n", expanded)
+	}
+	return exitSuccess
+}
+
+// runBookmarkRemove implements `claude-launcher bookmark remove <NAME>`
+func runBookmarkRemove(args []string) int {
+	fs := flag.NewFlagSet("bookmark remove", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if fs.NArg() != 1 {
+		printer.Error("Usage: claude-launcher bookmark remove <NAME>\n")
+		return exitError
+	}
+	name := strings.TrimPrefix(fs.Arg(0), bookmarkPrefix)
+	if err := config.RemoveBookmark("", name); err != nil {
+		printer.Error("Failed to remove bookmark: %v\n", err)
+		return exitError
+	}
+	printer.Success("✓ Removed bookmark @%s\n", name)
+	return exitSuccess
+}
+
+// runBookmarkList implements `claude-launcher bookmark list`
+func runBookmarkList(args []string) int {
+	fs := flag.NewFlagSet("bookmark list", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+	if jsonOutput {
+		return printJSON(nonNilMap(cfg.Bookmarks))
+	}
+	ui.NewPrinter(os.Stdout).ShowBookmarks(cfg.Bookmarks)
+	return exitSuccess
+}
+
+// runBookmarkLaunch implements `claude-launcher @<NAME> [CLAUDE_ARGUMENTS...]`
+func runBookmarkLaunch(name string, args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+	b, ok := cfg.Bookmarks[name]
+	if !ok {
+		printer.Error("✗ Bookmark '%s' not found\n", name)
+		printer.ShowBookmarks(cfg.Bookmarks)
+		return exitError
+	}
+	return launch(profileArgs(b.Profile(), args))
+}
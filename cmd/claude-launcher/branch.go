@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// applyBranchPolicy enforces the branch rules of project on a launch in dir (after the directory
+// check) and returns the permission preset to launch with. A deny rule refuses the launch; a
+// preset rule applies its preset when none was given, and refuses another preset and
+// --dangerously-skip-permissions in args. Outside git repositories and on a detached HEAD no
+// rule applies.
+func applyBranchPolicy(project *config.Project, dir, preset string, args []string, printer *ui.Printer) (string, int) {
+	if project == nil || len(project.Branches) == 0 {
+		return preset, exitSuccess
+	}
+	branch, err := gitOutput(dir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return preset, exitSuccess
+	}
+	rule := config.BranchRuleFor(project, branch)
+	switch {
+	case rule == nil:
+		return preset, exitSuccess
+	case rule.Deny:
+		printer.Error("✗ Launches on branch %s are not allowed in %s: switch to another branch first\n", branch, project.Path)
+		return "", exitDenied
+	case preset != "" && preset != rule.Preset:
+		printer.Error("✗ Branch %s requires the %s preset, not %s\n", branch, rule.Preset, preset)
+		return "", exitDenied
+	case launcher.HasSkipPermissions(args):
+		printer.Error("✗ --dangerously-skip-permissions is not allowed on branch %s\n", branch)
+		return "", exitDenied
+	}
+	printer.ShowBranchPreset(branch, rule.Preset)
+	return rule.Preset, exitSuccess
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+func TestApplyBranchPolicy(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "init")
+
+	project := &config.Project{Path: repo, Branches: []config.BranchRule{
+		{Pattern: "main", Deny: true},
+		{Pattern: "release/*", Preset: "readonly"},
+	}}
+	printer := ui.NewPrinter(io.Discard)
+
+	if _, code := applyBranchPolicy(project, repo, "", nil, printer); code != exitDenied {
+		t.Errorf("applyBranchPolicy() on main = %d, want %d", code, exitDenied)
+	}
+
+	git("checkout", "-q", "-b", "release/1.0")
+	tests := []struct {
+		preset     string
+		args       []string
+		wantPreset string
+		wantCode   int
+	}{
+		{"", nil, "readonly", exitSuccess},
+		{"readonly", nil, "readonly", exitSuccess},
+		{"yolo", nil, "", exitDenied},
+		{"", []string{"--dangerously-skip-permissions"}, "", exitDenied},
+	}
+	for _, tt := range tests {
+		preset, code := applyBranchPolicy(project, repo, tt.preset, tt.args, printer)
+		if preset != tt.wantPreset || code != tt.wantCode {
+			t.Errorf("applyBranchPolicy(%q, %v) = %q, %d; want %q, %d", tt.preset, tt.args, preset, code, tt.wantPreset, tt.wantCode)
+		}
+	}
+
+	git("checkout", "-q", "-b", "feature")
+	if preset, code := applyBranchPolicy(project, repo, "yolo", nil, printer); preset != "yolo" || code != exitSuccess {
+		t.Errorf("applyBranchPolicy() on an unmatched branch = %q, %d; want yolo, %d", preset, code, exitSuccess)
+	}
+	git("checkout", "-q", "--detach")
+	if _, code := applyBranchPolicy(project, repo, "", nil, printer); code != exitSuccess {
+		t.Errorf("applyBranchPolicy() on a detached HEAD = %d, want %d", code, exitSuccess)
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/account"
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// completeCommand is the hidden command the completion scripts call with the words of the
+// command line, the last one being completed. It prints the candidates one per line and is
+// dispatched before the global flags are applied, so they reach it untouched.
+const completeCommand = "__complete"
+
+// completionScripts are printed by `completion`. They pass the command line to __complete
+// and fall back to file names when it has nothing to offer.
+var completionScripts = map[string]string{
+	"bash": `# bash completion for claude-launcher
+_claude_launcher() {
+    local IFS=$'\n'
+    COMPREPLY=($(claude-launcher __complete "${COMP_WORDS[@]:0:COMP_CWORD+1}" 2>/dev/null))
+    if [[ ${#COMPREPLY[@]} -eq 0 ]]; then
+        compopt -o default 2>/dev/null
+    fi
+}
+complete -F _claude_launcher claude-launcher
+`,
+	"zsh": `#compdef claude-launcher
+# zsh completion for claude-launcher
+_claude_launcher() {
+    local -a candidates
+    candidates=("${(@f)$(claude-launcher __complete "${(@)words[1,CURRENT]}" 2>/dev/null)}")
+    candidates=("${(@)candidates:#}")
+    if (( ${#candidates} )); then
+        compadd -a candidates
+    else
+        _files
+    fi
+}
+compdef _claude_launcher claude-launcher
+`,
+	"fish": `# fish completion for claude-launcher
+complete -c claude-launcher -f -a '(claude-launcher __complete (commandline -opc) (commandline -ct) 2>/dev/null)'
+`,
+}
+
+// runCompletion implements `claude-launcher completion <bash|zsh|fish>`
+func runCompletion(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	if len(args) != 1 {
+		printer.Error("Usage: claude-launcher completion <bash|zsh|fish>\n")
+		return exitError
+	}
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		printer.Error("Unknown shell %q (available: bash, zsh, fish)\n", args[0])
+		return exitError
+	}
+
+	fmt.Print(script)
+	return exitSuccess
+}
+
+// runComplete implements the hidden __complete command
+func runComplete(args []string) int {
+	for _, candidate := range complete(args, loadCompletionData()) {
+		fmt.Println(candidate)
+	}
+	return exitSuccess
+}
+
+// completionData holds the configured names offered as candidates
+type completionData struct {
+	accounts, presets, profiles, aliases, workspaces, bookmarks, plugins []string
+}
+
+// loadCompletionData reads the configured names without prompting, printing or applying any
+// settings. A missing or invalid config only means fewer candidates.
+func loadCompletionData() completionData {
+	var d completionData
+	if cfg, err := config.LoadConfig(); err == nil {
+		d.presets = slices.Sorted(maps.Keys(cfg.PermissionPresets))
+		d.profiles = slices.Sorted(maps.Keys(cfg.Profiles))
+		d.aliases = slices.Sorted(maps.Keys(cfg.Aliases))
+		d.workspaces = slices.Sorted(maps.Keys(cfg.Workspaces))
+		d.bookmarks = slices.Sorted(maps.Keys(cfg.Bookmarks))
+	}
+	d.plugins = pluginNames()
+	if accCfg, err := account.LoadAccountConfig(); err == nil && accCfg != nil {
+		for _, acc := range accCfg.Accounts {
+			d.accounts = append(d.accounts, acc.Name)
+		}
+	}
+	return d
+}
+
+// complete returns the candidates for the last of words, which start with the program name
+func complete(words []string, d completionData) []string {
+	if len(words) < 2 {
+		return nil
+	}
+	args, cur := words[1:len(words)-1], words[len(words)-1]
+	if slices.Contains(args, "--") {
+		return nil // Claude's own arguments
+	}
+
+	// Find the command and the arguments given to it so far
+	fs := completionFlags("")
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			if i == len(args)-1 && takesValue(fs, arg) {
+				return filterPrefix(flagValues(fs, arg, d), cur)
+			}
+			if takesValue(fs, arg) && !strings.Contains(arg, "=") {
+				i++
+			}
+			continue
+		}
+		positional = append(positional, arg)
+		switch len(positional) {
+		case 1:
+			if slices.Contains(d.plugins, arg) && !slices.Contains(d.aliases, arg) {
+				return nil // The plugin's own arguments
+			}
+			fs = completionFlags(arg)
+		case 2:
+			// Commands with their own subcommands, e.g. `integrate direnv`
+			if sub := documentedFlags(positional[0] + " " + arg); sub != nil {
+				fs = sub
+			}
+		}
+	}
+
+	if strings.HasPrefix(cur, "-") {
+		var names []string
+		for _, doc := range slices.Concat(globalFlags, flagDocs(fs)) {
+			names = append(names, doc.Names...)
+		}
+		return filterPrefix(names, cur)
+	}
+
+	switch {
+	case len(positional) == 0 && strings.HasPrefix(cur, bookmarkPrefix):
+		var names []string
+		for _, name := range d.bookmarks {
+			names = append(names, bookmarkPrefix+name)
+		}
+		return filterPrefix(names, cur)
+	case len(positional) == 0:
+		return filterPrefix(slices.Concat(completionCommands(), d.aliases, d.plugins), cur)
+	case len(positional) == 1 && positional[0] == "up":
+		return filterPrefix(d.profiles, cur)
+	case len(positional) == 1 && positional[0] == "workspace":
+		return filterPrefix([]string{"open", "list"}, cur)
+	case len(positional) == 2 && positional[0] == "workspace" && positional[1] == "open":
+		return filterPrefix(d.workspaces, cur)
+	case len(positional) == 1 && positional[0] == "integrate":
+		return filterPrefix(slices.Sorted(maps.Keys(integrations)), cur)
+	case len(positional) == 1 && positional[0] == "history":
+		return filterPrefix(slices.Sorted(maps.Keys(historyCommands)), cur)
+	case len(positional) == 1 && positional[0] == "mcp":
+		return filterPrefix(slices.Sorted(maps.Keys(mcpCommands)), cur)
+	case len(positional) == 1 && positional[0] == "config":
+		return filterPrefix(slices.Sorted(maps.Keys(configCommands)), cur)
+	case len(positional) == 1 && positional[0] == "prefs":
+		return filterPrefix(slices.Sorted(maps.Keys(prefsCommands)), cur)
+	case len(positional) == 2 && positional[0] == "prefs" && (positional[1] == "set" || positional[1] == "unset"):
+		return filterPrefix(slices.Sorted(maps.Keys(prefKeys)), cur)
+	case len(positional) == 1 && positional[0] == "account":
+		return filterPrefix(slices.Sorted(maps.Keys(accountCommands)), cur)
+	case len(positional) == 1 && positional[0] == "bookmark":
+		return filterPrefix(slices.Sorted(maps.Keys(bookmarkCommands)), cur)
+	case len(positional) == 2 && positional[0] == "bookmark" && positional[1] == "remove":
+		return filterPrefix(d.bookmarks, cur)
+	case len(positional) == 1 && (positional[0] == "completion" || positional[0] == "shell-init"):
+		return filterPrefix(slices.Sorted(maps.Keys(completionScripts)), cur)
+	}
+	return nil
+}
+
+// completionCommands returns the documented command names
+func completionCommands() []string {
+	var names []string
+	for _, c := range commandDocs {
+		name, _, _ := strings.Cut(c.Name, " ")
+		if name != "" && !strings.HasPrefix(name, "<") && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// completionFlags returns the options of the named command (the launch flags for "" and aliases)
+func completionFlags(name string) *flag.FlagSet {
+	if fs := documentedFlags(name); fs != nil {
+		return fs
+	}
+	if _, ok := subcommands[name]; ok || name == "workspace" {
+		return flag.NewFlagSet(name, flag.ContinueOnError)
+	}
+	return completionFlags("")
+}
+
+// documentedFlags returns the options documented for the named command, or nil
+func documentedFlags(name string) *flag.FlagSet {
+	for _, c := range commandDocs {
+		if c.Name == name && c.Flags != nil {
+			return c.Flags()
+		}
+	}
+	return nil
+}
+
+// takesValue reports whether arg is an option of fs (or a global one) followed by a value
+func takesValue(fs *flag.FlagSet, arg string) bool {
+	name := strings.TrimLeft(arg, "-")
+	if name == "ui" {
+		return true
+	}
+	f := fs.Lookup(name)
+	if f == nil {
+		return false
+	}
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		return false
+	}
+	return true
+}
+
+// flagValues returns the candidates for the value of the option arg of the command fs
+func flagValues(fs *flag.FlagSet, arg string, d completionData) []string {
+	switch strings.TrimLeft(arg, "-") {
+	case "a", "account":
+		return d.accounts
+	case "preset":
+		return d.presets
+	case "profile":
+		return d.profiles
+	case "tool":
+		return launcher.ToolNames()
+	case "ui":
+		return []string{ui.ModeAuto, ui.ModeColor, ui.ModePlain, ui.ModeJSON, ui.ModeSilent, ui.ModeAccessible}
+	case "shell":
+		return []string{shellPOSIX, shellFish, shellPowerShell}
+	case "format":
+		if fs.Name() == "history export" {
+			return []string{exportCSV, exportJSON}
+		}
+		return []string{dirsFormatText, dirsFormatJSON, dirsFormatTSV}
+	case "type":
+		return []string{"stdio", "http", "sse"}
+	default:
+		return nil // Directories and free text are left to the shell
+	}
+}
+
+// filterPrefix returns the candidates starting with prefix
+func filterPrefix(candidates []string, prefix string) []string {
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	d := completionData{
+		accounts:   []string{"Personal", "Work"},
+		presets:    []string{"review", "yolo"},
+		profiles:   []string{"backend", "docs"},
+		aliases:    []string{"fix"},
+		workspaces: []string{"api"},
+		bookmarks:  []string{"api", "blog"},
+		plugins:    []string{"jira"},
+	}
+
+	tests := []struct {
+		words    []string
+		expected []string
+	}{
+		{[]string{"claude-launcher", "--account", ""}, []string{"Personal", "Work"}},
+		{[]string{"claude-launcher", "-a", "W"}, []string{"Work"}},
+		{[]string{"claude-launcher", "--verbose", "--preset", "r"}, []string{"review"}},
+		{[]string{"claude-launcher", "run", "--preset", ""}, []string{"review", "yolo"}},
+		{[]string{"claude-launcher", "up", ""}, []string{"backend", "docs"}},
+		{[]string{"claude-launcher", "-q", "up", "d"}, []string{"docs"}},
+		{[]string{"claude-launcher", "workspace", "open", ""}, []string{"api"}},
+		{[]string{"claude-launcher", "--ui", "p"}, []string{"plain"}},
+		{[]string{"claude-launcher", "f"}, []string{"fix"}},
+		{[]string{"claude-launcher", "--dir", "/tmp", "w"}, []string{"workspace", "which"}},
+		{[]string{"claude-launcher", "which", "--t"}, []string{"--tool"}},
+		{[]string{"claude-launcher", "integrate", ""}, []string{"direnv", "git-hooks", "systemd"}},
+		{[]string{"claude-launcher", "integrate", "git-hooks", "--p"}, []string{"--print"}},
+		{[]string{"claude-launcher", "integrate", "direnv", "--l"}, []string{"--lib"}},
+		{[]string{"claude-launcher", "integrate", "direnv", "--profile", "b"}, []string{"backend"}},
+		{[]string{"claude-launcher", "history", ""}, []string{"export"}},
+		{[]string{"claude-launcher", "history", "export", "--format", ""}, []string{"csv", "json"}},
+		{[]string{"claude-launcher", "--show-dirs", "--format", "t"}, []string{"text", "tsv"}},
+		{[]string{"claude-launcher", "mcp", "r"}, []string{"remove"}},
+		{[]string{"claude-launcher", "config", ""}, []string{"path", "validate"}},
+		{[]string{"claude-launcher", "@b"}, []string{"@blog"}},
+		{[]string{"claude-launcher", "bookmark", ""}, []string{"add", "list", "remove"}},
+		{[]string{"claude-launcher", "account", ""}, []string{"stats"}},
+		{[]string{"claude-launcher", "bookmark", "remove", "a"}, []string{"api"}},
+		{[]string{"claude-launcher", "bookmark", "add", "api", "--p"}, []string{"--preset"}},
+		{[]string{"claude-launcher", "mcp", "test", "--t"}, []string{"--timeout"}},
+		{[]string{"claude-launcher", "j"}, []string{"jira"}},
+		{[]string{"claude-launcher", "jira", "--d"}, nil},
+		{[]string{"claude-launcher", "shell-init", "f"}, []string{"fish"}},
+		{[]string{"claude-launcher", "shell-init", "bash", "--c"}, []string{"--chpwd"}},
+		{[]string{"claude-launcher", "--dir", ""}, nil},
+		{[]string{"claude-launcher", "--", ""}, nil},
+		{[]string{"claude-launcher", "up", "backend", ""}, nil},
+	}
+	for _, tt := range tests {
+		if got := complete(tt.words, d); !slices.Equal(got, tt.expected) {
+			t.Errorf("complete(%q) = %q, expected %q", tt.words, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/account"
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// configCommands maps the `config` subcommands to their entry points
+var configCommands = map[string]func(args []string) int{
+	"path":     runConfigPath,
+	"validate": runConfigValidate,
+}
+
+// runConfig implements `claude-launcher config <COMMAND>`
+func runConfig(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	commands := strings.Join(slices.Sorted(maps.Keys(configCommands)), ", ")
+	if len(args) == 0 {
+		printer.Error("Usage: claude-launcher config <COMMAND> (available: %s)\n", commands)
+		return exitError
+	}
+	cmd, ok := configCommands[args[0]]
+	if !ok {
+		printer.Error("Unknown config command %q (available: %s)\n", args[0], commands)
+		return exitError
+	}
+	return cmd(args[1:])
+}
+
+// runConfigPath implements `claude-launcher config path`: it prints the path of the config
+// file in effect, whether or not it exists, for scripts such as the direnv library
+func runConfigPath(args []string) int {
+	fs := flag.NewFlagSet("config path", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	path, err := config.DefaultConfigPath()
+	if err != nil {
+		ui.NewPrinter(os.Stderr).Error("Error: %v\n", err)
+		return exitError
+	}
+	fmt.Println(path)
+	return exitSuccess
+}
+
+// runConfigValidate implements `claude-launcher config validate`: it reports why the config
+// file does not load, then lints the configuration in effect. It exits with exitConfig when the
+// configuration is invalid and with exitError when the lint finds problems.
+func runConfigValidate(args []string) int {
+	fs := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	// CLAUDE_SAFE_DIRS would hide an invalid file from loadConfig
+	if path, err := config.DefaultConfigPath(); err == nil {
+		if _, statErr := os.Stat(path); statErr == nil {
+			if _, err := (&config.FileLoader{Path: path}).Load(); err != nil {
+				printer.Error("✗ %s: %v\n", tildePath(path), err)
+				return exitConfig
+			}
+		}
+	}
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	findings := append([]config.Finding{}, config.Lint(cfg, os.Environ())...)
+	if accCfg, err := account.LoadAccountConfig(); err == nil && accCfg != nil {
+		findings = append(findings, accCfg.Lint()...)
+	}
+
+	code := exitSuccess
+	if len(findings) > 0 {
+		code = exitError
+	}
+	if jsonOutput {
+		if printJSON(findings) != exitSuccess {
+			return exitError
+		}
+		return code
+	}
+	printer.ShowFindings(findings)
+	return code
+}
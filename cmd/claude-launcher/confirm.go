@@ -0,0 +1,113 @@
+package main
+
+import (
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/security"
+	"github.com/23prime/claude-launcher/internal/session"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// confirmFileLimit bounds the file count of the first-launch summary, so huge trees do not
+// delay the question
+const confirmFileLimit = 10000
+
+// confirmNewDir asks before the first launch in dir (confirmNewDirs), after showing what Claude
+// will be able to access. The answer is kept in the state directory, so neither dir nor its
+// subdirectories are asked about again. With approve (--approve-dir) dir is confirmed without
+// asking; otherwise, when nobody can answer, unconfirmed directories are refused.
+func confirmNewDir(dir string, approve, noPrompts bool, prompter *session.InteractivePrompter, printer *ui.Printer) int {
+	store, err := state.NewStore()
+	if err != nil {
+		printer.Error("Failed to open state directory: %v\n", err)
+		return exitError
+	}
+	confirmed, err := store.ConfirmedDirs()
+	if err != nil {
+		printer.Error("Failed to read confirmed directories: %v\n", err)
+		return exitError
+	}
+	resolved, err := security.ResolvePath(dir)
+	if err != nil {
+		printer.Error("Failed to resolve target directory: %v\n", err)
+		return exitError
+	}
+	if isConfirmed(confirmed, resolved) {
+		return exitSuccess
+	}
+
+	switch {
+	case approve:
+		printer.Success("✓ Confirmed %s (--approve-dir)\n", dir)
+	case noPrompts:
+		printer.Error("✗ %s has not been confirmed yet: launch there once interactively, or pass --approve-dir to confirm it\n", dir)
+		return exitDenied
+	default:
+		files, complete := countFiles(dir, confirmFileLimit)
+		printer.ShowFirstLaunch(dir, gitRemote(dir), files, complete)
+		ok, err := prompter.Confirm("Let Claude access this directory?", false)
+		if err != nil {
+			printer.Error("%v\n", err)
+			return promptExitCode(err)
+		}
+		if !ok {
+			return exitAborted
+		}
+	}
+
+	if err := store.ConfirmDir(resolved, time.Now()); err != nil {
+		printer.Error("Failed to record the confirmation: %v\n", err)
+		return exitError
+	}
+	return exitSuccess
+}
+
+// isConfirmed reports whether dir is one of the confirmed directories or inside one
+func isConfirmed(confirmed map[string]time.Time, dir string) bool {
+	for confirmedDir := range confirmed {
+		if security.IsWithin(dir, confirmedDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// gitRemote returns the URL of the origin remote of the repository containing dir, or ""
+func gitRemote(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// countFiles counts the files under dir, leaving out .git directories and stopping at limit.
+// complete is false when the count stopped early; unreadable directories are skipped.
+func countFiles(dir string, limit int) (n int, complete bool) {
+	complete = true
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error { //nolint:errcheck // the callback never fails
+		switch {
+		case err != nil:
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		case d.IsDir() && d.Name() == ".git":
+			return fs.SkipDir
+		case d.IsDir():
+			return nil
+		}
+		if n >= limit {
+			complete = false
+			return fs.SkipAll
+		}
+		n++
+		return nil
+	})
+	return n, complete
+}
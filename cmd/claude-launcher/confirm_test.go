@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/home"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+func TestCountFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"main.go", "src/a.go", "src/b.go", ".git/HEAD", ".git/objects/x"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, complete := countFiles(dir, 10); n != 3 || !complete {
+		t.Errorf("countFiles() = %d, %v, want 3 files without .git", n, complete)
+	}
+	if n, complete := countFiles(dir, 2); n != 2 || complete {
+		t.Errorf("countFiles() with limit 2 = %d, %v, want 2 and incomplete", n, complete)
+	}
+}
+
+func TestIsConfirmed(t *testing.T) {
+	confirmed := map[string]time.Time{"/work/api": time.Now()}
+
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{"/work/api", true},
+		{"/work/api/src", true},
+		{"/work/api-v2", false},
+		{"/work", false},
+	}
+	for _, tt := range tests {
+		if got := isConfirmed(confirmed, tt.dir); got != tt.want {
+			t.Errorf("isConfirmed(%q) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestConfirmNewDirWithoutPrompts(t *testing.T) {
+	t.Setenv(home.EnvVar, t.TempDir())
+	dir := t.TempDir()
+	printer := ui.NewPrinter(io.Discard)
+
+	if code := confirmNewDir(dir, false, true, nil, printer); code != exitDenied {
+		t.Errorf("confirmNewDir() without prompts = %d, want %d", code, exitDenied)
+	}
+	if code := confirmNewDir(dir, true, true, nil, printer); code != exitSuccess {
+		t.Errorf("confirmNewDir() with approve = %d, want %d", code, exitSuccess)
+	}
+	// The approval is recorded like an answer, for the subdirectories too
+	sub := filepath.Join(dir, "src")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if code := confirmNewDir(sub, false, true, nil, printer); code != exitSuccess {
+		t.Errorf("confirmNewDir() after approval = %d, want %d", code, exitSuccess)
+	}
+}
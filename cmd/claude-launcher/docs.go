@@ -0,0 +1,511 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// genDocsCommand is the hidden subcommand that writes the manuals.
+// It is not listed in the help text; packagers run it at build time.
+const genDocsCommand = "gen-docs"
+
+// commandDoc describes a command for the generated manuals
+type commandDoc struct {
+	Name    string               // Empty for the default launch
+	Usage   string               // Arguments after the name
+	Summary string               // One paragraph
+	Flags   func() *flag.FlagSet // Optional: the command's own options
+}
+
+// commandDocs lists the documented commands in the order they appear in the manuals.
+// Every entry of subcommands except the hidden ones must be listed (checked by tests).
+var commandDocs = []commandDoc{
+	{
+		Usage:   "[OPTIONS] [CLAUDE_ARGUMENTS...]",
+		Summary: "Check the directory, select an account and a session, and launch Claude Code. Arguments after the options (or after --) are passed to claude.",
+		Flags:   func() *flag.FlagSet { fs, _ := newLaunchFlags(); return fs },
+	},
+	{
+		Name:    "up",
+		Usage:   "<PROFILE> [CLAUDE_ARGUMENTS...]",
+		Summary: "Launch with a named profile from config.json. Without a name, list the profiles.",
+	},
+	{
+		Name:    "<ALIAS>",
+		Usage:   "[CLAUDE_ARGUMENTS...]",
+		Summary: "Launch with a user-defined alias from the aliases section of config.json.",
+	},
+	{
+		Name:    "<PLUGIN>",
+		Usage:   "[ARGUMENTS...]",
+		Summary: "Run the plugin claude-launcher-<PLUGIN> found on PATH with the arguments, like git and kubectl plugins. Built-in commands and aliases take precedence.",
+	},
+	{
+		Name:    "workspace open",
+		Usage:   "<NAME> [CLAUDE_ARGUMENTS...]",
+		Summary: "Launch in one member of a workspace with the others added via --add-dir.",
+	},
+	{
+		Name:    "workspace list",
+		Summary: "List the configured workspaces.",
+	},
+	{
+		Name:    "tui",
+		Usage:   "[CLAUDE_ARGUMENTS...]",
+		Summary: "Full-screen menu: pick a directory, project, profile, workspace, account or detached session with the arrow keys and enter.",
+	},
+	{
+		Name:    "pick",
+		Usage:   "[--sort config|name|recent] [CLAUDE_ARGUMENTS...]",
+		Summary: "Search the allowed directories and the git repositories directly inside them by typing, and launch in the chosen one from anywhere. --sort lists them in config order, by name or most recently launched first, and is remembered for the next pick.",
+	},
+	{
+		Name:    "recent",
+		Usage:   "[-n N] [CLAUDE_ARGUMENTS...]",
+		Summary: "Pick one of the last projects launched (from the launch history) and launch it again with the same account, preset, model, mode and session choice. -r is a shortcut.",
+		Flags:   func() *flag.FlagSet { fs, _ := newRecentFlags(); return fs },
+	},
+	{
+		Name:    "@<BOOKMARK>",
+		Usage:   "[CLAUDE_ARGUMENTS...]",
+		Summary: "Launch in a bookmarked directory from anywhere, with the account and permission preset of the bookmark.",
+	},
+	{
+		Name:    "bookmark add",
+		Usage:   "<NAME> [PATH] [OPTIONS]",
+		Summary: "Bookmark PATH (default: the current directory) as @NAME in config.json.",
+		Flags:   func() *flag.FlagSet { fs, _ := newBookmarkAddFlags(); return fs },
+	},
+	{
+		Name:    "bookmark remove",
+		Usage:   "<NAME>",
+		Summary: "Remove a bookmark from config.json.",
+	},
+	{
+		Name:    "bookmark list",
+		Summary: "List the bookmarks.",
+	},
+	{
+		Name:    "run",
+		Usage:   "[-p PROMPT] [OPTIONS] [CLAUDE_ARGUMENTS...]",
+		Summary: "Run Claude non-interactively in print mode (for pipelines). stdin is passed through; only Claude's output goes to stdout.",
+		Flags:   func() *flag.FlagSet { fs, _ := newRunFlags(); return fs },
+	},
+	{
+		Name:    "exec",
+		Usage:   "[-d DIR] -- COMMAND [ARGS...]",
+		Summary: "Run any command in the directory only if the directory check of a launch passes, so that other risky tools (migration scripts, terraform) share the allowlist. Runs and refusals are written to the audit log; the command's exit code is returned.",
+		Flags:   func() *flag.FlagSet { fs, _ := newExecFlags(); return fs },
+	},
+	{
+		Name:    "attach",
+		Usage:   "[ID]",
+		Summary: "Reattach to a detached session (Ctrl-\\ detaches again).",
+	},
+	{
+		Name:    "ps",
+		Summary: "List running Claude sessions started by the launcher.",
+	},
+	{
+		Name:    "kill",
+		Usage:   "<ID>",
+		Summary: "Stop a running session.",
+	},
+	{
+		Name:    "rollback",
+		Usage:   "[OPTIONS] [ID]",
+		Summary: "Restore a snapshot taken before a launch (snapshot setting), by default the latest one of the current repository: HEAD, the files and the index go back to how they were, and files created since are deleted. The discarded state is saved as a new snapshot first, so the rollback can be undone.",
+		Flags:   func() *flag.FlagSet { fs, _ := newRollbackFlags(); return fs },
+	},
+	{
+		Name:    "ssh",
+		Usage:   "[OPTIONS] HOST:PATH [-- CLAUDE_ARGS]",
+		Summary: "Run claude in PATH on a host from remoteHosts over ssh with a pty. The host checks PATH against its allowedDirs after resolving symlinks; -a selects the account's remote config directory.",
+		Flags:   func() *flag.FlagSet { fs, _ := newSSHFlags(); return fs },
+	},
+	{
+		Name:    "which",
+		Usage:   "[OPTIONS]",
+		Summary: "Print the path of the binary a launch would execute (like command -v) and its version.",
+		Flags:   func() *flag.FlagSet { fs, _ := newWhichFlags(); return fs },
+	},
+	{
+		Name:    "env",
+		Usage:   "[OPTIONS]",
+		Summary: "Print the environment changes a launch would apply as shell commands, e.g. eval \"$(claude-launcher env -a Work)\".",
+		Flags:   func() *flag.FlagSet { fs, _ := newEnvFlags(); return fs },
+	},
+	{
+		Name:    "explain",
+		Usage:   "[PATH]",
+		Summary: "Walk through the directory check for PATH (default: the current directory): how it resolved, the outcome of every allowed directory and the config change that would allow it. Exits with 3 when a launch there would be refused.",
+	},
+	{
+		Name:    "stats",
+		Summary: "Summarize the launch history: launches per project, account and preset, and the session hours this week and this month.",
+		Flags:   func() *flag.FlagSet { fs, _ := newStatsFlags(); return fs },
+	},
+	{
+		Name:    "account stats",
+		Summary: "Show how many tokens each account used in the last 5 hours (the window of Claude's usage limits) and the last 7 days, when it was last used and when it last hit a usage limit, read from its Claude Code transcripts.",
+	},
+	{
+		Name:    "history export",
+		Usage:   "[OPTIONS]",
+		Summary: "Print one record per launch as CSV (with a header row) or JSON lines: id, mode, project, directory, account, preset, model, the allowed directory that matched, start and end time, duration in seconds, exit code and error. With --audit the audit log is exported instead.",
+		Flags:   func() *flag.FlagSet { fs, _ := newHistoryExportFlags(); return fs },
+	},
+	{
+		Name:    "mcp list",
+		Summary: "List the global MCP servers and those of each project, as configured in mcpServers and projects[].mcpServers.",
+	},
+	{
+		Name:    "mcp add",
+		Usage:   "<NAME> [OPTIONS] (--url URL | -- COMMAND [ARGS...])",
+		Summary: "Add (or replace) an MCP server in config.json, globally or for the project of --project. Other settings in the file are kept.",
+		Flags:   func() *flag.FlagSet { fs, _ := newMCPAddFlags(); return fs },
+	},
+	{
+		Name:    "mcp remove",
+		Usage:   "<NAME> [OPTIONS]",
+		Summary: "Remove an MCP server from config.json, globally or from the project of --project.",
+		Flags:   func() *flag.FlagSet { fs, _ := newMCPRemoveFlags(); return fs },
+	},
+	{
+		Name:    "mcp test",
+		Usage:   "[OPTIONS] [NAME...]",
+		Summary: "Start the MCP servers a launch in the directory would get (or the named ones), or connect to remote ones, and perform the MCP initialize handshake. Exits with 1 when a server fails.",
+		Flags:   func() *flag.FlagSet { fs, _ := newMCPTestFlags(); return fs },
+	},
+	{
+		Name:    "plugins",
+		Summary: "List the plugins (claude-launcher-<name> executables) on PATH, marking those whose config and pre-launch hooks the plugins config option enables.",
+	},
+	{
+		Name:    "prefs list",
+		Summary: "List the saved interactive preferences: prompt style, color palette, symbols and pick sort order. They are kept in the state directory and apply where config.json and the options say nothing.",
+	},
+	{
+		Name:    "prefs set",
+		Usage:   "<KEY> <VALUE>",
+		Summary: "Save a preference: prompts (menu or numbered), palette (default or colorblind), symbols (auto, unicode or ascii) or pickSort (config, name or recent).",
+	},
+	{
+		Name:    "prefs unset",
+		Usage:   "<KEY>",
+		Summary: "Forget a saved preference.",
+	},
+	{
+		Name:    "config path",
+		Summary: "Print the path of the config file in effect (--config, then CLAUDE_LAUNCHER_HOME, then ~/.config/claude-launcher/config.json), whether or not it exists.",
+	},
+	{
+		Name:    "config validate",
+		Summary: "Check that config.json loads, then look for rules that overlap or can never match: allowed directories inside other allowed directories or listed twice, yoloAllowedDirs and projects outside allowedDirs, allow rules that are also denied, missing allowed directories, env.passthrough patterns matching no variable and accounts sharing a config directory. Each problem comes with a suggested fix. Exits with 2 when the configuration is invalid and 1 when problems are found.",
+	},
+	{
+		Name:    "integrate direnv",
+		Usage:   "[OPTIONS]",
+		Summary: "Write a managed block into the directory's .envrc that exports the account's launch environment (via env) and CLAUDE_LAUNCHER_PROFILE, or with --lib install a global use_claude_launcher direnv function.",
+		Flags:   func() *flag.FlagSet { fs, _ := newDirenvFlags(); return fs },
+	},
+	{
+		Name:    "integrate git-hooks",
+		Usage:   "[OPTIONS]",
+		Summary: "Install pre-commit and post-checkout hooks in the repository that run the directory check (as explain does) on it. Commits are refused and checkouts warned about when the repository is outside the allowed directories. Existing hooks are kept; only the managed block is updated.",
+		Flags:   func() *flag.FlagSet { fs, _ := newGitHooksFlags(); return fs },
+	},
+	{
+		Name:    "integrate systemd",
+		Usage:   "[OPTIONS]",
+		Summary: "Install a systemd user unit that starts a detached session in the directory at login, to reattach with attach. The directory and account are checked when the unit is written. Linux only, like --detach.",
+		Flags:   func() *flag.FlagSet { fs, _ := newSystemdFlags(); return fs },
+	},
+	{
+		Name:    "shell-init",
+		Usage:   "<bash|zsh|fish> [OPTIONS]",
+		Summary: "Print the completion script plus a wrapper function using it (cl by default), e.g. eval \"$(claude-launcher shell-init bash --chpwd)\". --chpwd adds a hook reporting after cd whether launches are allowed in the new directory.",
+		Flags:   func() *flag.FlagSet { fs, _ := newShellInitFlags(); return fs },
+	},
+	{
+		Name:    "prompt-status",
+		Usage:   "[OPTIONS]",
+		Summary: "Print one word for shell prompts: whether launches are allowed in the current directory (exit code 0) or not (exit code 3). It skips plugins and caches its answers for a minute, so it can run before every prompt.",
+		Flags:   func() *flag.FlagSet { fs, _ := newPromptStatusFlags(); return fs },
+	},
+	{
+		Name:    "completion",
+		Usage:   "<bash|zsh|fish>",
+		Summary: "Print a completion script for the shell. Account names, presets, profiles and workspaces are completed from the configuration.",
+	},
+}
+
+// flagDoc is one documented option, with its shorthand if any
+type flagDoc struct {
+	Names []string // e.g. "-q", "--quiet"
+	Arg   string   // Placeholder of the value, empty for switches
+	Usage string
+}
+
+// globalFlags are the options handled by applyGlobalFlags for every command
+var globalFlags = []flagDoc{
+	{Names: []string{"--no-color"}, Usage: "Disable colored output (also: NO_COLOR environment variable)"},
+	{Names: []string{"--ascii"}, Usage: "Use [OK] / [X] / -> instead of symbols"},
+	{Names: []string{"-q", "--quiet"}, Usage: "Only print errors, warnings and prompts"},
+	{Names: []string{"--verbose"}, Usage: "Trace config loading, directory checks and the final command (also: CLAUDE_LAUNCHER_DEBUG=1)"},
+	{Names: []string{"--ui"}, Arg: "MODE", Usage: "Message style: auto (default), color, plain, json, silent or accessible"},
+	{Names: []string{"--accessible"}, Usage: "Screen-reader-friendly output and prompts (same as --ui accessible)"},
+	{Names: []string{"--json"}, Usage: "Print JSON to stdout for the listing commands"},
+}
+
+// exitStatuses documents the launcher's own exit codes
+var exitStatuses = []struct {
+	Code    int
+	Meaning string
+}{
+	{exitSuccess, "Success"},
+	{exitError, "Other error"},
+	{exitConfig, "Configuration missing or invalid"},
+	{exitDenied, "Directory not allowed"},
+	{exitAborted, "Prompt or menu cancelled"},
+	{exitNotFound, "claude (or the selected tool) not found"},
+}
+
+// flagDocs collects the options of fs, merging flags that share a variable (e.g. -a and --account).
+// The usage without a "(shorthand)" / "(long form)" suffix wins.
+func flagDocs(fs *flag.FlagSet) []flagDoc {
+	var docs []flagDoc
+	index := map[any]int{}
+	fs.VisitAll(func(f *flag.Flag) {
+		name := "--" + f.Name
+		if len(f.Name) == 1 {
+			name = "-" + f.Name
+		}
+
+		key := flagVariable(f)
+		i, ok := index[key]
+		if !ok {
+			i = len(docs)
+			index[key] = i
+			docs = append(docs, flagDoc{})
+		}
+		doc := &docs[i]
+		doc.Names = append(doc.Names, name)
+		if doc.Usage == "" || isAliasUsage(doc.Usage) && !isAliasUsage(f.Usage) {
+			doc.Arg, doc.Usage = flag.UnquoteUsage(f)
+		}
+	})
+
+	for i := range docs {
+		// Shorthands first, as in the help text
+		slices.SortStableFunc(docs[i].Names, func(a, b string) int { return len(a) - len(b) })
+	}
+	slices.SortStableFunc(docs, func(a, b flagDoc) int {
+		return strings.Compare(strings.TrimLeft(a.Names[len(a.Names)-1], "-"), strings.TrimLeft(b.Names[len(b.Names)-1], "-"))
+	})
+	return docs
+}
+
+// isAliasUsage reports whether usage describes the second spelling of another flag
+func isAliasUsage(usage string) bool {
+	return strings.HasSuffix(usage, "(shorthand)") || strings.HasSuffix(usage, "(long form)")
+}
+
+// flagVariable identifies the variable behind f, so both spellings of an option share a key
+func flagVariable(f *flag.Flag) any {
+	v := reflect.ValueOf(f.Value)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		return v.UnsafePointer()
+	default:
+		return f
+	}
+}
+
+// runGenDocs implements the hidden `claude-launcher gen-docs` command
+func runGenDocs(args []string) int {
+	fs := flag.NewFlagSet(genDocsCommand, flag.ContinueOnError)
+	out := fs.String("out", ".", "Directory to write claude-launcher.1 and cli-reference.md to")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if err := os.MkdirAll(*out, 0o750); err != nil {
+		printer.Error("Failed to create %s: %v\n", *out, err)
+		return exitError
+	}
+
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"claude-launcher.1", writeManPage},
+		{"cli-reference.md", writeMarkdownReference},
+	}
+	for _, file := range files {
+		path := filepath.Join(*out, file.name)
+		if err := writeDocFile(path, file.write); err != nil {
+			printer.Error("Failed to write %s: %v\n", path, err)
+			return exitError
+		}
+		printer.Success("✓ Wrote %s\n", path)
+	}
+	return exitSuccess
+}
+
+// writeDocFile creates path and fills it with write
+func writeDocFile(path string, write func(io.Writer) error) error {
+	// #nosec G304 -- path is chosen by whoever runs gen-docs
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close() //nolint:errcheck // the write error is reported instead
+		return err
+	}
+	return f.Close()
+}
+
+// commandLine returns the synopsis of c without the program name
+func (c commandDoc) commandLine() string {
+	return strings.TrimSpace(c.Name + " " + c.Usage)
+}
+
+// names joins the spellings of the option, e.g. "-a, --account NAME"
+func (d flagDoc) names() string {
+	s := strings.Join(d.Names, ", ")
+	if d.Arg != "" {
+		s += " " + d.Arg
+	}
+	return s
+}
+
+// writeMarkdownReference writes the CLI reference in Markdown
+func writeMarkdownReference(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# claude-launcher CLI reference\n\n")
+	b.WriteString("<!-- Generated by `claude-launcher gen-docs`. Do not edit. -->\n\n")
+
+	b.WriteString("## Synopsis\n\n```txt\n")
+	for _, c := range commandDocs {
+		fmt.Fprintf(&b, "claude-launcher %s\n", c.commandLine())
+	}
+	b.WriteString("```\n\n")
+
+	b.WriteString("## Global options\n\nAccepted by every command.\n\n")
+	writeMarkdownFlags(&b, globalFlags)
+
+	b.WriteString("\n## Commands\n")
+	for _, c := range commandDocs {
+		fmt.Fprintf(&b, "\n### `claude-launcher %s`\n\n%s\n", c.commandLine(), c.Summary)
+		if c.Flags != nil {
+			b.WriteString("\n")
+			writeMarkdownFlags(&b, flagDocs(c.Flags()))
+		}
+	}
+
+	b.WriteString("\n## Exit status\n\n| Code | Meaning |\n| --- | --- |\n")
+	for _, s := range exitStatuses {
+		fmt.Fprintf(&b, "| %d | %s |\n", s.Code, s.Meaning)
+	}
+	b.WriteString("\nOnce Claude Code has run, its own exit code is returned.\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownFlags writes docs as a Markdown table
+func writeMarkdownFlags(b *strings.Builder, docs []flagDoc) {
+	b.WriteString("| Option | Description |\n| --- | --- |\n")
+	for _, d := range docs {
+		fmt.Fprintf(b, "| `%s` | %s |\n", d.names(), strings.ReplaceAll(d.Usage, "|", "\\|"))
+	}
+}
+
+// writeManPage writes the manual in roff (man(7)) format
+func writeManPage(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH CLAUDE-LAUNCHER 1 %q %q\n", manDate(), "claude-launcher "+Version)
+	b.WriteString(".SH NAME\nclaude-launcher \\- comprehensive launcher for Claude Code\n")
+
+	b.WriteString(".SH SYNOPSIS\n")
+	for i, c := range commandDocs {
+		if i > 0 {
+			b.WriteString(".br\n")
+		}
+		fmt.Fprintf(&b, "\\fBclaude-launcher\\fR %s\n", roffEscape(c.commandLine()))
+	}
+
+	b.WriteString(".SH DESCRIPTION\n")
+	b.WriteString("Combines directory security, account selection and session management for Claude Code.\n")
+	b.WriteString("The configuration is read from ~/.config/claude-launcher/config.json.\n")
+
+	b.WriteString(".SH GLOBAL OPTIONS\n")
+	writeManFlags(&b, globalFlags)
+
+	b.WriteString(".SH COMMANDS\n")
+	for _, c := range commandDocs {
+		fmt.Fprintf(&b, ".SS \"%s\"\n%s\n", roffEscape("claude-launcher "+c.commandLine()), roffEscape(c.Summary))
+		if c.Flags != nil {
+			writeManFlags(&b, flagDocs(c.Flags()))
+		}
+	}
+
+	b.WriteString(".SH EXIT STATUS\n")
+	for _, s := range exitStatuses {
+		fmt.Fprintf(&b, ".TP\n.B %d\n%s\n", s.Code, roffEscape(s.Meaning))
+	}
+	b.WriteString(".PP\nOnce Claude Code has run, its own exit code is returned.\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeManFlags writes docs as roff tagged paragraphs
+func writeManFlags(b *strings.Builder, docs []flagDoc) {
+	for _, d := range docs {
+		names := make([]string, len(d.Names))
+		for i, name := range d.Names {
+			names[i] = "\\fB" + roffEscape(name) + "\\fR"
+		}
+		b.WriteString(".TP\n" + strings.Join(names, ", "))
+		if d.Arg != "" {
+			b.WriteString(" \\fI" + roffEscape(d.Arg) + "\\fR")
+		}
+		b.WriteString("\n" + roffEscape(d.Usage) + "\n")
+	}
+}
+
+// roffEscape escapes s for use in roff text
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\e")
+	s = strings.ReplaceAll(s, "-", "\\-")
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = "\\&" + s
+	}
+	return s
+}
+
+// manDate is the date shown in the manual's footer. SOURCE_DATE_EPOCH keeps package builds reproducible.
+func manDate() string {
+	t := time.Now()
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if sec, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			t = time.Unix(sec, 0)
+		}
+	}
+	return t.UTC().Format("2006-01-02")
+}
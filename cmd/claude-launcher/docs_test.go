@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/detach"
+	"github.com/23prime/claude-launcher/internal/limits"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+func TestCommandDocsCoverSubcommands(t *testing.T) {
+	hidden := []string{genDocsCommand, detach.ServeCommand, limits.ExecCommand}
+
+	for name := range subcommands {
+		if slices.Contains(hidden, name) {
+			continue
+		}
+		documented := slices.ContainsFunc(commandDocs, func(c commandDoc) bool {
+			return c.Name == name || strings.HasPrefix(c.Name, name+" ")
+		})
+		if !documented {
+			t.Errorf("subcommand %q is missing from commandDocs", name)
+		}
+	}
+}
+
+func TestGlobalFlagsAreApplied(t *testing.T) {
+	t.Cleanup(func() {
+		_ = ui.SetMode(ui.ModeAuto) //nolint:errcheck // auto is always valid
+		jsonOutput = false
+	})
+
+	for _, d := range globalFlags {
+		for _, name := range d.Names {
+			if name == "--verbose" {
+				continue // Debug logging is process-wide and cannot be turned off again
+			}
+			args := []string{name}
+			if d.Arg != "" {
+				args = append(args, "plain")
+			}
+			rest, err := applyGlobalFlags(args)
+			if err != nil || len(rest) != 0 {
+				t.Errorf("applyGlobalFlags(%v) = %v, %v; expected the flag to be consumed", args, rest, err)
+			}
+		}
+	}
+}
+
+func TestFlagDocs(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var dir string
+	var quiet bool
+	env := envFlag{}
+	fs.StringVar(&dir, "dir", "", "Directory (`DIR`) to use")
+	fs.StringVar(&dir, "d", "", "Directory to use (shorthand)")
+	fs.BoolVar(&quiet, "quiet", false, "Print less")
+	fs.Var(env, "env", "Set a variable (`KEY=VALUE`)")
+
+	expected := []flagDoc{
+		{Names: []string{"-d", "--dir"}, Arg: "DIR", Usage: "Directory (DIR) to use"},
+		{Names: []string{"--env"}, Arg: "KEY=VALUE", Usage: "Set a variable (KEY=VALUE)"},
+		{Names: []string{"--quiet"}, Usage: "Print less"},
+	}
+	docs := flagDocs(fs)
+	if len(docs) != len(expected) {
+		t.Fatalf("flagDocs() = %+v, expected %+v", docs, expected)
+	}
+	for i, d := range docs {
+		e := expected[i]
+		if !slices.Equal(d.Names, e.Names) || d.Arg != e.Arg || d.Usage != e.Usage {
+			t.Errorf("flagDocs()[%d] = %+v, expected %+v", i, d, e)
+		}
+	}
+}
+
+func TestWriteMarkdownReferenceListsEveryFlag(t *testing.T) {
+	var b strings.Builder
+	if err := writeMarkdownReference(&b); err != nil {
+		t.Fatalf("writeMarkdownReference() error = %v", err)
+	}
+	out := b.String()
+
+	for _, c := range commandDocs {
+		if !strings.Contains(out, "### `claude-launcher "+c.commandLine()+"`") {
+			t.Errorf("reference is missing command %q", c.commandLine())
+		}
+		if c.Flags == nil {
+			continue
+		}
+		c.Flags().VisitAll(func(f *flag.Flag) {
+			if !strings.Contains(out, "-"+f.Name) {
+				t.Errorf("reference is missing flag -%s of %q", f.Name, c.commandLine())
+			}
+		})
+	}
+}
+
+func TestWriteManPage(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "0")
+
+	var b strings.Builder
+	if err := writeManPage(&b); err != nil {
+		t.Fatalf("writeManPage() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		`.TH CLAUDE-LAUNCHER 1 "1970-01-01"`,
+		".SH SYNOPSIS",
+		`\fB\-a\fR, \fB\-\-account\fR \fINAME\fR`,
+		".SH EXIT STATUS",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("man page is missing %q", want)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "'") {
+			t.Errorf("line %q would be read as a roff request", line)
+		}
+	}
+}
+
+func TestRoffEscape(t *testing.T) {
+	tests := map[string]string{
+		"--dir":      `\-\-dir`,
+		`Ctrl-\`:     `Ctrl\-\e`,
+		".hidden":    `\&.hidden`,
+		"plain text": "plain text",
+	}
+	for in, expected := range tests {
+		if got := roffEscape(in); got != expected {
+			t.Errorf("roffEscape(%q) = %q, expected %q", in, got, expected)
+		}
+	}
+}
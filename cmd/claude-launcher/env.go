@@ -0,0 +1,194 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/account"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// Shells supported by `env --shell`
+const (
+	shellPOSIX      = "sh"
+	shellFish       = "fish"
+	shellPowerShell = "powershell"
+)
+
+// envChangesJSON is the `env --json` output
+type envChangesJSON struct {
+	Set   map[string]string `json:"set"`
+	Unset []string          `json:"unset"`
+}
+
+// envFlags holds the options of `env`
+type envFlags struct {
+	accountName, dir, toolName, shell string
+	noOtel                            bool
+}
+
+// newEnvFlags defines the options of `env`
+func newEnvFlags() (*flag.FlagSet, *envFlags) {
+	fs := flag.NewFlagSet("env", flag.ContinueOnError)
+	f := &envFlags{}
+	fs.StringVar(&f.accountName, "a", "", "Account `NAME` to use (required if several are configured)")
+	fs.StringVar(&f.accountName, "account", "", "Account name to use (long form)")
+	fs.StringVar(&f.dir, "d", "", "Directory (`DIR`) whose project settings apply (defaults to the current directory)")
+	fs.StringVar(&f.dir, "dir", "", "Directory whose project settings apply (long form)")
+	fs.StringVar(&f.toolName, "tool", "", "Agent CLI (`NAME`) whose config dir variable is set (default: the tool configured for the directory)")
+	fs.BoolVar(&f.noOtel, "no-otel", false, "Leave out OpenTelemetry environment variables")
+	fs.StringVar(&f.shell, "shell", defaultShell(), "Output syntax (`SHELL`): sh, fish or powershell")
+	return fs, f
+}
+
+// runEnv implements `claude-launcher env`: it prints the environment changes a launch would
+// apply (config dir, OpenTelemetry, proxy and env settings, scrubbed variables) as shell
+// commands, so that `eval "$(claude-launcher env)"` reproduces them in the current shell
+func runEnv(args []string) int {
+	fs, f := newEnvFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if !slices.Contains([]string{shellPOSIX, shellFish, shellPowerShell}, f.shell) {
+		printer.Error("Unknown shell %q (available: sh, fish, powershell)\n", f.shell)
+		return exitError
+	}
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	currentDir, err := resolveTargetDir(f.dir)
+	if err != nil {
+		printer.Error("Failed to resolve target directory: %v\n", err)
+		return exitError
+	}
+	project := cfg.FindProject(currentDir)
+	if f.toolName == "" {
+		f.toolName = cfg.ToolFor(project)
+	}
+	tool, err := launcher.LookupTool(f.toolName)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return exitError
+	}
+
+	selectedAccount, err := account.SelectAccountNonInteractively(f.accountName)
+	if err != nil {
+		printer.Error("Failed to select account: %v\n", err)
+		return exitError
+	}
+	if selectedAccount != nil {
+		if err := selectedAccount.Prepare(); err != nil {
+			printer.Error("Failed to prepare account: %v\n", err)
+			return exitError
+		}
+	}
+	env, secretEnv, err := launchEnv(cfg, project, selectedAccount, nil)
+	if err != nil {
+		printer.Error("Failed to resolve secrets: %v\n", err)
+		return exitError
+	}
+	opts := launcher.LaunchOptions{
+		Dir:       currentDir,
+		OtelEnv:   buildLaunchOtelEnv(cfg, selectedAccount, f.noOtel),
+		Env:       env,
+		SecretEnv: secretEnv,
+	}
+	if selectedAccount != nil {
+		opts.ConfigDir = selectedAccount.ConfigDir
+	}
+
+	cmd, err := newLauncher(cfg, tool).Prepare(opts)
+	if err != nil {
+		printer.Error("Failed to prepare launch: %v\n", err)
+		return exitError
+	}
+	defer cmd.Cleanup()
+
+	set, unset := envChanges(os.Environ(), cmd.Env)
+	if jsonOutput {
+		return printJSON(envChangesJSON{Set: set, Unset: unset})
+	}
+	writeEnvScript(os.Stdout, f.shell, set, unset)
+	return exitSuccess
+}
+
+// defaultShell guesses the syntax of the user's shell from $SHELL
+func defaultShell() string {
+	if filepath.Base(os.Getenv("SHELL")) == "fish" {
+		return shellFish
+	}
+	if runtime.GOOS == "windows" && os.Getenv("SHELL") == "" {
+		return shellPowerShell
+	}
+	return shellPOSIX
+}
+
+// envChanges returns the variables env sets or changes compared to base, and the names
+// of those it removes
+func envChanges(base, env []string) (map[string]string, []string) {
+	set := make(map[string]string)
+	for _, e := range launcher.EnvDelta(base, env) {
+		key, value, _ := strings.Cut(e, "=")
+		set[key] = value
+	}
+	unset := launcher.EnvRemoved(base, env)
+	slices.Sort(unset)
+	return set, unset
+}
+
+// writeEnvScript writes set and unset as commands for shell, sorted by name
+func writeEnvScript(w io.Writer, shell string, set map[string]string, unset []string) {
+	for _, key := range slices.Sorted(maps.Keys(set)) {
+		var line string
+		switch shell {
+		case shellFish:
+			line = fmt.Sprintf("set -gx %s %s", key, fishQuote(set[key]))
+		case shellPowerShell:
+			line = fmt.Sprintf("$env:%s = %s", key, powerShellQuote(set[key]))
+		default:
+			line = fmt.Sprintf("export %s=%s", key, shQuote(set[key]))
+		}
+		_, _ = fmt.Fprintln(w, line) //nolint:errcheck // stdout write errors are not actionable
+	}
+	for _, key := range unset {
+		var line string
+		switch shell {
+		case shellFish:
+			line = "set -e " + key
+		case shellPowerShell:
+			line = "Remove-Item Env:" + key + " -ErrorAction SilentlyContinue"
+		default:
+			line = "unset " + key
+		}
+		_, _ = fmt.Fprintln(w, line) //nolint:errcheck // stdout write errors are not actionable
+	}
+}
+
+// shQuote quotes s for POSIX shells
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes s for fish, where \ and ' are escaped inside single quotes
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// powerShellQuote quotes s for PowerShell, where ' is doubled inside single quotes
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
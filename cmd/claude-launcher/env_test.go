@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnvChanges(t *testing.T) {
+	base := []string{"HOME=/home/user", "SECRET=x", "LANG=C"}
+	env := []string{"HOME=/home/user", "LANG=ja_JP.UTF-8", "CLAUDE_CONFIG_DIR=/home/user/.claude-work"}
+
+	set, unset := envChanges(base, env)
+
+	expectedSet := map[string]string{"LANG": "ja_JP.UTF-8", "CLAUDE_CONFIG_DIR": "/home/user/.claude-work"}
+	if !reflect.DeepEqual(set, expectedSet) {
+		t.Errorf("set = %v, expected %v", set, expectedSet)
+	}
+	if !reflect.DeepEqual(unset, []string{"SECRET"}) {
+		t.Errorf("unset = %v, expected [SECRET]", unset)
+	}
+}
+
+func TestWriteEnvScript(t *testing.T) {
+	set := map[string]string{"B": "it's", "A": `C:\x`}
+	unset := []string{"SECRET"}
+
+	tests := []struct {
+		shell    string
+		expected string
+	}{
+		{shell: shellPOSIX, expected: "export A='C:\\x'\nexport B='it'\\''s'\nunset SECRET\n"},
+		{shell: shellFish, expected: "set -gx A 'C:\\\\x'\nset -gx B 'it\\'s'\nset -e SECRET\n"},
+		{shell: shellPowerShell, expected: "$env:A = 'C:\\x'\n$env:B = 'it''s'\nRemove-Item Env:SECRET -ErrorAction SilentlyContinue\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			var b strings.Builder
+			writeEnvScript(&b, tt.shell, set, unset)
+			if b.String() != tt.expected {
+				t.Errorf("writeEnvScript() = %q, expected %q", b.String(), tt.expected)
+			}
+		})
+	}
+}
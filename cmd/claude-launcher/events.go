@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/event"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/notify"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// events carries the lifecycle events of the command being run
+var events = &event.Bus{}
+
+// subscribeEvents registers the features that follow the launch flow: traces, plugin config
+// hooks, the audit log, the launch history, notifications, the session summary and the update notice
+func subscribeEvents(bus *event.Bus) {
+	bus.Subscribe(func(e event.Event) error {
+		log.Debug("event published", "event", e.Name())
+		return nil
+	})
+
+	// Later subscribers see the configuration of the latest ConfigLoaded
+	var cfg *config.Config
+	event.On(bus, func(e event.ConfigLoaded) error {
+		cfg = e.Config
+		applyPluginConfig(e.Config, ui.NewPrinter(os.Stderr))
+		return nil
+	})
+
+	// Audit log: forwarded and refused --dangerously-skip-permissions
+	event.On(bus, func(e event.DirectoryChecked) error {
+		if e.Rule != "" && e.SkipPermissions && !e.YoloAllowed {
+			_ = recordAudit(state.AuditSkipPermissionsDenied, e.Dir, "", e.Args) //nolint:errcheck // the launch is refused anyway
+		}
+		return nil
+	})
+	event.On(bus, func(e event.LaunchStarted) error {
+		if !launcher.HasSkipPermissions(e.Options.Args) {
+			return nil
+		}
+		if err := recordAudit(state.AuditSkipPermissions, e.Options.Dir, e.Options.Account, e.Options.Args); err != nil {
+			return fmt.Errorf("failed to write audit log: %w", err)
+		}
+		return nil
+	})
+
+	// Launch history and webhooks (detached sessions record from their session server)
+	event.On(bus, func(e event.LaunchExited) error {
+		store, err := state.NewStore()
+		if err != nil {
+			return err
+		}
+		return (&launcher.StoreRecorder{Store: store}).Record(e.Record)
+	})
+	event.On(bus, func(e event.LaunchExited) error {
+		if cfg == nil {
+			return nil
+		}
+		return notify.New(cfg.Notifications, projectKey(cfg, e.Record.Dir)).Record(e.Record)
+	})
+
+	subscribeSessionSummary(bus)
+	subscribeUpdateCheck(bus)
+}
+
+// recordAudit appends an entry to the audit log in the state directory
+func recordAudit(name, dir, accountName string, args []string) error {
+	store, err := state.NewStore()
+	if err != nil {
+		return err
+	}
+	return store.AppendAudit(state.AuditEntry{Event: name, Dir: dir, Account: accountName, Args: args})
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// execFlags holds the options of `exec`
+type execFlags struct {
+	dir string
+}
+
+// newExecFlags defines the options of `exec`
+func newExecFlags() (*flag.FlagSet, *execFlags) {
+	fs := flag.NewFlagSet("exec", flag.ContinueOnError)
+	f := &execFlags{}
+	fs.StringVar(&f.dir, "d", "", "Directory (`DIR`) to run the command in (defaults to the current directory)")
+	fs.StringVar(&f.dir, "dir", "", "Directory to run the command in (long form)")
+	return fs, f
+}
+
+// runExec implements `claude-launcher exec [-d DIR] -- COMMAND [ARGS...]`: the directory
+// check of a launch applied to any command, so that other risky tools share the allowlist.
+// Every run and every refusal is written to the audit log; the command's exit code is returned.
+func runExec(args []string) int {
+	fs, f := newExecFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	argv := fs.Args()
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if len(argv) == 0 {
+		printer.Error("Usage: claude-launcher exec [-d DIR] -- COMMAND [ARGS...]\n")
+		return exitError
+	}
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	target, err := resolveTargetDir(f.dir)
+	if err != nil {
+		printer.Error("Failed to resolve target directory: %v\n", err)
+		return exitError
+	}
+	dir, _, _, code := authorizeLaunch(cfg, target, nil, printer)
+	if code == exitDenied {
+		_ = recordAudit(state.AuditExecDenied, target, "", argv) //nolint:errcheck // the command is refused anyway
+	}
+	if code != exitSuccess {
+		return code
+	}
+
+	path, err := exec.LookPath(argv[0])
+	if err != nil {
+		printer.Error("✗ %s not found\n", argv[0])
+		return exitNotFound
+	}
+	// The run is only allowed once it is on record
+	if err := recordAudit(state.AuditExec, dir, "", argv); err != nil {
+		printer.Error("Failed to write audit log: %v\n", err)
+		return exitError
+	}
+
+	// #nosec G204 -- running the user's command is the point of exec
+	cmd := exec.Command(path, argv[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	// Ctrl-C reaches the command as well; the launcher stays to return its exit code
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		printer.Error("Failed to run %s: %v\n", argv[0], err)
+		return exitError
+	}
+	return exitSuccess
+}
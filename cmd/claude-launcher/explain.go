@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/security"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// explainJSON is the `explain --json` output
+type explainJSON struct {
+	Path            string           `json:"path"`
+	Resolved        string           `json:"resolved"`
+	Source          string           `json:"source"` // "CLAUDE_SAFE_DIRS" or the config file path
+	Allowed         bool             `json:"allowed"`
+	MatchedBy       string           `json:"matchedBy,omitempty"`
+	Rules           []ruleJSON       `json:"rules"`
+	SkipPermissions *explainYoloJSON `json:"skipPermissions,omitempty"`
+}
+
+// explainYoloJSON is the yoloAllowedDirs part of the `explain --json` output
+type explainYoloJSON struct {
+	Allowed   bool       `json:"allowed"`
+	MatchedBy string     `json:"matchedBy,omitempty"`
+	Rules     []ruleJSON `json:"rules"`
+}
+
+// ruleJSON is one evaluated allowed directory
+type ruleJSON struct {
+	Dir      string `json:"dir"`
+	Resolved string `json:"resolved,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// newRulesJSON converts evaluated rules; an empty list is encoded as []
+func newRulesJSON(rules []security.RuleResult) []ruleJSON {
+	out := make([]ruleJSON, 0, len(rules))
+	for _, rule := range rules {
+		r := ruleJSON{Dir: rule.Dir, Resolved: rule.Resolved, Status: string(rule.Status)}
+		if rule.Err != nil {
+			r.Error = rule.Err.Error()
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// newExplainJSON builds the `explain --json` output
+func newExplainJSON(e, yolo *security.Explanation, source string) explainJSON {
+	out := explainJSON{
+		Path:     e.Path,
+		Resolved: e.Resolved,
+		Source:   source,
+		Rules:    newRulesJSON(e.Rules),
+	}
+	if match := e.Match(); match != nil {
+		out.Allowed, out.MatchedBy = true, match.Dir
+	}
+	if yolo != nil {
+		out.SkipPermissions = &explainYoloJSON{Rules: newRulesJSON(yolo.Rules)}
+		if match := yolo.Match(); match != nil {
+			out.SkipPermissions.Allowed, out.SkipPermissions.MatchedBy = true, match.Dir
+		}
+	}
+	return out
+}
+
+// runExplain implements `claude-launcher explain [PATH]`: it walks through the directory
+// check for PATH (default: the current directory) and exits with exitDenied when a launch
+// there would be refused
+func runExplain(args []string) int {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if fs.NArg() > 1 {
+		printer.Error("Usage: claude-launcher explain [PATH]\n")
+		return exitError
+	}
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	path, err := explainPath(fs.Arg(0))
+	if err != nil {
+		printer.Error("Failed to resolve %s: %v\n", fs.Arg(0), err)
+		return exitError
+	}
+
+	e, err := security.NewDirectoryChecker(cfg.AllowedDirs).Explain(path)
+	if err != nil {
+		printer.Error("Failed to check directory: %v\n", err)
+		return exitError
+	}
+	var yolo *security.Explanation
+	if len(cfg.YoloAllowedDirs) > 0 {
+		if yolo, err = security.NewDirectoryChecker(cfg.YoloAllowedDirs).Explain(path); err != nil {
+			printer.Error("Failed to check directory: %v\n", err)
+			return exitError
+		}
+	}
+
+	// CLAUDE_SAFE_DIRS replaces allowedDirs from config.json when it is set
+	_, envErr := (&config.EnvLoader{}).Load()
+	fromEnv := envErr == nil
+	configPath, _ := config.DefaultConfigPath() //nolint:errcheck // only shown in the hint
+
+	if jsonOutput {
+		source := configPath
+		if fromEnv {
+			source = "CLAUDE_SAFE_DIRS"
+		}
+		if code := printJSON(newExplainJSON(e, yolo, source)); code != exitSuccess {
+			return code
+		}
+	} else {
+		printer.ShowDirectoryExplanation(e, yolo, configPath, fromEnv)
+	}
+
+	if e.Match() == nil {
+		return exitDenied
+	}
+	return exitSuccess
+}
+
+// explainPath returns the path to explain: arg with ~ expanded, or the current directory.
+// Unlike --dir, the path does not need to exist.
+func explainPath(arg string) (string, error) {
+	if arg == "" {
+		return os.Getwd()
+	}
+	expanded, err := config.ExpandPath(arg)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(expanded)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/security"
+)
+
+func TestNewExplainJSON(t *testing.T) {
+	e := &security.Explanation{
+		Path:     "/home/user/work/link",
+		Resolved: "/mnt/ext",
+		Rules: []security.RuleResult{
+			{Dir: "/home/user/work", Resolved: "/home/user/work", Status: security.RuleNotMatched},
+			{Dir: "/mnt", Resolved: "/mnt", Status: security.RuleMatched},
+			{Dir: "/nfs", Status: security.RuleUnresolvable, Err: errors.New("stale handle")},
+		},
+	}
+	yolo := &security.Explanation{Path: e.Path, Resolved: e.Resolved}
+
+	out := newExplainJSON(e, yolo, "CLAUDE_SAFE_DIRS")
+
+	if !out.Allowed || out.MatchedBy != "/mnt" {
+		t.Errorf("Allowed, MatchedBy = %v, %q; expected true, /mnt", out.Allowed, out.MatchedBy)
+	}
+	if len(out.Rules) != 3 || out.Rules[2].Error != "stale handle" || out.Rules[0].Status != "not matched" {
+		t.Errorf("Rules = %+v", out.Rules)
+	}
+	if out.SkipPermissions == nil || out.SkipPermissions.Allowed || out.SkipPermissions.Rules == nil {
+		t.Errorf("SkipPermissions = %+v, expected a denial with an empty rule list", out.SkipPermissions)
+	}
+
+	if out := newExplainJSON(e, nil, "config.json"); out.SkipPermissions != nil {
+		t.Errorf("SkipPermissions = %+v, expected nil without yoloAllowedDirs", out.SkipPermissions)
+	}
+}
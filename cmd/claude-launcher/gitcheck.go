@@ -0,0 +1,124 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// Choices offered when the working tree of the launch directory is not clean
+const (
+	gitCheckContinue = "continue"
+	gitCheckStash    = "stash"
+	gitCheckAbort    = "abort"
+)
+
+// gitCheckChoiceLabels are the menu entries of the choices
+var gitCheckChoiceLabels = map[string]string{
+	gitCheckContinue: "Continue anyway",
+	gitCheckStash:    "Stash the changes and continue",
+	gitCheckAbort:    "Abort",
+}
+
+// gitOperations are the files a git directory holds while an operation is unfinished, with the
+// name of the operation, in the order they are looked for
+var gitOperations = []struct{ file, name string }{
+	{"rebase-merge", "rebase"},
+	{"rebase-apply", "rebase"},
+	{"MERGE_HEAD", "merge"},
+	{"CHERRY_PICK_HEAD", "cherry-pick"},
+	{"REVERT_HEAD", "revert"},
+	{"BISECT_LOG", "bisect"},
+}
+
+// checkWorkTree applies the git working-tree check (gitCheck) to dir before it is handed to an
+// agent. With "warn" an unclean tree is reported and the user chooses to continue, stash the
+// changes or abort; with "block" the launch is refused unless stashing the changes makes the
+// tree clean. Without prompts a warning lets the launch go on and a block refuses it.
+func checkWorkTree(dir, mode string, noPrompts bool, printer *ui.Printer) int {
+	if mode == config.GitCheckOff {
+		return exitSuccess
+	}
+	tree := inspectWorkTree(dir)
+	if tree == nil || tree.Clean() {
+		return exitSuccess
+	}
+
+	blocked := mode == config.GitCheckBlock
+	printer.ShowWorkTree(*tree, blocked)
+	choices := gitCheckChoices(*tree, blocked)
+	switch {
+	case blocked && (noPrompts || !slices.Contains(choices, gitCheckStash)):
+		return exitDenied
+	case noPrompts:
+		return exitSuccess
+	}
+
+	labels := make([]string, len(choices))
+	for i, choice := range choices {
+		labels[i] = i18n.T(gitCheckChoiceLabels[choice])
+	}
+	idx, err := ui.Select(i18n.T("What do you want to do?"), labels)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return promptExitCode(err)
+	}
+
+	switch choices[idx] {
+	case gitCheckStash:
+		if _, err := gitOutput(dir, "stash", "push", "--include-untracked", "--message", "claude-launcher: before launch"); err != nil {
+			printer.Error("Failed to stash the changes: %v\n", err)
+			return exitError
+		}
+		printer.Success("✓ Changes stashed ('git stash pop' brings them back)\n")
+	case gitCheckAbort:
+		return exitAborted
+	}
+	return exitSuccess
+}
+
+// gitCheckChoices returns the choices for an unclean tree. Stashing is offered when there are
+// changes and no unfinished operation; when blocked, only if it leaves the tree clean.
+func gitCheckChoices(tree ui.WorkTree, blocked bool) []string {
+	var choices []string
+	if !blocked {
+		choices = append(choices, gitCheckContinue)
+	}
+	if tree.Changes > 0 && tree.Operation == "" && (!blocked || !tree.Detached) {
+		choices = append(choices, gitCheckStash)
+	}
+	return append(choices, gitCheckAbort)
+}
+
+// inspectWorkTree returns the state of the working tree containing dir, or nil outside git
+// repositories
+func inspectWorkTree(dir string) *ui.WorkTree {
+	root, err := gitOutput(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil
+	}
+	gitDir, err := gitOutput(dir, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil
+	}
+	status, err := gitOutput(dir, "status", "--porcelain")
+	if err != nil {
+		return nil
+	}
+
+	tree := &ui.WorkTree{Root: root, Changes: len(outputLines(status))}
+	// symbolic-ref fails when HEAD points at a commit rather than a branch
+	_, err = gitOutput(dir, "symbolic-ref", "--quiet", "HEAD")
+	tree.Detached = err != nil
+	for _, op := range gitOperations {
+		if _, err := os.Stat(filepath.Join(gitDir, op.file)); err == nil {
+			tree.Operation = op.name
+			break
+		}
+	}
+	return tree
+}
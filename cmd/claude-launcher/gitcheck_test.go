@@ -0,0 +1,84 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+func TestInspectWorkTree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	write := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(name), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	printer := ui.NewPrinter(io.Discard)
+
+	if inspectWorkTree(repo) != nil {
+		t.Error("inspectWorkTree() outside a repository should be nil")
+	}
+	git("init", "-q")
+	write("main.go")
+	git("add", "main.go")
+	git("commit", "-q", "-m", "init")
+	if tree := inspectWorkTree(repo); tree == nil || !tree.Clean() {
+		t.Fatalf("inspectWorkTree() = %+v, want a clean tree", tree)
+	}
+
+	write("main.go.orig")
+	tree := inspectWorkTree(repo)
+	if tree == nil || tree.Changes != 1 || tree.Detached || tree.Operation != "" {
+		t.Fatalf("inspectWorkTree() = %+v, want 1 change", tree)
+	}
+	if code := checkWorkTree(repo, config.GitCheckWarn, true, printer); code != exitSuccess {
+		t.Errorf("checkWorkTree(warn) without prompts = %d, want %d", code, exitSuccess)
+	}
+	if code := checkWorkTree(repo, config.GitCheckBlock, true, printer); code != exitDenied {
+		t.Errorf("checkWorkTree(block) without prompts = %d, want %d", code, exitDenied)
+	}
+
+	git("checkout", "-q", "--detach")
+	write(filepath.Join(".git", "MERGE_HEAD"))
+	tree = inspectWorkTree(repo)
+	if tree == nil || !tree.Detached || tree.Operation != "merge" {
+		t.Errorf("inspectWorkTree() = %+v, want a detached HEAD and a merge", tree)
+	}
+}
+
+func TestGitCheckChoices(t *testing.T) {
+	tests := []struct {
+		name    string
+		tree    ui.WorkTree
+		blocked bool
+		want    []string
+	}{
+		{"changes", ui.WorkTree{Changes: 2}, false, []string{gitCheckContinue, gitCheckStash, gitCheckAbort}},
+		{"changes blocked", ui.WorkTree{Changes: 2}, true, []string{gitCheckStash, gitCheckAbort}},
+		{"detached", ui.WorkTree{Detached: true}, false, []string{gitCheckContinue, gitCheckAbort}},
+		{"detached with changes blocked", ui.WorkTree{Changes: 2, Detached: true}, true, []string{gitCheckAbort}},
+		{"rebase with changes", ui.WorkTree{Changes: 2, Detached: true, Operation: "rebase"}, false, []string{gitCheckContinue, gitCheckAbort}},
+	}
+	for _, tt := range tests {
+		if got := gitCheckChoices(tt.tree, tt.blocked); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: gitCheckChoices() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/security"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// Choices offered when a session is already running in the launch directory
+const (
+	guardAttach = "attach"
+	guardLaunch = "launch"
+	guardAbort  = "abort"
+)
+
+// guardChoiceLabels are the menu entries of the choices
+var guardChoiceLabels = map[string]string{
+	guardAttach: "Attach to the running session",
+	guardLaunch: "Launch another session anyway",
+	guardAbort:  "Abort",
+}
+
+// guardDoubleLaunch warns when a session started by the launcher is still running in dir (or in
+// a directory containing it or inside it), as two agents editing the same files conflict, and
+// asks whether to attach to it (detached sessions only), launch anyway or abort.
+// done reports that the launch ends here (attached, aborted or failed) with code.
+// Without prompts the warning is printed and the launch goes on.
+func guardDoubleLaunch(dir string, noPrompts bool, printer *ui.Printer) (code int, done bool) {
+	store, err := state.NewStore()
+	if err != nil {
+		log.Debug("double-launch check skipped", "error", err)
+		return exitSuccess, false
+	}
+	instances, err := store.PruneInstances()
+	if err != nil {
+		log.Debug("double-launch check skipped", "error", err)
+		return exitSuccess, false
+	}
+	inst := runningSession(instances, dir)
+	if inst == nil {
+		return exitSuccess, false
+	}
+
+	printer.Warning("⚠ A session is already running in %s (PID %d, started %s)\n", inst.Dir, inst.PID, ui.FormatAge(time.Since(inst.StartedAt)))
+	if noPrompts {
+		return exitSuccess, false
+	}
+
+	choices := guardChoices(inst)
+	labels := make([]string, len(choices))
+	for i, choice := range choices {
+		labels[i] = i18n.T(guardChoiceLabels[choice])
+	}
+	idx, err := ui.Select(i18n.T("What do you want to do?"), labels)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return promptExitCode(err), true
+	}
+
+	switch choices[idx] {
+	case guardAttach:
+		return runAttach([]string{inst.ID}), true
+	case guardAbort:
+		return exitAborted, true
+	}
+	return exitSuccess, false
+}
+
+// guardChoices returns the choices for a launch next to inst: only detached sessions can be attached to
+func guardChoices(inst *state.Instance) []string {
+	if inst.Socket != "" {
+		return []string{guardAttach, guardLaunch, guardAbort}
+	}
+	return []string{guardLaunch, guardAbort}
+}
+
+// runningSession returns the earliest of instances running in dir, in a directory containing
+// it or in one inside it, or nil
+func runningSession(instances []state.Instance, dir string) *state.Instance {
+	for i := range instances {
+		if security.IsWithin(dir, instances[i].Dir) || security.IsWithin(instances[i].Dir, dir) {
+			return &instances[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+func TestRunningSession(t *testing.T) {
+	instances := []state.Instance{
+		{ID: "aaaa", Dir: "/work/web"},
+		{ID: "bbbb", Dir: "/work/api"},
+		{ID: "cccc", Dir: "/work/api/src"},
+	}
+
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"/work/api", "bbbb"},
+		{"/work/api/docs", "bbbb"},
+		{"/work", "aaaa"},
+		{"/work/api-v2", ""},
+		{"/tmp", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if inst := runningSession(instances, tt.dir); inst != nil {
+			got = inst.ID
+		}
+		if got != tt.want {
+			t.Errorf("runningSession(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestGuardChoices(t *testing.T) {
+	if got := guardChoices(&state.Instance{Socket: "/tmp/a.sock"}); !slices.Equal(got, []string{guardAttach, guardLaunch, guardAbort}) {
+		t.Errorf("guardChoices(detached) = %v", got)
+	}
+	if got := guardChoices(&state.Instance{}); !slices.Equal(got, []string{guardLaunch, guardAbort}) {
+		t.Errorf("guardChoices(foreground) = %v, foreground sessions cannot be attached to", got)
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// historyCommands maps the `history` subcommands to their entry points
+var historyCommands = map[string]func(args []string) int{
+	"export": runHistoryExport,
+}
+
+// runHistory implements `claude-launcher history <COMMAND>`
+func runHistory(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	commands := strings.Join(slices.Sorted(maps.Keys(historyCommands)), ", ")
+	if len(args) == 0 {
+		printer.Error("Usage: claude-launcher history <COMMAND> (available: %s)\n", commands)
+		return exitError
+	}
+	cmd, ok := historyCommands[args[0]]
+	if !ok {
+		printer.Error("Unknown history command %q (available: %s)\n", args[0], commands)
+		return exitError
+	}
+	return cmd(args[1:])
+}
+
+// Export formats
+const (
+	exportCSV  = "csv"
+	exportJSON = "json"
+)
+
+// historyExportFlags holds the options of `history export`
+type historyExportFlags struct {
+	format, since string
+	audit         bool
+}
+
+// newHistoryExportFlags defines the options of `history export`
+func newHistoryExportFlags() (*flag.FlagSet, *historyExportFlags) {
+	fs := flag.NewFlagSet("history export", flag.ContinueOnError)
+	f := &historyExportFlags{}
+	fs.StringVar(&f.format, "format", exportCSV, "Output `FORMAT`: csv (with a header row) or json (one object per line)")
+	fs.StringVar(&f.since, "since", "", "Only export records since `WHEN`: a duration such as 30d, 2w or 12h, or a date (2026-01-02)")
+	fs.BoolVar(&f.audit, "audit", false, "Export the audit log (uses of --dangerously-skip-permissions) instead of the launches")
+	return fs, f
+}
+
+// exportRecord is one launch in `history export`
+type exportRecord struct {
+	ID              string     `json:"id"`
+	Mode            string     `json:"mode"`
+	Project         string     `json:"project"`
+	Dir             string     `json:"dir"`
+	Account         string     `json:"account"`
+	Preset          string     `json:"preset"`
+	Model           string     `json:"model"`
+	Rule            string     `json:"rule"` // The allowed directory that matched
+	StartedAt       time.Time  `json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt"`         // Null when the end was not observed
+	DurationSeconds *float64   `json:"durationSeconds"` // Null when the end was not observed
+	ExitCode        *int       `json:"exitCode"`
+	Error           string     `json:"error"`
+}
+
+// exportColumns are the CSV columns of exportRecord, named after its JSON fields
+var exportColumns = []string{"id", "mode", "project", "dir", "account", "preset", "model", "rule",
+	"startedAt", "endedAt", "durationSeconds", "exitCode", "error"}
+
+// newExportRecord converts a launch record, counting it under its project.
+// Times and durations are kept to the second, so both formats carry the same values.
+func newExportRecord(cfg *config.Config, rec launcher.LaunchRecord) exportRecord {
+	r := exportRecord{
+		ID:        rec.ID,
+		Mode:      rec.Mode,
+		Project:   projectKey(cfg, rec.Dir),
+		Dir:       rec.Dir,
+		Account:   rec.Account,
+		Preset:    rec.Preset,
+		Model:     rec.Model,
+		Rule:      rec.Rule,
+		StartedAt: rec.StartedAt.Truncate(time.Second),
+		ExitCode:  rec.ExitCode,
+		Error:     rec.Error,
+	}
+	if !rec.EndedAt.IsZero() {
+		endedAt, seconds := rec.EndedAt.Truncate(time.Second), math.Round(rec.Duration().Seconds())
+		r.EndedAt, r.DurationSeconds = &endedAt, &seconds
+	}
+	return r
+}
+
+// row returns the CSV fields of r, in the order of exportColumns
+func (r exportRecord) row() []string {
+	var endedAt, duration, exitCode string
+	if r.EndedAt != nil {
+		endedAt = r.EndedAt.Format(time.RFC3339)
+		duration = strconv.FormatFloat(*r.DurationSeconds, 'f', 0, 64)
+	}
+	if r.ExitCode != nil {
+		exitCode = strconv.Itoa(*r.ExitCode)
+	}
+	return []string{r.ID, r.Mode, r.Project, r.Dir, r.Account, r.Preset, r.Model, r.Rule,
+		r.StartedAt.Format(time.RFC3339), endedAt, duration, exitCode, r.Error}
+}
+
+// auditColumns are the CSV columns of an audit entry, named after its JSON fields
+var auditColumns = []string{"time", "event", "dir", "account", "args"}
+
+// auditRow returns the CSV fields of e, in the order of auditColumns; arguments are space-separated
+func auditRow(e state.AuditEntry) []string {
+	return []string{e.Time.Format(time.RFC3339), e.Event, e.Dir, e.Account, strings.Join(e.Args, " ")}
+}
+
+// runHistoryExport implements `claude-launcher history export`: the launch history (or the
+// audit log) as CSV or JSON lines on stdout, for spreadsheets and log pipelines
+func runHistoryExport(args []string) int {
+	fs, f := newHistoryExportFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if f.format != exportCSV && f.format != exportJSON {
+		printer.Error("Unknown format %q (available: %s, %s)\n", f.format, exportCSV, exportJSON)
+		return exitError
+	}
+	since, err := parseSince(f.since, time.Now())
+	if err != nil {
+		printer.Error("%v\n", err)
+		return exitError
+	}
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+	store, err := state.NewStore()
+	if err != nil {
+		printer.Error("Failed to open state directory: %v\n", err)
+		return exitError
+	}
+
+	if f.audit {
+		entries, err := store.ReadAudit()
+		if err != nil {
+			printer.Error("Failed to read audit log: %v\n", err)
+			return exitError
+		}
+		entries = slices.DeleteFunc(entries, func(e state.AuditEntry) bool { return e.Time.Before(since) })
+		err = writeExport(os.Stdout, f.format, auditColumns, entries, auditRow)
+		return exportExitCode(printer, err)
+	}
+
+	records, err := launcher.History(store)
+	if err != nil {
+		printer.Error("Failed to read launch history: %v\n", err)
+		return exitError
+	}
+	var exported []exportRecord
+	for _, rec := range records {
+		if !rec.StartedAt.Before(since) {
+			exported = append(exported, newExportRecord(cfg, rec))
+		}
+	}
+	err = writeExport(os.Stdout, f.format, exportColumns, exported, exportRecord.row)
+	return exportExitCode(printer, err)
+}
+
+// writeExport writes items to w as CSV (columns, then one row per item) or as JSON lines
+func writeExport[T any](w io.Writer, format string, columns []string, items []T, row func(T) []string) error {
+	if format == exportJSON {
+		enc := json.NewEncoder(w)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := cw.Write(row(item)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportExitCode reports a failed write
+func exportExitCode(printer *ui.Printer, err error) int {
+	if err != nil {
+		printer.Error("Failed to write export: %v\n", err)
+		return exitError
+	}
+	return exitSuccess
+}
+
+// parseSince parses --since: a duration back from now (Go durations, plus d for days and w for
+// weeks), a date (local midnight) or an RFC 3339 time. Empty means the beginning.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				break
+			}
+			return now.Add(-time.Duration(count) * unit), nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid --since %q: expected a duration such as 30d, 2w or 12h, or a date such as 2026-01-02", value)
+	}
+	return now.Add(-d), nil
+}
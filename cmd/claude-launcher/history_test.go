@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"", time.Time{}},
+		{"30d", now.AddDate(0, 0, -30)},
+		{"2w", now.AddDate(0, 0, -14)},
+		{"12h", now.Add(-12 * time.Hour)},
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)},
+		{"2026-03-01T08:00:00Z", time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if err != nil {
+			t.Errorf("parseSince(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("parseSince(%q) = %v, expected %v", tt.value, got, tt.expected)
+		}
+	}
+
+	for _, value := range []string{"yesterday", "-3d", "xd"} {
+		if _, err := parseSince(value, now); err == nil {
+			t.Errorf("parseSince(%q) should fail", value)
+		}
+	}
+}
+
+func TestWriteExport(t *testing.T) {
+	cfg := &config.Config{Projects: []config.Project{{Path: "/work/api"}}}
+	started := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	code := 1
+	records := []exportRecord{
+		newExportRecord(cfg, launcher.LaunchRecord{
+			ID: "a1", Mode: launcher.ModeForeground, Dir: "/work/api/cmd", Account: "Work", Rule: "/work",
+			StartedAt: started, EndedAt: started.Add(90 * time.Second), ExitCode: &code,
+		}),
+		newExportRecord(cfg, launcher.LaunchRecord{ID: "b2", Mode: launcher.ModeTmux, Dir: "/home/me, blog", StartedAt: started}),
+	}
+
+	var buf bytes.Buffer
+	if err := writeExport(&buf, exportCSV, exportColumns, records, exportRecord.row); err != nil {
+		t.Fatalf("writeExport() error = %v", err)
+	}
+	expected := "id,mode,project,dir,account,preset,model,rule,startedAt,endedAt,durationSeconds,exitCode,error\n" +
+		"a1,foreground,/work/api,/work/api/cmd,Work,,,/work,2026-03-01T09:00:00Z,2026-03-01T09:01:30Z,90,1,\n" +
+		"b2,tmux,\"/home/me, blog\",\"/home/me, blog\",,,,,2026-03-01T09:00:00Z,,,,\n"
+	if buf.String() != expected {
+		t.Errorf("CSV =\n%s\nexpected\n%s", buf.String(), expected)
+	}
+
+	buf.Reset()
+	if err := writeExport(&buf, exportJSON, exportColumns, records, exportRecord.row); err != nil {
+		t.Fatalf("writeExport() error = %v", err)
+	}
+	expected = `{"id":"a1","mode":"foreground","project":"/work/api","dir":"/work/api/cmd","account":"Work","preset":"","model":"","rule":"/work","startedAt":"2026-03-01T09:00:00Z","endedAt":"2026-03-01T09:01:30Z","durationSeconds":90,"exitCode":1,"error":""}` + "\n" +
+		`{"id":"b2","mode":"tmux","project":"/home/me, blog","dir":"/home/me, blog","account":"","preset":"","model":"","rule":"","startedAt":"2026-03-01T09:00:00Z","endedAt":null,"durationSeconds":null,"exitCode":null,"error":""}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("JSON =\n%s\nexpected\n%s", buf.String(), expected)
+	}
+}
@@ -0,0 +1,572 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/23prime/claude-launcher/internal/account"
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/home"
+	"github.com/23prime/claude-launcher/internal/security"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// integrations maps `integrate` targets to their entry points
+var integrations = map[string]func(args []string) int{
+	"direnv":    integrateDirenv,
+	"git-hooks": integrateGitHooks,
+	"systemd":   integrateSystemd,
+}
+
+// runIntegrate implements `claude-launcher integrate <TARGET>`: it writes the files that
+// hook the launcher into other tools
+func runIntegrate(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	targets := strings.Join(slices.Sorted(maps.Keys(integrations)), ", ")
+	if len(args) == 0 {
+		printer.Error("Usage: claude-launcher integrate <TARGET> (available: %s)\n", targets)
+		return exitError
+	}
+	integrate, ok := integrations[args[0]]
+	if !ok {
+		printer.Error("Unknown integration %q (available: %s)\n", args[0], targets)
+		return exitError
+	}
+	return integrate(args[1:])
+}
+
+// Markers around the part of a file managed by the launcher. Everything outside is left alone.
+const (
+	managedBegin = "# >>> claude-launcher >>>"
+	managedEnd   = "# <<< claude-launcher <<<"
+)
+
+// replaceManagedBlock returns content with its managed block replaced by block,
+// or with block appended when there is none
+func replaceManagedBlock(content, block string) string {
+	start := strings.Index(content, managedBegin)
+	end := strings.Index(content, managedEnd)
+	if start >= 0 && end > start {
+		end += len(managedEnd)
+		if end < len(content) && content[end] == '\n' {
+			end++
+		}
+		return content[:start] + block + content[end:]
+	}
+
+	switch {
+	case content == "":
+	case strings.HasSuffix(content, "\n\n"):
+	case strings.HasSuffix(content, "\n"):
+		content += "\n"
+	default:
+		content += "\n\n"
+	}
+	return content + block
+}
+
+// writeManagedBlock creates path or updates the managed block in it, keeping the file's mode
+func writeManagedBlock(path, block string, mode os.FileMode) error {
+	// #nosec G304 -- path is the integration file chosen by the user
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	// #nosec G306 -- integration files (.envrc, hooks) hold commands, not secrets
+	return os.WriteFile(path, []byte(replaceManagedBlock(string(data), block)), mode)
+}
+
+// direnvLib is the global direnv function written by `integrate direnv --lib`
+const direnvLib = `# direnv library for claude-launcher, written by 'claude-launcher integrate direnv --lib'
+# Usage in .envrc: use claude_launcher [ENV_OPTIONS...], e.g. use claude_launcher -a Work
+use_claude_launcher() {
+  watch_file "$(claude-launcher config path)"
+  eval "$(claude-launcher env --shell sh "$@")"
+}
+`
+
+// direnvFlags holds the options of `integrate direnv`
+type direnvFlags struct {
+	accountName, profile, dir string
+	lib, printOnly            bool
+}
+
+// newDirenvFlags defines the options of `integrate direnv`
+func newDirenvFlags() (*flag.FlagSet, *direnvFlags) {
+	fs := flag.NewFlagSet("integrate direnv", flag.ContinueOnError)
+	f := &direnvFlags{}
+	fs.StringVar(&f.accountName, "a", "", "Account `NAME` whose launch environment is exported")
+	fs.StringVar(&f.accountName, "account", "", "Account name (long form)")
+	fs.StringVar(&f.profile, "profile", "", "Profile (`NAME`) applied to launches in the directory")
+	fs.StringVar(&f.dir, "d", "", "Directory (`DIR`) of the .envrc (defaults to the current directory)")
+	fs.StringVar(&f.dir, "dir", "", "Directory of the .envrc (long form)")
+	fs.BoolVar(&f.lib, "lib", false, "Install the global use_claude_launcher function instead")
+	fs.BoolVar(&f.printOnly, "print", false, "Print the file contents instead of writing them")
+	return fs, f
+}
+
+// integrateDirenv implements `claude-launcher integrate direnv`
+func integrateDirenv(args []string) int {
+	fs, f := newDirenvFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if f.lib {
+		return installDirenvLib(f.printOnly, printer)
+	}
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	targetDir, err := resolveTargetDir(f.dir)
+	if err != nil {
+		printer.Error("Failed to resolve target directory: %v\n", err)
+		return exitError
+	}
+
+	if f.profile != "" {
+		p, ok := cfg.Profiles[f.profile]
+		if !ok {
+			printer.Error("✗ Profile '%s' not found\n", f.profile)
+			printer.ShowProfiles(cfg.Profiles)
+			return exitError
+		}
+		if f.accountName == "" {
+			f.accountName = p.Account
+		}
+	}
+	// Check now what `env` would fail on every time the directory is entered
+	if _, err := account.SelectAccountNonInteractively(f.accountName); err != nil {
+		printer.Error("Failed to select account: %v\n", err)
+		return exitError
+	}
+
+	configPath, err := config.DefaultConfigPath()
+	if err != nil {
+		printer.Error("Error: %v\n", err)
+		return exitError
+	}
+
+	block := direnvBlock(f.accountName, f.profile, configPath)
+	if f.printOnly {
+		fmt.Print(block)
+		return exitSuccess
+	}
+
+	path := filepath.Join(targetDir, ".envrc")
+	if err := writeManagedBlock(path, block, 0o644); err != nil {
+		printer.Error("Failed to write %s: %v\n", path, err)
+		return exitError
+	}
+	printer.Success("✓ Wrote %s\n", path)
+	printer.Print("Run 'direnv allow %s' to load it.\n", targetDir)
+	return exitSuccess
+}
+
+// direnvBlock returns the managed .envrc block: the launch environment of the account and,
+// when set, the profile applied to launches. direnv reloads it when configPath changes.
+func direnvBlock(accountName, profile, configPath string) string {
+	command := "claude-launcher env --shell sh"
+	if accountName != "" {
+		command += " -a " + shQuote(accountName)
+	}
+
+	var b strings.Builder
+	b.WriteString(managedBegin + "\n")
+	b.WriteString("# Written by 'claude-launcher integrate direnv'; changes inside this block are overwritten\n")
+	fmt.Fprintf(&b, "watch_file %s\n", shQuote(configPath))
+	fmt.Fprintf(&b, "eval \"$(%s)\"\n", command)
+	if profile != "" {
+		fmt.Fprintf(&b, "export %s=%s\n", profileEnvVar, shQuote(profile))
+	}
+	b.WriteString(managedEnd + "\n")
+	return b.String()
+}
+
+// installDirenvLib writes the global use_claude_launcher function into direnv's lib directory
+func installDirenvLib(printOnly bool, printer *ui.Printer) int {
+	if printOnly {
+		fmt.Print(direnvLib)
+		return exitSuccess
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := home.Dir()
+		if err != nil {
+			printer.Error("Error: %v\n", err)
+			return exitError
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+
+	path := filepath.Join(configHome, "direnv", "lib", "claude-launcher.sh")
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		printer.Error("Failed to write %s: %v\n", path, err)
+		return exitError
+	}
+	if err := os.WriteFile(path, []byte(direnvLib), 0o644); err != nil { // #nosec G306 -- direnv libraries are not secret
+		printer.Error("Failed to write %s: %v\n", path, err)
+		return exitError
+	}
+	printer.Success("✓ Wrote %s\n", path)
+	printer.Print("Add 'use claude_launcher' to an .envrc to use it.\n")
+	return exitSuccess
+}
+
+// gitHookChecks are the hook scripts written by `integrate git-hooks`. Each runs the
+// directory check on the repository (explain exits with 3 when it is outside allowedDirs)
+// and does nothing when the launcher is not on PATH or cannot load its configuration.
+var gitHookChecks = map[string]string{
+	// Refuses commits in a repository where launches would be refused
+	"pre-commit": `if command -v claude-launcher >/dev/null 2>&1; then
+  claude_launcher_out=$(claude-launcher explain "$(git rev-parse --show-toplevel)" 2>&1)
+  if [ $? -eq 3 ]; then
+    printf '%s\n' "$claude_launcher_out" >&2
+    echo "claude-launcher: refusing to commit outside the allowed directories" >&2
+    exit 1
+  fi
+fi
+`,
+	// Only warns: the checkout has already happened
+	"post-checkout": `if command -v claude-launcher >/dev/null 2>&1; then
+  claude-launcher explain "$(git rev-parse --show-toplevel)" >/dev/null 2>&1
+  if [ $? -eq 3 ]; then
+    echo "claude-launcher: this checkout is outside the allowed directories; launches here will be refused" >&2
+  fi
+fi
+`,
+}
+
+// integrateGitHooks implements `claude-launcher integrate git-hooks`
+func integrateGitHooks(args []string) int {
+	fs, f := newGitHooksFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	names := slices.Sorted(maps.Keys(gitHookChecks))
+	if f.printOnly {
+		for _, name := range names {
+			fmt.Printf("# %s\n%s", name, gitHookBlock(name))
+		}
+		return exitSuccess
+	}
+
+	targetDir, err := resolveTargetDir(f.dir)
+	if err != nil {
+		printer.Error("Failed to resolve target directory: %v\n", err)
+		return exitError
+	}
+	hooksDir, err := gitHooksDir(targetDir)
+	if err != nil {
+		printer.Error("Failed to find the git hooks directory: %v\n", err)
+		return exitError
+	}
+
+	for _, name := range names {
+		path := filepath.Join(hooksDir, name)
+		if err := writeGitHook(path, gitHookBlock(name)); err != nil {
+			printer.Error("Failed to write %s: %v\n", path, err)
+			return exitError
+		}
+		printer.Success("✓ Wrote %s\n", path)
+	}
+	return exitSuccess
+}
+
+// gitHooksFlags holds the options of `integrate git-hooks`
+type gitHooksFlags struct {
+	dir       string
+	printOnly bool
+}
+
+// newGitHooksFlags defines the options of `integrate git-hooks`
+func newGitHooksFlags() (*flag.FlagSet, *gitHooksFlags) {
+	fs := flag.NewFlagSet("integrate git-hooks", flag.ContinueOnError)
+	f := &gitHooksFlags{}
+	fs.StringVar(&f.dir, "d", "", "Directory (`DIR`) inside the repository (defaults to the current directory)")
+	fs.StringVar(&f.dir, "dir", "", "Directory inside the repository (long form)")
+	fs.BoolVar(&f.printOnly, "print", false, "Print the hook contents instead of writing them")
+	return fs, f
+}
+
+// gitHookBlock returns the managed block of the named hook
+func gitHookBlock(name string) string {
+	return managedBegin + "\n" +
+		"# Written by 'claude-launcher integrate git-hooks'; changes inside this block are overwritten\n" +
+		gitHookChecks[name] +
+		managedEnd + "\n"
+}
+
+// gitHooksDir returns the hooks directory of the repository containing dir, honoring
+// core.hooksPath and linked worktrees
+func gitHooksDir(dir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", "hooks")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+
+	hooksDir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(dir, hooksDir)
+	}
+	return hooksDir, nil
+}
+
+// writeGitHook writes block into the hook at path, creating an executable shell script when
+// there is no hook yet
+func writeGitHook(path, block string) error {
+	mode := os.FileMode(0o755)
+	// #nosec G304 -- path is a hook in the repository chosen by the user
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		data = []byte("#!/bin/sh\n")
+	case err != nil:
+		return err
+	default:
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	content, err := insertGitHookBlock(string(data), block)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	// #nosec G306 -- git only runs executable hooks
+	return os.WriteFile(path, []byte(content), mode)
+}
+
+// gitHookShells are the interpreters whose hooks can run the shell check
+var gitHookShells = []string{"sh", "bash", "dash", "ksh", "zsh"}
+
+// insertGitHookBlock returns the hook content with its managed block replaced by block, or with
+// block inserted right after the shebang, so that an exit or exec in the existing hook cannot
+// skip the check. Hooks in other languages are refused.
+func insertGitHookBlock(content, block string) (string, error) {
+	if strings.Contains(content, managedBegin) {
+		return replaceManagedBlock(content, block), nil
+	}
+
+	shebang, rest := "", content
+	if strings.HasPrefix(content, "#!") {
+		line, after, _ := strings.Cut(content, "\n")
+		shebang, rest = line+"\n", after
+		fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+		interpreter := ""
+		if len(fields) > 0 {
+			interpreter = filepath.Base(fields[0])
+		}
+		if interpreter == "env" && len(fields) > 1 {
+			interpreter = fields[1]
+		}
+		if !slices.Contains(gitHookShells, interpreter) {
+			return "", fmt.Errorf("the hook is not a shell script (%s); add the check from --print by hand", line)
+		}
+	}
+
+	if rest == "" {
+		return shebang + "\n" + block, nil
+	}
+	return shebang + "\n" + block + "\n" + rest, nil
+}
+
+// systemdFlags holds the options of `integrate systemd`
+type systemdFlags struct {
+	accountName, profile, dir string
+	printOnly                 bool
+}
+
+// newSystemdFlags defines the options of `integrate systemd`
+func newSystemdFlags() (*flag.FlagSet, *systemdFlags) {
+	fs := flag.NewFlagSet("integrate systemd", flag.ContinueOnError)
+	f := &systemdFlags{}
+	fs.StringVar(&f.accountName, "a", "", "Account `NAME` the session runs under")
+	fs.StringVar(&f.accountName, "account", "", "Account name (long form)")
+	fs.StringVar(&f.profile, "profile", "", "Profile (`NAME`) applied to the session")
+	fs.StringVar(&f.dir, "d", "", "Directory (`DIR`) of the session (defaults to the current directory)")
+	fs.StringVar(&f.dir, "dir", "", "Directory of the session (long form)")
+	fs.BoolVar(&f.printOnly, "print", false, "Print the unit instead of writing it")
+	return fs, f
+}
+
+// systemdEnv lists the variables copied into the unit: the user manager does not see the
+// login shell's environment, and without them the session could not find claude, the
+// configuration or the state directory `attach` looks in
+var systemdEnv = []string{"PATH", "CLAUDE_LAUNCHER_HOME", "CLAUDE_SAFE_DIRS", "XDG_CONFIG_HOME", "XDG_STATE_HOME"}
+
+// integrateSystemd implements `claude-launcher integrate systemd`: it installs a user unit that
+// starts a detached session in the directory at login
+func integrateSystemd(args []string) int {
+	fs, f := newSystemdFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if runtime.GOOS != "linux" {
+		printer.Error("✗ integrate systemd needs Linux, the only platform with detached sessions\n")
+		return exitError
+	}
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	targetDir, err := resolveTargetDir(f.dir)
+	if err != nil {
+		printer.Error("Failed to resolve target directory: %v\n", err)
+		return exitError
+	}
+	// Nobody is there to answer at login, so check now what the launch would stop on
+	if allowed, _ := security.NewDirectoryChecker(cfg.AllowedDirs).IsAllowed(targetDir); !allowed { //nolint:errcheck // unresolvable directories are not allowed
+		printer.Error("✗ %s is not an allowed directory: add it to allowedDirs first\n", targetDir)
+		return exitDenied
+	}
+	if f.profile != "" {
+		p, ok := cfg.Profiles[f.profile]
+		if !ok {
+			printer.Error("✗ Profile '%s' not found\n", f.profile)
+			printer.ShowProfiles(cfg.Profiles)
+			return exitError
+		}
+		if f.accountName == "" {
+			f.accountName = p.Account
+		}
+	}
+	if _, err := account.SelectAccountNonInteractively(f.accountName); err != nil {
+		printer.Error("Failed to select account: %v\n", err)
+		return exitError
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		printer.Error("Failed to locate launcher executable: %v\n", err)
+		return exitError
+	}
+
+	unit := systemdUnit(self, targetDir, f.accountName, f.profile, os.Getenv)
+	if f.printOnly {
+		fmt.Print(unit)
+		return exitSuccess
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := home.Dir()
+		if err != nil {
+			printer.Error("Error: %v\n", err)
+			return exitError
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+
+	name := systemdUnitName(targetDir)
+	path := filepath.Join(configHome, "systemd", "user", name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		printer.Error("Failed to write %s: %v\n", path, err)
+		return exitError
+	}
+	if err := os.WriteFile(path, []byte(unit), 0o644); err != nil { // #nosec G306 -- systemd units are not secret
+		printer.Error("Failed to write %s: %v\n", path, err)
+		return exitError
+	}
+	printer.Success("✓ Wrote %s\n", path)
+	printer.Print("Run 'systemctl --user daemon-reload && systemctl --user enable --now %s' to start it at login.\n", name)
+	return exitSuccess
+}
+
+// systemdUnit returns a user unit that starts a detached session in dir. Nobody answers prompts
+// at login, so the launch is confirmed and refuses whatever would ask. The unit stays active
+// until it is stopped, even after the session ends; stopping it ends a running session.
+func systemdUnit(self, dir, accountName, profile string, getenv func(string) string) string {
+	command := []string{self, "--detach", "--new", "--no-prompts", "--approve-dir", "--dir", dir}
+	if accountName != "" {
+		command = append(command, "--account", accountName)
+	}
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		// ExecStart also expands $VARIABLES, unlike Environment
+		quoted[i] = systemdQuote(strings.ReplaceAll(arg, "$", "$$"))
+	}
+
+	var b strings.Builder
+	b.WriteString("# Written by 'claude-launcher integrate systemd'; running the command again overwrites it\n")
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=Claude session in %s (claude-launcher)\n", strings.ReplaceAll(dir, "%", "%%"))
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=oneshot\n")
+	b.WriteString("RemainAfterExit=yes\n")
+	for _, name := range systemdEnv {
+		if value := getenv(name); value != "" {
+			fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(name+"="+value))
+		}
+	}
+	if profile != "" {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(profileEnvVar+"="+profile))
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(quoted, " "))
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=default.target\n")
+	return b.String()
+}
+
+// systemdQuote quotes s as one word of a unit setting, escaping specifiers
+func systemdQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%").Replace(s) + `"`
+}
+
+// systemdUnitName returns the unit file name for a session in dir: its base name, and a hash of
+// the full path so that directories with the same base name get their own units
+func systemdUnitName(dir string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < 0x80 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.') {
+			return r
+		}
+		return '-'
+	}, filepath.Base(dir))
+	sum := sha256.Sum256([]byte(dir))
+	return "claude-launcher-" + name + "-" + hex.EncodeToString(sum[:4]) + ".service"
+}
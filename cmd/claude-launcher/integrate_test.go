@@ -0,0 +1,159 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReplaceManagedBlock(t *testing.T) {
+	block := managedBegin + "\nnew\n" + managedEnd + "\n"
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"empty file", "", block},
+		{"trailing newline", "export A=1\n", "export A=1\n\n" + block},
+		{"no trailing newline", "export A=1", "export A=1\n\n" + block},
+		{
+			name:     "existing block",
+			content:  "export A=1\n" + managedBegin + "\nold\n" + managedEnd + "\nexport B=2\n",
+			expected: "export A=1\n" + block + "export B=2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replaceManagedBlock(tt.content, block); got != tt.expected {
+				t.Errorf("replaceManagedBlock() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteManagedBlockKeepsMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".envrc")
+	if err := os.WriteFile(path, []byte("export A=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	block := managedBegin + "\n" + managedEnd + "\n"
+	for range 2 {
+		if err := writeManagedBlock(path, block, 0o644); err != nil {
+			t.Fatalf("writeManagedBlock() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "export A=1\n\n" + block; string(data) != expected {
+		t.Errorf("content = %q, expected %q", data, expected)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, expected 0600", info.Mode().Perm())
+	}
+}
+
+func TestDirenvBlock(t *testing.T) {
+	got := direnvBlock("My Work", "backend", "/home/user/my config/config.json")
+	for _, line := range []string{
+		`watch_file '/home/user/my config/config.json'`,
+		`eval "$(claude-launcher env --shell sh -a 'My Work')"`,
+		profileEnvVar + "='backend'",
+	} {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("direnvBlock() = %q, expected it to contain %q", got, line)
+		}
+	}
+
+	got = direnvBlock("", "", "/c.json")
+	if !strings.Contains(got, `eval "$(claude-launcher env --shell sh)"`) || strings.Contains(got, profileEnvVar) {
+		t.Errorf("direnvBlock() = %q, expected the default account without a profile", got)
+	}
+}
+
+func TestIntegrateGitHooks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	hook := filepath.Join(repo, ".git", "hooks", "pre-commit")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\necho existing\nexit 0\n"), 0o700); err != nil { // #nosec G306 -- test hook
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if code := integrateGitHooks([]string{"-d", repo}); code != exitSuccess {
+			t.Fatalf("integrateGitHooks() = %d, expected %d", code, exitSuccess)
+		}
+	}
+
+	data, err := os.ReadFile(hook) // #nosec G304 -- test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The check runs before the existing hook can exit
+	if expected := "#!/bin/sh\n\n" + gitHookBlock("pre-commit") + "\necho existing\nexit 0\n"; string(data) != expected {
+		t.Errorf("pre-commit = %q, expected %q", data, expected)
+	}
+
+	info, err := os.Stat(filepath.Join(repo, ".git", "hooks", "post-checkout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("post-checkout mode = %v, expected it to be executable", info.Mode().Perm())
+	}
+}
+
+func TestInsertGitHookBlock(t *testing.T) {
+	block := gitHookBlock("pre-commit")
+	if got, err := insertGitHookBlock("#!/usr/bin/env bash\nexec other-hook\n", block); err != nil || got != "#!/usr/bin/env bash\n\n"+block+"\nexec other-hook\n" {
+		t.Errorf("insertGitHookBlock() = %q, %v, expected the block before the exec", got, err)
+	}
+	if _, err := insertGitHookBlock("#!/usr/bin/env python3\nimport sys\n", block); err == nil {
+		t.Error("insertGitHookBlock() should refuse a hook that is not a shell script")
+	}
+}
+
+func TestSystemdUnit(t *testing.T) {
+	env := map[string]string{"PATH": "/usr/bin:/bin", "XDG_STATE_HOME": "/home/me/100%"}
+	got := systemdUnit("/usr/local/bin/claude-launcher", "/home/me/my $proj", "Work", "backend", func(name string) string { return env[name] })
+
+	for _, want := range []string{
+		`ExecStart="/usr/local/bin/claude-launcher" "--detach" "--new" "--no-prompts" "--approve-dir" "--dir" "/home/me/my $$proj" "--account" "Work"` + "\n",
+		`Environment="PATH=/usr/bin:/bin"` + "\n",
+		`Environment="XDG_STATE_HOME=/home/me/100%%"` + "\n",
+		`Environment="` + profileEnvVar + `=backend"` + "\n",
+		"WantedBy=default.target\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("systemdUnit() = %q, expected it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "CLAUDE_LAUNCHER_HOME") {
+		t.Errorf("systemdUnit() = %q, expected unset variables to be left out", got)
+	}
+}
+
+func TestSystemdUnitName(t *testing.T) {
+	got := systemdUnitName("/home/me/a/my proj.v2")
+	if !strings.HasPrefix(got, "claude-launcher-my-proj.v2-") || !strings.HasSuffix(got, ".service") {
+		t.Errorf("systemdUnitName() = %q, expected it to be named after the directory", got)
+	}
+	if other := systemdUnitName("/home/me/b/my proj.v2"); other == got {
+		t.Errorf("systemdUnitName() = %q for two directories with the same base name", got)
+	}
+}
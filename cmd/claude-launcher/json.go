@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// jsonOutput is set by the global --json flag: listing commands print JSON to stdout
+var jsonOutput bool
+
+// writeJSON writes v as indented JSON to w
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printJSON writes v as JSON to stdout and returns the exit code
+func printJSON(v any) int {
+	if err := writeJSON(os.Stdout, v); err != nil {
+		ui.NewPrinter(os.Stderr).Error("Failed to write JSON: %v\n", err)
+		return exitError
+	}
+	return exitSuccess
+}
+
+// dirsJSON is the --show-dirs --json output
+type dirsJSON struct {
+	AllowedDirs     []string `json:"allowedDirs"`
+	YoloAllowedDirs []string `json:"yoloAllowedDirs"`
+	SharedDirs      []string `json:"sharedDirs"`
+}
+
+// newDirsJSON builds the --show-dirs --json output; empty lists are encoded as []
+func newDirsJSON(cfg *config.Config) dirsJSON {
+	return dirsJSON{
+		AllowedDirs:     nonNil(cfg.AllowedDirs),
+		YoloAllowedDirs: nonNil(cfg.YoloAllowedDirs),
+		SharedDirs:      nonNil(cfg.SharedDirs),
+	}
+}
+
+// --show-dirs output formats
+const (
+	dirsFormatText = "text"
+	dirsFormatJSON = "json"
+	dirsFormatTSV  = "tsv"
+)
+
+// Lists of a dirEntry
+const (
+	dirListAllowed = "allowed"
+	dirListYolo    = "yolo"
+)
+
+// dirEntry is one directory of --show-dirs --format json|tsv
+type dirEntry struct {
+	List     string     `json:"list"` // "allowed" or "yolo" (yoloAllowedDirs)
+	Path     string     `json:"path"`
+	Exists   bool       `json:"exists"`
+	RealPath string     `json:"realPath"`         // Path with symlinks resolved, "" when it does not exist
+	Source   string     `json:"source,omitempty"` // e.g. "config.json", "CLAUDE_SAFE_DIRS" or "plugin jira"
+	Shared   bool       `json:"shared"`           // Passed to claude with --add-dir (shareWithClaude)
+	Expires  *time.Time `json:"expires,omitempty"`
+}
+
+// dirEntriesColumns is the header row of --show-dirs --format tsv
+var dirEntriesColumns = []string{"list", "path", "exists", "realPath", "source", "shared", "expires"}
+
+// newDirEntries describes the allowed directories, then the yoloAllowedDirs, of cfg
+func newDirEntries(cfg *config.Config) []dirEntry {
+	entries := make([]dirEntry, 0, len(cfg.AllowedDirs)+len(cfg.YoloAllowedDirs))
+	for _, dir := range cfg.AllowedDirs {
+		entry := newDirEntry(dirListAllowed, dir)
+		entry.Source = cfg.DirSources[dir]
+		entry.Shared = slices.Contains(cfg.SharedDirs, dir)
+		if expires, ok := cfg.DirExpiry[dir]; ok {
+			entry.Expires = &expires
+		}
+		entries = append(entries, entry)
+	}
+	for _, dir := range cfg.YoloAllowedDirs {
+		entry := newDirEntry(dirListYolo, dir)
+		entry.Source = config.SourceFile
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// newDirEntry checks dir on disk
+func newDirEntry(list, dir string) dirEntry {
+	entry := dirEntry{List: list, Path: dir}
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		entry.Exists = true
+	}
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		entry.RealPath = real
+	}
+	return entry
+}
+
+// writeDirEntriesTSV writes entries as tab-separated values after a header row
+func writeDirEntriesTSV(w io.Writer, entries []dirEntry) error {
+	if _, err := fmt.Fprintln(w, strings.Join(dirEntriesColumns, "\t")); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		var expires string
+		if e.Expires != nil {
+			expires = e.Expires.Format(time.RFC3339)
+		}
+		row := []string{e.List, e.Path, strconv.FormatBool(e.Exists), e.RealPath, e.Source, strconv.FormatBool(e.Shared), expires}
+		if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// versionJSON is the --version --json output
+type versionJSON struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+}
+
+// configFileJSON is the --show-config --json output
+type configFileJSON struct {
+	Path     string          `json:"path"`
+	Exists   bool            `json:"exists"`
+	Contents json.RawMessage `json:"contents,omitempty"`
+}
+
+// showConfigFileJSON prints the config file path and its parsed contents as JSON
+func showConfigFileJSON() int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	configPath, err := config.DefaultConfigPath()
+	if err != nil {
+		printer.Error("Error: %v\n", err)
+		return exitError
+	}
+
+	out := configFileJSON{Path: filepath.Clean(configPath)}
+	data, err := os.ReadFile(out.Path)
+	switch {
+	case os.IsNotExist(err):
+		return printJSON(out)
+	case err != nil:
+		printer.Error("Error reading file: %v\n", err)
+		return exitError
+	}
+
+	if !json.Valid(data) {
+		printer.Error("Error: %s is not valid JSON\n", out.Path)
+		return exitError
+	}
+	out.Exists = true
+	out.Contents = data
+	return printJSON(out)
+}
+
+// instanceJSON is one entry of the `ps --json` output
+type instanceJSON struct {
+	ID        string    `json:"id"`
+	PID       int       `json:"pid"`
+	Mode      string    `json:"mode"`
+	StartedAt time.Time `json:"startedAt"`
+	Account   string    `json:"account,omitempty"`
+	Dir       string    `json:"dir"`
+}
+
+// newInstancesJSON converts instance records into the `ps --json` output
+func newInstancesJSON(instances []state.Instance) []instanceJSON {
+	result := make([]instanceJSON, 0, len(instances))
+	for _, inst := range instances {
+		mode := "foreground"
+		if inst.Socket != "" {
+			mode = "detached"
+		}
+		result = append(result, instanceJSON{
+			ID:        inst.ID,
+			PID:       inst.PID,
+			Mode:      mode,
+			StartedAt: inst.StartedAt,
+			Account:   inst.Account,
+			Dir:       inst.Dir,
+		})
+	}
+	return result
+}
+
+// nonNil returns s, or an empty slice when s is nil
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// nonNilMap returns m, or an empty map when m is nil
+func nonNilMap[V any](m map[string]V) map[string]V {
+	if m == nil {
+		return map[string]V{}
+	}
+	return m
+}
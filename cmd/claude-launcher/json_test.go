@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+func TestNewDirsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := &config.Config{AllowedDirs: []string{"/home/user/projects"}}
+	if err := writeJSON(&buf, newDirsJSON(cfg)); err != nil {
+		t.Fatalf("writeJSON() error = %v", err)
+	}
+
+	want := `{
+  "allowedDirs": [
+    "/home/user/projects"
+  ],
+  "yoloAllowedDirs": [],
+  "sharedDirs": []
+}
+`
+	if buf.String() != want {
+		t.Errorf("got %s, want %s", buf.String(), want)
+	}
+}
+
+func TestNewDirEntries(t *testing.T) {
+	existing, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(existing, link); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(existing, "missing")
+	expires := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	cfg := &config.Config{
+		AllowedDirs:     []string{link, missing},
+		SharedDirs:      []string{link},
+		YoloAllowedDirs: []string{existing},
+		DirSources:      map[string]string{link: config.SourceFile, missing: "plugin jira"},
+		DirExpiry:       map[string]time.Time{missing: expires},
+	}
+
+	var buf bytes.Buffer
+	if err := writeDirEntriesTSV(&buf, newDirEntries(cfg)); err != nil {
+		t.Fatalf("writeDirEntriesTSV() error = %v", err)
+	}
+	want := "list\tpath\texists\trealPath\tsource\tshared\texpires\n" +
+		"allowed\t" + link + "\ttrue\t" + existing + "\tconfig.json\ttrue\t\n" +
+		"allowed\t" + missing + "\tfalse\t\tplugin jira\tfalse\t2026-12-31T00:00:00Z\n" +
+		"yolo\t" + existing + "\ttrue\t" + existing + "\tconfig.json\tfalse\t\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestNewInstancesJSON(t *testing.T) {
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	instances := []state.Instance{
+		{ID: "a1", PID: 10, Dir: "/p/one", StartedAt: started},
+		{ID: "b2", PID: 20, Dir: "/p/two", Account: "work", StartedAt: started, Socket: "/s/b2.sock"},
+	}
+
+	got := newInstancesJSON(instances)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(got))
+	}
+	if got[0].Mode != "foreground" || got[1].Mode != "detached" {
+		t.Errorf("unexpected modes: %q, %q", got[0].Mode, got[1].Mode)
+	}
+	if got[1].Account != "work" || got[1].Dir != "/p/two" {
+		t.Errorf("unexpected entry: %+v", got[1])
+	}
+
+	if empty := newInstancesJSON(nil); empty == nil || len(empty) != 0 {
+		t.Errorf("expected an empty non-nil slice, got %#v", empty)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/account"
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// How the account of a launch was chosen, for --explain
+type accountChoice int
+
+const (
+	accountNamed    accountChoice = iota // --account (or the launched profile) named it
+	accountNotFound                      // --account named no account, so the menu chose one
+	accountDefault                       // Nothing named it: the menu chose, or the only account was used
+)
+
+// ruleReasons explains the directory check of a launch for --explain: the allowed directory
+// that matched and where it comes from, the project entry and the config sources that contributed
+func ruleReasons(cfg *config.Config, rule string, project *config.Project, configPath string, prefs state.Prefs) []string {
+	reasons := []string{fmt.Sprintf(i18n.T("Allowed by %s (from %s)"), rule, dirSource(cfg.DirSources[rule], configPath))}
+	if project != nil {
+		reasons = append(reasons, fmt.Sprintf(i18n.T("Project settings from the projects entry for %s"), project.Path))
+	}
+	return append(reasons, fmt.Sprintf(i18n.T("Config sources: %s"), strings.Join(configSources(cfg, configPath, prefs), ", ")))
+}
+
+// dirSource describes a DirSources value
+func dirSource(source, configPath string) string {
+	switch source {
+	case config.SourceFile, "":
+		return fmt.Sprintf(i18n.T("allowedDirs in %s"), configPath)
+	case config.SourceSafeDirs:
+		return i18n.T("CLAUDE_SAFE_DIRS, which overrides allowedDirs")
+	}
+	return source // CLAUDE_LAUNCHER_CI_ALLOW or "plugin NAME"
+}
+
+// configSources lists what the configuration of a launch was read from: the config file,
+// the environment variables and plugins adding allowed directories, and the saved preferences
+func configSources(cfg *config.Config, configPath string, prefs state.Prefs) []string {
+	var sources []string
+	if _, err := os.Stat(configPath); err == nil {
+		sources = append(sources, configPath)
+	}
+	seen := map[string]bool{}
+	for _, dir := range cfg.AllowedDirs {
+		source := cfg.DirSources[dir]
+		if source == "" || source == config.SourceFile || seen[source] {
+			continue
+		}
+		seen[source] = true
+		sources = append(sources, source)
+	}
+	if prefs != (state.Prefs{}) {
+		sources = append(sources, i18n.T("saved preferences"))
+	}
+	if len(sources) == 0 {
+		return []string{i18n.T("defaults only")}
+	}
+	return sources
+}
+
+// accountReason explains for --explain why selected is the account of the launch. named is the
+// account name given with --account, profile the launched profile, accounts the number of
+// configured accounts and source where they are configured.
+func accountReason(selected *account.Account, how accountChoice, named, profile string, accounts int, source string) string {
+	if selected == nil {
+		return i18n.T("No account: none is configured, so claude uses its default configuration")
+	}
+	var why string
+	switch {
+	case how == accountNamed && profile != "":
+		why = fmt.Sprintf(i18n.T("named by profile %s"), profile)
+	case how == accountNamed:
+		why = i18n.T("named by --account")
+	case how == accountNotFound:
+		why = fmt.Sprintf(i18n.T("--account %s was not found, so it was chosen in the menu"), named)
+	case accounts == 1:
+		why = i18n.T("the only configured account")
+	default:
+		why = i18n.T("chosen in the account menu")
+	}
+	return fmt.Sprintf(i18n.T("Account %s: %s (accounts from %s)"), selected.Name, why, source)
+}
+
+// accountSource returns where the accounts are configured and how many there are:
+// CLAUDE_ACCOUNTS, which takes precedence, or the config file
+func accountSource(configPath string) (string, int) {
+	if accounts, err := (&account.EnvLoader{}).Load(); err == nil {
+		return "CLAUDE_ACCOUNTS", len(accounts.Accounts)
+	}
+	accounts, err := account.LoadAccountConfig()
+	if err != nil || accounts == nil {
+		return configPath, 0
+	}
+	return configPath, len(accounts.Accounts)
+}
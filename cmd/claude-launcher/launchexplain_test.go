@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/account"
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+func TestRuleReasons(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		AllowedDirs: []string{"/work", "/jira", "/tickets"},
+		DirSources:  map[string]string{"/work": config.SourceFile, "/jira": "plugin jira", "/tickets": "plugin jira"},
+	}
+	project := &config.Project{Path: "/work/api"}
+
+	got := ruleReasons(cfg, "/work", project, configPath, state.Prefs{Palette: "mono"})
+	want := []string{
+		"Allowed by /work (from allowedDirs in " + configPath + ")",
+		"Project settings from the projects entry for /work/api",
+		"Config sources: " + configPath + ", plugin jira, saved preferences",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ruleReasons() = %q, want %q", got, want)
+	}
+
+	envCfg := &config.Config{AllowedDirs: []string{"/work"}, DirSources: map[string]string{"/work": config.SourceSafeDirs}}
+	got = ruleReasons(envCfg, "/work", nil, filepath.Join(t.TempDir(), "missing.json"), state.Prefs{})
+	want = []string{
+		"Allowed by /work (from CLAUDE_SAFE_DIRS, which overrides allowedDirs)",
+		"Config sources: CLAUDE_SAFE_DIRS",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ruleReasons() = %q, want %q", got, want)
+	}
+}
+
+func TestAccountReason(t *testing.T) {
+	work := &account.Account{Name: "Work"}
+
+	tests := []struct {
+		name     string
+		selected *account.Account
+		how      accountChoice
+		named    string
+		profile  string
+		accounts int
+		want     string
+	}{
+		{"none configured", nil, accountDefault, "", "", 0, "No account: none is configured, so claude uses its default configuration"},
+		{"--account", work, accountNamed, "Work", "", 2, "Account Work: named by --account (accounts from config.json)"},
+		{"profile", work, accountNamed, "Work", "api", 2, "Account Work: named by profile api (accounts from config.json)"},
+		{"not found", work, accountNotFound, "Wrok", "", 2, "Account Work: --account Wrok was not found, so it was chosen in the menu (accounts from config.json)"},
+		{"only account", work, accountDefault, "", "", 1, "Account Work: the only configured account (accounts from config.json)"},
+		{"menu", work, accountDefault, "", "", 2, "Account Work: chosen in the account menu (accounts from config.json)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := accountReason(tt.selected, tt.how, tt.named, tt.profile, tt.accounts, "config.json"); got != tt.want {
+				t.Errorf("accountReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
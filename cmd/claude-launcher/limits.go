@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/home"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/limits"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// runExecLimited implements the hidden subcommand that applies resource limits and execs claude
+func runExecLimited(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	opts, argv, err := limits.ParseExecArgs(args)
+	if err != nil {
+		printer.Error("Invalid resource limit arguments: %v\n", err)
+		return exitError
+	}
+
+	// Exec only returns on failure
+	if err := limits.Exec(opts, argv); err != nil {
+		printer.Error("Failed to apply resource limits: %v\n", err)
+	}
+	return exitError
+}
+
+// landlockPaths returns the paths claude may write when writes are restricted with Landlock:
+// the launch directory and its extra directories (unless readOnly), the account's config dir, the
+// temp directory and landlock.writable. Without a config dir, Claude Code also keeps ~/.claude.json
+// next to ~/.claude.
+func landlockPaths(cfg *config.Config, dir string, addDirs []string, configDir string, readOnly bool) []string {
+	var paths []string
+	if !readOnly {
+		paths = slices.Concat([]string{dir}, addDirs)
+	}
+	paths = slices.Concat(paths, []string{os.TempDir()}, cfg.Landlock.Writable)
+	if claudeDir := launcher.ClaudeConfigDir(configDir); claudeDir != "" {
+		paths = append(paths, claudeDir)
+	}
+	if configDir == "" && os.Getenv("CLAUDE_CONFIG_DIR") == "" {
+		if homeDir, err := home.Dir(); err == nil {
+			paths = append(paths, filepath.Join(homeDir, ".claude.json"))
+		}
+	}
+	return paths
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
+)
+
+func TestLandlockPaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Landlock is Linux only")
+	}
+	t.Setenv("HOME", "/home/tester")
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	t.Setenv("TMPDIR", "/scratch")
+	cfg := &config.Config{Landlock: config.Landlock{Enabled: true, Writable: []string{"/home/tester/.cache"}}}
+
+	got := landlockPaths(cfg, "/work/app", []string{"/work/lib"}, "/home/tester/.claude-work", false)
+	want := []string{"/work/app", "/work/lib", "/scratch", "/home/tester/.cache", "/home/tester/.claude-work"}
+	if !slices.Equal(got, want) {
+		t.Errorf("landlockPaths() with a config dir = %v, want %v", got, want)
+	}
+
+	got = landlockPaths(cfg, "/work/app", []string{"/work/lib"}, "/home/tester/.claude-work", true)
+	want = []string{"/scratch", "/home/tester/.cache", "/home/tester/.claude-work"}
+	if !slices.Equal(got, want) {
+		t.Errorf("landlockPaths() read-only = %v, want %v", got, want)
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	got = landlockPaths(cfg, "/work/app", nil, "", false)
+	want = []string{"/work/app", "/scratch", "/home/tester/.cache", filepath.Join(homeDir, ".claude"), filepath.Join(homeDir, ".claude.json")}
+	if !slices.Equal(got, want) {
+		t.Errorf("landlockPaths() without a config dir = %v, want %v", got, want)
+	}
+}
@@ -1,23 +1,46 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/23prime/claude-launcher/internal/account"
 	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/container"
+	"github.com/23prime/claude-launcher/internal/detach"
+	"github.com/23prime/claude-launcher/internal/event"
+	"github.com/23prime/claude-launcher/internal/home"
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/installer"
 	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/limits"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/notify"
+	"github.com/23prime/claude-launcher/internal/secrets"
 	"github.com/23prime/claude-launcher/internal/security"
 	"github.com/23prime/claude-launcher/internal/session"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/timing"
+	"github.com/23prime/claude-launcher/internal/tmux"
 	"github.com/23prime/claude-launcher/internal/ui"
 )
 
+// Exit codes. Launches that reach claude exit with claude's own code.
 const (
-	exitSuccess = 0
-	exitError   = 1
+	exitSuccess  = 0
+	exitError    = 1 // Any other failure
+	exitConfig   = 2 // Configuration missing or invalid
+	exitDenied   = 3 // Directory not allowed
+	exitAborted  = 4 // A prompt or menu was cancelled
+	exitNotFound = 5 // claude (or the selected tool) not found
 )
 
 var (
@@ -27,88 +50,485 @@ var (
 	BuildDate = "unknown"
 )
 
+// ciEnv is the CI runner or cloud development environment the launcher runs in, if any
+var ciEnv *config.Environment
+
+// uiModeFlag is set when --ui or --accessible chose the message style, which then
+// overrides the "accessible" config option
+var uiModeFlag bool
+
+// processStart is when the launcher started, the start of the --timing breakdown
+var processStart = time.Now()
+
+// timings measures the startup phases with --timing; nil (recording nothing) without it
+var timings *timing.Recorder
+
 func main() {
 	os.Exit(run())
 }
 
+// subcommands maps subcommand names to their entry points.
+// They are dispatched before the launch flags are parsed.
+var subcommands = map[string]func(args []string) int{
+	"up":                runUp,
+	"workspace":         runWorkspace,
+	"tui":               runTUI,
+	"pick":              runPick,
+	"recent":            runRecent,
+	"bookmark":          runBookmark,
+	"run":               runHeadless,
+	"attach":            runAttach,
+	"ps":                runPs,
+	"which":             runWhich,
+	"env":               runEnv,
+	"kill":              runKill,
+	"rollback":          runRollback,
+	"completion":        runCompletion,
+	"explain":           runExplain,
+	"stats":             runStats,
+	"account":           runAccount,
+	"history":           runHistory,
+	"mcp":               runMCP,
+	"integrate":         runIntegrate,
+	"shell-init":        runShellInit,
+	"prompt-status":     runPromptStatus,
+	"exec":              runExec,
+	"ssh":               runSSH,
+	"plugins":           runPlugins,
+	"config":            runConfig,
+	"prefs":             runPrefs,
+	genDocsCommand:      runGenDocs,
+	detach.ServeCommand: runServeDetached,
+	limits.ExecCommand:  runExecLimited,
+}
+
 func run() int {
-	// Parse command-line flags
-	showDirs := flag.Bool("show-dirs", false, "Show configured allowed directories")
-	flag.BoolVar(showDirs, "l", false, "Show configured allowed directories (shorthand)")
+	if len(os.Args) > 1 && os.Args[1] == completeCommand {
+		return runComplete(os.Args[2:])
+	}
+
+	// CI logs are read after the fact, often without ANSI support
+	ciEnv = config.DetectEnvironment()
+	if ciEnv != nil && ciEnv.Runner {
+		ui.DisableColor()
+	}
+
+	subscribeEvents(events)
+	defer state.RemoveEphemeral()
+
+	args, err := applyGlobalFlags(os.Args[1:])
+	if err != nil {
+		ui.NewPrinter(os.Stderr).Error("%v\n", err)
+		return exitError
+	}
+
+	if len(args) > 0 {
+		if cmd, ok := subcommands[args[0]]; ok {
+			return cmd(args[1:])
+		}
+		if args[0] == recentShortcut {
+			return runRecent(args[1:])
+		}
+		if name, ok := strings.CutPrefix(args[0], bookmarkPrefix); ok {
+			return runBookmarkLaunch(name, args[1:])
+		}
+		if code, ok := runAlias(args[0], args[1:]); ok {
+			return code
+		}
+		if code, ok := runPlugin(args[0], args[1:]); ok {
+			return code
+		}
+	}
+
+	if name := os.Getenv(profileEnvVar); name != "" {
+		return launchEnvProfile(name, args)
+	}
+	return launch(args)
+}
+
+// applyGlobalFlags applies and removes the flags accepted by every command
+// (before any "--", so claude arguments are untouched)
+func applyGlobalFlags(args []string) ([]string, error) {
+	result := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(result, args[i:]...), nil
+		}
+		if mode, ok := strings.CutPrefix(arg, "--ui="); ok || arg == "--ui" {
+			if !ok {
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("--ui requires a mode (auto, color, plain, json, silent, accessible)")
+				}
+				i++
+				mode = args[i]
+			}
+			if err := ui.SetMode(mode); err != nil {
+				return nil, err
+			}
+			uiModeFlag = true
+			continue
+		}
+		if arg == "--accessible" {
+			ui.SetAccessible()
+			uiModeFlag = true
+			continue
+		}
+		if arg == "--no-color" {
+			ui.DisableColor()
+			continue
+		}
+		if arg == "--ascii" {
+			ui.ForceASCII()
+			continue
+		}
+		if arg == "--quiet" || arg == "-q" {
+			ui.SetQuiet()
+			continue
+		}
+		if arg == "--verbose" {
+			log.EnableDebug()
+			continue
+		}
+		if arg == "--json" {
+			jsonOutput = true
+			continue
+		}
+		if path, ok := strings.CutPrefix(arg, "--config="); ok || arg == "--config" {
+			if !ok {
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("--config requires a path")
+				}
+				i++
+				path = args[i]
+			}
+			expanded, err := config.ExpandPath(path)
+			if err != nil {
+				return nil, err
+			}
+			config.SetPath(expanded)
+			continue
+		}
+		if arg == "--timing" {
+			timings = timing.New(processStart)
+			timings.Idle = ui.InputWait
+			continue
+		}
+		result = append(result, arg)
+	}
+	return result, nil
+}
+
+// launchFlags holds the options of a launch (the command without a subcommand)
+type launchFlags struct {
+	showDirs, showHelp, showVersion, showConfig bool
+	accountName, model, dir                     string
+	detached, useTmux, banner, useContainer     bool
+	useDevcontainer                             bool
+	noOtel, continueSession, newSession         bool
+	preset                                      string
+	addDirs                                     stringsFlag
+	extraEnv                                    envFlag
+	printEnv, dryRun, explain, approveDir       bool
+	noPrompts                                   bool
+	allowRoot, landlock, readOnly               bool
+	plan                                        bool
+	permissionMode                              string
+	toolName                                    string
+	format                                      string
+}
+
+// launchesNothing reports whether the launch only prints what it would run (--print-env, --dry-run),
+// so prompts and checks with side effects are skipped
+func (f *launchFlags) launchesNothing() bool {
+	return f.printEnv || f.dryRun
+}
+
+// newLaunchFlags defines the launch flags; gen-docs documents them from the same definitions
+func newLaunchFlags() (*flag.FlagSet, *launchFlags) {
+	fs := flag.NewFlagSet("claude-launcher", flag.ExitOnError)
+	f := &launchFlags{extraEnv: envFlag{}}
+
+	fs.BoolVar(&f.showDirs, "show-dirs", false, "Show configured allowed directories")
+	fs.BoolVar(&f.showDirs, "l", false, "Show configured allowed directories (shorthand)")
+	fs.StringVar(&f.format, "format", dirsFormatText, "Output `FORMAT` of --show-dirs: text, json or tsv (with details for scripts)")
+
+	fs.BoolVar(&f.showHelp, "help", false, "Show help message")
+	fs.BoolVar(&f.showHelp, "h", false, "Show help message (shorthand)")
+
+	fs.BoolVar(&f.showVersion, "version", false, "Show version information")
+	fs.BoolVar(&f.showVersion, "v", false, "Show version information (shorthand)")
+
+	fs.BoolVar(&f.showConfig, "show-config", false, "Show configuration file path and contents")
+	fs.BoolVar(&f.showConfig, "c", false, "Show configuration file path and contents (shorthand)")
+
+	fs.StringVar(&f.accountName, "account", "", "Account `NAME` to use (must exist in config)")
+	fs.StringVar(&f.accountName, "a", "", "Account name to use (shorthand)")
+
+	fs.StringVar(&f.model, "model", "", "`MODEL` to use (overrides project and account defaults)")
+	fs.StringVar(&f.model, "m", "", "Model to use (shorthand)")
+
+	fs.StringVar(&f.dir, "dir", "", "Directory (`DIR`) to launch in (defaults to the current directory)")
+	fs.StringVar(&f.dir, "d", "", "Directory to launch in (shorthand)")
+
+	fs.BoolVar(&f.detached, "detach", false, "Run Claude in the background (reattach with 'attach')")
+
+	fs.BoolVar(&f.useTmux, "tmux", false, "Launch in a tmux window named after the project")
+
+	fs.BoolVar(&f.banner, "banner", false, "Show a one-line summary of the launch context")
+
+	fs.BoolVar(&f.useContainer, "container", false, "Run Claude inside a Docker/Podman container")
+
+	fs.BoolVar(&f.plan, "plan", false, "Start Claude in plan mode (same as --permission-mode plan)")
+	fs.StringVar(&f.permissionMode, "permission-mode", "", "Permission `MODE` to start Claude in: "+strings.Join(config.PermissionModes, ", "))
 
-	showHelp := flag.Bool("help", false, "Show help message")
-	flag.BoolVar(showHelp, "h", false, "Show help message (shorthand)")
+	fs.BoolVar(&f.readOnly, "read-only", false, "Deny Claude the tools and commands that change files (with --landlock, enforced by the kernel)")
 
-	showVersion := flag.Bool("version", false, "Show version information")
-	flag.BoolVar(showVersion, "v", false, "Show version information (shorthand)")
+	fs.BoolVar(&f.landlock, "landlock", false, "Only let Claude write to the project, its config dir and temp (Linux 5.13+, --landlock=false: unrestricted)")
 
-	showConfig := flag.Bool("show-config", false, "Show configuration file path and contents")
-	flag.BoolVar(showConfig, "c", false, "Show configuration file path and contents (shorthand)")
+	fs.BoolVar(&f.useDevcontainer, "devcontainer", false, "Run Claude inside the project's devcontainer without asking (--devcontainer=false: on the host)")
 
-	accountName := flag.String("account", "", "Account name to use (must exist in config)")
-	flag.StringVar(accountName, "a", "", "Account name to use (shorthand)")
+	fs.BoolVar(&f.noOtel, "no-otel", false, "Disable OpenTelemetry environment variable injection")
 
-	noOtel := flag.Bool("no-otel", false, "Disable OpenTelemetry environment variable injection")
+	fs.BoolVar(&f.continueSession, "continue", false, "Continue the previous session without asking")
+	fs.BoolVar(&f.newSession, "new", false, "Start a new session without asking")
 
-	flag.Parse()
+	fs.StringVar(&f.preset, "preset", "", "Permission preset (`NAME`) to apply (from permissionPresets in config)")
+
+	fs.Var(&f.addDirs, "add-dir", "Give Claude access to another allowed directory (`DIR`, repeatable)")
+
+	fs.Var(f.extraEnv, "env", "Set an environment variable for claude (`KEY=VALUE`, repeatable)")
+
+	fs.BoolVar(&f.printEnv, "print-env", false, "Print the environment claude would receive and exit")
+
+	fs.BoolVar(&f.dryRun, "dry-run", false, "Print the command that would run claude and exit")
+
+	fs.BoolVar(&f.explain, "explain", false, "Explain the allowed directory, account and config sources of the launch")
+
+	fs.BoolVar(&f.allowRoot, "allow-root", false, "Launch even when running as root (logged to the audit log)")
+
+	fs.BoolVar(&f.approveDir, "approve-dir", false, "Confirm the directory for confirmNewDirs without asking")
+
+	fs.BoolVar(&f.noPrompts, "no-prompts", false, "Never ask: refuse or take the default where a question would be asked")
+
+	fs.StringVar(&f.toolName, "tool", "", "Agent CLI (`NAME`) to launch: "+strings.Join(launcher.ToolNames(), ", ")+" (default: claude)")
+
+	return fs, f
+}
+
+// launch parses the launch flags from args and runs the interactive launch flow
+func launch(args []string) int {
+	fs, f := newLaunchFlags()
+	_ = fs.Parse(args) //nolint:errcheck // the flag set exits on parse errors
 
 	printer := ui.NewPrinter(os.Stderr)
 
+	if f.continueSession && f.newSession {
+		printer.Error("--continue and --new cannot be used together\n")
+		return exitError
+	}
+	if !slices.Contains([]string{dirsFormatText, dirsFormatJSON, dirsFormatTSV}, f.format) {
+		printer.Error("Unknown format %q (available: %s, %s, %s)\n", f.format, dirsFormatText, dirsFormatJSON, dirsFormatTSV)
+		return exitError
+	}
+	// A temporary state directory is gone when this process exits, and with it the session socket
+	if f.detached {
+		if store, err := state.NewStore(); err == nil && store.Ephemeral() {
+			printer.Error("✗ --detach needs a writable state directory: set CLAUDE_LAUNCHER_HOME or XDG_STATE_HOME to one\n")
+			return exitError
+		}
+	}
+
 	// Show help if requested
-	if *showHelp {
+	if f.showHelp {
 		showHelpMessage()
 		return exitSuccess
 	}
 
-	if *showVersion {
+	if f.showVersion {
+		if jsonOutput {
+			return printJSON(versionJSON{Version: Version, Commit: GitCommit, BuildDate: BuildDate})
+		}
 		showVersionInformation()
 		return exitSuccess
 	}
 
-	if *showConfig {
+	if f.showConfig {
+		if jsonOutput {
+			return showConfigFileJSON()
+		}
 		showConfigFile()
 		return exitSuccess
 	}
 
 	// Load configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		printer.ShowConfigError()
-		return exitError
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
 	}
+	timings.Mark("config")
 
 	// Show allowed directories if requested
-	if *showDirs {
+	if f.showDirs {
+		switch {
+		case f.format == dirsFormatJSON:
+			return printJSON(newDirEntries(cfg))
+		case f.format == dirsFormatTSV:
+			if err := writeDirEntriesTSV(os.Stdout, newDirEntries(cfg)); err != nil {
+				printer.Error("Failed to write directories: %v\n", err)
+				return exitError
+			}
+			return exitSuccess
+		case jsonOutput:
+			return printJSON(newDirsJSON(cfg))
+		}
 		printer.ShowAllowedDirs(cfg.AllowedDirs)
 		return exitSuccess
 	}
 
-	// Check if current directory is allowed
-	currentDir, err := os.Getwd()
+	// Check if the target directory is allowed
+	currentDir, rule, skipPermissions, code := authorizeLaunch(cfg, f.dir, fs.Args(), printer)
+	if code != exitSuccess {
+		return code
+	}
+	if f.explain {
+		configPath, _ := config.DefaultConfigPath() //nolint:errcheck // only shown in the explanation
+		printer.ShowExplanation(ruleReasons(cfg, rule, cfg.FindProject(currentDir), configPath, loadPrefs()))
+	}
+
+	extraDirs, code := authorizeAddDirs(cfg, f.addDirs, printer)
+	if code != exitSuccess {
+		return code
+	}
+	if !f.launchesNothing() {
+		if code := checkRoot(cfg, f.allowRoot, currentDir, fs.Args(), printer); code != exitSuccess {
+			return code
+		}
+	}
+	timings.Mark("directory check")
+
+	project := cfg.FindProject(currentDir)
+	if f.preset, code = applyBranchPolicy(project, currentDir, f.preset, fs.Args(), printer); code != exitSuccess {
+		return code
+	}
+	linkedDirs, code := authorizeLinkedDirs(cfg, project, printer)
+	if code != exitSuccess {
+		return code
+	}
+	if f.toolName == "" {
+		f.toolName = cfg.ToolFor(project)
+	}
+	tool, err := launcher.LookupTool(f.toolName)
 	if err != nil {
-		printer.Error("Failed to get current directory: %v\n", err)
+		printer.Error("%v\n", err)
 		return exitError
 	}
+	if project != nil {
+		log.Debug("project found", "path", project.Path)
+	}
+	log.Debug("tool selected", "tool", tool.Name())
 
-	checker := security.NewDirectoryChecker(cfg.AllowedDirs)
-	allowed, err := checker.IsAllowed(currentDir)
+	permissions, err := cfg.PermissionsFor(project, f.preset)
 	if err != nil {
-		printer.Error("Failed to check directory: %v\n", err)
+		printer.Error("%v\n", err)
 		return exitError
 	}
-
-	if !allowed {
-		printer.ShowAccessDenied(currentDir, cfg.AllowedDirs)
+	mode, err := permissionModeFlag(f.plan, f.permissionMode)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return exitError
+	}
+	if mode != "" {
+		permissions.DefaultMode = mode
+	}
+	if f.readOnly {
+		if permissions, code = applyReadOnly(permissions, skipPermissions, printer); code != exitSuccess {
+			return code
+		}
+	}
+	if !permissions.IsZero() && tool.SettingsFlag() == "" {
+		printer.Error("%s does not support permission settings\n", tool.Name())
 		return exitError
 	}
 
-	printer.ShowDirectoryAllowed()
+	l := newLauncher(cfg, tool)
+	prompter := session.NewInteractivePrompter(os.Stdin, printer)
+	timings.Mark("project")
+
+	// Piped input belongs to claude, so nothing may read stdin before it starts;
+	// on CI runners nobody would answer
+	noPrompts := stdinPassThrough(fs.Args())
+	if noPrompts {
+		log.Debug("stdin is piped to claude, skipping prompts")
+	} else if f.noPrompts {
+		noPrompts = true
+	} else if ciEnv != nil && ciEnv.Runner {
+		log.Debug("running in CI, skipping prompts", "ci", ciEnv.Name)
+		noPrompts = true
+	}
+
+	if !f.launchesNothing() {
+		if code, done := checkLaunchDir(cfg, project, currentDir, f.approveDir, noPrompts, prompter, printer); done {
+			return code
+		}
+	}
+
+	timings.Mark("launch checks")
+
+	var devcontainerWorkspace string
+	if tool == launcher.Claude && !f.useContainer {
+		devcontainerWorkspace, code = chooseDevcontainer(cfg, fs, f, currentDir, noPrompts, prompter, printer)
+		if code != exitSuccess {
+			return code
+		}
+		if devcontainerWorkspace == "" {
+			applyVersionPin(l, currentDir, printer)
+		}
+	}
+
+	// Verify the binary before prompting (it runs inside the image in container mode).
+	// The version check and install offer only apply to claude.
+	if !f.useContainer && devcontainerWorkspace == "" {
+		minVersion := ""
+		if tool == launcher.Claude {
+			minVersion = cfg.MinClaudeVersion
+		}
+		if err := preflight(l, minVersion, printer); err != nil {
+			code := showPreflightError(printer, l, err)
+			if noPrompts || tool != launcher.Claude || !offerInstall(prompter, printer, cfg.ClaudeInstall, err) {
+				return code
+			}
+			if err := preflight(l, minVersion, printer); err != nil {
+				return showPreflightError(printer, l, err)
+			}
+		}
+	}
+
+	timings.Mark("claude binary")
+
+	// Tools in a container or devcontainer are not the host's
+	if !f.launchesNothing() && !f.useContainer && devcontainerWorkspace == "" {
+		if code := checkRequirements(project, currentDir, noPrompts, prompter, printer); code != exitSuccess {
+			return code
+		}
+		timings.Mark("requirements")
+	}
 
 	// Select account (if configured)
 	var selectedAccount *account.Account
-	if *accountName != "" {
+	accountHow := accountNamed
+	if noPrompts {
+		if f.accountName == "" {
+			accountHow = accountDefault
+		}
+		selectedAccount, err = account.SelectAccountNonInteractively(f.accountName)
+		if err != nil {
+			printer.Error("Failed to select account: %v\n", err)
+			return exitError
+		}
+	} else if f.accountName != "" {
 		// Try to find the specified account
-		found, foundOk, err := account.FindAccountByName(*accountName)
+		found, foundOk, err := account.FindAccountByName(f.accountName)
 		if err != nil {
 			printer.Error("Failed to find account: %v\n", err)
 			return exitError
@@ -118,93 +538,700 @@ func run() int {
 			selectedAccount = found
 		} else {
 			// Account not found - show warning before interactive selection
-			printer.ShowAccountNotFound(*accountName)
+			printer.ShowAccountNotFound(f.accountName)
+			accountHow = accountNotFound
 			selectedAccount, err = account.SelectAccountInteractively()
 			if err != nil {
 				printer.Error("Failed to select account: %v\n", err)
-				return exitError
+				return promptExitCode(err)
 			}
 		}
 	} else {
 		// No account name specified - use interactive selection
+		accountHow = accountDefault
 		var err error
 		selectedAccount, err = account.SelectAccountInteractively()
 		if err != nil {
 			printer.Error("Failed to select account: %v\n", err)
-			return exitError
+			return promptExitCode(err)
 		}
 	}
 
+	_ = events.Publish(event.AccountSelected{Dir: currentDir, Account: selectedAccount}) //nolint:errcheck // subscribers only observe the choice
+	timings.Mark("account")
+
 	var configDir string
 	if selectedAccount != nil {
 		printer.ShowAccountSelected(selectedAccount.Name, selectedAccount.ConfigDir)
 		configDir = selectedAccount.ConfigDir
+		if tool.ConfigDirEnv() == "" {
+			printer.ShowToolIgnoresConfigDir(tool.Name())
+		}
+	}
+	if f.explain {
+		configPath, _ := config.DefaultConfigPath() //nolint:errcheck // only shown in the explanation
+		source, accounts := accountSource(configPath)
+		profile := ""
+		if launchProfile != "" && cfg.Profiles[launchProfile].Account == f.accountName {
+			profile = launchProfile
+		}
+		printer.ShowExplanation([]string{accountReason(selectedAccount, accountHow, f.accountName, profile, accounts, source)})
 	}
 
-	// Ask user about session continuation
-	prompter := session.NewInteractivePrompter(os.Stdin, printer)
-	shouldContinue, err := prompter.AskContinue()
-	if err != nil {
-		printer.Error("Failed to read input: %v\n", err)
-		return exitError
+	resolvedModel := resolveModel(f.model, project, accountForModel(tool, selectedAccount))
+	if flagOrDefault(fs, "banner", f.banner, cfg.Banner) {
+		printer.ShowBanner(bannerParts(cfg, currentDir, launchProfile, selectedAccount, resolvedModel))
+	}
+
+	accountLabel := ""
+	if selectedAccount != nil {
+		accountLabel = selectedAccount.Name
+	}
+
+	// Plugins and the launch script may refuse the launch before anything is asked
+	req := pluginLaunchRequest(cfg, currentDir, accountLabel, resolvedModel, tool.Name(), withDefaultArgs(cfg, project, tool, fs.Args()))
+	claudeArgs, extraEnv, code := runLaunchHooks(cfg, req, f.extraEnv, printer)
+	if code != exitSuccess {
+		return code
+	}
+	skipPermissions = skipPermissions && launcher.HasSkipPermissions(claudeArgs)
+	timings.Mark("launch hooks")
+
+	// Ask user about session continuation (unless --continue or --new, or else the project's
+	// or global session setting, decided it, the tool cannot resume sessions, or nobody can answer)
+	sessionMode := cfg.SessionFor(project)
+	switch {
+	case f.continueSession:
+		sessionMode = config.SessionContinue
+	case f.newSession:
+		sessionMode = config.SessionNew
+	}
+	shouldContinue := sessionMode == config.SessionContinue && tool.CanContinue()
+	pickSession := false
+	if sessionMode == config.SessionAsk && tool.CanContinue() && !f.launchesNothing() && !noPrompts {
+		choice, err := prompter.AskSession(tool.CanPick())
+		if err != nil {
+			printer.Error("Failed to read input: %v\n", err)
+			return promptExitCode(err)
+		}
+		shouldContinue, pickSession = choice == session.ChoiceContinue, choice == session.ChoicePick
 	}
 
+	_ = events.Publish(event.SessionChosen{Dir: currentDir, Continue: shouldContinue, Pick: pickSession}) //nolint:errcheck // subscribers only observe the choice
+	timings.Mark("session")
+
 	// Show what we're doing
-	if shouldContinue {
+	switch {
+	case f.launchesNothing():
+	case pickSession:
+		printer.ShowPickingSession()
+	case shouldContinue:
 		printer.ShowContinuingSession()
-	} else {
+	default:
 		printer.ShowStartingNewSession()
 	}
 
 	// Launch Claude
-	l := launcher.NewLauncher()
+	if selectedAccount != nil {
+		if err := selectedAccount.Prepare(); err != nil {
+			printer.Error("Failed to prepare account: %v\n", err)
+			return exitError
+		}
+	}
+	env, secretEnv, err := launchEnv(cfg, project, selectedAccount, extraEnv)
+	if err != nil {
+		printer.Error("Failed to resolve secrets: %v\n", err)
+		return exitError
+	}
+
 	launchOpts := launcher.LaunchOptions{
-		Continue:  shouldContinue,
-		Args:      flag.Args(),
-		ConfigDir: configDir,
-		OtelEnv:   buildLaunchOtelEnv(cfg, selectedAccount, *noOtel),
+		Account:     accountLabel,
+		Preset:      config.PresetFor(project, f.preset),
+		Rule:        rule,
+		Continue:    shouldContinue,
+		Pick:        pickSession,
+		Dir:         currentDir,
+		Model:       resolvedModel,
+		AddDirs:     mergeDirs(extraDirs, linkedDirs, cfg.SharedDirsFor(currentDir)),
+		Args:        claudeArgs,
+		ConfigDir:   configDir,
+		OtelEnv:     buildLaunchOtelEnv(cfg, selectedAccount, f.noOtel),
+		Env:         env,
+		SecretEnv:   secretEnv,
+		MCPServers:  cfg.MCPServersFor(project),
+		Permissions: permissions,
 	}
 
-	if err := l.Launch(launchOpts); err != nil {
+	timings.Mark("environment")
+
+	if f.printEnv {
+		showTiming(printer)
+		return printLaunchEnv(l, launchOpts, printer)
+	}
+	if f.dryRun {
+		showTiming(printer)
+		return printLaunchCommand(l, launchOpts, printer)
+	}
+
+	if skipPermissions {
+		printer.ShowSkipPermissionsEnabled()
+	}
+	if f.readOnly {
+		printer.ShowReadOnly()
+	}
+
+	if f.useContainer {
+		cc := cfg.ContainerFor(project)
+		for _, dir := range launchOpts.AddDirs {
+			cc.Mounts = append(cc.Mounts, dir+":"+dir)
+		}
+		l.Wrap = containerWrapper(cc, cfg.Limits, configDir)
+	} else if devcontainerWorkspace != "" {
+		printer.ShowDevcontainerLaunch(tildePath(devcontainerWorkspace))
+		l.Wrap = func(c *launcher.Command) (*launcher.Command, error) {
+			return container.WrapDevcontainer(c, container.DevcontainerOptions{Workspace: devcontainerWorkspace, ConfigDir: configDir})
+		}
+	} else {
+		var writable []string
+		if flagOrDefault(fs, "landlock", f.landlock, cfg.Landlock.Enabled) {
+			writable = landlockPaths(cfg, currentDir, launchOpts.AddDirs, configDir, f.readOnly)
+			printer.ShowLandlockEnabled(f.readOnly)
+		}
+		l.Wrap = limitsWrapper(cfg.Limits, writable)
+	}
+
+	switch {
+	case f.detached:
+		launchOpts.Mode = launcher.ModeDetached
+	case flagOrDefault(fs, "tmux", f.useTmux, cfg.UseTmux(project)):
+		launchOpts.Mode = launcher.ModeTmux
+	}
+	if err := events.Publish(event.LaunchStarted{Options: launchOpts}); err != nil {
 		printer.Error("Failed to launch Claude: %v\n", err)
 		return exitError
 	}
+	l.Recorder = event.Recorder{Bus: events}
+	showTiming(printer)
+
+	switch launchOpts.Mode {
+	case launcher.ModeDetached:
+		return launchDetached(l, launchOpts, accountLabel, notify.New(cfg.Notifications, projectKey(cfg, currentDir)), printer)
+	case launcher.ModeTmux:
+		return launchTmux(l, launchOpts, printer)
+	}
+
+	if store, err := state.NewStore(); err == nil {
+		l.Tracker = &instanceTracker{store: store, account: accountLabel}
+	}
+
+	if err := l.Launch(launchOpts); err != nil {
+		return launchExitCode(printer, err)
+	}
 
 	return exitSuccess
 }
 
+// stdinPassThrough reports whether stdin carries input for claude rather than answers for the
+// launcher's prompts: it is piped (or redirected from a file) and claude runs in print mode,
+// e.g. `git diff | claude-launcher -- -p "review this"`
+func stdinPassThrough(claudeArgs []string) bool {
+	return ui.StdinPiped() && isPrintMode(claudeArgs)
+}
+
+// isPrintMode reports whether claudeArgs run claude non-interactively (-p/--print)
+func isPrintMode(claudeArgs []string) bool {
+	return slices.Contains(claudeArgs, "-p") || slices.Contains(claudeArgs, "--print")
+}
+
+// loadConfig loads the configuration and applies its theme, symbols and log settings, with the
+// saved preferences filling in what it leaves unset, reporting errors
+func loadConfig(printer *ui.Printer) (*config.Config, bool) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		printer.ShowConfigError()
+		if ciEnv != nil {
+			printer.Print("Running in %s: set %s=1 to allow its workspace, or to a list of directories.\n", ciEnv.Name, config.CIAllowEnvVar)
+		}
+		return nil, false
+	}
+	theme, symbols := applyPrefs(cfg, loadPrefs())
+	if err := ui.SetTheme(theme); err != nil {
+		printer.Error("Invalid theme in config: %v\n", err)
+		return nil, false
+	}
+	if err := ui.SetSymbols(symbols); err != nil {
+		printer.Error("Invalid symbols in config: %v\n", err)
+		return nil, false
+	}
+	if cfg.Accessible && !uiModeFlag {
+		ui.SetAccessible()
+	}
+	i18n.SetOverrides(promptOverrides(cfg.Prompts, i18n.Lang()))
+	rotateLogs(cfg)
+	if err := log.Setup(log.Options{Level: cfg.Log.Level, Format: cfg.Log.Format, File: cfg.Log.File}); err != nil {
+		printer.Error("Failed to set up logging: %v\n", err)
+		return nil, false
+	}
+	_ = events.Publish(event.ConfigLoaded{Config: cfg}) //nolint:errcheck // subscribers report their own problems
+	return cfg, true
+}
+
+// promptMessages maps the prompts the config can reword to the messages they replace
+var promptMessages = map[string]string{
+	config.PromptSession: "Continue previous Claude session?",
+	config.PromptAccount: "Select Claude account",
+	config.PromptDenied:  "Claude Code is not allowed to run in this directory.",
+	config.PromptConfirm: "Let Claude access this directory?",
+}
+
+// promptOverrides returns the configured wording of prompts in the language lang, keyed by the
+// messages they replace
+func promptOverrides(prompts map[string]config.PromptText, lang string) map[string]string {
+	overrides := map[string]string{}
+	for name, text := range prompts {
+		if t, ok := text.For(lang); ok {
+			overrides[promptMessages[name]] = t
+		}
+	}
+	return overrides
+}
+
 func showHelpMessage() {
 	help := `claude-launcher - Comprehensive launcher for Claude Code
 
 USAGE:
     claude-launcher [OPTIONS] [CLAUDE_ARGUMENTS...]
+    claude-launcher up <PROFILE> [CLAUDE_ARGUMENTS...]
+    claude-launcher <ALIAS> [CLAUDE_ARGUMENTS...]
+    claude-launcher workspace open <NAME> [CLAUDE_ARGUMENTS...]
+    claude-launcher tui [CLAUDE_ARGUMENTS...]
+    claude-launcher pick [--sort config|name|recent] [CLAUDE_ARGUMENTS...]
+    claude-launcher recent [-n N] [CLAUDE_ARGUMENTS...]   (or: claude-launcher -r)
+    claude-launcher @<BOOKMARK> [CLAUDE_ARGUMENTS...]
+    claude-launcher bookmark add|remove|list
+    claude-launcher run [-p PROMPT] [RUN_OPTIONS] [CLAUDE_ARGUMENTS...]
+    claude-launcher exec [-d DIR] -- COMMAND [ARGS...]
+    claude-launcher attach [ID]
+    claude-launcher ps
+    claude-launcher kill <ID>
+    claude-launcher rollback [--list] [--yes] [ID]
+    claude-launcher ssh [-a ACCOUNT] HOST:PATH [-- CLAUDE_ARGS]
+    claude-launcher which [--tool NAME] [-d DIR]
+    claude-launcher env [-a ACCOUNT] [-d DIR] [--shell sh|fish|powershell]
+    claude-launcher explain [PATH]
+    claude-launcher stats [--cost]
+    claude-launcher account stats
+    claude-launcher history export [--format csv|json] [--since 30d] [--audit]
+    claude-launcher mcp list|add|remove|test
+    claude-launcher plugins
+    claude-launcher config path|validate
+    claude-launcher prefs [list|set KEY VALUE|unset KEY]
+    claude-launcher <PLUGIN> [ARGUMENTS...]
+    claude-launcher integrate direnv [-a ACCOUNT] [--profile NAME] [-d DIR] [--lib]
+    claude-launcher integrate git-hooks [-d DIR]
+    claude-launcher integrate systemd [-a ACCOUNT] [--profile NAME] [-d DIR]
+    claude-launcher completion <bash|zsh|fish>
+    claude-launcher shell-init <bash|zsh|fish> [--name NAME] [--chpwd]
+    claude-launcher prompt-status [--allowed TEXT] [--denied TEXT]
 
 OPTIONS:
     -h, --help         Show this help message
+    --no-color         Disable colored output (also: NO_COLOR environment variable)
+    --ascii            Use [OK] / [X] / -> instead of ✓ / ✗ / 👉 (automatic on the Linux
+                       console, dumb terminals and non-UTF-8 locales)
+    -q, --quiet        Only print errors, warnings and prompts
+    --verbose          Trace config loading, directory checks and the final command
+                       (also: CLAUDE_LAUNCHER_DEBUG=1)
+    --config PATH      Use PATH as the config file (default: $CLAUDE_LAUNCHER_HOME/config.json
+                       or ~/.config/claude-launcher/config.json)
+    --timing           Print how long each startup phase took before launching
+    --ui MODE          Message style: auto (default), color, plain, json (one event per
+                       line), silent or accessible
+    --accessible       Screen-reader-friendly mode: numbered menus read line by line and
+                       messages prefixed with "Error:", "Warning:" or "OK:" (same as
+                       --ui accessible)
+    --json             Print JSON to stdout for --show-dirs, --show-config, --version,
+                       ps, up, workspace list, which, env, explain, stats, mcp list,
+                       plugins, config validate, bookmark list, account stats, prefs
+                       and rollback --list
     -l, --show-dirs    Show configured allowed directories
+    --format FORMAT    Output format of --show-dirs: text (default), json or tsv (with
+                       existence, real path, source and expiry of each directory)
     -c, --show-config  Show configuration file path and contents
     -v, --version      Show version information
     -a, --account      Account name to use (skips interactive selection)
+    -m, --model        Model to use (overrides project and account defaults)
+    -d, --dir          Directory to launch in (defaults to the current directory)
+    --detach           Run Claude in the background (reattach with 'attach')
+    --tmux             Launch in a tmux window named after the project
+                       (reuses an existing window; --tmux=false overrides config)
+    --banner           Show "profile ▸ account ▸ allowed dir ▸ model" before the prompts
+                       (--banner=false overrides config)
+    --container        Run Claude inside a Docker/Podman container
+    --devcontainer     Run Claude inside the project's devcontainer without asking
+                       (--devcontainer=false launches on the host without asking)
+    --landlock         Only let Claude write to the project, its config dir and temp
+                       (Linux 5.13+; --landlock=false: unrestricted)
     --no-otel          Disable OpenTelemetry environment variable injection
+    --continue         Continue the previous session without asking
+    --new              Start a new session without asking
+    --add-dir DIR      Give Claude access to another allowed directory (repeatable)
+    --preset NAME      Apply a permission preset (from permissionPresets)
+    --plan             Start Claude in plan mode (same as --permission-mode plan)
+    --permission-mode MODE
+                       Start Claude in MODE: default, acceptEdits or plan (overrides the
+                       defaultMode of the project and preset)
+    --read-only        Deny Claude the tools and commands that change files (with
+                       --landlock, the kernel also blocks writes to the project)
+    --env KEY=VALUE    Set an environment variable for Claude (repeatable)
+    --print-env        Print the environment claude would receive and exit
+    --dry-run          Print the command that would run claude and exit
+    --explain          Also show which allowed directory matched, why the account was
+                       chosen and which config sources contributed
+    --allow-root       Launch even when running as root (logged to the audit log)
+    --approve-dir      Confirm the directory for confirmNewDirs without asking
+    --no-prompts       Never ask: refuse or take the default where a question would be
+                       asked (for services and scripts)
+    --tool             Agent CLI to launch: claude (default), codex, gemini, aider
+
+COMMANDS:
+    up <PROFILE>       Launch with a named profile from config.json
+    workspace open <NAME>
+                       Launch in one member of a workspace with the others added via --add-dir
+    workspace list     List the configured workspaces
+    tui                Full-screen menu: pick a directory, project, profile, workspace,
+                       account or detached session with the arrow keys and enter
+    pick               Search the allowed directories and the git repositories directly
+                       inside them, and launch in the chosen one from anywhere.
+                       --sort config|name|recent orders the list and is remembered
+    recent, -r         Pick one of the last projects launched and launch it again with
+                       the same account, preset, model, mode and session choice
+                       Options: -n (number of projects, default 10)
+    bookmark add NAME [PATH]
+                       Bookmark PATH (default: the current directory) as @NAME.
+                       Options: -a/--account, --preset (applied when launching it)
+    bookmark remove NAME
+                       Remove a bookmark
+    bookmark list      List the bookmarks
+    @NAME              Launch in the bookmarked directory from anywhere
+    run -p PROMPT      Run Claude non-interactively in print mode (for pipelines)
+                       stdin is passed through; only Claude's output goes to stdout.
+                       Options: -a/--account, -m/--model, -d/--dir, --continue, --no-otel,
+                       --preset, --plan, --permission-mode, --read-only, --landlock,
+                       --allow-root, --approve-dir
+    exec -- COMMAND    Run any command (e.g. terraform, a migration script) only if the
+                       directory check passes, and write it to the audit log. Returns
+                       the command's exit code. Options: -d/--dir
+    attach [ID]        Reattach to a detached session (Ctrl-\ detaches again)
+    ps                 List running Claude sessions started by the launcher
+    kill <ID>          Stop a running session
+    rollback [ID]      Restore a snapshot taken before a launch (default: the latest of
+                       the current repository); the discarded state is saved as a new
+                       snapshot first. Options: --list, --yes
+    ssh HOST:PATH      Run claude in PATH on a host from remoteHosts (ssh -t). The host
+                       checks PATH against its allowedDirs; -a uses the account's
+                       remote config directory
+    which              Print the path of the binary a launch would execute (like
+                       'command -v') and its version. Options: --tool, -d/--dir
+    env                Print the environment changes a launch would apply as shell
+                       commands, e.g. eval "$(claude-launcher env -a Work)".
+                       Options: -a/--account, -d/--dir, --tool, --no-otel, --shell
+    explain [PATH]     Walk through the directory check for PATH (default: the current
+                       directory): how it resolved, the outcome of every allowed
+                       directory and the config change that would allow it
+    stats              Summarize the launch history: launches per project, account and
+                       preset, and session hours this week and month
+                       Options: --cost (tokens and estimated cost per project and account)
+    account stats      Show the tokens each account used in the last 5 hours and 7 days
+                       and when it last hit a usage limit (from its Claude transcripts)
+    history export     Print one record per launch (project, account, duration, exit
+                       code, allowed directory matched) as CSV or JSON lines.
+                       Options: --format, --since (30d, 2w, 12h or a date), --audit
+    mcp list           List the global MCP servers and those of each project
+    mcp add NAME -- COMMAND [ARGS...]
+                       Add an MCP server to config.json (--url URL for remote servers,
+                       --project DIR for a project, --env and --header KEY=VALUE)
+    mcp remove NAME    Remove an MCP server (--project DIR for a project's)
+    mcp test [NAME...] Start the servers a launch in the directory would get and perform
+                       the MCP initialize handshake with each. Options: -d/--dir, --timeout
+    plugins            List the claude-launcher-<name> executables on PATH; each runs as
+                       'claude-launcher <name>'. Those listed in the plugins config
+                       option are also called before launches (see README)
+    prefs              List the saved interactive preferences (prompt style, palette,
+                       symbols, pick sort order), used where config.json says nothing
+    prefs set KEY VALUE
+                       Save a preference, e.g. prefs set prompts numbered
+    prefs unset KEY    Forget a saved preference
+    integrate direnv   Write a managed block into DIR/.envrc that exports the account's
+                       launch environment (via 'env') and CLAUDE_LAUNCHER_PROFILE.
+                       --lib installs a global use_claude_launcher direnv function
+    integrate git-hooks
+                       Install pre-commit and post-checkout hooks that run the
+                       directory check on the repository: commits are refused and
+                       checkouts warned about outside the allowed directories
+    integrate systemd  Install a systemd user unit that starts a detached session in
+                       DIR at login (Linux only); reattach with 'attach'
+    completion <SHELL> Print a completion script for bash, zsh or fish, e.g.
+                       source <(claude-launcher completion bash). Account names,
+                       presets, profiles and workspaces are completed from config
+    shell-init <SHELL> Print the completion script plus a wrapper function using it
+                       (cl, or --name NAME), e.g. eval "$(claude-launcher shell-init
+                       bash)". --chpwd reports after cd whether launches are allowed
+    prompt-status      Print ✓ (or --allowed TEXT) when launches are allowed in the
+                       current directory and ✗ (or --denied TEXT) with exit code 3 when
+                       not, for shell prompts. Answers are cached for a minute
 
 DESCRIPTION:
     Combines directory security, account selection, and session management
     for Claude Code.
 
-    1. Checks if current directory is in allowed list
+    1. Checks if current directory (or --dir) is in allowed list
     2. Prompts to select account (if multiple accounts configured)
-    3. Prompts to continue previous session or start fresh
+    3. Asks whether to continue the latest session, choose one, or start fresh
     4. Launches Claude Code with appropriate flags
 
+    When stdin is piped and the Claude arguments include -p/--print, the prompts
+    are skipped and stdin is passed to Claude untouched (use --account when
+    several accounts are configured), e.g.
+    git diff | claude-launcher -- -p "review this"
+
 CONFIGURATION (priority order):
     Allowed Directories:
-    1. CLAUDE_SAFE_DIRS (highest priority)
-        Colon-separated list of allowed directory paths
+    1. CLAUDE_LAUNCHER_CI_ALLOW (CI runners, Codespaces and Gitpod only)
+        1 or true allows the workspace (e.g. GITHUB_WORKSPACE), or a list like CLAUDE_SAFE_DIRS
+        CI runners also launch without prompts or colors
+        Example: export CLAUDE_LAUNCHER_CI_ALLOW=1
+
+    2. CLAUDE_SAFE_DIRS
+        Colon-separated list of allowed directory paths (semicolon on Windows)
         Example: export CLAUDE_SAFE_DIRS="$HOME/projects:$HOME/work"
 
-    2. ~/.config/claude-launcher/config.json (fallback)
+    3. ~/.config/claude-launcher/config.json (fallback)
         Read from allowedDirs array
         Example: {"allowedDirs": ["/home/user/projects"]}
+        Entries may note an expiry (reported, not enforced): {"path": "~/client-x", "expires": "2026-12-31"}
+        Duplicates are dropped; "collapseNestedDirs": true also drops nested entries
+
+    Per-Project Settings (optional):
+    ~/.config/claude-launcher/config.json
+        Read from projects array; the deepest matching path wins
+        Example: {"projects": [{"path": "~/scratch", "model": "haiku"}]}
+
+    Default Arguments (optional):
+    ~/.config/claude-launcher/config.json
+        Read from defaults.extraArgs (global) and projects[].defaults.extraArgs; added to
+        every claude invocation before the profile's args and the command line, which win
+        Example: {"defaults": {"extraArgs": ["--verbose"]}}
+
+    Session Behavior (optional):
+    ~/.config/claude-launcher/config.json
+        Read from session (global) and projects[].session (per project): ask (default),
+        continue or new; --continue and --new override it
+        Example: {"projects": [{"path": "~/sandbox", "session": "new"}]}
+
+    Snapshots (optional):
+    ~/.config/claude-launcher/config.json
+        Read from snapshot (global) and projects[].snapshot (per project; off disables
+        it): stash, commit (a ref under refs/claude-launcher/snapshots/) or worktree
+        records the git working tree before each launch (and run) for 'rollback'
+        Example: {"snapshot": "commit"}
+
+    Git Working-Tree Check (optional):
+    ~/.config/claude-launcher/config.json
+        Read from gitCheck (global) and projects[].gitCheck (per project): off (default),
+        warn or block a launch when the git working tree has uncommitted changes, a
+        detached HEAD or an unfinished rebase or merge; both offer to stash the changes.
+        Without prompts (run, piped input) block refuses any unclean tree
+        Example: {"gitCheck": "warn", "projects": [{"path": "~/develop/api", "gitCheck": "block"}]}
+
+    tmux Mode (optional):
+    ~/.config/claude-launcher/config.json
+        Read from tmux (global) and projects[].tmux (per project)
+        Example: {"tmux": true}
+
+    Container Mode (optional):
+    ~/.config/claude-launcher/config.json
+        Read from container (global) and projects[].container (per project)
+        Example: {"container": {"image": "my-claude:latest", "mounts": ["~/.gitconfig:/root/.gitconfig:ro"]}}
+
+    Minimum Claude Version (optional):
+    ~/.config/claude-launcher/config.json
+        Read from minClaudeVersion; checked via 'claude --version' before launch
+        Example: {"minClaudeVersion": "2.0.0"}
+
+    Launch Profiles (optional):
+    ~/.config/claude-launcher/config.json
+        Read from profiles; each binds dir, account, model, tool, session
+        ("ask", "continue" or "new"), args, and tmux/detach/container
+        Example: {"profiles": {"backend": {"dir": "~/develop/api", "account": "Work"}}}
+
+    CLAUDE_LAUNCHER_PROFILE (optional):
+        A plain launch applies the options of this profile (except its dir and
+        args); flags on the command line win
+        Example: export CLAUDE_LAUNCHER_PROFILE=backend
+
+    Prompt Wording (optional):
+    ~/.config/claude-launcher/config.json
+        Read from prompts: session (session question), account (account menu),
+        denied (refused directory) and confirm (confirmNewDirs question); a text,
+        or one per language code
+        Example: {"prompts": {"denied": {"en": "Not approved by SecEng.", "ja": "..."}}}
+
+    Aliases (optional):
+    ~/.config/claude-launcher/config.json
+        Read from aliases; run as 'claude-launcher <NAME>'. Same options as profiles,
+        but dir is optional and the session is set with continue/new
+        Example: {"aliases": {"fix": {"preset": "yolo", "account": "Personal", "continue": true}}}
+
+    Bookmarks (optional):
+    ~/.config/claude-launcher/config.json
+        Read from bookmarks (written by 'bookmark add'); launched as '@<NAME>'
+        with the optional account and preset
+        Example: {"bookmarks": {"api": {"path": "~/develop/api", "account": "Work"}}}
+
+    Plugins (optional):
+    ~/.config/claude-launcher/config.json
+        Read from plugins; each claude-launcher-<name> on PATH listed there is sent
+        a JSON request on stdin while the config loads (it may add allowedDirs) and
+        before every launch (it may refuse it or add environment variables)
+        Example: {"plugins": ["vault", "ticket-check"]}
+
+    Launch Script (optional):
+    ~/.config/claude-launcher/config.json
+        Read from launchScript; its launch(ctx) Starlark function sees the directory,
+        account, model, args, env and git state, may change ctx.args and ctx.env,
+        and may call deny("reason") to refuse the launch
+        Example: {"launchScript": "~/.config/claude-launcher/launch.star"}
+
+    Update Check (optional):
+    ~/.config/claude-launcher/config.json
+        Read from updateCheck; interactive launches check for a newer claude-launcher
+        once a day and print a notice after claude exits (false, or
+        CLAUDE_LAUNCHER_NO_UPDATE_CHECK=1, turns it off)
+        Example: {"updateCheck": false}
+
+    Session Summary (optional):
+    ~/.config/claude-launcher/config.json
+        Read from sessionSummary; after an interactive session, its duration, exit
+        code, changed files (in git repositories), tokens and resume command are
+        printed (false turns it off)
+        Example: {"sessionSummary": false}
+
+    Log Retention (optional):
+    ~/.config/claude-launcher/config.json
+        Read from retention; the audit log, the launch history and log.file are
+        rotated at maxSize (default 10M) or once they span maxAge, and rotated logs
+        older than maxAge or beyond maxTotalSize per log (default 100M) are deleted
+        Example: {"retention": {"maxSize": "5M", "maxAge": "180d", "maxTotalSize": "50M"}}
+
+    Agent CLI (optional):
+    ~/.config/claude-launcher/config.json
+        Read from tool (global) and projects[].tool (per project); --tool overrides
+        Example: {"projects": [{"path": "~/oss/site", "tool": "codex"}]}
+
+    Environment (optional):
+    ~/.config/claude-launcher/config.json
+        Read from env.passthrough (only these inherited variables are passed) and
+        env.block (never passed); names may use * wildcards
+        Example: {"env": {"passthrough": ["PATH", "HOME", "TERM", "LANG", "LC_*"]}}
+        env.set (global, projects[].env.set, profiles.*.env.set) adds variables
+        Example: {"env": {"set": {"BASH_DEFAULT_TIMEOUT_MS": "300000"}}}
+
+    Permission Presets (optional):
+    ~/.config/claude-launcher/config.json
+        Read from permissionPresets (selected with --preset or projects[].permissionPreset)
+        and projects[].permissions; passed to Claude via --settings
+        Example: {"permissionPresets": {"review": {"disabledTools": ["Edit", "Write"]}}}
+
+    Branch Rules (optional):
+    ~/.config/claude-launcher/config.json
+        Read from projects[].branches; the first rule whose pattern (glob) matches the
+        checked-out branch denies the launch or requires a permission preset
+        Example: {"projects": [{"path": "~/api", "branches": [{"pattern": "main", "deny": true}]}]}
+
+    Project Requirements (optional):
+    ~/.config/claude-launcher/config.json
+        Read from projects[].requires; tools (with a minimum version), files and commands
+        checked before a launch. Failures are reported and the launch needs a confirmation
+        Example: {"projects": [{"path": "~/api", "requires": [{"command": "node", "version": ">=20"}]}]}
+
+    Workspaces (optional):
+    ~/.config/claude-launcher/config.json
+        Read from workspaces; each has dirs and an optional primary (the working directory)
+        Example: {"workspaces": {"backend": {"dirs": ["~/develop/api", "~/develop/libs"]}}}
+
+    Shared Directories (optional):
+    ~/.config/claude-launcher/config.json
+        allowedDirs entries with shareWithClaude are passed to Claude via --add-dir
+        Example: {"allowedDirs": ["~/develop", {"path": "~/libs", "shareWithClaude": true}]}
+        A project's linkedDirs (relative to the project) are passed for launches in it
+        Example: {"projects": [{"path": "~/develop/app", "linkedDirs": ["../shared-lib"]}]}
+
+    Resource Limits (optional, Linux):
+    ~/.config/claude-launcher/config.json
+        Read from limits (nice, cpus, memory); applied to claude and its subprocesses
+        memory uses a systemd scope (cgroup v2) when available, RLIMIT_DATA otherwise
+        In --container mode, memory and cpus are passed to the container runtime
+        Example: {"limits": {"nice": 10, "cpus": [0, 1], "memory": "4G"}}
+
+    Landlock (optional, Linux 5.13+):
+    ~/.config/claude-launcher/config.json
+        Read from landlock; with enabled, the kernel lets Claude write only to the launch
+        directory, --add-dir directories, its config dir, temp and landlock.writable
+        Not applied in --container mode or devcontainers
+        Example: {"landlock": {"enabled": true, "writable": ["~/.cache"]}}
+
+    Skip Permissions (optional):
+    ~/.config/claude-launcher/config.json
+        --dangerously-skip-permissions is only forwarded to Claude inside yoloAllowedDirs
+        Every such launch (and refusal) is logged to ~/.local/state/claude-launcher/audit.log
+        Example: {"yoloAllowedDirs": ["~/sandbox"]}
+
+    Running as Root (optional):
+    ~/.config/claude-launcher/config.json
+        Launches (and run) as root are refused unless --allow-root is passed or
+        allowRoot is set; both outcomes are logged to the audit log
+        Example: {"allowRoot": true}
+
+    First-Launch Confirmation (optional):
+    ~/.config/claude-launcher/config.json
+        With confirmNewDirs, the first launch in a directory shows its path, git remote
+        and file count and asks before starting; confirmed directories (and their
+        subdirectories) are kept in ~/.local/state/claude-launcher/confirmed-dirs.json.
+        run and piped launches cannot ask and refuse unconfirmed directories unless
+        --approve-dir confirms them
+        Example: {"confirmNewDirs": true}
+
+    Claude Binary (optional):
+    ~/.config/claude-launcher/config.json
+        Read from claudePath (a string or a list of candidates tried in order)
+        Example: {"claudePath": ["claude", "claude-code", "~/.local/bin/claude"]}
+        A claude-code version pinned in mise.toml, .mise.toml or .tool-versions is
+        resolved through mise or asdf instead (with a warning on mismatch)
+
+    Claude Installation (optional):
+    ~/.config/claude-launcher/config.json
+        When claude is missing or too old, the launcher offers to install/update it
+        Read from claudeInstall (method: "npm" or "native", command, updateCommand)
+        Example: {"claudeInstall": {"method": "native"}}
+
+    Devcontainers (optional):
+    ~/.config/claude-launcher/config.json
+        Read from devcontainer ("ask" (default), "always" or "never"): whether to launch in
+        the devcontainer of a workspace with .devcontainer/devcontainer.json
+        Example: {"devcontainer": "always"}
+
+    MCP Servers (optional):
+    ~/.config/claude-launcher/config.json
+        Read from mcpServers (global) and projects[].mcpServers (per project)
+        Passed to Claude via --mcp-config; project entries override global ones
+        Example: {"mcpServers": {"github": {"command": "github-mcp-server"}}}
+
+    Notifications (optional):
+    ~/.config/claude-launcher/config.json
+        Read from notifications (url, format "json" or "slack", modes, minDuration)
+        POSTed when a headless or detached launch finishes (modes changes which)
+        Example: {"notifications": [{"url": "op://Private/Slack/webhook", "format": "slack"}]}
 
     Multiple Accounts (optional):
     1. CLAUDE_ACCOUNTS environment variable (highest priority)
@@ -217,6 +1244,26 @@ CONFIGURATION (priority order):
             {"name": "Personal", "configDir": "~/.claude-personal"},
             {"name": "Work", "configDir": "~/.claude-work"}
         ]}
+        Accounts may set proxy (http, https, noProxy, caBundle) and env.set, applied to that
+        account only. env values may be secret references (op://VAULT/ITEM/FIELD,
+        keychain:ITEM, env:NAME), resolved at launch time
+        Accounts with "type": "apiKey" set ANTHROPIC_API_KEY from apiKey (a key or a secret
+        reference); without a configDir they get one in the state directory
+        Example: {"name": "Console", "type": "apiKey", "apiKey": "keychain:anthropic-console"}
+        backend (anthropic, bedrock, vertex) switches the backend of an account, with region,
+        awsProfile, gcpProject and models (model IDs for opus, sonnet and haiku)
+        Example: {"name": "AWS", "backend": "bedrock", "region": "us-east-1"}
+        settingsTemplate is a settings.json fragment merged into the account's config
+        directory before each launch
+
+EXIT CODES:
+    0    Success
+    1    Other error
+    2    Configuration missing or invalid
+    3    Directory not allowed
+    4    Prompt or menu cancelled
+    5    claude (or the selected tool) not found
+    Once Claude Code has run, its own exit code is returned.
 
 EXAMPLES:
     # Configure allowed directories via environment variable
@@ -231,7 +1278,7 @@ EXAMPLES:
     #   "allowedDirs": ["/home/user/develop", "/home/user/projects"],
     #   "accounts": [
     #     {"name": "Personal", "configDir": "~/.claude-personal"},
-    #     {"name": "Work", "configDir": "~/.claude-work"}
+    #     {"name": "Work", "configDir": "~/.claude-work", "model": "opus"}
     #   ]
     # }
 
@@ -241,12 +1288,56 @@ EXAMPLES:
     # Launch with specific account (skips interactive selection)
     claude-launcher --account Personal
 
+    # Launch with a specific model
+    claude-launcher --model sonnet
+
+    # Launch in another allowed directory without cd-ing there
+    claude-launcher --dir ~/develop/myproject
+
+    # Start a long-running session in the background and reattach later
+    claude-launcher --detach
+    claude-launcher attach
+
+    # Launch a saved working context
+    claude-launcher up backend
+
+    # Run a one-off prompt in a pipeline
+    git diff | claude-launcher run -a Work -p "Review this diff" > review.md
+
     # Show allowed directories
     claude-launcher --show-dirs
 `
 	fmt.Print(help)
 }
 
+// resolveTargetDir returns the absolute directory to launch in.
+// An empty dir means the current working directory.
+func resolveTargetDir(dir string) (string, error) {
+	if dir == "" {
+		return os.Getwd()
+	}
+
+	expanded, err := config.ExpandPath(dir)
+	if err != nil {
+		return "", err
+	}
+
+	absDir, err := filepath.Abs(expanded)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	info, err := os.Stat(absDir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", absDir)
+	}
+
+	return absDir, nil
+}
+
 func showVersionInformation() {
 	fmt.Printf("claude-launcher %s\n", Version)
 	fmt.Printf("  commit: %s\n", GitCommit)
@@ -294,3 +1385,554 @@ func buildLaunchOtelEnv(cfg *config.Config, selectedAccount *account.Account, no
 
 	return otelEnv
 }
+
+// showTiming prints the startup phases measured with --timing
+func showTiming(printer *ui.Printer) {
+	if timings != nil {
+		printer.ShowTiming(timings.Phases(), timings.Total())
+	}
+}
+
+// printLaunchEnv writes the environment the launched tool would receive to stdout, one entry per line
+func printLaunchEnv(l *launcher.Launcher, opts launcher.LaunchOptions, printer *ui.Printer) int {
+	cmd, err := l.Prepare(opts)
+	if err != nil {
+		printer.Error("Failed to prepare launch: %v\n", err)
+		return exitError
+	}
+	defer cmd.Cleanup()
+
+	for _, e := range slices.Sorted(slices.Values(cmd.Env)) {
+		fmt.Println(e)
+	}
+	return exitSuccess
+}
+
+// printLaunchCommand prints the claude command line opts resolve to, quoted for POSIX shells.
+// Files it names (--settings, --mcp-config) are temporary and removed before it returns.
+func printLaunchCommand(l *launcher.Launcher, opts launcher.LaunchOptions, printer *ui.Printer) int {
+	cmd, err := l.Prepare(opts)
+	if err != nil {
+		printer.Error("Failed to prepare launch: %v\n", err)
+		return exitError
+	}
+	defer cmd.Cleanup()
+
+	words := make([]string, 0, len(cmd.Args)+1)
+	for _, word := range slices.Concat([]string{cmd.Path}, cmd.Args) {
+		words = append(words, shellWord(word))
+	}
+	fmt.Println(strings.Join(words, " "))
+	return exitSuccess
+}
+
+// shellWord quotes s for POSIX shells unless it only contains characters that need no quoting
+func shellWord(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=+./:,@%") == "" {
+		return s
+	}
+	return shQuote(s)
+}
+
+// launchEnv returns the environment variables the launcher sets for claude, with secret
+// references (keychain:, op://, env:) resolved, and the names of the variables holding secrets.
+// Priority: --env flags > account API key > account proxy > account env.set > project env.set > global env.set
+func launchEnv(cfg *config.Config, project *config.Project, selectedAccount *account.Account, extra map[string]string) (map[string]string, []string, error) {
+	env := cfg.EnvFor(project)
+	if selectedAccount != nil {
+		maps.Copy(env, selectedAccount.LaunchEnv())
+	}
+	maps.Copy(env, extra)
+
+	secretEnv, err := secrets.ResolveEnv(env)
+	if err != nil {
+		return nil, nil, err
+	}
+	if selectedAccount != nil && selectedAccount.Type == account.TypeAPIKey && !slices.Contains(secretEnv, "ANTHROPIC_API_KEY") {
+		secretEnv = append(secretEnv, "ANTHROPIC_API_KEY")
+	}
+	return env, secretEnv, nil
+}
+
+// stringsFlag collects repeated string flags
+type stringsFlag []string
+
+func (s *stringsFlag) String() string { return strings.Join(*s, ",") }
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// envFlag collects repeated --env KEY=VALUE flags
+type envFlag map[string]string
+
+func (e envFlag) String() string {
+	entries := make([]string, 0, len(e))
+	for _, key := range slices.Sorted(maps.Keys(e)) {
+		entries = append(entries, key+"="+e[key])
+	}
+	return strings.Join(entries, ",")
+}
+
+func (e envFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
+	}
+	e[key] = val
+	return nil
+}
+
+// bannerParts returns the launch context shown by --banner: profile, account,
+// the matching allowedDirs entry and model, skipping the ones that do not apply
+func bannerParts(cfg *config.Config, currentDir, profile string, selectedAccount *account.Account, model string) []string {
+	var parts []string
+	if profile != "" {
+		parts = append(parts, profile)
+	}
+	if selectedAccount != nil {
+		parts = append(parts, selectedAccount.Name)
+	}
+	if rule, err := security.NewDirectoryChecker(cfg.AllowedDirs).Match(currentDir); err == nil && rule != "" {
+		parts = append(parts, tildePath(rule))
+	}
+	if model != "" {
+		parts = append(parts, model)
+	}
+	return parts
+}
+
+// tildePath abbreviates the home directory in path to ~
+func tildePath(path string) string {
+	homeDir, err := home.Dir()
+	if err != nil || homeDir == "" {
+		return path
+	}
+	if path == homeDir {
+		return "~"
+	}
+	if rest, ok := strings.CutPrefix(path, homeDir+string(filepath.Separator)); ok {
+		return "~" + string(filepath.Separator) + rest
+	}
+	return path
+}
+
+// flagOrDefault returns the flag value if the flag was set on the command line,
+// otherwise the configured default
+func flagOrDefault(fs *flag.FlagSet, name string, value, configured bool) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	if set {
+		return value
+	}
+	return configured
+}
+
+// launchTmux launches Claude in a tmux window
+func launchTmux(l *launcher.Launcher, opts launcher.LaunchOptions, printer *ui.Printer) int {
+	cmd, err := l.Prepare(opts)
+	if err != nil {
+		printer.Error("Failed to launch Claude: %v\n", err)
+		return exitError
+	}
+	// Temp files are not cleaned up: claude keeps running in tmux after this process exits
+
+	// claude's exit is not observed in tmux, so only failures to start are recorded
+	rec := launcher.NewRecord(opts)
+	if err := tmux.Launch(cmd); err != nil {
+		rec.Finish(err)
+		l.Record(rec)
+		cmd.Cleanup()
+		printer.Error("Failed to launch Claude: %v\n", err)
+		return exitError
+	}
+	l.Record(rec)
+
+	return exitSuccess
+}
+
+// authorizeLaunch resolves the target directory and checks it against allowedDirs, and
+// against yoloAllowedDirs when args contain --dangerously-skip-permissions.
+// It reports problems to the user and returns the matching allowed directory (rule) and the
+// exit code, exitSuccess if the launch may proceed.
+func authorizeLaunch(cfg *config.Config, dirFlag string, args []string, printer *ui.Printer) (currentDir, rule string, skipPermissions bool, code int) {
+	currentDir, err := resolveTargetDir(dirFlag)
+	if err != nil {
+		printer.Error("Failed to resolve target directory: %v\n", err)
+		return "", "", false, exitError
+	}
+
+	spinner := printer.Spinner()
+	rule, err = newDirectoryChecker(cfg.AllowedDirs, spinner).Match(currentDir)
+	spinner.Stop()
+	if err != nil {
+		printer.Error("Failed to check directory: %v\n", err)
+		return "", "", false, exitError
+	}
+
+	checked := event.DirectoryChecked{Dir: currentDir, Rule: rule, SkipPermissions: launcher.HasSkipPermissions(args), Args: args}
+	// --dangerously-skip-permissions is only forwarded inside yoloAllowedDirs
+	if rule != "" && checked.SkipPermissions {
+		yoloAllowed, err := security.NewDirectoryChecker(cfg.YoloAllowedDirs).IsAllowed(currentDir)
+		checked.YoloAllowed = err == nil && yoloAllowed
+	}
+	_ = events.Publish(checked) //nolint:errcheck // subscribers only observe the outcome
+
+	switch {
+	case rule == "":
+		printer.ShowAccessDenied(currentDir, cfg.AllowedDirs)
+		return "", "", false, exitDenied
+	case !checked.Allowed():
+		printer.ShowDirectoryAllowed()
+		printer.ShowSkipPermissionsDenied(currentDir, cfg.YoloAllowedDirs)
+		return "", "", false, exitDenied
+	}
+
+	printer.ShowDirectoryAllowed()
+	return currentDir, rule, checked.SkipPermissions, exitSuccess
+}
+
+// checkLaunchDir runs the checks between authorizing dir and launching there, for launches and
+// `run` alike: the confirmNewDirs question (approve records the confirmation without asking),
+// the double-launch guard, the git working-tree check and the snapshot. done reports that the
+// launch ends here with code. Without prompts nothing is asked: what would need an answer is refused.
+func checkLaunchDir(cfg *config.Config, project *config.Project, dir string, approve, noPrompts bool, prompter *session.InteractivePrompter, printer *ui.Printer) (code int, done bool) {
+	// CI workspaces are new on every run; CLAUDE_LAUNCHER_CI_ALLOW already vouches for them
+	if cfg.ConfirmNewDirs && (ciEnv == nil || !ciEnv.Runner) {
+		if code := confirmNewDir(dir, approve, noPrompts, prompter, printer); code != exitSuccess {
+			return code, true
+		}
+	}
+	if code, done := guardDoubleLaunch(dir, noPrompts, printer); done {
+		return code, true
+	}
+	if code := checkWorkTree(dir, cfg.GitCheckFor(project), noPrompts, printer); code != exitSuccess {
+		return code, true
+	}
+	if mode := cfg.SnapshotFor(project); mode != "" {
+		if code := takeSnapshot(dir, mode, printer); code != exitSuccess {
+			return code, true
+		}
+	}
+	return exitSuccess, false
+}
+
+// newLauncher creates a Launcher for tool.
+// Claude uses the configured binary candidates; other tools are looked up in PATH.
+func newLauncher(cfg *config.Config, tool launcher.Tool) *launcher.Launcher {
+	l := launcher.NewLauncher()
+	l.EnvPolicy = launcher.EnvPolicy{Passthrough: cfg.Env.Passthrough, Block: cfg.Env.Block}
+	if store, err := state.NewStore(); err == nil {
+		l.Versions = store
+	}
+	if tool != launcher.Claude {
+		l.Tool = tool
+		l.ClaudePath = tool.Binary()
+		return l
+	}
+	if len(cfg.ClaudePath) > 0 {
+		l.Candidates = cfg.ClaudePath
+	}
+	return l
+}
+
+// chooseDevcontainer returns the devcontainer workspace to launch in for dir, or "" to launch on
+// the host. --devcontainer and the devcontainer setting decide, or the user is asked. Only a
+// workspace inside the allowed directories is used, as all of it is mounted into the container.
+func chooseDevcontainer(cfg *config.Config, fs *flag.FlagSet, f *launchFlags, dir string, noPrompts bool, prompter *session.InteractivePrompter, printer *ui.Printer) (string, int) {
+	mode := cfg.Devcontainer
+	fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == "devcontainer" {
+			mode = config.DevcontainerNever
+			if f.useDevcontainer {
+				mode = config.DevcontainerAlways
+			}
+		}
+	})
+	if mode == config.DevcontainerNever {
+		return "", exitSuccess
+	}
+
+	workspace := container.FindDevcontainer(dir)
+	if workspace == "" {
+		if f.useDevcontainer {
+			printer.Error("✗ No devcontainer configuration found for %s\n", dir)
+			return "", exitNotFound
+		}
+		return "", exitSuccess
+	}
+	allowed, err := security.NewDirectoryChecker(cfg.AllowedDirs).IsAllowed(workspace)
+	if err != nil || !allowed {
+		if f.useDevcontainer {
+			printer.ShowAccessDenied(workspace, cfg.AllowedDirs)
+			return "", exitDenied
+		}
+		log.Debug("devcontainer workspace outside the allowed directories, ignoring it", "workspace", workspace)
+		return "", exitSuccess
+	}
+
+	if mode == config.DevcontainerAlways {
+		return workspace, exitSuccess
+	}
+	if noPrompts {
+		return "", exitSuccess
+	}
+	ok, err := prompter.Confirm("Launch Claude inside the project's devcontainer?", false)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return "", promptExitCode(err)
+	}
+	if !ok {
+		return "", exitSuccess
+	}
+	return workspace, exitSuccess
+}
+
+// applyVersionPin makes l run the claude version a mise or asdf file pins for dir, resolved
+// through the version manager, and warns when the pin cannot be honored
+func applyVersionPin(l *launcher.Launcher, dir string, printer *ui.Printer) {
+	pin, err := launcher.FindPin(dir)
+	if err != nil {
+		log.Warn("failed to look for a claude version pin", "error", err)
+		return
+	}
+	if pin == nil {
+		return
+	}
+
+	binary, err := pin.Which(dir)
+	if err != nil {
+		printer.Warning("⚠ %s pins claude %s, but it cannot be resolved: %v\n", tildePath(pin.File), pin.Version, err)
+		return
+	}
+	log.Debug("claude version pinned", "file", pin.File, "version", pin.Version, "binary", binary)
+	l.Candidates = []string{binary}
+
+	if version, err := launcher.CachedClaudeVersion(l.Versions, binary); err == nil && !pin.Matches(version) {
+		printer.Warning("⚠ %s pins claude %s, but the resolved claude is %s\n", tildePath(pin.File), pin.Version, version)
+	}
+}
+
+// preflight runs the pre-flight checks of l, showing each step on a progress line
+func preflight(l *launcher.Launcher, minVersion string, printer *ui.Printer) error {
+	spinner := printer.Spinner()
+	defer spinner.Stop()
+
+	l.Progress = spinner.Update
+	defer func() { l.Progress = nil }()
+
+	_, err := l.Preflight(minVersion)
+	return err
+}
+
+// newDirectoryChecker creates a checker for dirs that reports its progress on spinner
+func newDirectoryChecker(dirs []string, spinner *ui.Spinner) *security.DirectoryChecker {
+	checker := security.NewDirectoryChecker(dirs)
+	checker.Progress = func(done, total int, _ string) {
+		spinner.Update("Checking allowed directories (%d/%d)", done+1, total)
+	}
+	return checker
+}
+
+// authorizeAddDirs resolves the --add-dir directories and checks that each is allowed.
+// It returns the exit code, exitSuccess if all of them are.
+func authorizeAddDirs(cfg *config.Config, dirs []string, printer *ui.Printer) ([]string, int) {
+	resolved := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		expanded, err := config.ExpandPath(dir)
+		if err != nil {
+			printer.Error("Invalid --add-dir %s: %v\n", dir, err)
+			return nil, exitError
+		}
+		abs, err := filepath.Abs(expanded)
+		if err != nil {
+			printer.Error("Invalid --add-dir %s: %v\n", dir, err)
+			return nil, exitError
+		}
+
+		spinner := printer.Spinner()
+		allowed, err := newDirectoryChecker(cfg.AllowedDirs, spinner).IsAllowed(abs)
+		spinner.Stop()
+		if err != nil || !allowed {
+			printer.ShowAccessDenied(abs, cfg.AllowedDirs)
+			return nil, exitDenied
+		}
+		resolved = append(resolved, abs)
+	}
+	return resolved, exitSuccess
+}
+
+// authorizeLinkedDirs returns the linkedDirs of project to pass with --add-dir, refusing the
+// launch when one is outside the allowed directories. Missing ones (e.g. a sibling that is not
+// cloned yet) are skipped with a warning.
+func authorizeLinkedDirs(cfg *config.Config, project *config.Project, printer *ui.Printer) ([]string, int) {
+	if project == nil {
+		return nil, exitSuccess
+	}
+
+	var dirs []string
+	for _, dir := range project.LinkedDirs {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			printer.Warning("⚠ Linked directory %s does not exist, launching without it\n", tildePath(dir))
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+
+	linked, code := authorizeAddDirs(cfg, dirs, printer)
+	if code == exitDenied {
+		printer.Print("Remove it from linkedDirs of %s, or add it to allowedDirs.\n", tildePath(project.Path))
+	}
+	return linked, code
+}
+
+// mergeDirs concatenates directory lists, dropping duplicates
+func mergeDirs(lists ...[]string) []string {
+	var merged []string
+	for _, dir := range slices.Concat(lists...) {
+		if !slices.Contains(merged, dir) {
+			merged = append(merged, dir)
+		}
+	}
+	return merged
+}
+
+// launchExitCode returns claude's own exit code when it ran and failed, and otherwise
+// reports why it could not be run
+func launchExitCode(printer *ui.Printer, err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	printer.Error("Failed to launch Claude: %v\n", err)
+	return exitError
+}
+
+// promptExitCode returns the exit code for a failed prompt: exitAborted when the user cancelled it
+func promptExitCode(err error) int {
+	if errors.Is(err, ui.ErrAborted) {
+		return exitAborted
+	}
+	return exitError
+}
+
+// showPreflightError prints an actionable message for a failed pre-flight check and
+// returns the exit code
+func showPreflightError(printer *ui.Printer, l *launcher.Launcher, err error) int {
+	var tooOld *launcher.VersionTooOldError
+	switch {
+	case errors.Is(err, launcher.ErrClaudeNotFound) && l.Tool != nil:
+		printer.ShowToolNotFound(l.Tool.Name())
+		return exitNotFound
+	case errors.Is(err, launcher.ErrClaudeNotFound):
+		printer.ShowClaudeNotFound(l.Candidates)
+		return exitNotFound
+	case errors.As(err, &tooOld):
+		printer.ShowClaudeTooOld(tooOld.Found, tooOld.Minimum)
+	default:
+		printer.Error("Failed to check claude: %v\n", err)
+	}
+	return exitError
+}
+
+// offerInstall offers to install (or update) claude after a failed pre-flight check.
+// It returns true if the command ran successfully.
+func offerInstall(prompter *session.InteractivePrompter, printer *ui.Printer, ci config.ClaudeInstall, preflightErr error) bool {
+	opts := installer.Options{Method: ci.Method, Command: ci.Command, UpdateCommand: ci.UpdateCommand}
+
+	var commandLine string
+	var tooOld *launcher.VersionTooOldError
+	switch {
+	case errors.Is(preflightErr, launcher.ErrClaudeNotFound):
+		var err error
+		commandLine, err = opts.InstallCommand()
+		if err != nil {
+			printer.Error("Invalid claudeInstall config: %v\n", err)
+			return false
+		}
+	case errors.As(preflightErr, &tooOld):
+		commandLine = opts.UpdateCommandLine()
+	default:
+		return false
+	}
+
+	confirmed, err := prompter.Confirm(fmt.Sprintf(i18n.T("Run '%s' now?"), commandLine), false)
+	if err != nil || !confirmed {
+		return false
+	}
+
+	if err := installer.Run(commandLine); err != nil {
+		printer.Error("%v\n", err)
+		return false
+	}
+
+	printer.Print("\n")
+	return true
+}
+
+// containerWrapper returns a launcher wrap function running claude in a container
+func containerWrapper(cc config.ContainerConfig, lim config.Limits, configDir string) func(*launcher.Command) (*launcher.Command, error) {
+	return func(c *launcher.Command) (*launcher.Command, error) {
+		return container.Wrap(c, container.Options{
+			Runtime:   cc.Runtime,
+			Image:     cc.Image,
+			Mounts:    cc.Mounts,
+			ConfigDir: configDir,
+			Memory:    lim.Memory,
+			CPUs:      lim.CPUs,
+		})
+	}
+}
+
+// limitsWrapper returns a launcher.Launcher Wrap func that applies the configured resource limits
+// and, when writable is not empty, restricts claude's writes to it
+func limitsWrapper(lim config.Limits, writable []string) func(*launcher.Command) (*launcher.Command, error) {
+	return func(c *launcher.Command) (*launcher.Command, error) {
+		return limits.Wrap(c, limits.Options{Nice: lim.Nice, CPUs: lim.CPUs, Memory: lim.Memory, Writable: writable})
+	}
+}
+
+// withDefaultArgs puts the defaults.extraArgs of a launch of tool in project in front of args.
+// They are claude arguments, so other tools do not get them.
+func withDefaultArgs(cfg *config.Config, project *config.Project, tool launcher.Tool, args []string) []string {
+	if tool != launcher.Claude {
+		return args
+	}
+	extra := cfg.ExtraArgsFor(project)
+	if len(extra) > 0 {
+		log.Debug("default arguments added", "args", strings.Join(extra, " "))
+	}
+	return slices.Concat(extra, args)
+}
+
+// accountForModel returns the account whose default model applies to tool.
+// Account models name Claude models, so other tools ignore them.
+func accountForModel(tool launcher.Tool, selectedAccount *account.Account) *account.Account {
+	if tool != launcher.Claude {
+		return nil
+	}
+	return selectedAccount
+}
+
+// resolveModel picks the model to launch with.
+// Priority: --model flag > project model > account model
+func resolveModel(flagModel string, project *config.Project, selectedAccount *account.Account) string {
+	if flagModel != "" {
+		return flagModel
+	}
+
+	if project != nil && project.Model != "" {
+		return project.Model
+	}
+
+	if selectedAccount != nil {
+		return selectedAccount.Model
+	}
+
+	return ""
+}
@@ -1,10 +1,18 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/23prime/claude-launcher/internal/account"
 	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/ui"
 )
 
 func TestBuildLaunchOtelEnv_NoOtel(t *testing.T) {
@@ -62,3 +70,335 @@ func TestBuildLaunchOtelEnv_NilAccount(t *testing.T) {
 		t.Errorf("expected OTEL_METRICS_EXPORTER=otlp, got %v", result["OTEL_METRICS_EXPORTER"])
 	}
 }
+
+func TestResolveModel(t *testing.T) {
+	project := &config.Project{Path: "/tmp/scratch", Model: "haiku"}
+	acc := &account.Account{Name: "test", ConfigDir: "/tmp", Model: "opus"}
+
+	tests := []struct {
+		name      string
+		flagModel string
+		project   *config.Project
+		account   *account.Account
+		expected  string
+	}{
+		{name: "flag wins", flagModel: "sonnet", project: project, account: acc, expected: "sonnet"},
+		{name: "project over account", project: project, account: acc, expected: "haiku"},
+		{name: "account fallback", project: &config.Project{Path: "/tmp"}, account: acc, expected: "opus"},
+		{name: "nothing configured", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := resolveModel(tt.flagModel, tt.project, tt.account)
+			if result != tt.expected {
+				t.Errorf("resolveModel() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveTargetDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "file.txt")
+	if err := os.WriteFile(filePath, []byte("x"), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		dir      string
+		expected string
+		wantErr  bool
+	}{
+		{name: "empty uses current directory", dir: "", expected: cwd},
+		{name: "existing directory", dir: tmpDir, expected: tmpDir},
+		{name: "nonexistent directory", dir: filepath.Join(tmpDir, "missing"), wantErr: true},
+		{name: "file is rejected", dir: filePath, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := resolveTargetDir(tt.dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveTargetDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && result != tt.expected {
+				t.Errorf("resolveTargetDir() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildPrintArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		prompt   string
+		extra    []string
+		expected []string
+	}{
+		{name: "prompt", prompt: "hello", expected: []string{"-p", "hello"}},
+		{name: "prompt from stdin", expected: []string{"-p"}},
+		{name: "extra args", prompt: "hello", extra: []string{"--output-format", "json"}, expected: []string{"-p", "hello", "--output-format", "json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildPrintArgs(tt.prompt, tt.extra); !slices.Equal(got, tt.expected) {
+				t.Errorf("buildPrintArgs() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProfileArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  config.Profile
+		extra    []string
+		expected []string
+	}{
+		{
+			name:     "directory only",
+			profile:  config.Profile{Dir: "/home/user/api"},
+			expected: []string{"--dir", "/home/user/api", "--"},
+		},
+		{
+			name: "full profile",
+			profile: config.Profile{
+				Dir: "/home/user/api", Account: "Work", Model: "opus", Session: config.SessionContinue,
+				Args: []string{"--verbose"}, Tmux: true,
+			},
+			extra: []string{"--debug"},
+			expected: []string{
+				"--dir", "/home/user/api", "--account", "Work", "--model", "opus", "--continue", "--tmux",
+				"--", "--verbose", "--debug",
+			},
+		},
+		{
+			name:     "new session in background",
+			profile:  config.Profile{Dir: "/home/user/api", Session: config.SessionNew, Detach: true},
+			expected: []string{"--dir", "/home/user/api", "--new", "--detach", "--"},
+		},
+		{
+			name:     "other tool",
+			profile:  config.Profile{Dir: "/home/user/api", Tool: "codex"},
+			expected: []string{"--dir", "/home/user/api", "--tool", "codex", "--"},
+		},
+		{
+			name:     "permission preset",
+			profile:  config.Profile{Dir: "/home/user/api", Preset: "review"},
+			expected: []string{"--dir", "/home/user/api", "--preset", "review", "--"},
+		},
+		{
+			name:     "alias without a directory",
+			profile:  config.Alias{Preset: "yolo", Account: "Personal", Continue: true}.Profile(),
+			expected: []string{"--account", "Personal", "--preset", "yolo", "--continue", "--"},
+		},
+		{
+			name: "environment variables",
+			profile: config.Profile{
+				Dir: "/home/user/api",
+				Env: config.EnvSet{Set: map[string]string{"B": "2", "A": "1=x"}},
+			},
+			expected: []string{"--dir", "/home/user/api", "--env", "A=1=x", "--env", "B=2", "--"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := profileArgs(tt.profile, tt.extra); !slices.Equal(got, tt.expected) {
+				t.Errorf("profileArgs() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEnvFlag(t *testing.T) {
+	env := envFlag{}
+	for _, value := range []string{"A=1", "B=x=y", "A=2"} {
+		if err := env.Set(value); err != nil {
+			t.Fatalf("Set(%q) error = %v", value, err)
+		}
+	}
+	if env.String() != "A=2,B=x=y" {
+		t.Errorf("envFlag = %q, expected A=2,B=x=y", env.String())
+	}
+
+	for _, value := range []string{"NOVALUE", "=1"} {
+		if err := env.Set(value); err == nil {
+			t.Errorf("Set(%q) should fail", value)
+		}
+	}
+}
+
+func TestLaunchEnv(t *testing.T) {
+	cfg := &config.Config{Env: config.EnvConfig{EnvSet: config.EnvSet{Set: map[string]string{"A": "global", "B": "global", "C": "global"}}}}
+	project := &config.Project{Env: config.EnvSet{Set: map[string]string{"B": "project", "C": "project"}}}
+	acc := &account.Account{
+		Env:   config.EnvSet{Set: map[string]string{"C": "account", "HTTPS_PROXY": "account"}},
+		Proxy: account.Proxy{HTTPS: "http://proxy:3128"},
+	}
+
+	env, secretEnv, err := launchEnv(cfg, project, acc, map[string]string{"D": "flag"})
+	if err != nil {
+		t.Fatalf("launchEnv() error = %v", err)
+	}
+	expected := map[string]string{
+		"A": "global", "B": "project", "C": "account", "D": "flag",
+		"HTTPS_PROXY": "http://proxy:3128", "https_proxy": "http://proxy:3128",
+	}
+	if !maps.Equal(env, expected) {
+		t.Errorf("launchEnv() = %v, expected %v", env, expected)
+	}
+	if len(secretEnv) != 0 {
+		t.Errorf("secretEnv = %v, expected none without references", secretEnv)
+	}
+}
+
+func TestWorkspaceArgs(t *testing.T) {
+	ws := config.Workspace{Dirs: []string{"/home/user/api", "/home/user/libs", "/home/user/web"}}
+
+	args := workspaceArgs(ws, "/home/user/libs", []string{"--verbose"})
+	expected := []string{"--dir", "/home/user/libs", "--add-dir", "/home/user/api", "--add-dir", "/home/user/web", "--", "--verbose"}
+	if !slices.Equal(args, expected) {
+		t.Errorf("workspaceArgs() = %v, expected %v", args, expected)
+	}
+}
+
+func TestMergeDirs(t *testing.T) {
+	merged := mergeDirs([]string{"/a", "/b"}, []string{"/b", "/c"})
+	if !slices.Equal(merged, []string{"/a", "/b", "/c"}) {
+		t.Errorf("mergeDirs() = %v, expected [/a /b /c]", merged)
+	}
+}
+
+func TestWithDefaultArgs(t *testing.T) {
+	cfg := &config.Config{Defaults: config.Defaults{ExtraArgs: []string{"--verbose"}}}
+	project := &config.Project{Defaults: config.Defaults{ExtraArgs: []string{"--model", "opus"}}}
+
+	got := withDefaultArgs(cfg, project, launcher.Claude, []string{"-p", "hi"})
+	if expected := []string{"--verbose", "--model", "opus", "-p", "hi"}; !slices.Equal(got, expected) {
+		t.Errorf("withDefaultArgs() = %v, expected %v", got, expected)
+	}
+
+	other, err := launcher.LookupTool("codex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := withDefaultArgs(cfg, project, other, []string{"exec"}); !slices.Equal(got, []string{"exec"}) {
+		t.Errorf("withDefaultArgs() for another tool = %v, expected the arguments unchanged", got)
+	}
+}
+
+func TestShellWord(t *testing.T) {
+	for word, expected := range map[string]string{
+		"--settings":   "--settings",
+		"/tmp/a.json":  "/tmp/a.json",
+		"--model=opus": "--model=opus",
+		"hi there":     "'hi there'",
+		"it's":         `'it'\''s'`,
+		"":             "''",
+		"$HOME":        "'$HOME'",
+	} {
+		if got := shellWord(word); got != expected {
+			t.Errorf("shellWord(%q) = %s, expected %s", word, got, expected)
+		}
+	}
+}
+
+func TestApplyGlobalFlags(t *testing.T) {
+	t.Cleanup(func() { _ = ui.SetMode(ui.ModeAuto) }) //nolint:errcheck // auto is always valid
+
+	args, err := applyGlobalFlags([]string{"--no-color", "-a", "Work", "-q", "--ui", "plain", "--ui=auto", "--", "--no-color"})
+	if err != nil {
+		t.Fatalf("applyGlobalFlags() error = %v", err)
+	}
+	expected := []string{"-a", "Work", "--", "--no-color"}
+	if !slices.Equal(args, expected) {
+		t.Errorf("applyGlobalFlags() = %v, expected %v", args, expected)
+	}
+
+	for _, bad := range [][]string{{"--ui"}, {"--ui=fancy"}} {
+		if _, err := applyGlobalFlags(bad); err == nil {
+			t.Errorf("applyGlobalFlags(%v) should fail", bad)
+		}
+	}
+}
+
+func TestBannerParts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	work := filepath.Join(home, "work")
+	api := filepath.Join(work, "api")
+	if err := os.MkdirAll(api, 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	cfg := &config.Config{AllowedDirs: []string{work}}
+
+	got := bannerParts(cfg, api, "review", &account.Account{Name: "Work"}, "sonnet")
+	expected := []string{"review", "Work", "~" + string(filepath.Separator) + "work", "sonnet"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("bannerParts() = %v, expected %v", got, expected)
+	}
+
+	got = bannerParts(cfg, api, "", nil, "")
+	if !slices.Equal(got, expected[2:3]) {
+		t.Errorf("bannerParts() = %v, expected only the allow rule", got)
+	}
+}
+
+func TestPromptExitCode(t *testing.T) {
+	if code := promptExitCode(fmt.Errorf("account selection failed: %w", ui.ErrAborted)); code != exitAborted {
+		t.Errorf("promptExitCode(aborted) = %d, expected %d", code, exitAborted)
+	}
+	if code := promptExitCode(errors.New("read error")); code != exitError {
+		t.Errorf("promptExitCode(other) = %d, expected %d", code, exitError)
+	}
+}
+
+func TestIsPrintMode(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{[]string{"-p", "review this"}, true},
+		{[]string{"--model", "opus", "--print"}, true},
+		{[]string{"review this"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isPrintMode(tt.args); got != tt.expected {
+			t.Errorf("isPrintMode(%v) = %v, expected %v", tt.args, got, tt.expected)
+		}
+	}
+}
+
+func TestPromptOverrides(t *testing.T) {
+	prompts := map[string]config.PromptText{
+		config.PromptSession: {"": "Resume?"},
+		config.PromptDenied:  {"en": "Not approved by SecEng.", "ja": "SecEng の承認がありません。"},
+		config.PromptAccount: {"ja": "アカウント"},
+	}
+
+	got := promptOverrides(prompts, "ja")
+	want := map[string]string{
+		"Continue previous Claude session?":                    "Resume?",
+		"Claude Code is not allowed to run in this directory.": "SecEng の承認がありません。",
+		"Select Claude account":                                "アカウント",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("promptOverrides(ja) = %q, want %q", got, want)
+	}
+
+	// Texts for other languages only are left to the catalogs
+	got = promptOverrides(prompts, "fr")
+	if len(got) != 2 || got["Claude Code is not allowed to run in this directory."] != "Not approved by SecEng." {
+		t.Errorf("promptOverrides(fr) = %q, expected the English texts", got)
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/mcp"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// mcpCommands maps the `mcp` subcommands to their entry points
+var mcpCommands = map[string]func(args []string) int{
+	"list":   runMCPList,
+	"add":    runMCPAdd,
+	"remove": runMCPRemove,
+	"test":   runMCPTest,
+}
+
+// runMCP implements `claude-launcher mcp <COMMAND>`
+func runMCP(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	commands := strings.Join(slices.Sorted(maps.Keys(mcpCommands)), ", ")
+	if len(args) == 0 {
+		printer.Error("Usage: claude-launcher mcp <COMMAND> (available: %s)\n", commands)
+		return exitError
+	}
+	cmd, ok := mcpCommands[args[0]]
+	if !ok {
+		printer.Error("Unknown mcp command %q (available: %s)\n", args[0], commands)
+		return exitError
+	}
+	return cmd(args[1:])
+}
+
+// mcpServerJSON is one server in the `mcp list --json` output
+type mcpServerJSON struct {
+	Name    string `json:"name"`
+	Project string `json:"project,omitempty"` // Empty for global servers
+	config.MCPServer
+}
+
+// newMCPServersJSON builds the `mcp list --json` output: the global servers, then those of each
+// project, sorted by name
+func newMCPServersJSON(cfg *config.Config) []mcpServerJSON {
+	out := []mcpServerJSON{}
+	add := func(project string, servers map[string]config.MCPServer) {
+		for _, name := range slices.Sorted(maps.Keys(servers)) {
+			out = append(out, mcpServerJSON{Name: name, Project: project, MCPServer: servers[name]})
+		}
+	}
+	add("", cfg.MCPServers)
+	for _, proj := range cfg.Projects {
+		add(proj.Path, proj.MCPServers)
+	}
+	return out
+}
+
+// runMCPList implements `claude-launcher mcp list`
+func runMCPList(args []string) int {
+	fs := flag.NewFlagSet("mcp list", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+	if jsonOutput {
+		return printJSON(newMCPServersJSON(cfg))
+	}
+	ui.NewPrinter(os.Stdout).ShowMCPServers(cfg.MCPServers, cfg.Projects)
+	return exitSuccess
+}
+
+// mcpAddFlags holds the options of `mcp add`
+type mcpAddFlags struct {
+	project, url, transport string
+	env, headers            envFlag
+}
+
+// newMCPAddFlags defines the options of `mcp add`
+func newMCPAddFlags() (*flag.FlagSet, *mcpAddFlags) {
+	fs := flag.NewFlagSet("mcp add", flag.ContinueOnError)
+	f := &mcpAddFlags{env: envFlag{}, headers: envFlag{}}
+	fs.StringVar(&f.project, "project", "", "Add the server to the projects entry of `DIR` (created if missing) instead of the global servers")
+	fs.StringVar(&f.url, "url", "", "`URL` of a remote server, instead of a command")
+	fs.StringVar(&f.transport, "type", "", "Transport (`TYPE`): stdio for commands, http (default for --url) or sse")
+	fs.Var(f.env, "env", "Environment variable for the server command as `KEY=VALUE` (repeatable)")
+	fs.Var(f.headers, "header", "HTTP header for a remote server as `KEY=VALUE` (repeatable)")
+	return fs, f
+}
+
+// runMCPAdd implements `claude-launcher mcp add <NAME> [OPTIONS] [-- COMMAND [ARGS...]]`
+func runMCPAdd(args []string) int {
+	fs, f := newMCPAddFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	// Accept the options after the name as well; the rest is the command
+	name := fs.Arg(0)
+	if fs.NArg() > 0 {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return exitError
+		}
+	}
+	command := fs.Args()
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if name == "" || (len(command) == 0) == (f.url == "") {
+		printer.Error("Usage: claude-launcher mcp add <NAME> [OPTIONS] (--url URL | -- COMMAND [ARGS...])\n")
+		return exitError
+	}
+
+	server := config.MCPServer{Type: f.transport, URL: f.url}
+	if len(command) > 0 {
+		server.Command, server.Args = command[0], command[1:]
+	} else if server.Type == "" {
+		server.Type = "http"
+	}
+	if len(f.env) > 0 {
+		server.Env = f.env
+	}
+	if len(f.headers) > 0 {
+		server.Headers = f.headers
+	}
+
+	project, ok := mcpProject(f.project, printer)
+	if !ok {
+		return exitError
+	}
+	if err := config.SetMCPServer("", project, name, server); err != nil {
+		printer.Error("Failed to add MCP server: %v\n", err)
+		return exitError
+	}
+
+	if project == "" {
+		printer.Success("✓ Added global MCP server %s\n", name)
+	} else {
+		printer.Success("✓ Added MCP server %s to %s\n", name, project)
+	}
+	printer.Print("Check that it starts with: claude-launcher mcp test %s\n", name)
+	return exitSuccess
+}
+
+// mcpRemoveFlags holds the options of `mcp remove`
+type mcpRemoveFlags struct {
+	project string
+}
+
+// newMCPRemoveFlags defines the options of `mcp remove`
+func newMCPRemoveFlags() (*flag.FlagSet, *mcpRemoveFlags) {
+	fs := flag.NewFlagSet("mcp remove", flag.ContinueOnError)
+	f := &mcpRemoveFlags{}
+	fs.StringVar(&f.project, "project", "", "Remove the server from the projects entry of `DIR` instead of the global servers")
+	return fs, f
+}
+
+// runMCPRemove implements `claude-launcher mcp remove <NAME> [--project DIR]`
+func runMCPRemove(args []string) int {
+	fs, f := newMCPRemoveFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	name := fs.Arg(0)
+	if fs.NArg() > 0 {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return exitError
+		}
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if name == "" || fs.NArg() > 0 {
+		printer.Error("Usage: claude-launcher mcp remove <NAME> [--project DIR]\n")
+		return exitError
+	}
+
+	project, ok := mcpProject(f.project, printer)
+	if !ok {
+		return exitError
+	}
+	if err := config.RemoveMCPServer("", project, name); err != nil {
+		printer.Error("Failed to remove MCP server: %v\n", err)
+		return exitError
+	}
+	printer.Success("✓ Removed MCP server %s\n", name)
+	return exitSuccess
+}
+
+// mcpProject resolves --project to the path written into the projects entry ("" for the
+// global servers)
+func mcpProject(dir string, printer *ui.Printer) (string, bool) {
+	if dir == "" {
+		return "", true
+	}
+	resolved, err := resolveTargetDir(dir)
+	if err != nil {
+		printer.Error("Failed to resolve project directory: %v\n", err)
+		return "", false
+	}
+	return tildePath(resolved), true
+}
+
+// mcpTestFlags holds the options of `mcp test`
+type mcpTestFlags struct {
+	dir     string
+	timeout time.Duration
+}
+
+// newMCPTestFlags defines the options of `mcp test`
+func newMCPTestFlags() (*flag.FlagSet, *mcpTestFlags) {
+	fs := flag.NewFlagSet("mcp test", flag.ContinueOnError)
+	f := &mcpTestFlags{}
+	fs.StringVar(&f.dir, "d", "", "Directory (`DIR`) whose project servers apply (defaults to the current directory)")
+	fs.StringVar(&f.dir, "dir", "", "Directory whose project servers apply (long form)")
+	fs.DurationVar(&f.timeout, "timeout", 30*time.Second, "How long (`DURATION`) each server may take to answer")
+	return fs, f
+}
+
+// runMCPTest implements `claude-launcher mcp test [NAME...]`: it starts each server a launch in
+// the directory would get (or the named ones) and performs the MCP initialize handshake
+func runMCPTest(args []string) int {
+	fs, f := newMCPTestFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+	currentDir, err := resolveTargetDir(f.dir)
+	if err != nil {
+		printer.Error("Failed to resolve target directory: %v\n", err)
+		return exitError
+	}
+
+	servers := cfg.MCPServersFor(cfg.FindProject(currentDir))
+	names := fs.Args()
+	if len(names) == 0 {
+		if len(servers) == 0 {
+			printer.Print("No MCP servers configured.\n")
+			return exitSuccess
+		}
+		names = slices.Sorted(maps.Keys(servers))
+	}
+
+	code := exitSuccess
+	for _, name := range names {
+		server, ok := servers[name]
+		if !ok {
+			printer.Error("✗ %s: not configured for %s\n", name, currentDir)
+			code = exitError
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+		result, err := mcp.Test(ctx, server, Version)
+		cancel()
+		if err != nil {
+			printer.Error("✗ %s: %v\n", name, err)
+			code = exitError
+			continue
+		}
+		printer.Success("✓ %s: %s %s (protocol %s)\n", name, result.ServerInfo.Name, result.ServerInfo.Version, result.ProtocolVersion)
+		if capabilities := result.CapabilityNames(); len(capabilities) > 0 {
+			printer.Print("    Capabilities: %s\n", strings.Join(capabilities, ", "))
+		}
+	}
+	return code
+}
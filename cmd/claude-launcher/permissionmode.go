@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/23prime/claude-launcher/internal/config"
+)
+
+// permissionModeFlag returns the permission mode chosen with --plan or --permission-mode, or ""
+// to keep the defaultMode of the project and preset
+func permissionModeFlag(plan bool, mode string) (string, error) {
+	if plan {
+		if mode != "" && mode != "plan" {
+			return "", fmt.Errorf("--plan and --permission-mode %s cannot be used together", mode)
+		}
+		return "plan", nil
+	}
+	if mode == "" {
+		return "", nil
+	}
+	if err := config.ValidatePermissionMode(mode); err != nil {
+		return "", err
+	}
+	return mode, nil
+}
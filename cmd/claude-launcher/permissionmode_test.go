@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestPermissionModeFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		plan    bool
+		mode    string
+		want    string
+		wantErr bool
+	}{
+		{name: "none"},
+		{name: "--plan", plan: true, want: "plan"},
+		{name: "--permission-mode", mode: "acceptEdits", want: "acceptEdits"},
+		{name: "both agreeing", plan: true, mode: "plan", want: "plan"},
+		{name: "both conflicting", plan: true, mode: "acceptEdits", wantErr: true},
+		{name: "unknown", mode: "planning", wantErr: true},
+		{name: "bypass", mode: "bypassPermissions", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := permissionModeFlag(tt.plan, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("permissionModeFlag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("permissionModeFlag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// Sort orders of the pick menu
+const (
+	pickSortConfig = "config" // Allowed directories in config order, each followed by its repositories
+	pickSortName   = "name"   // By directory name
+	pickSortRecent = "recent" // Most recently launched first, then the others in config order
+)
+
+// pickSorts are the sort orders accepted by `pick --sort`
+var pickSorts = []string{pickSortConfig, pickSortName, pickSortRecent}
+
+// runPick implements `claude-launcher pick [--sort ORDER]`: a searchable menu of the allowed
+// directories and the git repositories directly inside them. The chosen directory goes through
+// the normal launch. A sort order given with --sort is remembered for the next pick.
+func runPick(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	order, args, err := cutPickSort(args)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return exitError
+	}
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	dirs := pickTargets(cfg.AllowedDirs)
+	if len(dirs) == 0 {
+		printer.Error("✗ None of the allowed directories exists\n")
+		printer.ShowAllowedDirs(cfg.AllowedDirs)
+		return exitConfig
+	}
+
+	var launched map[string]time.Time
+	if store, err := state.NewStore(); err == nil {
+		order = rememberPickSort(store, order)
+		if order == pickSortRecent {
+			records, err := launcher.History(store)
+			if err != nil {
+				log.Debug("launch history unavailable", "error", err)
+			}
+			launched = lastLaunches(records)
+		}
+	}
+	sortPickTargets(dirs, order, launched)
+
+	items := make([]string, len(dirs))
+	for i, dir := range dirs {
+		items[i] = ui.TruncatePath(dir, ui.MenuWidth())
+	}
+	idx, err := ui.Search(i18n.T("Select project (type to search)"), items)
+	if err != nil {
+		printer.Error("Failed to select project: %v\n", err)
+		return promptExitCode(err)
+	}
+
+	return launch(slices.Concat([]string{"--dir", dirs[idx], "--"}, args))
+}
+
+// cutPickSort removes a leading --sort ORDER (or --sort=ORDER) from args, which are otherwise
+// passed to claude. It returns "" when args do not start with it.
+func cutPickSort(args []string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", args, nil
+	}
+	order, ok := strings.CutPrefix(args[0], "--sort=")
+	switch {
+	case ok:
+		args = args[1:]
+	case args[0] == "--sort":
+		if len(args) < 2 {
+			return "", nil, fmt.Errorf("--sort requires an order (%s)", strings.Join(pickSorts, ", "))
+		}
+		order, args = args[1], args[2:]
+	default:
+		return "", args, nil
+	}
+	if !slices.Contains(pickSorts, order) {
+		return "", nil, fmt.Errorf("unknown sort order %q (available: %s)", order, strings.Join(pickSorts, ", "))
+	}
+	return order, args, nil
+}
+
+// rememberPickSort returns the sort order of this pick: order when given, which is then saved
+// in the preferences, or else the saved one
+func rememberPickSort(store *state.Store, order string) string {
+	prefs, err := store.Prefs()
+	if err != nil {
+		log.Debug("preferences unavailable", "error", err)
+		return cmp.Or(order, pickSortConfig)
+	}
+	if order == "" {
+		return cmp.Or(prefs.PickSort, pickSortConfig)
+	}
+	if order != prefs.PickSort {
+		prefs.PickSort = order
+		if err := store.SavePrefs(prefs); err != nil {
+			log.Debug("sort order not remembered", "error", err)
+		}
+	}
+	return order
+}
+
+// lastLaunches returns when each directory in records was last launched
+func lastLaunches(records []launcher.LaunchRecord) map[string]time.Time {
+	last := map[string]time.Time{}
+	for _, rec := range records {
+		if rec.StartedAt.After(last[rec.Dir]) {
+			last[rec.Dir] = rec.StartedAt
+		}
+	}
+	return last
+}
+
+// sortPickTargets sorts dirs, listed by pickTargets, in order. For pickSortRecent, launched
+// holds when each directory was last launched; the others keep their order after them.
+func sortPickTargets(dirs []string, order string, launched map[string]time.Time) {
+	switch order {
+	case pickSortName:
+		slices.SortStableFunc(dirs, func(a, b string) int {
+			return cmp.Or(
+				cmp.Compare(strings.ToLower(filepath.Base(a)), strings.ToLower(filepath.Base(b))),
+				cmp.Compare(a, b),
+			)
+		})
+	case pickSortRecent:
+		slices.SortStableFunc(dirs, func(a, b string) int {
+			return launched[b].Compare(launched[a])
+		})
+	}
+}
+
+// pickTargets lists every existing allowed directory followed by its subdirectories that are
+// git repositories (with a .git directory, or a .git file for worktrees). Hidden directories
+// are skipped, and a directory reachable from two allowed directories is listed once.
+func pickTargets(allowedDirs []string) []string {
+	var targets []string
+	seen := map[string]bool{}
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			targets = append(targets, dir)
+		}
+	}
+
+	for _, root := range allowedDirs {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			log.Debug("allowed directory not listed", "dir", root, "error", err)
+			continue
+		}
+		add(root)
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			dir := filepath.Join(root, entry.Name())
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+				add(dir)
+			}
+		}
+	}
+	return targets
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPickTargets(t *testing.T) {
+	root := t.TempDir()
+	develop := filepath.Join(root, "develop")
+	for _, dir := range []string{"api/.git", "web", ".hidden/.git", "notes"} {
+		if err := os.MkdirAll(filepath.Join(develop, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A worktree has a .git file
+	if err := os.WriteFile(filepath.Join(develop, "web", ".git"), []byte("gitdir: ../api/.git/worktrees/web\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(develop, "README.md"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	api := filepath.Join(develop, "api")
+	got := pickTargets([]string{filepath.Join(root, "missing"), develop, api})
+	want := []string{develop, api, filepath.Join(develop, "web")}
+	if !slices.Equal(got, want) {
+		t.Errorf("pickTargets() = %v, want %v", got, want)
+	}
+}
+
+func TestSortPickTargets(t *testing.T) {
+	dirs := []string{"/work", "/work/web", "/other/API", "/work/api"}
+	now := time.Now()
+	launched := map[string]time.Time{"/work/api": now, "/work": now.Add(-time.Hour)}
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{order: pickSortConfig, want: []string{"/work", "/work/web", "/other/API", "/work/api"}},
+		{order: pickSortName, want: []string{"/other/API", "/work/api", "/work/web", "/work"}},
+		{order: pickSortRecent, want: []string{"/work/api", "/work", "/work/web", "/other/API"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			got := slices.Clone(dirs)
+			sortPickTargets(got, tt.order, launched)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sortPickTargets(%s) = %v, want %v", tt.order, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCutPickSort(t *testing.T) {
+	tests := []struct {
+		args      []string
+		wantOrder string
+		wantRest  []string
+		wantErr   bool
+	}{
+		{args: []string{"-p", "hi"}, wantRest: []string{"-p", "hi"}},
+		{args: []string{"--sort", "name", "-p", "hi"}, wantOrder: "name", wantRest: []string{"-p", "hi"}},
+		{args: []string{"--sort=recent"}, wantOrder: "recent", wantRest: []string{}},
+		{args: []string{"--sort", "size"}, wantErr: true},
+		{args: []string{"--sort"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		order, rest, err := cutPickSort(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("cutPickSort(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (order != tt.wantOrder || !slices.Equal(rest, tt.wantRest)) {
+			t.Errorf("cutPickSort(%v) = %q, %v, want %q, %v", tt.args, order, rest, tt.wantOrder, tt.wantRest)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/plugin"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// pluginHookTimeout bounds each hook call, so a hanging plugin cannot block every launch
+const pluginHookTimeout = 30 * time.Second
+
+// runPlugin runs the plugin name (claude-launcher-<name> on PATH) with args, and reports
+// whether there is such a plugin. Built-in commands and aliases are dispatched first, so
+// they cannot be overridden. The plugin's exit code is passed through.
+func runPlugin(name string, args []string) (int, bool) {
+	if !plugin.ValidName(name) {
+		return 0, false
+	}
+	path, err := plugin.Find(name)
+	if err != nil {
+		return 0, false
+	}
+
+	log.Debug("plugin found", "plugin", name, "path", path)
+	// #nosec G204 -- plugins are executables the user put on PATH and named on the command line
+	cmd := exec.Command(path, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = plugin.Env(Version)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), true
+		}
+		ui.NewPrinter(os.Stderr).Error("Failed to run plugin %s: %v\n", name, err)
+		return exitError, true
+	}
+	return exitSuccess, true
+}
+
+// pluginJSON is one plugin in the `plugins --json` output
+type pluginJSON struct {
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"` // Empty when an enabled plugin is not on PATH
+	Enabled bool   `json:"enabled"`        // Listed in the "plugins" config option
+}
+
+// runPlugins implements `claude-launcher plugins`: it lists the plugins on PATH and those
+// whose hooks the config enables
+func runPlugins(args []string) int {
+	fs := flag.NewFlagSet("plugins", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	var enabled []string
+	if cfg, err := config.LoadConfig(); err == nil {
+		enabled = cfg.Plugins
+	}
+
+	paths := map[string]string{}
+	for _, p := range plugin.Discover() {
+		paths[p.Name] = p.Path
+	}
+	for _, name := range enabled {
+		if _, ok := paths[name]; !ok {
+			paths[name] = ""
+		}
+	}
+
+	if jsonOutput {
+		out := []pluginJSON{}
+		for _, name := range slices.Sorted(maps.Keys(paths)) {
+			out = append(out, pluginJSON{Name: name, Path: paths[name], Enabled: slices.Contains(enabled, name)})
+		}
+		return printJSON(out)
+	}
+	ui.NewPrinter(os.Stdout).ShowPlugins(paths, enabled)
+	return exitSuccess
+}
+
+// callPlugin calls the hook of req in the plugin name
+func callPlugin(name string, req plugin.Request) (*plugin.Response, error) {
+	req.Version = Version
+	ctx, cancel := context.WithTimeout(context.Background(), pluginHookTimeout)
+	defer cancel()
+	resp, err := plugin.Call(ctx, name, req)
+	log.Debug("plugin hook called", "plugin", name, "hook", req.Hook, "result", hookResult(err))
+	return resp, err
+}
+
+// hookResult describes the outcome of a hook for debug traces
+func hookResult(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
+
+// pluginConfigResult is the outcome of the config hook of a plugin
+type pluginConfigResult struct {
+	resp *plugin.Response
+	err  error
+}
+
+// pluginConfigs holds the config hook outcome of each plugin called so far. Some commands load
+// the config twice (`up` and then the launch), and each hook runs once per process.
+var pluginConfigs = map[string]pluginConfigResult{}
+
+// applyPluginConfig adds the allowed directories contributed by the config hook of the
+// enabled plugins. Directories a plugin fails to contribute are simply not allowed.
+func applyPluginConfig(cfg *config.Config, printer *ui.Printer) {
+	for _, name := range cfg.Plugins {
+		result, called := pluginConfigs[name]
+		if !called {
+			result.resp, result.err = callPlugin(name, plugin.Request{Hook: plugin.HookConfig})
+			pluginConfigs[name] = result
+			if result.err != nil {
+				printer.Warning("⚠ Failed to load allowed directories from plugin %s: %v\n", name, result.err)
+			}
+		}
+		resp := result.resp
+		if result.err != nil {
+			continue
+		}
+		for _, dir := range resp.AllowedDirs {
+			expanded, err := config.ExpandPath(dir)
+			if err != nil || dir == "" {
+				log.Debug("plugin directory ignored", "plugin", name, "dir", dir)
+				continue
+			}
+			expanded = filepath.Clean(expanded)
+			if slices.Contains(cfg.AllowedDirs, expanded) {
+				continue
+			}
+			cfg.AllowedDirs = append(cfg.AllowedDirs, expanded)
+			if cfg.DirSources == nil {
+				cfg.DirSources = map[string]string{}
+			}
+			cfg.DirSources[expanded] = "plugin " + name
+		}
+	}
+}
+
+// runPreLaunchHooks calls the pre-launch hook of the enabled plugins in order. It returns the
+// variables they add for claude, or an exit code other than exitSuccess when a plugin refuses
+// the launch or fails: a check that cannot run does not let the launch through.
+func runPreLaunchHooks(cfg *config.Config, req plugin.Request, printer *ui.Printer) (map[string]string, int) {
+	req.Hook = plugin.HookPreLaunch
+	env := map[string]string{}
+	for _, name := range cfg.Plugins {
+		resp, err := callPlugin(name, req)
+		if err != nil {
+			printer.Error("Failed to run pre-launch check of plugin %s: %v\n", name, err)
+			return nil, exitError
+		}
+		if resp.Deny {
+			message := resp.Message
+			if message == "" {
+				message = "no reason given"
+			}
+			printer.Error("✗ Launch refused by plugin %s: %s\n", name, message)
+			return nil, exitDenied
+		}
+		if resp.Message != "" {
+			printer.Print("%s: %s\n", name, resp.Message)
+		}
+		maps.Copy(env, resp.Env)
+	}
+	return env, exitSuccess
+}
+
+// pluginLaunchRequest describes a launch to the pre-launch hook
+func pluginLaunchRequest(cfg *config.Config, dir, accountName, model, tool string, args []string) plugin.Request {
+	req := plugin.Request{Dir: dir, Account: accountName, Model: model, Tool: tool, Args: args}
+	if project := cfg.FindProject(dir); project != nil {
+		req.Project = project.Path
+	}
+	return req
+}
+
+// pluginNames returns the names of the plugins on PATH, for completion
+func pluginNames() []string {
+	var names []string
+	for _, p := range plugin.Discover() {
+		if _, builtin := subcommands[p.Name]; !builtin && !strings.HasPrefix(p.Name, "_") {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/plugin"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+func TestApplyPluginConfigCallsHookOnce(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts")
+	}
+	dir := t.TempDir()
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	calls := filepath.Join(t.TempDir(), "calls")
+	script := "#!/bin/sh\ncat >/dev/null\necho call >> " + calls + "\necho '{\"allowedDirs\": [\"/srv/tickets\"]}'\n"
+	if err := os.WriteFile(filepath.Join(dir, plugin.Prefix+"tickets"), []byte(script), 0o755); err != nil { // #nosec G306 -- test script
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { delete(pluginConfigs, "tickets") })
+	printer := ui.NewPrinter(io.Discard)
+
+	// `up` loads the config, then the launch loads it again
+	for range 2 {
+		cfg := &config.Config{AllowedDirs: []string{"/work"}, Plugins: []string{"tickets"}}
+		applyPluginConfig(cfg, printer)
+		if !slices.Equal(cfg.AllowedDirs, []string{"/work", "/srv/tickets"}) || cfg.DirSources["/srv/tickets"] != "plugin tickets" {
+			t.Errorf("AllowedDirs = %v, DirSources = %v, expected the plugin's directory", cfg.AllowedDirs, cfg.DirSources)
+		}
+	}
+
+	out, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(out), "call"); n != 1 {
+		t.Errorf("config hook called %d times, want once", n)
+	}
+}
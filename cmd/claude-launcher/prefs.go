@@ -0,0 +1,202 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// prefKey is a preference `prefs set` accepts
+type prefKey struct {
+	values []string                        // The accepted values
+	field  func(p *state.Prefs) *string    // Where the value is kept
+	config func(cfg *config.Config) string // The config setting taking precedence; nil when none
+}
+
+// prefKeys maps the preference names to their definitions. A preference with config is only
+// used when that config setting is empty.
+var prefKeys = map[string]prefKey{
+	"prompts": {
+		values: []string{ui.PromptsMenu, ui.PromptsNumbered},
+		field:  func(p *state.Prefs) *string { return &p.Prompts },
+	},
+	"palette": {
+		values: ui.Palettes(),
+		field:  func(p *state.Prefs) *string { return &p.Palette },
+		config: func(cfg *config.Config) string { return cfg.Theme.Palette },
+	},
+	"symbols": {
+		values: []string{ui.SymbolsAuto, ui.SymbolsUnicode, ui.SymbolsASCII},
+		field:  func(p *state.Prefs) *string { return &p.Symbols },
+		config: func(cfg *config.Config) string { return cfg.Symbols },
+	},
+	"pickSort": {
+		values: pickSorts,
+		field:  func(p *state.Prefs) *string { return &p.PickSort },
+	},
+}
+
+// prefsCommands maps the `prefs` subcommands to their entry points
+var prefsCommands = map[string]func(args []string) int{
+	"list":  runPrefsList,
+	"set":   runPrefsSet,
+	"unset": runPrefsUnset,
+}
+
+// runPrefs implements `claude-launcher prefs [COMMAND]`; without a command it lists the preferences
+func runPrefs(args []string) int {
+	if len(args) == 0 {
+		return runPrefsList(args)
+	}
+	cmd, ok := prefsCommands[args[0]]
+	if !ok {
+		ui.NewPrinter(os.Stderr).Error("Unknown prefs command %q (available: %s)\n", args[0], strings.Join(slices.Sorted(maps.Keys(prefsCommands)), ", "))
+		return exitError
+	}
+	return cmd(args[1:])
+}
+
+// loadPrefs returns the saved preferences; none when the state directory cannot be read
+func loadPrefs() state.Prefs {
+	store, err := state.NewStore()
+	if err == nil {
+		var prefs state.Prefs
+		if prefs, err = store.Prefs(); err == nil {
+			return prefs
+		}
+	}
+	log.Debug("preferences unavailable", "error", err)
+	return state.Prefs{}
+}
+
+// applyPrefs sets the prompt style from prefs and fills in the theme palette and symbols that
+// cfg leaves empty, for loadConfig to apply
+func applyPrefs(cfg *config.Config, prefs state.Prefs) (config.Theme, string) {
+	if err := ui.SetPromptStyle(prefs.Prompts); err != nil {
+		log.Warn("ignoring saved prompt style", "error", err)
+	}
+	theme := cfg.Theme
+	theme.Palette = cmp.Or(theme.Palette, prefs.Palette)
+	return theme, cmp.Or(cfg.Symbols, prefs.Symbols)
+}
+
+// runPrefsList implements `claude-launcher prefs list`
+func runPrefsList(args []string) int {
+	fs := flag.NewFlagSet("prefs list", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+	prefs := loadPrefs()
+	if jsonOutput {
+		return printJSON(prefs)
+	}
+
+	out := ui.NewPrinter(os.Stdout)
+	out.Print("Saved preferences:\n")
+	var rows [][]string
+	for _, name := range slices.Sorted(maps.Keys(prefKeys)) {
+		key := prefKeys[name]
+		value := *key.field(&prefs)
+		if value == "" {
+			value = "-"
+		}
+		note := ""
+		if key.config != nil && key.config(cfg) != "" {
+			note = i18n.T("(the config file sets it)")
+		}
+		rows = append(rows, []string{"  " + name, value, note})
+	}
+	for _, line := range ui.AlignColumns(rows, 0) {
+		out.Print("%s\n", strings.TrimRight(line, " "))
+	}
+	return exitSuccess
+}
+
+// runPrefsSet implements `claude-launcher prefs set <KEY> <VALUE>`
+func runPrefsSet(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	if len(args) != 2 {
+		printer.Error("Usage: claude-launcher prefs set <KEY> <VALUE>\n")
+		return exitError
+	}
+	key, ok := lookupPrefKey(args[0], printer)
+	if !ok {
+		return exitError
+	}
+	if !slices.Contains(key.values, args[1]) {
+		printer.Error("✗ Unknown value %q for %s (available: %s)\n", args[1], args[0], strings.Join(key.values, ", "))
+		return exitError
+	}
+
+	if code := updatePrefs(func(p *state.Prefs) { *key.field(p) = args[1] }, printer); code != exitSuccess {
+		return code
+	}
+	printer.Success("✓ Set %s to %s\n", args[0], args[1])
+	return exitSuccess
+}
+
+// runPrefsUnset implements `claude-launcher prefs unset <KEY>`
+func runPrefsUnset(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	if len(args) != 1 {
+		printer.Error("Usage: claude-launcher prefs unset <KEY>\n")
+		return exitError
+	}
+	key, ok := lookupPrefKey(args[0], printer)
+	if !ok {
+		return exitError
+	}
+
+	if code := updatePrefs(func(p *state.Prefs) { *key.field(p) = "" }, printer); code != exitSuccess {
+		return code
+	}
+	printer.Success("✓ Unset %s\n", args[0])
+	return exitSuccess
+}
+
+// lookupPrefKey returns the preference called name, reporting unknown names
+func lookupPrefKey(name string, printer *ui.Printer) (prefKey, bool) {
+	key, ok := prefKeys[name]
+	if !ok {
+		printer.Error("✗ Unknown preference %q (available: %s)\n", name, strings.Join(slices.Sorted(maps.Keys(prefKeys)), ", "))
+	}
+	return key, ok
+}
+
+// updatePrefs applies change to the saved preferences
+func updatePrefs(change func(p *state.Prefs), printer *ui.Printer) int {
+	store, err := state.NewStore()
+	if err != nil {
+		printer.Error("Failed to open state directory: %v\n", err)
+		return exitError
+	}
+	prefs, err := store.Prefs()
+	if err != nil {
+		printer.Error("Failed to read preferences: %v\n", err)
+		return exitError
+	}
+	change(&prefs)
+	if err := store.SavePrefs(prefs); err != nil {
+		printer.Error("Failed to save preferences: %v\n", err)
+		return exitError
+	}
+	return exitSuccess
+}
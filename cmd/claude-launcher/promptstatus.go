@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/security"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// promptStatusCache is the cache file of prompt-status in the state directory
+const promptStatusCache = "prompt-status.json"
+
+// promptStatusTTL is how long a cached answer is reused: directories are created and deleted
+// without the config file changing
+const promptStatusTTL = time.Minute
+
+// promptStatusMaxDirs bounds the cache; it is emptied when it grows beyond that
+const promptStatusMaxDirs = 200
+
+// promptStatusCacheJSON holds the answers for the configuration identified by Key
+type promptStatusCacheJSON struct {
+	Key  string                      `json:"key"`
+	Dirs map[string]promptStatusJSON `json:"dirs"`
+}
+
+// promptStatusJSON is the cached answer for one directory
+type promptStatusJSON struct {
+	Allowed   bool      `json:"allowed"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// promptStatusFlags holds the options of `prompt-status`
+type promptStatusFlags struct {
+	allowed, denied string
+}
+
+// newPromptStatusFlags defines the options of `prompt-status`
+func newPromptStatusFlags() (*flag.FlagSet, *promptStatusFlags) {
+	fs := flag.NewFlagSet("prompt-status", flag.ContinueOnError)
+	f := &promptStatusFlags{}
+	fs.StringVar(&f.allowed, "allowed", "✓", "`TEXT` printed when launches are allowed in the current directory")
+	fs.StringVar(&f.denied, "denied", "✗", "`TEXT` printed when they are not (may be empty)")
+	return fs, f
+}
+
+// runPromptStatus implements `claude-launcher prompt-status`: one word for shell prompts telling
+// whether the current directory is allowed. It runs before every prompt, so it skips everything
+// but the directory check (no plugins, no events) and caches its answers.
+func runPromptStatus(args []string) int {
+	fs, f := newPromptStatusFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return exitError
+	}
+
+	check := func(dir string) (bool, error) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return false, err
+		}
+		allowed, _ := security.NewDirectoryChecker(cfg.AllowedDirs).IsAllowed(dir) //nolint:errcheck // unresolvable directories are not allowed
+		return allowed, nil
+	}
+	var allowed bool
+	if store, storeErr := state.NewStore(); storeErr == nil {
+		allowed, err = promptStatus(store, promptStatusKey(), dir, time.Now(), check)
+	} else {
+		allowed, err = check(dir)
+	}
+	if err != nil {
+		// A broken config is reported by the launch itself; the prompt stays quiet
+		log.Debug("prompt status unavailable", "error", err)
+		return exitConfig
+	}
+
+	if !allowed {
+		if f.denied != "" {
+			fmt.Println(ui.Symbols(f.denied))
+		}
+		return exitDenied
+	}
+	fmt.Println(ui.Symbols(f.allowed))
+	return exitSuccess
+}
+
+// promptStatusKey identifies the configuration the cached answers belong to: the config file
+// (by modification time and size) and the variables that replace its directories
+func promptStatusKey() string {
+	key := fmt.Sprintf("%s\n%s", os.Getenv("CLAUDE_SAFE_DIRS"), os.Getenv(config.CIAllowEnvVar))
+	if path, err := config.DefaultConfigPath(); err == nil {
+		if info, err := os.Stat(path); err == nil {
+			key += fmt.Sprintf("\n%s\n%d\n%d", path, info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	return key
+}
+
+// promptStatus returns whether dir is allowed, from the cache in store when it holds a fresh
+// answer for the same key, and from check otherwise (caching the result)
+func promptStatus(store *state.Store, key, dir string, now time.Time, check func(dir string) (bool, error)) (bool, error) {
+	var cache promptStatusCacheJSON
+	if err := store.ReadJSON(promptStatusCache, &cache); err == nil && cache.Key == key {
+		if entry, ok := cache.Dirs[dir]; ok && now.Sub(entry.CheckedAt) < promptStatusTTL {
+			return entry.Allowed, nil
+		}
+	}
+
+	allowed, err := check(dir)
+	if err != nil {
+		return false, err
+	}
+
+	if cache.Key != key || len(cache.Dirs) >= promptStatusMaxDirs {
+		cache = promptStatusCacheJSON{Key: key}
+	}
+	if cache.Dirs == nil {
+		cache.Dirs = map[string]promptStatusJSON{}
+	}
+	cache.Dirs[dir] = promptStatusJSON{Allowed: allowed, CheckedAt: now}
+	_ = store.WriteJSON(promptStatusCache, cache) //nolint:errcheck // the next prompt checks again
+	return allowed, nil
+}
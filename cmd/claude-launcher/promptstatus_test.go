@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+func TestPromptStatus(t *testing.T) {
+	store := &state.Store{Dir: t.TempDir()}
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	checks := 0
+	allowed := true
+	check := func(string) (bool, error) {
+		checks++
+		return allowed, nil
+	}
+
+	expect := func(key, dir string, want bool, wantChecks int) {
+		t.Helper()
+		got, err := promptStatus(store, key, dir, now, check)
+		if err != nil || got != want || checks != wantChecks {
+			t.Errorf("promptStatus(%q, %q) = %v, %v after %d checks, want %v after %d", key, dir, got, err, checks, want, wantChecks)
+		}
+	}
+
+	expect("v1", "/work/api", true, 1)
+
+	// A fresh answer is reused, even when the check would now say otherwise
+	allowed = false
+	now = now.Add(promptStatusTTL - time.Second)
+	expect("v1", "/work/api", true, 1)
+
+	// Other directories, stale answers and a changed configuration are checked again
+	expect("v1", "/tmp", false, 2)
+	now = now.Add(2 * time.Second)
+	expect("v1", "/work/api", false, 3)
+	allowed = true
+	expect("v2", "/work/api", true, 4)
+
+	// Failures are not cached
+	failing := func(string) (bool, error) { return false, errors.New("invalid config") }
+	if _, err := promptStatus(store, "v3", "/work/api", now, failing); err == nil {
+		t.Error("promptStatus() should return the error of the check")
+	}
+	expect("v3", "/work/api", true, 5)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// instanceTracker records foreground claude processes in the state store so `ps` can list them
+type instanceTracker struct {
+	store   *state.Store
+	account string
+}
+
+// Started implements launcher.Tracker. Tracking is best-effort and never blocks a launch.
+func (t *instanceTracker) Started(pid int, opts launcher.LaunchOptions) func() {
+	id, err := state.NewID()
+	if err != nil {
+		log.Warn("failed to track session", "error", err)
+		return func() {}
+	}
+
+	inst := &state.Instance{
+		ID:           id,
+		PID:          pid,
+		Dir:          opts.Dir,
+		Account:      t.account,
+		StartedAt:    time.Now(),
+		ProcessStart: state.ProcessStartTime(pid),
+	}
+	if err := t.store.SaveInstance(inst); err != nil {
+		log.Warn("failed to track session", "error", err)
+		return func() {}
+	}
+
+	return func() {
+		_ = t.store.RemoveInstance(id) //nolint:errcheck // stale records are pruned by `ps`
+	}
+}
+
+// runPs implements `claude-launcher ps`
+func runPs(args []string) int {
+	fs := flag.NewFlagSet("ps", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	store, err := state.NewStore()
+	if err != nil {
+		printer.Error("Failed to open state directory: %v\n", err)
+		return exitError
+	}
+
+	instances, err := store.PruneInstances()
+	if err != nil {
+		printer.Error("Failed to list sessions: %v\n", err)
+		return exitError
+	}
+
+	if jsonOutput {
+		return printJSON(newInstancesJSON(instances))
+	}
+	ui.NewPrinter(os.Stdout).ShowInstances(instances, time.Now())
+	return exitSuccess
+}
+
+// runKill implements `claude-launcher kill <ID>`
+func runKill(args []string) int {
+	fs := flag.NewFlagSet("kill", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if fs.NArg() != 1 {
+		printer.Error("Usage: claude-launcher kill <ID>\n")
+		return exitError
+	}
+	id := fs.Arg(0)
+
+	store, err := state.NewStore()
+	if err != nil {
+		printer.Error("Failed to open state directory: %v\n", err)
+		return exitError
+	}
+
+	inst, err := store.FindInstance(id)
+	if err != nil {
+		printer.Error("Failed to find session: %v\n", err)
+		return exitError
+	}
+	// A PID reused by an unrelated process fails Running, so it is never signalled
+	if inst == nil || !inst.Running() {
+		_ = store.RemoveInstance(id) //nolint:errcheck // drop a stale record if there is one
+		printer.Error("✗ No running session with ID '%s'\n", id)
+		return exitError
+	}
+
+	if err := state.Terminate(inst.PID); err != nil {
+		printer.Error("Failed to stop session: %v\n", err)
+		return exitError
+	}
+
+	printer.ShowSessionKilled(id, inst.PID)
+	return exitSuccess
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+func TestInstanceTracker(t *testing.T) {
+	store := &state.Store{Dir: t.TempDir()}
+	tracker := &instanceTracker{store: store, account: "Work"}
+
+	finished := tracker.Started(os.Getpid(), launcher.LaunchOptions{Dir: "/tmp/project"})
+
+	instances, err := store.ListInstances()
+	if err != nil {
+		t.Fatalf("ListInstances() error = %v", err)
+	}
+	if len(instances) != 1 {
+		t.Fatalf("ListInstances() returned %d instances, expected 1", len(instances))
+	}
+	if instances[0].Account != "Work" || instances[0].Dir != "/tmp/project" {
+		t.Errorf("recorded instance = %+v, expected account Work in /tmp/project", instances[0])
+	}
+
+	finished()
+
+	instances, err = store.ListInstances()
+	if err != nil {
+		t.Fatalf("ListInstances() error = %v", err)
+	}
+	if len(instances) != 0 {
+		t.Errorf("ListInstances() returned %d instances after exit, expected 0", len(instances))
+	}
+}
+
+func TestFindDetachedInstance(t *testing.T) {
+	store := &state.Store{Dir: t.TempDir()}
+
+	foreground := &state.Instance{ID: "fg", PID: 1, StartedAt: time.Now()}
+	if err := store.SaveInstance(foreground); err != nil {
+		t.Fatalf("SaveInstance() error = %v", err)
+	}
+
+	// No detached sessions
+	if inst, err := findDetachedInstance(store, ""); err != nil || inst != nil {
+		t.Errorf("findDetachedInstance() = (%v, %v), expected (nil, nil)", inst, err)
+	}
+	if inst, err := findDetachedInstance(store, "fg"); err != nil || inst != nil {
+		t.Errorf("findDetachedInstance(fg) = (%v, %v), expected foreground sessions to be ignored", inst, err)
+	}
+
+	// Exactly one detached session is picked without an ID
+	first := &state.Instance{ID: "d1", PID: 2, StartedAt: time.Now(), Socket: "/tmp/d1.sock"}
+	if err := store.SaveInstance(first); err != nil {
+		t.Fatalf("SaveInstance() error = %v", err)
+	}
+	inst, err := findDetachedInstance(store, "")
+	if err != nil || inst == nil || inst.ID != "d1" {
+		t.Errorf("findDetachedInstance() = (%v, %v), expected d1", inst, err)
+	}
+
+	// Several detached sessions require an ID
+	second := &state.Instance{ID: "d2", PID: 3, StartedAt: time.Now(), Socket: "/tmp/d2.sock"}
+	if err := store.SaveInstance(second); err != nil {
+		t.Fatalf("SaveInstance() error = %v", err)
+	}
+	if inst, err := findDetachedInstance(store, ""); err != nil || inst != nil {
+		t.Errorf("findDetachedInstance() = (%v, %v), expected ambiguity to return nil", inst, err)
+	}
+	inst, err = findDetachedInstance(store, "d2")
+	if err != nil || inst == nil || inst.ID != "d2" {
+		t.Errorf("findDetachedInstance(d2) = (%v, %v), expected d2", inst, err)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// applyReadOnly adds the --read-only rules to permissions. Skipping permission prompts would make
+// the rules moot, so the two cannot be combined.
+func applyReadOnly(permissions config.Permissions, skipPermissions bool, printer *ui.Printer) (config.Permissions, int) {
+	if skipPermissions {
+		printer.Error("--read-only cannot be used with --dangerously-skip-permissions\n")
+		return config.Permissions{}, exitError
+	}
+	return permissions.Merge(config.ReadOnly), exitSuccess
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+func TestApplyReadOnly(t *testing.T) {
+	printer := ui.NewPrinter(io.Discard)
+	project := config.Permissions{Allow: []string{"Bash(rm:*)"}, DefaultMode: "acceptEdits"}
+
+	got, code := applyReadOnly(project, false, printer)
+	if code != exitSuccess {
+		t.Fatalf("applyReadOnly() code = %d, want %d", code, exitSuccess)
+	}
+	if !slices.Contains(got.DisabledTools, "Edit") || !slices.Contains(got.Deny, "Bash(rm:*)") {
+		t.Errorf("applyReadOnly() = %+v, want the read-only rules added", got)
+	}
+	if !slices.Equal(got.Allow, project.Allow) || got.DefaultMode != project.DefaultMode {
+		t.Errorf("applyReadOnly() = %+v, want the project's rules kept", got)
+	}
+
+	if _, code := applyReadOnly(project, true, printer); code != exitError {
+		t.Errorf("applyReadOnly() with --dangerously-skip-permissions code = %d, want %d", code, exitError)
+	}
+}
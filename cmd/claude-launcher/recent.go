@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// recentShortcut runs `recent` when given instead of a command
+const recentShortcut = "-r"
+
+// newRecentFlags defines the options of `recent`
+func newRecentFlags() (*flag.FlagSet, *int) {
+	fs := flag.NewFlagSet("recent", flag.ContinueOnError)
+	n := fs.Int("n", 10, "Number of projects (`N`) to list")
+	return fs, n
+}
+
+// runRecent implements `claude-launcher recent` (or -r): the latest launch of each of the last
+// projects, from the launch history. The chosen one is launched again with the same settings.
+func runRecent(args []string) int {
+	fs, n := newRecentFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	store, err := state.NewStore()
+	if err != nil {
+		printer.Error("Failed to open state directory: %v\n", err)
+		return exitError
+	}
+	records, err := launcher.History(store)
+	if err != nil {
+		printer.Error("Failed to read launch history: %v\n", err)
+		return exitError
+	}
+
+	// Projects that were moved or deleted cannot be launched again
+	records = slices.DeleteFunc(records, func(rec launcher.LaunchRecord) bool {
+		info, err := os.Stat(rec.Dir)
+		return err != nil || !info.IsDir()
+	})
+	recent := launcher.Recent(records, *n)
+	if len(recent) == 0 {
+		printer.Print("No recent projects\n")
+		return exitSuccess
+	}
+
+	now := time.Now()
+	rows := make([][]string, len(recent))
+	for i, rec := range recent {
+		accountName := rec.Account
+		if accountName == "" {
+			accountName = "-"
+		}
+		session := i18n.T("new session")
+		if rec.Continue {
+			session = i18n.T("continued")
+		}
+		rows[i] = []string{ui.FormatAge(now.Sub(rec.StartedAt)), accountName, session, rec.Dir}
+	}
+	idx, err := ui.Select(i18n.T("Select recent project"), ui.AlignColumns(rows, ui.MenuWidth()))
+	if err != nil {
+		printer.Error("Failed to select project: %v\n", err)
+		return promptExitCode(err)
+	}
+
+	return launch(recentArgs(recent[idx], fs.Args()))
+}
+
+// recentArgs converts a launch record into the launch flags that repeat it, followed by the
+// claude arguments. The session is continued only when the recorded launch continued one.
+func recentArgs(rec launcher.LaunchRecord, extra []string) []string {
+	args := []string{"--dir", rec.Dir}
+	if rec.Account != "" {
+		args = append(args, "--account", rec.Account)
+	}
+	if rec.Preset != "" {
+		args = append(args, "--preset", rec.Preset)
+	}
+	if rec.Model != "" {
+		args = append(args, "--model", rec.Model)
+	}
+	switch rec.Mode {
+	case launcher.ModeDetached:
+		args = append(args, "--detach")
+	case launcher.ModeTmux:
+		args = append(args, "--tmux")
+	}
+	if rec.Continue {
+		args = append(args, "--continue")
+	} else {
+		args = append(args, "--new")
+	}
+	return append(append(args, "--"), extra...)
+}
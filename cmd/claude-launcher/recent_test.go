@@ -0,0 +1,30 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+func TestRecentArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		rec  launcher.LaunchRecord
+		want []string
+	}{
+		{"defaults", launcher.LaunchRecord{Dir: "/work/api", Mode: launcher.ModeForeground},
+			[]string{"--dir", "/work/api", "--new", "--", "--verbose"}},
+		{"all settings", launcher.LaunchRecord{Dir: "/work/api", Mode: launcher.ModeTmux, Account: "Work", Preset: "review", Model: "opus", Continue: true},
+			[]string{"--dir", "/work/api", "--account", "Work", "--preset", "review", "--model", "opus", "--tmux", "--continue", "--", "--verbose"}},
+		{"detached", launcher.LaunchRecord{Dir: "/work/api", Mode: launcher.ModeDetached},
+			[]string{"--dir", "/work/api", "--detach", "--new", "--", "--verbose"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recentArgs(tt.rec, []string{"--verbose"}); !slices.Equal(got, tt.want) {
+				t.Errorf("recentArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
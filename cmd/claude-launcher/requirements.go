@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/session"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// checkRequirements verifies the requirements of project (requires) for a launch in dir and
+// reports those not met. The user may launch anyway; without prompts the launch is refused.
+func checkRequirements(project *config.Project, dir string, noPrompts bool, prompter *session.InteractivePrompter, printer *ui.Printer) int {
+	if project == nil || len(project.Requires) == 0 {
+		return exitSuccess
+	}
+
+	spinner := printer.Spinner()
+	failures := launcher.CheckRequirements(dir, project.Requires, spinner.Update)
+	spinner.Stop()
+	if len(failures) == 0 {
+		return exitSuccess
+	}
+
+	printer.ShowRequirementFailures(failures)
+	if noPrompts {
+		return exitError
+	}
+	ok, err := prompter.Confirm("Launch anyway?", false)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return promptExitCode(err)
+	}
+	if !ok {
+		return exitAborted
+	}
+	return exitSuccess
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// rotateOnce makes rotateLogs run once per process, as some commands load the config twice
+var rotateOnce sync.Once
+
+// rotateLogs applies the retention config to the audit log, the launch history and the log file.
+// Problems are only logged: keeping too much is better than refusing to launch.
+func rotateLogs(cfg *config.Config) {
+	rotateOnce.Do(func() {
+		store, err := state.NewStore()
+		if err != nil {
+			log.Debug("logs not rotated", "error", err)
+			return
+		}
+		paths := []string{store.AuditPath(), launcher.HistoryPath(store)}
+		if cfg.Log.File != "" {
+			paths = append(paths, cfg.Log.File)
+		}
+		rotation := cfg.Retention.Rotation()
+		now := time.Now()
+		for _, path := range paths {
+			if err := store.RotateLog(path, rotation, now); err != nil {
+				log.Warn("log not rotated", "path", path, "error", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/session"
+	"github.com/23prime/claude-launcher/internal/snapshot"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// rollbackFlags holds the options of `claude-launcher rollback`
+type rollbackFlags struct {
+	list bool
+	yes  bool
+}
+
+// newRollbackFlags defines the options of `claude-launcher rollback`
+func newRollbackFlags() (*flag.FlagSet, *rollbackFlags) {
+	f := &rollbackFlags{}
+	fs := flag.NewFlagSet("rollback", flag.ContinueOnError)
+	fs.BoolVar(&f.list, "list", false, "List the snapshots of the current repository (all snapshots outside repositories)")
+	fs.BoolVar(&f.yes, "yes", false, "Roll back without asking")
+	return fs, f
+}
+
+// takeSnapshot makes the recovery point of the snapshot setting (mode) before a launch in dir.
+// Directories outside git repositories, or in repositories without commits, are launched
+// without one; any other failure stops the launch, since the user asked for a way back.
+func takeSnapshot(dir, mode string, printer *ui.Printer) int {
+	store, err := state.NewStore()
+	if err != nil {
+		printer.Error("Failed to open state directory: %v\n", err)
+		return exitError
+	}
+	id, err := state.NewID()
+	if err != nil {
+		printer.Error("Failed to take a snapshot: %v\n", err)
+		return exitError
+	}
+
+	s, err := snapshot.Create(dir, mode, id, filepath.Join(store.Dir, "snapshots", id))
+	switch {
+	case errors.Is(err, snapshot.ErrNotRepository):
+		log.Debug("snapshot skipped", "dir", dir, "reason", err)
+		return exitSuccess
+	case errors.Is(err, snapshot.ErrNoCommit):
+		printer.Warning("⚠ No snapshot taken: %v\n", err)
+		return exitSuccess
+	case err != nil:
+		printer.Error("Failed to take a snapshot: %v\n", err)
+		return exitError
+	}
+	if err := snapshot.Save(store, s); err != nil {
+		printer.Error("Failed to record the snapshot: %v\n", err)
+		return exitError
+	}
+	printer.ShowSnapshotTaken(id)
+	return exitSuccess
+}
+
+// runRollback implements `claude-launcher rollback [ID]`: it restores a snapshot taken before
+// a launch, by default the latest one of the current repository. The state being discarded is
+// saved as a commit snapshot first, so a rollback can itself be rolled back.
+func runRollback(args []string) int {
+	fs, f := newRollbackFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if fs.NArg() > 1 {
+		printer.Error("Usage: claude-launcher rollback [OPTIONS] [ID]\n")
+		return exitError
+	}
+
+	store, err := state.NewStore()
+	if err != nil {
+		printer.Error("Failed to open state directory: %v\n", err)
+		return exitError
+	}
+	snapshots, err := snapshot.List(store)
+	if err != nil {
+		printer.Error("Failed to read snapshots: %v\n", err)
+		return exitError
+	}
+	root, _ := gitOutput(".", "rev-parse", "--show-toplevel") //nolint:errcheck // "" outside repositories
+
+	if f.list {
+		if root != "" {
+			snapshots = slices.DeleteFunc(snapshots, func(s snapshot.Snapshot) bool { return s.Root != root })
+		}
+		if jsonOutput {
+			return printJSON(append([]snapshot.Snapshot{}, snapshots...))
+		}
+		ui.NewPrinter(os.Stdout).ShowSnapshots(snapshots, time.Now())
+		return exitSuccess
+	}
+
+	s := findSnapshot(snapshots, fs.Arg(0), root)
+	if s == nil {
+		switch {
+		case fs.NArg() == 1:
+			printer.Error("✗ No snapshot with ID '%s'\n", fs.Arg(0))
+		case root == "":
+			printer.Error("✗ Not in a git repository: pass the ID of a snapshot (see 'claude-launcher rollback --list')\n")
+		default:
+			printer.Error("✗ No snapshot of %s\n", root)
+		}
+		return exitNotFound
+	}
+
+	printer.Warning("⚠ Rolling back %s to snapshot %s (%s, taken %s)\n", s.Root, s.ID, s.Mode, ui.FormatAge(time.Since(s.CreatedAt)))
+	if !f.yes {
+		prompter := session.NewInteractivePrompter(os.Stdin, printer)
+		ok, err := prompter.Confirm(fmt.Sprintf(i18n.T("Discard every change made in %s since then?"), s.Root), false)
+		if err != nil {
+			printer.Error("%v\n", err)
+			return promptExitCode(err)
+		}
+		if !ok {
+			return exitAborted
+		}
+	}
+
+	id, err := state.NewID()
+	if err != nil {
+		printer.Error("Failed to save the current state: %v\n", err)
+		return exitError
+	}
+	current, err := snapshot.Create(s.Root, snapshot.ModeCommit, id, "")
+	if err == nil {
+		err = snapshot.Save(store, current)
+	}
+	if err != nil {
+		printer.Error("Failed to save the current state: %v\n", err)
+		return exitError
+	}
+
+	if err := snapshot.Restore(s); err != nil {
+		printer.Error("Failed to roll back: %v\n", err)
+		return exitError
+	}
+	printer.Success("✓ Rolled back to snapshot %s ('claude-launcher rollback %s' undoes it)\n", s.ID, current.ID)
+	return exitSuccess
+}
+
+// findSnapshot returns the snapshot with id or, without one, the latest snapshot of the
+// repository at root; nil when there is none
+func findSnapshot(snapshots []snapshot.Snapshot, id, root string) *snapshot.Snapshot {
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		s := &snapshots[i]
+		if (id != "" && s.ID == id) || (id == "" && root != "" && s.Root == root) {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/snapshot"
+)
+
+func TestFindSnapshot(t *testing.T) {
+	snapshots := []snapshot.Snapshot{
+		{ID: "aaaa", Root: "/work/api"},
+		{ID: "bbbb", Root: "/work/web"},
+		{ID: "cccc", Root: "/work/api"},
+	}
+
+	tests := []struct {
+		id, root, want string
+	}{
+		{"", "/work/api", "cccc"},
+		{"", "/work/web", "bbbb"},
+		{"aaaa", "/work/web", "aaaa"},
+		{"", "/work/cli", ""},
+		{"", "", ""},
+		{"dddd", "/work/api", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if s := findSnapshot(snapshots, tt.id, tt.root); s != nil {
+			got = s.ID
+		}
+		if got != tt.want {
+			t.Errorf("findSnapshot(%q, %q) = %q, want %q", tt.id, tt.root, got, tt.want)
+		}
+	}
+}
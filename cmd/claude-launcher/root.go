@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// runningAsRoot reports whether the launcher runs with an effective UID of 0.
+// Always false on Windows, where Geteuid returns -1.
+var runningAsRoot = func() bool { return os.Geteuid() == 0 }
+
+// checkRoot refuses a launch in dir as root unless --allow-root or allowRoot in config permits it.
+// Permitted launches are warned about; both outcomes are written to the audit log.
+func checkRoot(cfg *config.Config, allowFlag bool, dir string, args []string, printer *ui.Printer) int {
+	if !runningAsRoot() {
+		return exitSuccess
+	}
+
+	if !allowFlag && !cfg.AllowRoot {
+		_ = recordAudit(state.AuditRootDenied, dir, "", args) //nolint:errcheck // the launch is refused anyway
+		printer.ShowRootDenied()
+		return exitDenied
+	}
+
+	if err := recordAudit(state.AuditRoot, dir, "", args); err != nil {
+		printer.Error("Failed to write audit log: %v\n", err)
+		return exitError
+	}
+	printer.ShowRootAllowed()
+	return exitSuccess
+}
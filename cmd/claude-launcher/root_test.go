@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+func TestCheckRoot(t *testing.T) {
+	original := runningAsRoot
+	t.Cleanup(func() { runningAsRoot = original })
+
+	tests := []struct {
+		name      string
+		root      bool
+		allowFlag bool
+		cfg       config.Config
+		want      int
+		wantEvent string
+	}{
+		{name: "regular user", want: exitSuccess},
+		{name: "root refused", root: true, want: exitDenied, wantEvent: state.AuditRootDenied},
+		{name: "root with --allow-root", root: true, allowFlag: true, want: exitSuccess, wantEvent: state.AuditRoot},
+		{name: "root with allowRoot", root: true, cfg: config.Config{AllowRoot: true}, want: exitSuccess, wantEvent: state.AuditRoot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CLAUDE_LAUNCHER_HOME", "")
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+			runningAsRoot = func() bool { return tt.root }
+
+			if got := checkRoot(&tt.cfg, tt.allowFlag, "/work", []string{"-p", "hi"}, ui.NewPrinter(io.Discard)); got != tt.want {
+				t.Errorf("checkRoot() = %d, want %d", got, tt.want)
+			}
+
+			store, err := state.NewStore()
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries, err := store.ReadAudit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case tt.wantEvent == "" && len(entries) != 0:
+				t.Errorf("audit log = %+v, want no entries", entries)
+			case tt.wantEvent != "" && (len(entries) != 1 || entries[0].Event != tt.wantEvent || entries[0].Dir != "/work"):
+				t.Errorf("audit log = %+v, want one %q entry for /work", entries, tt.wantEvent)
+			}
+		})
+	}
+}
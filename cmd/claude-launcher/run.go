@@ -0,0 +1,203 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/account"
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/event"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// runFlags holds the options of `run`
+type runFlags struct {
+	prompt, accountName, model, dir, preset string
+	continueSession, noOtel, allowRoot      bool
+	approveDir                              bool
+	landlock, readOnly, plan                bool
+	permissionMode                          string
+}
+
+// newRunFlags defines the options of `run`
+func newRunFlags() (*flag.FlagSet, *runFlags) {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	f := &runFlags{}
+	fs.StringVar(&f.prompt, "p", "", "`PROMPT` to send (read from stdin when empty)")
+	fs.StringVar(&f.prompt, "print", "", "Prompt to send (long form)")
+	fs.StringVar(&f.accountName, "a", "", "Account `NAME` to use (required if several are configured)")
+	fs.StringVar(&f.accountName, "account", "", "Account name to use (long form)")
+	fs.StringVar(&f.model, "m", "", "`MODEL` to use")
+	fs.StringVar(&f.model, "model", "", "Model to use (long form)")
+	fs.StringVar(&f.dir, "d", "", "Directory (`DIR`) to run in (defaults to the current directory)")
+	fs.StringVar(&f.dir, "dir", "", "Directory to run in (long form)")
+	fs.BoolVar(&f.continueSession, "continue", false, "Continue the most recent session")
+	fs.BoolVar(&f.noOtel, "no-otel", false, "Disable OpenTelemetry environment variable injection")
+	fs.StringVar(&f.preset, "preset", "", "Permission preset (`NAME`) to apply (from permissionPresets in config)")
+	fs.BoolVar(&f.plan, "plan", false, "Start Claude in plan mode (same as --permission-mode plan)")
+	fs.StringVar(&f.permissionMode, "permission-mode", "", "Permission `MODE` to start Claude in: "+strings.Join(config.PermissionModes, ", "))
+	fs.BoolVar(&f.readOnly, "read-only", false, "Deny Claude the tools and commands that change files")
+	fs.BoolVar(&f.landlock, "landlock", false, "Only let Claude write to the project, its config dir and temp (Linux 5.13+)")
+	fs.BoolVar(&f.allowRoot, "allow-root", false, "Run even when running as root (logged to the audit log)")
+	fs.BoolVar(&f.approveDir, "approve-dir", false, "Confirm the directory for confirmNewDirs (run cannot ask)")
+	return fs, f
+}
+
+// runHeadless implements `claude-launcher run -p "prompt"`.
+// It applies the same directory and account checks as an interactive launch but never
+// prompts: what a launch would ask about is refused. It runs claude in print mode. Launcher messages go to stderr so that stdout
+// carries only claude's output; claude's exit code is passed through.
+func runHeadless(args []string) int {
+	fs, f := newRunFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+	timings.Mark("config")
+
+	claudeArgs := buildPrintArgs(f.prompt, fs.Args())
+
+	currentDir, rule, skipPermissions, code := authorizeLaunch(cfg, f.dir, claudeArgs, printer)
+	if code != exitSuccess {
+		return code
+	}
+	if code := checkRoot(cfg, f.allowRoot, currentDir, claudeArgs, printer); code != exitSuccess {
+		return code
+	}
+	project := cfg.FindProject(currentDir)
+	if f.preset, code = applyBranchPolicy(project, currentDir, f.preset, claudeArgs, printer); code != exitSuccess {
+		return code
+	}
+	linkedDirs, code := authorizeLinkedDirs(cfg, project, printer)
+	if code != exitSuccess {
+		return code
+	}
+	if code, done := checkLaunchDir(cfg, project, currentDir, f.approveDir, true, nil, printer); done {
+		return code
+	}
+	timings.Mark("directory check")
+
+	l := newLauncher(cfg, launcher.Claude)
+	applyVersionPin(l, currentDir, printer)
+	if err := preflight(l, cfg.MinClaudeVersion, printer); err != nil {
+		return showPreflightError(printer, l, err)
+	}
+	timings.Mark("claude binary")
+	if code := checkRequirements(project, currentDir, true, nil, printer); code != exitSuccess {
+		return code
+	}
+	timings.Mark("requirements")
+
+	selectedAccount, err := account.SelectAccountNonInteractively(f.accountName)
+	if err != nil {
+		printer.Error("Failed to select account: %v\n", err)
+		return exitError
+	}
+
+	_ = events.Publish(event.AccountSelected{Dir: currentDir, Account: selectedAccount}) //nolint:errcheck // subscribers only observe the choice
+
+	var configDir, accountLabel string
+	if selectedAccount != nil {
+		configDir = selectedAccount.ConfigDir
+		accountLabel = selectedAccount.Name
+	}
+
+	permissions, err := cfg.PermissionsFor(project, f.preset)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return exitError
+	}
+	mode, err := permissionModeFlag(f.plan, f.permissionMode)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return exitError
+	}
+	if mode != "" {
+		permissions.DefaultMode = mode
+	}
+	if f.readOnly {
+		if permissions, code = applyReadOnly(permissions, skipPermissions, printer); code != exitSuccess {
+			return code
+		}
+	}
+
+	model := resolveModel(f.model, project, selectedAccount)
+	req := pluginLaunchRequest(cfg, currentDir, accountLabel, model, launcher.Claude.Name(), withDefaultArgs(cfg, project, launcher.Claude, claudeArgs))
+	claudeArgs, extraEnv, code := runLaunchHooks(cfg, req, nil, printer)
+	if code != exitSuccess {
+		return code
+	}
+
+	if selectedAccount != nil {
+		if err := selectedAccount.Prepare(); err != nil {
+			printer.Error("Failed to prepare account: %v\n", err)
+			return exitError
+		}
+	}
+	env, secretEnv, err := launchEnv(cfg, project, selectedAccount, extraEnv)
+	if err != nil {
+		printer.Error("Failed to resolve secrets: %v\n", err)
+		return exitError
+	}
+
+	launchOpts := launcher.LaunchOptions{
+		Mode:        launcher.ModeHeadless,
+		Account:     accountLabel,
+		Preset:      config.PresetFor(project, f.preset),
+		Rule:        rule,
+		Continue:    f.continueSession,
+		Dir:         currentDir,
+		Model:       model,
+		AddDirs:     mergeDirs(linkedDirs, cfg.SharedDirsFor(currentDir)),
+		Args:        claudeArgs,
+		ConfigDir:   configDir,
+		OtelEnv:     buildLaunchOtelEnv(cfg, selectedAccount, f.noOtel),
+		Env:         env,
+		SecretEnv:   secretEnv,
+		MCPServers:  cfg.MCPServersFor(project),
+		Permissions: permissions,
+	}
+
+	var writable []string
+	if flagOrDefault(fs, "landlock", f.landlock, cfg.Landlock.Enabled) {
+		writable = landlockPaths(cfg, currentDir, launchOpts.AddDirs, configDir, f.readOnly)
+	}
+	l.Wrap = limitsWrapper(cfg.Limits, writable)
+
+	_ = events.Publish(event.SessionChosen{Dir: currentDir, Continue: f.continueSession}) //nolint:errcheck // subscribers only observe the choice
+	if err := events.Publish(event.LaunchStarted{Options: launchOpts}); err != nil {
+		printer.Error("Failed to launch Claude: %v\n", err)
+		return exitError
+	}
+	l.Recorder = event.Recorder{Bus: events}
+	if store, err := state.NewStore(); err == nil {
+		l.Tracker = &instanceTracker{store: store, account: accountLabel}
+	}
+	timings.Mark("account and environment")
+	showTiming(printer)
+
+	if err := l.Launch(launchOpts); err != nil {
+		return launchExitCode(printer, err)
+	}
+
+	return exitSuccess
+}
+
+// buildPrintArgs builds the claude arguments for print mode.
+// Without a prompt, claude reads it from stdin.
+func buildPrintArgs(prompt string, extra []string) []string {
+	args := []string{"-p"}
+	if prompt != "" {
+		args = append(args, prompt)
+	}
+	return append(args, extra...)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/home"
+	"github.com/23prime/claude-launcher/internal/snapshot"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// headlessRepo sets up a git repository allowed by a config with settings, and a fake claude
+// that records its runs. It returns the repository and the record of runs.
+func headlessRepo(t *testing.T, settings map[string]any) (repo, runs string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	launcherHome := t.TempDir()
+	t.Setenv(home.EnvVar, launcherHome)
+	t.Setenv("CLAUDE_SAFE_DIRS", "")
+	t.Setenv("CLAUDE_ACCOUNTS", "")
+
+	repo = t.TempDir()
+	runs = filepath.Join(t.TempDir(), "runs")
+	claude := filepath.Join(t.TempDir(), "claude")
+	script := "#!/bin/sh\nif [ \"$1\" = --version ]; then echo '2.0.0 (Claude Code)'; exit 0; fi\necho \"$*\" >> " + runs + "\n"
+	if err := os.WriteFile(claude, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := map[string]any{"allowedDirs": []string{repo}, "claudePath": claude, "allowRoot": true}
+	for k, v := range settings {
+		cfg[k] = v
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(launcherHome, "config.json"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "main.go")
+	git("commit", "-q", "-m", "init")
+	return repo, runs
+}
+
+func TestRunHeadlessRefusesDirtyTreeUnderBlock(t *testing.T) {
+	repo, runs := headlessRepo(t, map[string]any{"gitCheck": "block"})
+	if err := os.WriteFile(filepath.Join(repo, "main.go.orig"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := runHeadless([]string{"-d", repo, "-p", "hi"}); code != exitDenied {
+		t.Errorf("runHeadless() in a dirty tree = %d, want %d", code, exitDenied)
+	}
+	if _, err := os.Stat(runs); !os.IsNotExist(err) {
+		t.Error("claude ran in a dirty tree under gitCheck block")
+	}
+
+	if err := os.Remove(filepath.Join(repo, "main.go.orig")); err != nil {
+		t.Fatal(err)
+	}
+	if code := runHeadless([]string{"-d", repo, "-p", "hi"}); code != exitSuccess {
+		t.Errorf("runHeadless() in a clean tree = %d, want %d", code, exitSuccess)
+	}
+	if _, err := os.Stat(runs); err != nil {
+		t.Errorf("claude did not run in a clean tree: %v", err)
+	}
+}
+
+func TestRunHeadlessTakesSnapshot(t *testing.T) {
+	repo, runs := headlessRepo(t, map[string]any{"snapshot": snapshot.ModeCommit})
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main // edited\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := runHeadless([]string{"-d", repo, "-p", "hi"}); code != exitSuccess {
+		t.Fatalf("runHeadless() = %d, want %d", code, exitSuccess)
+	}
+	if _, err := os.Stat(runs); err != nil {
+		t.Errorf("claude did not run: %v", err)
+	}
+
+	store, err := state.NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshots, err := snapshot.List(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Mode != snapshot.ModeCommit {
+		t.Errorf("snapshots after run = %+v, want one commit snapshot", snapshots)
+	}
+}
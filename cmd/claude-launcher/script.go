@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/plugin"
+	"github.com/23prime/claude-launcher/internal/script"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// launchScriptTimeout bounds the launch script on top of its step limit
+const launchScriptTimeout = 5 * time.Second
+
+// runLaunchHooks runs the pre-launch hooks of the plugins, then the launch script, on the
+// launch described by req. It returns claude's arguments and the variables added for claude
+// (extra, i.e. --env, wins over plugins; the script has the last word), or an exit code other
+// than exitSuccess when the launch is refused.
+func runLaunchHooks(cfg *config.Config, req plugin.Request, extra map[string]string, printer *ui.Printer) ([]string, map[string]string, int) {
+	env, code := runPreLaunchHooks(cfg, req, printer)
+	if code != exitSuccess {
+		return nil, nil, code
+	}
+	maps.Copy(env, extra)
+
+	return runLaunchScript(cfg, script.Launch{
+		Dir:     req.Dir,
+		Project: req.Project,
+		Account: req.Account,
+		Model:   req.Model,
+		Tool:    req.Tool,
+		Args:    req.Args,
+		Env:     env,
+	}, printer)
+}
+
+// runLaunchScript runs the configured launch script, if any, on launch and returns claude's
+// arguments and the added variables after it. The script may refuse the launch, but it cannot
+// add arguments that bypass permissions (config.CheckNoBypass), directly or in a settings file:
+// the yoloAllowedDirs check already ran.
+func runLaunchScript(cfg *config.Config, launch script.Launch, printer *ui.Printer) ([]string, map[string]string, int) {
+	if cfg.LaunchScript == "" {
+		return launch.Args, launch.Env, exitSuccess
+	}
+
+	launch.Git = script.GitInfo(launch.Dir)
+	ctx, cancel := context.WithTimeout(context.Background(), launchScriptTimeout)
+	defer cancel()
+	result, err := script.Run(ctx, cfg.LaunchScript, launch, func(msg string) { printer.Print("%s\n", msg) })
+	if err != nil {
+		printer.Error("Failed to run launch script: %v\n", err)
+		return nil, nil, exitError
+	}
+	log.Debug("launch script ran", "script", cfg.LaunchScript, "denied", result.Denied)
+
+	if result.Denied {
+		reason := result.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		printer.Error("✗ Launch refused by the launch script: %s\n", reason)
+		return nil, nil, exitDenied
+	}
+	if err := checkAddedArgs(launch.Args, result.Args); err != nil {
+		printer.Error("✗ The launch script cannot add arguments: %v\n", err)
+		return nil, nil, exitDenied
+	}
+	if !slices.Equal(result.Args, launch.Args) {
+		log.Debug("launch script changed the arguments", "before", launch.Args, "after", result.Args)
+	}
+	return result.Args, result.Env, exitSuccess
+}
+
+// checkAddedArgs applies config.CheckNoBypass to the arguments in after that are not in before,
+// and to the settings files they name
+func checkAddedArgs(before, after []string) error {
+	var added []string
+	for i, arg := range after {
+		if slices.Contains(before, arg) {
+			continue
+		}
+		added = append(added, arg)
+		file, ok := strings.CutPrefix(arg, "--settings=")
+		if !ok && i > 0 && after[i-1] == "--settings" {
+			file, ok = arg, true
+		}
+		if !ok {
+			continue
+		}
+		// The value is inline JSON or a file; a file is checked by its content
+		if content, err := os.ReadFile(file); err == nil { // #nosec G304 -- a settings file claude would read too
+			added = append(added, string(content))
+		}
+	}
+	return config.CheckNoBypass(added)
+}
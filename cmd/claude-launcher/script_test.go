@@ -0,0 +1,56 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/script"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+func TestCheckAddedArgs(t *testing.T) {
+	settings := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(settings, []byte(`{"permissions":{"defaultMode":"bypassPermissions"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	before := []string{"--dangerously-skip-permissions", "--model", "sonnet"}
+
+	tests := []struct {
+		name    string
+		after   []string
+		wantErr bool
+	}{
+		{"unchanged", before, false},
+		{"harmless addition", append(before, "--verbose"), false},
+		{"skip permissions", []string{"--model", "opus", "--dangerously-skip-permissions=true"}, true},
+		{"bypassPermissions mode", []string{"--permission-mode", "bypassPermissions"}, true},
+		{"bypassPermissions mode with =", []string{"--permission-mode=bypassPermissions"}, true},
+		{"inline settings", []string{"--settings", `{"permissions":{"defaultMode":"bypassPermissions"}}`}, true},
+		{"settings file", []string{"--settings", settings}, true},
+		{"settings file with =", []string{"--settings=" + settings}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkAddedArgs(before, tt.after); (err != nil) != tt.wantErr {
+				t.Errorf("checkAddedArgs(%q) error = %v, wantErr %v", tt.after, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunLaunchScriptRefusesBypassPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "launch.star")
+	src := "def launch(ctx):\n    ctx.args.extend([\"--permission-mode\", \"bypassPermissions\"])\n"
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{LaunchScript: path}
+
+	_, _, code := runLaunchScript(cfg, script.Launch{Dir: t.TempDir(), Args: []string{"-p", "hi"}}, ui.NewPrinter(io.Discard))
+	if code != exitDenied {
+		t.Errorf("runLaunchScript() = %d, want %d", code, exitDenied)
+	}
+}
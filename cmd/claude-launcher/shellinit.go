@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// shellFunctionName matches the names accepted for the wrapper function
+var shellFunctionName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// shellInitWrappers define the wrapper function ({{name}}) and attach the claude-launcher
+// completion to it
+var shellInitWrappers = map[string]string{
+	"bash": `{{name}}() { command claude-launcher "$@"; }
+complete -F _claude_launcher {{name}}
+`,
+	"zsh": `{{name}}() { command claude-launcher "$@" }
+compdef _claude_launcher {{name}}
+`,
+	"fish": `function {{name}} --wraps claude-launcher --description 'claude-launcher'
+    command claude-launcher $argv
+end
+`,
+}
+
+// shellInitHooks report whether a launch would be allowed in the new directory after each cd,
+// when that differs from the previous directory. explain exits with 3 when it would be refused.
+var shellInitHooks = map[string]string{
+	// The previous exit status is kept for the prompt
+	"bash": `_claude_launcher_chpwd() {
+    local last_status=$?
+    if [[ $PWD != "${_claude_launcher_pwd-}" ]]; then
+        _claude_launcher_pwd=$PWD
+        claude-launcher explain >/dev/null 2>&1
+        local status_code=$?
+        if [[ $status_code != "${_claude_launcher_status-}" ]]; then
+            _claude_launcher_status=$status_code
+            case $status_code in
+                0) echo "claude-launcher: ✓ launches allowed here" >&2 ;;
+                3) echo "claude-launcher: ✗ launches not allowed here" >&2 ;;
+            esac
+        fi
+    fi
+    return $last_status
+}
+PROMPT_COMMAND="_claude_launcher_chpwd${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+`,
+	"zsh": `_claude_launcher_chpwd() {
+    claude-launcher explain >/dev/null 2>&1
+    local status_code=$?
+    [[ $status_code == "${_claude_launcher_status-}" ]] && return
+    _claude_launcher_status=$status_code
+    case $status_code in
+        0) echo "claude-launcher: ✓ launches allowed here" >&2 ;;
+        3) echo "claude-launcher: ✗ launches not allowed here" >&2 ;;
+    esac
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd _claude_launcher_chpwd
+_claude_launcher_chpwd
+`,
+	"fish": `function _claude_launcher_chpwd --on-variable PWD
+    claude-launcher explain >/dev/null 2>&1
+    set -l status_code $status
+    test "$status_code" = "$_claude_launcher_status"; and return
+    set -g _claude_launcher_status $status_code
+    switch $status_code
+        case 0
+            echo "claude-launcher: ✓ launches allowed here" >&2
+        case 3
+            echo "claude-launcher: ✗ launches not allowed here" >&2
+    end
+end
+_claude_launcher_chpwd
+`,
+}
+
+// shellInitFlags holds the options of `shell-init`
+type shellInitFlags struct {
+	name  string
+	chpwd bool
+}
+
+// newShellInitFlags defines the options of `shell-init`
+func newShellInitFlags() (*flag.FlagSet, *shellInitFlags) {
+	fs := flag.NewFlagSet("shell-init", flag.ContinueOnError)
+	f := &shellInitFlags{}
+	fs.StringVar(&f.name, "name", "cl", "Name of the wrapper function (`NAME`)")
+	fs.BoolVar(&f.chpwd, "chpwd", false, "Report after cd whether launches are allowed in the new directory")
+	return fs, f
+}
+
+// runShellInit implements `claude-launcher shell-init <bash|zsh|fish>`: it prints the
+// completion script, a wrapper function using it and optionally the cd hook, for e.g.
+// eval "$(claude-launcher shell-init bash)"
+func runShellInit(args []string) int {
+	fs, f := newShellInitFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	// Accept the options after the shell as well
+	shell := fs.Arg(0)
+	if fs.NArg() > 0 {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return exitError
+		}
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if shell == "" || fs.NArg() > 0 {
+		printer.Error("Usage: claude-launcher shell-init <bash|zsh|fish> [--name NAME] [--chpwd]\n")
+		return exitError
+	}
+	if _, ok := completionScripts[shell]; !ok {
+		printer.Error("Unknown shell %q (available: bash, zsh, fish)\n", shell)
+		return exitError
+	}
+	if !shellFunctionName.MatchString(f.name) {
+		printer.Error("Invalid function name %q\n", f.name)
+		return exitError
+	}
+
+	fmt.Print(shellInitScript(shell, f.name, f.chpwd))
+	return exitSuccess
+}
+
+// shellInitScript returns the shell-init output for shell
+func shellInitScript(shell, name string, chpwd bool) string {
+	script := completionScripts[shell] + strings.ReplaceAll(shellInitWrappers[shell], "{{name}}", name)
+	if chpwd {
+		script += shellInitHooks[shell]
+	}
+	return script
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShellInitScript(t *testing.T) {
+	for shell := range completionScripts {
+		t.Run(shell, func(t *testing.T) {
+			got := shellInitScript(shell, "cx", false)
+			if !strings.HasPrefix(got, completionScripts[shell]) {
+				t.Errorf("script does not start with the completion script")
+			}
+			if strings.Contains(got, "{{name}}") || !strings.Contains(got, "cx") {
+				t.Errorf("script does not define the cx function:\n%s", got)
+			}
+			if strings.Contains(got, "_claude_launcher_chpwd") {
+				t.Errorf("script contains the cd hook without --chpwd")
+			}
+			if got := shellInitScript(shell, "cx", true); !strings.Contains(got, "_claude_launcher_chpwd") {
+				t.Errorf("script does not contain the cd hook with --chpwd")
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/remote"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// sshFlags holds the options of `ssh`
+type sshFlags struct {
+	accountName string
+}
+
+// newSSHFlags defines the options of `ssh`
+func newSSHFlags() (*flag.FlagSet, *sshFlags) {
+	fs := flag.NewFlagSet("ssh", flag.ContinueOnError)
+	f := &sshFlags{}
+	fs.StringVar(&f.accountName, "a", "", "Account `NAME` whose remote config directory is used (from the host's accounts)")
+	fs.StringVar(&f.accountName, "account", "", "Account name (long form)")
+	return fs, f
+}
+
+// runSSH implements `claude-launcher ssh HOST:PATH [-- CLAUDE_ARGS]`: it runs claude in PATH on
+// a host from remoteHosts, after the host checked PATH against its allowed directories
+func runSSH(args []string) int {
+	fs, f := newSSHFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	// Accept the options after the target as well; "--" ends them
+	target := fs.Arg(0)
+	if fs.NArg() > 0 {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return exitError
+		}
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	if target == "" {
+		printer.Error("Usage: claude-launcher ssh [-a ACCOUNT] HOST:PATH [-- CLAUDE_ARGS]\n")
+		return exitError
+	}
+	hostName, dir, err := remote.ParseTarget(target)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return exitError
+	}
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+	host, ok := cfg.RemoteHosts[hostName]
+	if !ok {
+		printer.Error("✗ Host '%s' is not in remoteHosts\n", hostName)
+		return exitDenied
+	}
+	// yoloAllowedDirs only covers local directories
+	if launcher.HasSkipPermissions(fs.Args()) {
+		printer.Error("✗ --dangerously-skip-permissions is not allowed on remote hosts\n")
+		return exitDenied
+	}
+
+	l := remote.Launch{Dir: dir, AllowedDirs: host.AllowedDirs, Claude: host.Claude, Args: fs.Args()}
+	if f.accountName != "" {
+		configDir, ok := host.Accounts[f.accountName]
+		if !ok {
+			printer.Error("✗ Account '%s' has no config directory on %s\n", f.accountName, hostName)
+			return exitError
+		}
+		l.ConfigDir = configDir
+	}
+
+	sshPath, err := exec.LookPath("ssh")
+	if err != nil {
+		printer.Error("✗ %s not found in PATH\n", "ssh")
+		return exitNotFound
+	}
+
+	printer.ShowRemoteLaunch(dir, hostName)
+	// #nosec G204 -- the host is from remoteHosts and the script quotes every value
+	cmd := exec.Command(sshPath, l.SSHArgs(hostName)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode() // claude's, the remote check's or ssh's own (255)
+		}
+		printer.Error("Failed to run ssh: %v\n", err)
+		return exitError
+	}
+	return exitSuccess
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"math"
+	"os"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// statsJSON is the `stats --json` output
+type statsJSON struct {
+	Launches     int              `json:"launches"`
+	Projects     []launcher.Count `json:"projects"`
+	Accounts     []launcher.Count `json:"accounts"` // "" is the default configuration
+	Presets      []launcher.Count `json:"presets"`
+	SessionHours sessionHoursJSON `json:"sessionHours"`
+}
+
+// sessionHoursJSON is the observed session time in hours, rounded to 0.01
+type sessionHoursJSON struct {
+	Week  float64 `json:"week"`
+	Month float64 `json:"month"`
+}
+
+// newStatsJSON builds the `stats --json` output
+func newStatsJSON(s launcher.Stats) statsJSON {
+	hours := func(d time.Duration) float64 { return math.Round(d.Hours()*100) / 100 }
+	return statsJSON{
+		Launches: s.Launches,
+		Projects: s.Projects,
+		Accounts: s.Accounts,
+		Presets:  s.Presets,
+		SessionHours: sessionHoursJSON{
+			Week:  hours(s.WeekTime),
+			Month: hours(s.MonthTime),
+		},
+	}
+}
+
+// costStatsJSON is the `stats --cost --json` output
+type costStatsJSON struct {
+	Sessions int                   `json:"sessions"`
+	Total    launcher.Tokens       `json:"total"`
+	Projects []launcher.TokenCount `json:"projects"`
+	Accounts []launcher.TokenCount `json:"accounts"` // "" is the default configuration
+}
+
+// statsFlags holds the options of `stats`
+type statsFlags struct {
+	cost bool
+}
+
+// newStatsFlags defines the options of `stats`
+func newStatsFlags() (*flag.FlagSet, *statsFlags) {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	f := &statsFlags{}
+	fs.BoolVar(&f.cost, "cost", false, "Show the tokens and estimated cost of the sessions per project and account")
+	return fs, f
+}
+
+// runStats implements `claude-launcher stats`: a summary of the launch history
+func runStats(args []string) int {
+	fs, f := newStatsFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	store, err := state.NewStore()
+	if err != nil {
+		printer.Error("Failed to open state directory: %v\n", err)
+		return exitError
+	}
+	records, err := launcher.History(store)
+	if err != nil {
+		printer.Error("Failed to read launch history: %v\n", err)
+		return exitError
+	}
+
+	if f.cost {
+		c := launcher.SummarizeCost(records, func(dir string) string { return projectKey(cfg, dir) })
+		if jsonOutput {
+			return printJSON(costStatsJSON{Sessions: c.Sessions, Total: c.Total, Projects: c.Projects, Accounts: c.Accounts})
+		}
+		printer.ShowCostStats(c)
+		return exitSuccess
+	}
+
+	stats := launcher.Summarize(records, time.Now(), func(dir string) string { return projectKey(cfg, dir) })
+	if jsonOutput {
+		return printJSON(newStatsJSON(stats))
+	}
+	printer.ShowStats(stats)
+	return exitSuccess
+}
+
+// projectKey is the name a launch directory is counted under: the configured project
+// containing it, or the directory itself
+func projectKey(cfg *config.Config, dir string) string {
+	if project := cfg.FindProject(dir); project != nil {
+		return project.Path
+	}
+	return dir
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+func TestProjectKey(t *testing.T) {
+	cfg := &config.Config{Projects: []config.Project{{Path: "/work/api"}}}
+
+	if got := projectKey(cfg, "/work/api/internal"); got != "/work/api" {
+		t.Errorf("projectKey() = %q, expected the project path", got)
+	}
+	if got := projectKey(cfg, "/home/me/blog"); got != "/home/me/blog" {
+		t.Errorf("projectKey() = %q, expected the directory itself", got)
+	}
+}
+
+func TestNewStatsJSON(t *testing.T) {
+	out := newStatsJSON(launcher.Stats{WeekTime: 100 * time.Minute, MonthTime: 20 * time.Hour})
+
+	if out.SessionHours.Week != 1.67 || out.SessionHours.Month != 20 {
+		t.Errorf("SessionHours = %+v, expected 1.67 and 20", out.SessionHours)
+	}
+}
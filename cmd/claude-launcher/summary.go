@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/event"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// gitBaseline is the state of a git working tree when a session starts
+type gitBaseline struct {
+	commit    string   // A commit of the working tree (git stash create), or HEAD when it was clean
+	untracked []string // Untracked files, which the commit leaves out
+}
+
+// subscribeSessionSummary prints a summary when an interactive foreground session ends (unless
+// sessionSummary is false): how long it ran, how it exited, the files it changed in the git
+// working tree, its token usage and how to resume it
+func subscribeSessionSummary(bus *event.Bus) {
+	var cfg *config.Config
+	var opts *launcher.LaunchOptions
+	var baseline *gitBaseline
+	event.On(bus, func(e event.ConfigLoaded) error {
+		cfg = e.Config
+		return nil
+	})
+	event.On(bus, func(e event.LaunchStarted) error {
+		if e.Options.Mode != "" || cfg == nil || !cfg.ShowsSessionSummary() || isPrintMode(e.Options.Args) {
+			return nil
+		}
+		opts = &e.Options
+		baseline = newGitBaseline(e.Options.Dir)
+		return nil
+	})
+	event.On(bus, func(e event.LaunchExited) error {
+		if opts == nil || e.Record.Mode != launcher.ModeForeground || e.Record.Error != "" {
+			return nil
+		}
+		summary := ui.SessionSummary{
+			Duration: e.Record.Duration(),
+			ExitCode: e.Record.ExitCode,
+			Usage:    e.Record.Usage,
+			Resume:   resumeCommand(opts.Dir, opts.Account),
+		}
+		if baseline != nil {
+			summary.Git = true
+			summary.FilesChanged, summary.Insertions, summary.Deletions = baseline.changes(opts.Dir)
+		}
+		ui.NewPrinter(os.Stderr).ShowSessionSummary(summary)
+		return nil
+	})
+}
+
+// newGitBaseline records the working tree of dir, or returns nil outside git repositories.
+// git stash create writes a commit object without touching the working tree or the stash list.
+func newGitBaseline(dir string) *gitBaseline {
+	commit, err := gitOutput(dir, "stash", "create")
+	if err == nil && commit == "" {
+		commit, err = gitOutput(dir, "rev-parse", "--verify", "--quiet", "HEAD")
+	}
+	if err != nil || commit == "" {
+		return nil
+	}
+	untracked, err := gitOutput(dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil
+	}
+	return &gitBaseline{commit: commit, untracked: outputLines(untracked)}
+}
+
+// changes returns the files changed in the working tree of dir since the baseline, with the
+// lines inserted and deleted in them (new untracked files count as changed, without lines)
+func (b *gitBaseline) changes(dir string) (files, insertions, deletions int) {
+	numstat, err := gitOutput(dir, "diff", "--numstat", b.commit)
+	if err != nil {
+		return 0, 0, 0
+	}
+	for _, line := range outputLines(numstat) {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		files++
+		// Binary files show "-" instead of line counts
+		added, _ := strconv.Atoi(fields[0])   //nolint:errcheck // "-" counts as 0
+		removed, _ := strconv.Atoi(fields[1]) //nolint:errcheck // "-" counts as 0
+		insertions += added
+		deletions += removed
+	}
+
+	untracked, err := gitOutput(dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return files, insertions, deletions
+	}
+	for _, path := range outputLines(untracked) {
+		if !slices.Contains(b.untracked, path) {
+			files++
+		}
+	}
+	return files, insertions, deletions
+}
+
+// outputLines splits command output into its non-empty lines
+func outputLines(s string) []string {
+	return slices.DeleteFunc(strings.Split(s, "\n"), func(line string) bool { return line == "" })
+}
+
+// gitOutput runs git in dir and returns its trimmed output
+func gitOutput(dir string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// resumeCommand returns the command continuing the session in dir with the account
+func resumeCommand(dir, accountName string) string {
+	quote := func(s string) string {
+		if strings.ContainsAny(s, " \t'\"$`\\!*?&;|<>()[]{}#~") {
+			return shQuote(s)
+		}
+		return s
+	}
+	path := quote(tildePath(dir))
+	if rest, ok := strings.CutPrefix(tildePath(dir), "~/"); ok {
+		path = "~/" + quote(rest)
+	}
+	command := "claude-launcher -d " + path
+	if accountName != "" {
+		command += " -a " + quote(accountName)
+	}
+	return command + " --continue"
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitBaselineChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if newGitBaseline(repo) != nil {
+		t.Error("newGitBaseline() outside a repository should be nil")
+	}
+	git("init", "-q")
+	write("main.go", "a\nb\n")
+	git("add", "main.go")
+	git("commit", "-q", "-m", "init")
+
+	// Changes made before the session do not count
+	write("main.go", "a\nb\nc\n")
+	write("notes.txt", "todo\n")
+	baseline := newGitBaseline(repo)
+	if baseline == nil {
+		t.Fatal("newGitBaseline() = nil in a repository")
+	}
+
+	write("main.go", "a\nc\nd\ne\n")
+	write("new.go", "package main\n")
+	files, insertions, deletions := baseline.changes(repo)
+	if files != 2 || insertions != 2 || deletions != 1 {
+		t.Errorf("changes() = %d files (+%d -%d), want 2 files (+2 -1)", files, insertions, deletions)
+	}
+}
+
+func TestResumeCommand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		dir, account, want string
+	}{
+		{filepath.Join(home, "develop", "api"), "", "claude-launcher -d ~/develop/api --continue"},
+		{filepath.Join(home, "my project"), "Work", "claude-launcher -d ~/'my project' -a Work --continue"},
+		{"/srv/app", "Work Account", "claude-launcher -d /srv/app -a 'Work Account' --continue"},
+	}
+	for _, tt := range tests {
+		if got := resumeCommand(tt.dir, tt.account); got != tt.want {
+			t.Errorf("resumeCommand(%q, %q) = %q, want %q", tt.dir, tt.account, got, tt.want)
+		}
+	}
+}
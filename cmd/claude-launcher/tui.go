@@ -0,0 +1,106 @@
+package main
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/account"
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/security"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/tui"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// runTUI implements `claude-launcher tui`: a full-screen menu of launch targets, accounts and
+// sessions. The choice goes through the same flows as the matching command.
+func runTUI(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	if !ui.Interactive() {
+		printer.Error("✗ tui needs a terminal on stdin and stderr and is not available in accessible mode\n")
+		return exitError
+	}
+
+	st, err := tuiState(cfg)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return exitError
+	}
+
+	choice, err := tui.Run(st, os.Stderr)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return exitError
+	}
+
+	var flags []string
+	if choice.Account != "" {
+		flags = []string{"--account", choice.Account}
+	}
+
+	switch choice.Action {
+	case tui.ActionLaunch:
+		return launch(slices.Concat(flags, []string{"--dir", choice.Dir, "--"}, args))
+	case tui.ActionProfile:
+		launchProfile = choice.Name
+		// The profile's own account, if any, comes later and wins
+		return launch(slices.Concat(flags, profileArgs(cfg.Profiles[choice.Name], args)))
+	case tui.ActionWorkspace:
+		return openWorkspace(cfg, choice.Name, flags, args, printer)
+	case tui.ActionAttach:
+		return runAttach([]string{choice.Name})
+	default:
+		return exitAborted
+	}
+}
+
+// tuiState collects what the menu shows
+func tuiState(cfg *config.Config) (tui.State, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return tui.State{}, err
+	}
+	allowed, _ := security.NewDirectoryChecker(cfg.AllowedDirs).IsAllowed(currentDir) //nolint:errcheck // unresolvable directories are shown as not allowed
+
+	st := tui.State{
+		CurrentDir: currentDir,
+		Allowed:    allowed,
+		Profiles:   slices.Sorted(maps.Keys(cfg.Profiles)),
+		Workspaces: slices.Sorted(maps.Keys(cfg.Workspaces)),
+	}
+	for _, p := range cfg.Projects {
+		st.Projects = append(st.Projects, p.Path)
+	}
+
+	if accCfg, err := account.LoadAccountConfig(); err == nil && accCfg != nil {
+		for _, acc := range accCfg.Accounts {
+			st.Accounts = append(st.Accounts, acc.Name)
+		}
+	}
+
+	if store, err := state.NewStore(); err == nil {
+		instances, _ := store.PruneInstances() //nolint:errcheck // sessions are optional in the menu
+		now := time.Now()
+		for _, inst := range instances {
+			if inst.Socket == "" {
+				continue
+			}
+			st.Sessions = append(st.Sessions, tui.Session{
+				ID:      inst.ID,
+				Dir:     inst.Dir,
+				Account: inst.Account,
+				Age:     ui.FormatAge(now.Sub(inst.StartedAt)),
+			})
+		}
+	}
+
+	return st, nil
+}
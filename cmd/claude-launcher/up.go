@@ -0,0 +1,119 @@
+package main
+
+import (
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// launchProfile is the name of the profile being launched, shown by --banner
+var launchProfile string
+
+// runUp implements `claude-launcher up <profile> [CLAUDE_ARGUMENTS...]`.
+// The profile is translated into launch flags, so it goes through the same checks as
+// a launch from the command line.
+func runUp(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	if len(args) == 0 && jsonOutput {
+		return printJSON(nonNilMap(cfg.Profiles))
+	}
+
+	if len(args) == 0 {
+		printer.Error("Usage: claude-launcher up <profile> [CLAUDE_ARGUMENTS...]\n")
+		printer.ShowProfiles(cfg.Profiles)
+		return exitError
+	}
+
+	name := args[0]
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		printer.Error("✗ Profile '%s' not found\n", name)
+		printer.ShowProfiles(cfg.Profiles)
+		return exitError
+	}
+
+	printer.ShowProfileSelected(name)
+	launchProfile = name
+	return launch(profileArgs(profile, args[1:]))
+}
+
+// profileArgs converts a profile into launch flags followed by the claude arguments.
+// Without a directory (aliases) the launch uses the current one.
+func profileArgs(p config.Profile, extra []string) []string {
+	return append(append(profileFlags(p), "--"), slices.Concat(p.Args, extra)...)
+}
+
+// profileFlags converts the options of a profile (everything but its claude arguments) into launch flags
+func profileFlags(p config.Profile) []string {
+	var args []string
+	if p.Dir != "" {
+		args = append(args, "--dir", p.Dir)
+	}
+	if p.Account != "" {
+		args = append(args, "--account", p.Account)
+	}
+	if p.Model != "" {
+		args = append(args, "--model", p.Model)
+	}
+	if p.Tool != "" {
+		args = append(args, "--tool", p.Tool)
+	}
+	if p.Preset != "" {
+		args = append(args, "--preset", p.Preset)
+	}
+	for _, key := range slices.Sorted(maps.Keys(p.Env.Set)) {
+		args = append(args, "--env", key+"="+p.Env.Set[key])
+	}
+	switch p.Session {
+	case config.SessionContinue:
+		args = append(args, "--continue")
+	case config.SessionNew:
+		args = append(args, "--new")
+	}
+	if p.Tmux {
+		args = append(args, "--tmux")
+	}
+	if p.Detach {
+		args = append(args, "--detach")
+	}
+	if p.Container {
+		args = append(args, "--container")
+	}
+	return args
+}
+
+// profileEnvVar names a profile applied to plain launches, e.g. exported by a direnv .envrc
+const profileEnvVar = "CLAUDE_LAUNCHER_PROFILE"
+
+// launchEnvProfile launches with the options of the profile named by CLAUDE_LAUNCHER_PROFILE
+// before args, so flags given on the command line win. The launch stays in the current
+// directory and the profile's claude arguments are not applied, since args may already
+// contain claude arguments.
+func launchEnvProfile(name string, args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return launch(args) // The launch reports the config error
+	}
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		printer.Warning("⚠ Profile '%s' from %s not found, ignoring it\n", name, profileEnvVar)
+		return launch(args)
+	}
+
+	log.Debug("profile applied", "profile", name, "from", profileEnvVar)
+	launchProfile = name
+	profile.Dir = ""
+	return launch(slices.Concat(profileFlags(profile), args))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/event"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/ui"
+	"github.com/23prime/claude-launcher/internal/update"
+)
+
+// updateNoticeWait bounds how long the exit waits for a check still in flight
+const updateNoticeWait = 500 * time.Millisecond
+
+// subscribeUpdateCheck checks for a newer launcher release in the background while claude
+// runs in the foreground, and prints a notice once it exits
+func subscribeUpdateCheck(bus *event.Bus) {
+	var cfg *config.Config
+	var latest chan string
+	event.On(bus, func(e event.ConfigLoaded) error {
+		cfg = e.Config
+		return nil
+	})
+	event.On(bus, func(e event.LaunchStarted) error {
+		if e.Options.Mode != "" || !updateCheckEnabled(cfg) {
+			return nil
+		}
+		store, err := state.NewStore()
+		if err != nil {
+			return nil
+		}
+		latest = make(chan string, 1)
+		go func() {
+			version, err := (&update.Checker{Store: store}).Latest(context.Background())
+			if err != nil {
+				log.Debug("update check failed", "error", err)
+			}
+			latest <- version
+		}()
+		return nil
+	})
+	event.On(bus, func(e event.LaunchExited) error {
+		if latest == nil || e.Record.Mode != launcher.ModeForeground {
+			return nil
+		}
+		select {
+		case version := <-latest:
+			if update.Newer(Version, version) {
+				ui.NewPrinter(os.Stderr).Warning("⚠ claude-launcher %s is available (you have %s): %s\n", version, Version, update.ReleasesPage)
+			}
+		case <-time.After(updateNoticeWait):
+			log.Debug("update check still running at exit")
+		}
+		return nil
+	})
+}
+
+// updateCheckEnabled reports whether launches check for a newer launcher: not for development
+// builds, in CI, or when turned off by updateCheck or CLAUDE_LAUNCHER_NO_UPDATE_CHECK
+func updateCheckEnabled(cfg *config.Config) bool {
+	return cfg != nil && cfg.CheckForUpdates() && os.Getenv(update.DisableEnvVar) == "" &&
+		ciEnv == nil && Version != "dev"
+}
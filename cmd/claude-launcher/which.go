@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// binaryJSON is the `which --json` output
+type binaryJSON struct {
+	Tool    string   `json:"tool"`
+	Path    string   `json:"path"`
+	Version string   `json:"version,omitempty"`
+	Command []string `json:"command"` // What is executed, e.g. node and the script behind an npm shim
+}
+
+// whichFlags holds the options of `which`
+type whichFlags struct {
+	toolName, dir string
+}
+
+// newWhichFlags defines the options of `which`
+func newWhichFlags() (*flag.FlagSet, *whichFlags) {
+	fs := flag.NewFlagSet("which", flag.ContinueOnError)
+	f := &whichFlags{}
+	fs.StringVar(&f.toolName, "tool", "", "Agent CLI (`NAME`) to look up (default: the tool configured for the directory)")
+	fs.StringVar(&f.dir, "d", "", "Directory (`DIR`) whose project settings apply (defaults to the current directory)")
+	fs.StringVar(&f.dir, "dir", "", "Directory whose project settings apply (long form)")
+	return fs, f
+}
+
+// runWhich implements `claude-launcher which`. Like `command -v`, it prints the path of the
+// binary a launch in the directory would execute to stdout, and nothing but a non-zero exit code
+// (exitNotFound) when no candidate resolves (--verbose shows the candidates tried).
+// The version follows on stderr.
+func runWhich(args []string) int {
+	fs, f := newWhichFlags()
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	currentDir, err := resolveTargetDir(f.dir)
+	if err != nil {
+		printer.Error("Failed to resolve target directory: %v\n", err)
+		return exitError
+	}
+	if f.toolName == "" {
+		f.toolName = cfg.ToolFor(cfg.FindProject(currentDir))
+	}
+	tool, err := launcher.LookupTool(f.toolName)
+	if err != nil {
+		printer.Error("%v\n", err)
+		return exitError
+	}
+
+	l := newLauncher(cfg, tool)
+	if tool == launcher.Claude {
+		applyVersionPin(l, currentDir, printer)
+	}
+	binary, err := resolveBinary(l, tool)
+	if err != nil {
+		log.Debug("no binary resolved", "tool", tool.Name(), "error", err)
+		if errors.Is(err, launcher.ErrClaudeNotFound) {
+			return exitNotFound
+		}
+		return exitError
+	}
+
+	if jsonOutput {
+		return printJSON(binary)
+	}
+	fmt.Println(binary.Path)
+	command := strings.Join(binary.Command, " ")
+	if command == binary.Path {
+		command = ""
+	}
+	printer.ShowBinaryVersion(binary.Tool, binary.Version, command)
+	return exitSuccess
+}
+
+// resolveBinary resolves the binary l would execute and asks it for its version.
+// A binary that does not report a version is still returned, without one.
+func resolveBinary(l *launcher.Launcher, tool launcher.Tool) (binaryJSON, error) {
+	info, err := l.Preflight("")
+	if err != nil {
+		return binaryJSON{}, err
+	}
+
+	binary := binaryJSON{
+		Tool:    tool.Name(),
+		Path:    info.Path,
+		Command: append([]string{l.ClaudePath}, l.ClaudeArgs...),
+	}
+	if version, err := launcher.ClaudeVersion(info.Path); err == nil {
+		binary.Version = version
+	} else {
+		log.Debug("version unavailable", "path", info.Path, "error", err)
+	}
+	return binary, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+func TestResolveBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the fake binary")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "claude")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho '2.0.14 (Claude Code)'\n"), 0o755); err != nil {
+		t.Fatalf("failed to create fake claude: %v", err)
+	}
+
+	l := launcher.NewLauncher()
+	l.Candidates = []string{filepath.Join(dir, "missing"), path}
+	binary, err := resolveBinary(l, launcher.Claude)
+	if err != nil {
+		t.Fatalf("resolveBinary() error = %v", err)
+	}
+	if binary.Tool != "claude" || binary.Path != path || binary.Version != "2.0.14" {
+		t.Errorf("resolveBinary() = %+v, expected claude 2.0.14 at %s", binary, path)
+	}
+	if len(binary.Command) != 1 || binary.Command[0] != path {
+		t.Errorf("Command = %v, expected [%s]", binary.Command, path)
+	}
+
+	l = launcher.NewLauncher()
+	l.Candidates = []string{filepath.Join(dir, "missing")}
+	if _, err := resolveBinary(l, launcher.Claude); !errors.Is(err, launcher.ErrClaudeNotFound) {
+		t.Errorf("resolveBinary() error = %v, expected ErrClaudeNotFound", err)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// runWorkspace implements `claude-launcher workspace open <name> [CLAUDE_ARGUMENTS...]`
+// and `claude-launcher workspace list`.
+// Every member must be allowed; one becomes the working directory and the others are
+// passed to claude with --add-dir through the regular launch flow.
+func runWorkspace(args []string) int {
+	printer := ui.NewPrinter(os.Stderr)
+
+	cfg, ok := loadConfig(printer)
+	if !ok {
+		return exitConfig
+	}
+
+	if len(args) == 0 || args[0] == "list" {
+		if jsonOutput {
+			return printJSON(nonNilMap(cfg.Workspaces))
+		}
+		printer.ShowWorkspaces(cfg.Workspaces)
+		return exitSuccess
+	}
+
+	if args[0] != "open" || len(args) < 2 {
+		printer.Error("Usage: claude-launcher workspace open <name> [CLAUDE_ARGUMENTS...]\n")
+		printer.ShowWorkspaces(cfg.Workspaces)
+		return exitError
+	}
+
+	return openWorkspace(cfg, args[1], nil, args[2:], printer)
+}
+
+// openWorkspace checks and launches the workspace name.
+// flags are launch flags placed before the workspace's own (e.g. --account from the tui).
+func openWorkspace(cfg *config.Config, name string, flags, extra []string, printer *ui.Printer) int {
+	ws, ok := cfg.Workspaces[name]
+	if !ok {
+		printer.Error("✗ Workspace '%s' not found\n", name)
+		printer.ShowWorkspaces(cfg.Workspaces)
+		return exitError
+	}
+
+	for _, dir := range ws.Dirs {
+		spinner := printer.Spinner()
+		allowed, err := newDirectoryChecker(cfg.AllowedDirs, spinner).IsAllowed(dir)
+		spinner.Stop()
+		if err != nil || !allowed {
+			printer.ShowAccessDenied(dir, cfg.AllowedDirs)
+			return exitDenied
+		}
+	}
+
+	primary := ws.Primary
+	if primary == "" && stdinPassThrough(extra) {
+		// Like an empty answer to the menu
+		primary = ws.Dirs[0]
+	} else if primary == "" {
+		var err error
+		primary, err = selectWorkspaceMember(name, ws.Dirs)
+		if err != nil {
+			printer.Error("Failed to select directory: %v\n", err)
+			return promptExitCode(err)
+		}
+	}
+
+	printer.ShowWorkspaceOpened(name, primary)
+	return launch(append(slices.Clone(flags), workspaceArgs(ws, primary, extra)...))
+}
+
+// selectWorkspaceMember asks which member of the workspace to use as the working directory.
+// A single-member workspace is selected automatically.
+func selectWorkspaceMember(name string, dirs []string) (string, error) {
+	if len(dirs) == 1 {
+		return dirs[0], nil
+	}
+
+	items := make([]string, len(dirs))
+	for i, dir := range dirs {
+		items[i] = ui.TruncatePath(dir, ui.MenuWidth())
+	}
+
+	idx, err := ui.Select(fmt.Sprintf(i18n.T("Select working directory for workspace %s"), name), items)
+	if err != nil {
+		return "", err
+	}
+	return dirs[idx], nil
+}
+
+// workspaceArgs converts a workspace into launch flags followed by the claude arguments
+func workspaceArgs(ws config.Workspace, primary string, extra []string) []string {
+	args := []string{"--dir", primary}
+	for _, dir := range ws.Dirs {
+		if dir != primary {
+			args = append(args, "--add-dir", dir)
+		}
+	}
+
+	return append(append(args, "--"), slices.Clone(extra)...)
+}
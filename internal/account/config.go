@@ -3,18 +3,187 @@ package account
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/state"
 )
 
+// Account types
+const (
+	TypeSubscription = "subscription" // Signed in to a Claude plan in ConfigDir (default)
+	TypeAPIKey       = "apiKey"       // Authenticated with an Anthropic Console API key
+)
+
+// Backends an account can reach Claude through
+const (
+	BackendAnthropic = "anthropic" // The Anthropic API (default)
+	BackendBedrock   = "bedrock"   // Amazon Bedrock
+	BackendVertex    = "vertex"    // Google Cloud Vertex AI
+)
+
+// modelEnv maps the model aliases a backend can map to the variables naming their model IDs
+var modelEnv = map[string]string{
+	"opus":   "ANTHROPIC_DEFAULT_OPUS_MODEL",
+	"sonnet": "ANTHROPIC_DEFAULT_SONNET_MODEL",
+	"haiku":  "ANTHROPIC_DEFAULT_HAIKU_MODEL",
+}
+
 // Account represents a Claude account configuration
 type Account struct {
 	Name      string
+	Type      string // TypeSubscription or TypeAPIKey
 	ConfigDir string
+	APIKey    string // API-key accounts: the key, or a secret reference (keychain:, op://, env:)
 	OtelEnv   map[string]string
+	Model     string        // Optional: default model for this account
+	Proxy     Proxy         // Optional: network proxy used by this account only
+	Backend   Backend       // Optional: cloud backend the account reaches Claude through
+	Settings  string        // Optional: settings template synced into ConfigDir/settings.json
+	Env       config.EnvSet // Optional: environment variables for this account's launches
+}
+
+// LaunchEnv returns the environment variables the account sets for its launches: env.set, the
+// proxy settings, the backend settings and, for API-key accounts, ANTHROPIC_API_KEY
+func (a Account) LaunchEnv() map[string]string {
+	env := maps.Clone(a.Env.Set)
+	if env == nil {
+		env = make(map[string]string)
+	}
+	maps.Copy(env, a.Proxy.Env())
+	maps.Copy(env, a.Backend.Env())
+	if a.Type == TypeAPIKey {
+		env["ANTHROPIC_API_KEY"] = a.APIKey
+	}
+	return env
+}
+
+// Prepare readies the config directory of the account for a launch: it creates the directory of
+// an API-key or cloud backend account, which nobody signs in to, and applies the settings template
+func (a Account) Prepare() error {
+	if a.Type == TypeAPIKey || a.Backend.Name == BackendBedrock || a.Backend.Name == BackendVertex {
+		if err := os.MkdirAll(a.ConfigDir, 0o700); err != nil {
+			return fmt.Errorf("failed to create config directory of account %s: %w", a.Name, err)
+		}
+	}
+	if a.Settings != "" {
+		if err := syncSettings(a.ConfigDir, a.Settings); err != nil {
+			return fmt.Errorf("failed to apply the settings template of account %s: %w", a.Name, err)
+		}
+	}
+	return nil
+}
+
+// ManagedConfigDir returns the config directory the launcher keeps for an API-key or cloud
+// backend account without a configDir: accounts/NAME in the state directory
+func ManagedConfigDir(name string) (string, error) {
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("account name %q cannot be used as a directory name; set configDir", name)
+	}
+	dir, err := state.DefaultDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "accounts", name), nil
+}
+
+// Backend holds the cloud backend settings of an account
+type Backend struct {
+	Name       string            // BackendAnthropic, BackendBedrock or BackendVertex; "" keeps the inherited choice
+	Region     string            // AWS_REGION (Bedrock) or CLOUD_ML_REGION (Vertex)
+	AWSProfile string            // Bedrock: AWS_PROFILE
+	GCPProject string            // Vertex: ANTHROPIC_VERTEX_PROJECT_ID
+	Models     map[string]string // Model alias (opus, sonnet, haiku) -> model ID on the backend
+}
+
+// Env returns the environment variables selecting the backend. An explicit backend also
+// switches off the others, which may be enabled in the inherited environment.
+func (b Backend) Env() map[string]string {
+	env := make(map[string]string)
+	if b.Name == "" {
+		return env
+	}
+	env["CLAUDE_CODE_USE_BEDROCK"] = "0"
+	env["CLAUDE_CODE_USE_VERTEX"] = "0"
+	switch b.Name {
+	case BackendBedrock:
+		env["CLAUDE_CODE_USE_BEDROCK"] = "1"
+		setIfNotEmpty(env, "AWS_REGION", b.Region)
+		setIfNotEmpty(env, "AWS_PROFILE", b.AWSProfile)
+	case BackendVertex:
+		env["CLAUDE_CODE_USE_VERTEX"] = "1"
+		setIfNotEmpty(env, "CLOUD_ML_REGION", b.Region)
+		setIfNotEmpty(env, "ANTHROPIC_VERTEX_PROJECT_ID", b.GCPProject)
+	}
+	for alias, model := range b.Models {
+		env[modelEnv[alias]] = model
+	}
+	return env
+}
+
+// validate checks that the settings fit the backend
+func (b Backend) validate() error {
+	switch b.Name {
+	case "", BackendAnthropic:
+		if b.Region != "" || b.AWSProfile != "" || b.GCPProject != "" || len(b.Models) > 0 {
+			return fmt.Errorf("region, awsProfile, gcpProject and models need backend %s or %s", BackendBedrock, BackendVertex)
+		}
+	case BackendBedrock:
+		if b.GCPProject != "" {
+			return fmt.Errorf("gcpProject needs backend %s", BackendVertex)
+		}
+	case BackendVertex:
+		if b.AWSProfile != "" {
+			return fmt.Errorf("awsProfile needs backend %s", BackendBedrock)
+		}
+	default:
+		return fmt.Errorf("unknown backend %q (expected %s, %s or %s)", b.Name, BackendAnthropic, BackendBedrock, BackendVertex)
+	}
+	for alias := range b.Models {
+		if _, ok := modelEnv[alias]; !ok {
+			return fmt.Errorf("unknown model alias %q in models (expected opus, sonnet or haiku)", alias)
+		}
+	}
+	return nil
+}
+
+// setIfNotEmpty sets env[name] to value unless value is empty
+func setIfNotEmpty(env map[string]string, name, value string) {
+	if value != "" {
+		env[name] = value
+	}
+}
+
+// Proxy holds the network proxy settings of an account
+type Proxy struct {
+	HTTP     string `json:"http,omitempty"`     // HTTP_PROXY
+	HTTPS    string `json:"https,omitempty"`    // HTTPS_PROXY
+	NoProxy  string `json:"noProxy,omitempty"`  // NO_PROXY
+	CABundle string `json:"caBundle,omitempty"` // Extra CA certificates (NODE_EXTRA_CA_CERTS)
+}
+
+// Env returns the environment variables for the proxy settings.
+// Both upper- and lower-case names are set because tools differ in which they read.
+func (p Proxy) Env() map[string]string {
+	env := make(map[string]string)
+	for name, value := range map[string]string{
+		"HTTP_PROXY":  p.HTTP,
+		"HTTPS_PROXY": p.HTTPS,
+		"NO_PROXY":    p.NoProxy,
+	} {
+		if value != "" {
+			env[name] = value
+			env[strings.ToLower(name)] = value
+		}
+	}
+	if p.CABundle != "" {
+		env["NODE_EXTRA_CA_CERTS"] = p.CABundle
+	}
+	return env
 }
 
 // AccountConfig holds the list of configured accounts
@@ -51,7 +220,7 @@ func (e *EnvLoader) Load() (*AccountConfig, error) {
 }
 
 // parseAccountsString parses a comma-separated string of "Name:ConfigDir" pairs.
-// Note: OtelEnv is not supported via CLAUDE_ACCOUNTS; use config.json instead.
+// Note: API keys, OtelEnv, Model, Proxy, Backend, settings templates and Env are not supported via CLAUDE_ACCOUNTS; use config.json instead.
 func parseAccountsString(s string) ([]Account, error) {
 	entries := strings.Split(s, ",")
 	accounts := make([]Account, 0, len(entries))
@@ -81,6 +250,7 @@ func parseAccountsString(s string) ([]Account, error) {
 
 		accounts = append(accounts, Account{
 			Name:      name,
+			Type:      TypeSubscription,
 			ConfigDir: expandedDir,
 		})
 	}
@@ -96,8 +266,20 @@ type FileLoader struct {
 // accountJSON represents the account structure in JSON
 type accountJSON struct {
 	Name      string            `json:"name"`
+	Type      string            `json:"type,omitempty"`
 	ConfigDir string            `json:"configDir"`
+	APIKey    string            `json:"apiKey,omitempty"`
 	OtelEnv   map[string]string `json:"otelEnv,omitempty"`
+	Model     string            `json:"model,omitempty"`
+	Proxy     Proxy             `json:"proxy"`
+	Env       config.EnvSet     `json:"env"`
+
+	Settings   string            `json:"settingsTemplate,omitempty"`
+	Backend    string            `json:"backend,omitempty"`
+	Region     string            `json:"region,omitempty"`
+	AWSProfile string            `json:"awsProfile,omitempty"`
+	GCPProject string            `json:"gcpProject,omitempty"`
+	Models     map[string]string `json:"models,omitempty"`
 }
 
 // configJSON represents the structure of the config file for accounts
@@ -133,19 +315,83 @@ func (f *FileLoader) Load() (*AccountConfig, error) {
 
 	accounts := make([]Account, 0, len(cfg.Accounts))
 	for _, acc := range cfg.Accounts {
-		if acc.Name == "" || acc.ConfigDir == "" {
-			return nil, fmt.Errorf("invalid account: name and configDir cannot be empty")
+		if acc.Name == "" {
+			return nil, fmt.Errorf("invalid account: name cannot be empty")
 		}
 
-		expandedDir, err := config.ExpandPath(acc.ConfigDir)
-		if err != nil {
-			return nil, fmt.Errorf("failed to expand path %s: %w", acc.ConfigDir, err)
+		var expandedDir string
+		switch acc.Type {
+		case "", TypeSubscription:
+			switch {
+			case acc.ConfigDir == "" && (acc.Backend == BackendBedrock || acc.Backend == BackendVertex):
+				// Cloud credentials come from AWS or Google Cloud, not from a sign-in
+				if expandedDir, err = ManagedConfigDir(acc.Name); err != nil {
+					return nil, fmt.Errorf("invalid account %s: %w", acc.Name, err)
+				}
+			case acc.ConfigDir == "":
+				return nil, fmt.Errorf("invalid account: name and configDir cannot be empty")
+			}
+			if acc.APIKey != "" {
+				return nil, fmt.Errorf("invalid account %s: apiKey needs \"type\": %q", acc.Name, TypeAPIKey)
+			}
+			acc.Type = TypeSubscription
+		case TypeAPIKey:
+			if acc.APIKey == "" {
+				return nil, fmt.Errorf("invalid account %s: an %s account needs apiKey", acc.Name, TypeAPIKey)
+			}
+			if acc.ConfigDir == "" {
+				if expandedDir, err = ManagedConfigDir(acc.Name); err != nil {
+					return nil, fmt.Errorf("invalid account %s: %w", acc.Name, err)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("invalid account %s: unknown type %q (expected %s or %s)", acc.Name, acc.Type, TypeSubscription, TypeAPIKey)
+		}
+
+		if acc.ConfigDir != "" {
+			if expandedDir, err = config.ExpandPath(acc.ConfigDir); err != nil {
+				return nil, fmt.Errorf("failed to expand path %s: %w", acc.ConfigDir, err)
+			}
+		}
+
+		if err := acc.Env.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid account %s: %w", acc.Name, err)
+		}
+
+		settings := acc.Settings
+		if settings != "" {
+			if settings, err = config.ExpandPath(settings); err != nil {
+				return nil, fmt.Errorf("failed to expand path %s: %w", acc.Settings, err)
+			}
+		}
+
+		backend := Backend{Name: acc.Backend, Region: acc.Region, AWSProfile: acc.AWSProfile, GCPProject: acc.GCPProject, Models: acc.Models}
+		if err := backend.validate(); err != nil {
+			return nil, fmt.Errorf("invalid account %s: %w", acc.Name, err)
+		}
+		if acc.Type == TypeAPIKey && backend.Name != "" && backend.Name != BackendAnthropic {
+			return nil, fmt.Errorf("invalid account %s: %s accounts use the %s backend", acc.Name, TypeAPIKey, BackendAnthropic)
+		}
+
+		proxy := acc.Proxy
+		if proxy.CABundle != "" {
+			proxy.CABundle, err = config.ExpandPath(proxy.CABundle)
+			if err != nil {
+				return nil, fmt.Errorf("failed to expand path %s: %w", acc.Proxy.CABundle, err)
+			}
 		}
 
 		accounts = append(accounts, Account{
 			Name:      acc.Name,
+			Type:      acc.Type,
 			ConfigDir: expandedDir,
+			APIKey:    acc.APIKey,
 			OtelEnv:   acc.OtelEnv,
+			Model:     acc.Model,
+			Proxy:     proxy,
+			Backend:   backend,
+			Settings:  settings,
+			Env:       acc.Env,
 		})
 	}
 
@@ -163,8 +409,10 @@ func (c *ChainLoader) Load() (*AccountConfig, error) {
 	for _, loader := range c.Loaders {
 		cfg, err := loader.Load()
 		if err == nil {
+			log.Debug("accounts loaded", "loader", fmt.Sprintf("%T", loader), "count", len(cfg.Accounts))
 			return cfg, nil
 		}
+		log.Debug("accounts loader failed", "loader", fmt.Sprintf("%T", loader), "error", err)
 	}
 
 	// No accounts configured - this is not an error, just no accounts
@@ -185,3 +433,23 @@ func LoadAccountConfig() (*AccountConfig, error) {
 
 	return loader.Load()
 }
+
+// Lint finds accounts sharing a config directory: they would share credentials and sessions,
+// so choosing between them makes no difference
+func (c *AccountConfig) Lint() []config.Finding {
+	var findings []config.Finding
+	seen := make(map[string]string, len(c.Accounts))
+	for _, acc := range c.Accounts {
+		dir := filepath.Clean(acc.ConfigDir)
+		if first, ok := seen[dir]; ok {
+			findings = append(findings, config.Finding{
+				Rule:    config.LintDuplicateConfigDir,
+				Message: fmt.Sprintf("accounts %s and %s use the same config directory %s", first, acc.Name, acc.ConfigDir),
+				Fix:     fmt.Sprintf("give %s its own configDir, or remove it", acc.Name),
+			})
+			continue
+		}
+		seen[dir] = acc.Name
+	}
+	return findings
+}
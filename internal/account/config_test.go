@@ -3,7 +3,10 @@ package account
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
 )
 
 func TestParseAccountsString(t *testing.T) {
@@ -447,3 +450,251 @@ func TestFileLoaderAccountOtelEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestFileLoaderAccountProxy(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "config.json")
+	jsonContent := `{
+		"accounts": [
+			{
+				"name": "Work",
+				"configDir": "/home/user/.claude-work",
+				"proxy": {"https": "http://proxy.corp:3128", "noProxy": "localhost,.corp", "caBundle": "~/corp-ca.pem"}
+			},
+			{"name": "Personal", "configDir": "/home/user/.claude-personal"}
+		]
+	}`
+	if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	cfg, err := (&FileLoader{Path: testFile}).Load()
+	if err != nil {
+		t.Fatalf("FileLoader.Load() error = %v", err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("failed to get home directory: %v", err)
+	}
+
+	env := cfg.Accounts[0].Proxy.Env()
+	expected := map[string]string{
+		"HTTPS_PROXY":         "http://proxy.corp:3128",
+		"https_proxy":         "http://proxy.corp:3128",
+		"NO_PROXY":            "localhost,.corp",
+		"no_proxy":            "localhost,.corp",
+		"NODE_EXTRA_CA_CERTS": filepath.Join(home, "corp-ca.pem"),
+	}
+	if len(env) != len(expected) {
+		t.Errorf("Proxy.Env() = %v, expected %v", env, expected)
+	}
+	for k, v := range expected {
+		if env[k] != v {
+			t.Errorf("Proxy.Env()[%q] = %q, expected %q", k, env[k], v)
+		}
+	}
+
+	if env := cfg.Accounts[1].Proxy.Env(); len(env) != 0 {
+		t.Errorf("Proxy.Env() for an account without proxy = %v, expected empty", env)
+	}
+}
+
+func TestFileLoaderAccountEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		wantErr bool
+	}{
+		{name: "valid", env: `{"set": {"ANTHROPIC_API_KEY": "op://dev/anthropic/key"}}`},
+		{name: "invalid name", env: `{"set": {"A=B": "x"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			jsonContent := `{"accounts": [{"name": "Work", "configDir": "/home/user/.claude-work", "env": ` + tt.env + `}]}`
+			if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FileLoader.Load() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileLoader.Load() error = %v", err)
+			}
+
+			// References are kept as written; they are resolved at launch time
+			if got := cfg.Accounts[0].Env.Set["ANTHROPIC_API_KEY"]; got != "op://dev/anthropic/key" {
+				t.Errorf("Env.Set[ANTHROPIC_API_KEY] = %q, expected the reference", got)
+			}
+		})
+	}
+}
+
+func TestFileLoaderAPIKeyAccount(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+
+	tests := []struct {
+		name          string
+		account       string
+		wantErr       bool
+		wantConfigDir string
+	}{
+		{name: "managed config dir", account: `{"name": "Console", "type": "apiKey", "apiKey": "env:ANTHROPIC_KEY"}`, wantConfigDir: filepath.Join(state, "claude-launcher", "accounts", "Console")},
+		{name: "own config dir", account: `{"name": "Console", "type": "apiKey", "apiKey": "keychain:anthropic", "configDir": "/home/user/.claude-api"}`, wantConfigDir: "/home/user/.claude-api"},
+		{name: "missing key", account: `{"name": "Console", "type": "apiKey"}`, wantErr: true},
+		{name: "key without type", account: `{"name": "Console", "configDir": "/home/user/.claude", "apiKey": "sk-x"}`, wantErr: true},
+		{name: "unknown type", account: `{"name": "Console", "type": "token", "configDir": "/home/user/.claude"}`, wantErr: true},
+		{name: "name unusable as directory", account: `{"name": "a/b", "type": "apiKey", "apiKey": "sk-x"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(testFile, []byte(`{"accounts": [`+tt.account+`]}`), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FileLoader.Load() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileLoader.Load() error = %v", err)
+			}
+			acc := cfg.Accounts[0]
+			if acc.Type != TypeAPIKey || acc.ConfigDir != tt.wantConfigDir {
+				t.Errorf("account = %+v, expected an API-key account in %s", acc, tt.wantConfigDir)
+			}
+		})
+	}
+}
+
+func TestAccountLaunchEnv(t *testing.T) {
+	acc := Account{
+		Name:   "Console",
+		Type:   TypeAPIKey,
+		APIKey: "op://dev/anthropic/key",
+		Proxy:  Proxy{HTTPS: "http://proxy:3128"},
+		Env:    config.EnvSet{Set: map[string]string{"ANTHROPIC_API_KEY": "overridden", "A": "1"}},
+	}
+	env := acc.LaunchEnv()
+	if env["ANTHROPIC_API_KEY"] != "op://dev/anthropic/key" || env["A"] != "1" || env["HTTPS_PROXY"] != "http://proxy:3128" {
+		t.Errorf("LaunchEnv() = %v", env)
+	}
+
+	acc.Type = TypeSubscription
+	if got := acc.LaunchEnv()["ANTHROPIC_API_KEY"]; got != "overridden" {
+		t.Errorf("LaunchEnv()[ANTHROPIC_API_KEY] = %q for a subscription account, expected env.set", got)
+	}
+
+	acc.Type, acc.ConfigDir = TypeAPIKey, filepath.Join(t.TempDir(), "accounts", "Console")
+	if err := acc.Prepare(); err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if info, err := os.Stat(acc.ConfigDir); err != nil || !info.IsDir() {
+		t.Errorf("Prepare() did not create %s: %v", acc.ConfigDir, err)
+	}
+}
+
+func TestFileLoaderAccountBackend(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+
+	tests := []struct {
+		name    string
+		account string
+		wantErr bool
+		wantEnv map[string]string
+	}{
+		{
+			name:    "bedrock",
+			account: `{"name": "AWS", "backend": "bedrock", "region": "us-east-1", "awsProfile": "work", "models": {"sonnet": "us.anthropic.claude-sonnet-4-5"}}`,
+			wantEnv: map[string]string{
+				"CLAUDE_CODE_USE_BEDROCK":        "1",
+				"CLAUDE_CODE_USE_VERTEX":         "0",
+				"AWS_REGION":                     "us-east-1",
+				"AWS_PROFILE":                    "work",
+				"ANTHROPIC_DEFAULT_SONNET_MODEL": "us.anthropic.claude-sonnet-4-5",
+			},
+		},
+		{
+			name:    "vertex",
+			account: `{"name": "GCP", "backend": "vertex", "region": "us-east5", "gcpProject": "my-project", "configDir": "/home/user/.claude-gcp"}`,
+			wantEnv: map[string]string{
+				"CLAUDE_CODE_USE_BEDROCK":     "0",
+				"CLAUDE_CODE_USE_VERTEX":      "1",
+				"CLOUD_ML_REGION":             "us-east5",
+				"ANTHROPIC_VERTEX_PROJECT_ID": "my-project",
+			},
+		},
+		{
+			name:    "anthropic",
+			account: `{"name": "Personal", "backend": "anthropic", "configDir": "/home/user/.claude"}`,
+			wantEnv: map[string]string{"CLAUDE_CODE_USE_BEDROCK": "0", "CLAUDE_CODE_USE_VERTEX": "0"},
+		},
+		{name: "unknown backend", account: `{"name": "X", "backend": "azure", "configDir": "/x"}`, wantErr: true},
+		{name: "region without backend", account: `{"name": "X", "region": "us-east-1", "configDir": "/x"}`, wantErr: true},
+		{name: "project on bedrock", account: `{"name": "X", "backend": "bedrock", "gcpProject": "p"}`, wantErr: true},
+		{name: "unknown model alias", account: `{"name": "X", "backend": "bedrock", "models": {"default": "m"}}`, wantErr: true},
+		{name: "api key on vertex", account: `{"name": "X", "type": "apiKey", "apiKey": "sk-x", "backend": "vertex"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(testFile, []byte(`{"accounts": [`+tt.account+`]}`), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FileLoader.Load() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileLoader.Load() error = %v", err)
+			}
+			if env := cfg.Accounts[0].LaunchEnv(); !reflect.DeepEqual(env, tt.wantEnv) {
+				t.Errorf("LaunchEnv() = %v, expected %v", env, tt.wantEnv)
+			}
+		})
+	}
+
+	// Cloud accounts need no configDir
+	testFile := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(testFile, []byte(`{"accounts": [{"name": "AWS", "backend": "bedrock"}]}`), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	cfg, err := (&FileLoader{Path: testFile}).Load()
+	if err != nil {
+		t.Fatalf("FileLoader.Load() error = %v", err)
+	}
+	if want := filepath.Join(state, "claude-launcher", "accounts", "AWS"); cfg.Accounts[0].ConfigDir != want {
+		t.Errorf("ConfigDir = %q, expected %q", cfg.Accounts[0].ConfigDir, want)
+	}
+}
+
+func TestAccountConfigLint(t *testing.T) {
+	cfg := &AccountConfig{Accounts: []Account{
+		{Name: "Personal", ConfigDir: "/home/user/.claude"},
+		{Name: "Work", ConfigDir: "/home/user/.claude-work"},
+		{Name: "Client", ConfigDir: "/home/user/.claude-work/"},
+	}}
+
+	findings := cfg.Lint()
+	if len(findings) != 1 || findings[0].Message != "accounts Work and Client use the same config directory /home/user/.claude-work/" {
+		t.Errorf("Lint() = %+v, expected Client to be reported", findings)
+	}
+}
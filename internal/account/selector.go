@@ -1,9 +1,16 @@
 package account
 
 import (
+	"errors"
 	"fmt"
-
-	"github.com/manifoldco/promptui"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/ui"
 )
 
 // Selector is an interface for selecting an account
@@ -12,6 +19,7 @@ type Selector interface {
 }
 
 // InteractiveSelector provides arrow-key based account selection
+// (a numbered list when not attached to a terminal)
 type InteractiveSelector struct{}
 
 // NewInteractiveSelector creates a new InteractiveSelector
@@ -31,24 +39,27 @@ func (s *InteractiveSelector) Select(accounts []Account) (*Account, error) {
 		return &accounts[0], nil
 	}
 
-	// Create items for the prompt
-	items := make([]string, len(accounts))
+	// Create items for the prompt: names, recent usage (when any account has some) and
+	// config dirs in aligned columns. Each account's transcripts are read concurrently, as
+	// the menu waits for the slowest.
+	now := time.Now()
+	notes := make([]string, len(accounts))
+	var wg sync.WaitGroup
 	for i, acc := range accounts {
-		items[i] = fmt.Sprintf("%s (%s)", acc.Name, acc.ConfigDir)
+		wg.Go(func() { notes[i] = usageNote(acc, now) })
 	}
-
-	prompt := promptui.Select{
-		Label: "Select Claude account",
-		Items: items,
-		Templates: &promptui.SelectTemplates{
-			Label:    "{{ . }}",
-			Active:   "\U0001F449 {{ . | cyan }}",
-			Inactive: "  {{ . }}",
-			Selected: "\U00002714 {{ . | green }}",
-		},
+	wg.Wait()
+	withNotes := slices.ContainsFunc(notes, func(note string) bool { return note != "" })
+	rows := make([][]string, len(accounts))
+	for i, acc := range accounts {
+		rows[i] = []string{acc.Name, acc.ConfigDir}
+		if withNotes {
+			rows[i] = []string{acc.Name, notes[i], acc.ConfigDir}
+		}
 	}
+	items := ui.AlignColumns(rows, ui.MenuWidth())
 
-	idx, _, err := prompt.Run()
+	idx, err := ui.Select(i18n.T("Select Claude account"), items)
 	if err != nil {
 		return nil, fmt.Errorf("account selection failed: %w", err)
 	}
@@ -56,6 +67,23 @@ func (s *InteractiveSelector) Select(accounts []Account) (*Account, error) {
 	return &accounts[idx], nil
 }
 
+// usageNote describes the use of acc within launcher.UsageWindow for the account menu: a usage limit
+// reached, or the tokens used; "" when it was not used
+func usageNote(acc Account, now time.Time) string {
+	u, err := launcher.ReadUsage(acc.ConfigDir, now.Add(-launcher.UsageWindow))
+	if err != nil {
+		log.Debug("account usage unavailable", "account", acc.Name, "error", err)
+		return ""
+	}
+	switch {
+	case !u.LimitReached.IsZero():
+		return fmt.Sprintf(i18n.T("limit reached %s"), ui.FormatAge(now.Sub(u.LimitReached)))
+	case u.Total() > 0:
+		return fmt.Sprintf(i18n.T("%s tokens in the last 5h"), ui.FormatTokens(u.Total()))
+	}
+	return ""
+}
+
 // SelectAccount loads account configuration and prompts for selection if needed
 // Returns nil if no accounts are configured (uses default)
 func SelectAccount() (*Account, error) {
@@ -107,3 +135,37 @@ func SelectAccountInteractively() (*Account, error) {
 	selector := NewInteractiveSelector()
 	return selector.Select(cfg.Accounts)
 }
+
+// ErrAccountRequired is returned when several accounts are configured but none was named
+var ErrAccountRequired = errors.New("multiple accounts configured; specify one with --account")
+
+// SelectAccountNonInteractively picks an account without prompting.
+// A named account must exist; without a name, the only configured account is used.
+// Returns nil if no accounts are configured (uses default)
+func SelectAccountNonInteractively(accountName string) (*Account, error) {
+	cfg, err := LoadAccountConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account config: %w", err)
+	}
+
+	if cfg == nil || len(cfg.Accounts) == 0 {
+		if accountName != "" {
+			return nil, fmt.Errorf("account '%s' not found", accountName)
+		}
+		return nil, nil
+	}
+
+	if accountName == "" {
+		if len(cfg.Accounts) > 1 {
+			return nil, ErrAccountRequired
+		}
+		return &cfg.Accounts[0], nil
+	}
+
+	for i := range cfg.Accounts {
+		if cfg.Accounts[i].Name == accountName {
+			return &cfg.Accounts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("account '%s' not found", accountName)
+}
@@ -1,6 +1,7 @@
 package account
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -133,3 +134,35 @@ func TestFindAccountByName_AccountNotFound(t *testing.T) {
 		t.Error("FindAccountByName() should return nil for non-existent account")
 	}
 }
+
+func TestSelectAccountNonInteractively(t *testing.T) {
+	t.Setenv("CLAUDE_ACCOUNTS", "Personal:/home/user/.claude-personal,Work:/home/user/.claude-work")
+
+	selected, err := SelectAccountNonInteractively("Work")
+	if err != nil {
+		t.Fatalf("SelectAccountNonInteractively() error = %v", err)
+	}
+	if selected == nil || selected.Name != "Work" {
+		t.Errorf("SelectAccountNonInteractively() = %v, expected Work", selected)
+	}
+
+	if _, err := SelectAccountNonInteractively(""); !errors.Is(err, ErrAccountRequired) {
+		t.Errorf("SelectAccountNonInteractively() error = %v, expected %v", err, ErrAccountRequired)
+	}
+
+	if _, err := SelectAccountNonInteractively("NonExistent"); err == nil {
+		t.Error("SelectAccountNonInteractively() should fail for a non-existent account")
+	}
+}
+
+func TestSelectAccountNonInteractively_SingleAccount(t *testing.T) {
+	t.Setenv("CLAUDE_ACCOUNTS", "Personal:/home/user/.claude-personal")
+
+	selected, err := SelectAccountNonInteractively("")
+	if err != nil {
+		t.Fatalf("SelectAccountNonInteractively() error = %v", err)
+	}
+	if selected == nil || selected.Name != "Personal" {
+		t.Errorf("SelectAccountNonInteractively() = %v, expected Personal", selected)
+	}
+}
@@ -0,0 +1,105 @@
+package account
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/23prime/claude-launcher/internal/log"
+)
+
+// syncSettings merges the settings template at template into the settings.json of configDir.
+// The template wins over what the file holds; keys it does not mention are kept, so settings
+// Claude Code or the user add to the account survive. The file is only written when it changes.
+func syncSettings(configDir, template string) error {
+	tmpl, err := readSettings(template)
+	if err != nil {
+		return fmt.Errorf("failed to read settings template: %w", err)
+	}
+	if tmpl == nil {
+		return fmt.Errorf("failed to read settings template: %s does not exist", template)
+	}
+
+	path := filepath.Join(configDir, "settings.json")
+	current, err := readSettings(path)
+	if err != nil {
+		return err
+	}
+	merged := mergeSettings(current, tmpl)
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, data) { // #nosec G304 -- the account's own settings file
+		return nil
+	}
+
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+	log.Debug("settings template applied", "template", template, "settings", path)
+	return nil
+}
+
+// readSettings reads a JSON object; nil when the file does not exist
+func readSettings(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- paths from the user's config
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var settings map[string]any
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// mergeSettings returns base with overlay merged into it: objects are merged key by key, any
+// other value of overlay (arrays included) replaces the one in base
+func mergeSettings(base, overlay map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		if sub, ok := value.(map[string]any); ok {
+			if baseSub, ok := merged[key].(map[string]any); ok {
+				merged[key] = mergeSettings(baseSub, sub)
+				continue
+			}
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// writeFileAtomic replaces path with data through a temporary file in the same directory, so
+// a Claude Code instance reading the file never sees it half-written
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }() //nolint:errcheck // already renamed on success
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close() //nolint:errcheck // the write error is reported
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
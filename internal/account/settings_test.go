@@ -0,0 +1,76 @@
+package account
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSyncSettings(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "work.json")
+	if err := os.WriteFile(template, []byte(`{
+		"model": "opus",
+		"permissions": {"deny": ["Bash(rm:*)"]},
+		"hooks": {"Stop": [{"hooks": [{"type": "command", "command": "notify"}]}]}
+	}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	configDir := filepath.Join(dir, ".claude-work")
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	settingsPath := filepath.Join(configDir, "settings.json")
+	if err := os.WriteFile(settingsPath, []byte(`{"model": "sonnet", "theme": "dark", "permissions": {"allow": ["Read"], "deny": ["WebFetch"]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := syncSettings(configDir, template); err != nil {
+		t.Fatalf("syncSettings() error = %v", err)
+	}
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"model":       "opus",
+		"theme":       "dark",
+		"permissions": map[string]any{"allow": []any{"Read"}, "deny": []any{"Bash(rm:*)"}},
+		"hooks":       map[string]any{"Stop": []any{map[string]any{"hooks": []any{map[string]any{"type": "command", "command": "notify"}}}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("settings.json = %v, expected %v", got, want)
+	}
+
+	// An unchanged file is left alone
+	info, err := os.Stat(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := syncSettings(configDir, template); err != nil {
+		t.Fatalf("syncSettings() error = %v", err)
+	}
+	if again, err := os.Stat(settingsPath); err != nil || !again.ModTime().Equal(info.ModTime()) {
+		t.Errorf("syncSettings() rewrote an up-to-date settings.json")
+	}
+
+	// Account directories are created on first use
+	fresh := filepath.Join(dir, "new")
+	if err := syncSettings(fresh, template); err != nil {
+		t.Fatalf("syncSettings() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(fresh, "settings.json")); err != nil {
+		t.Errorf("settings.json not created: %v", err)
+	}
+
+	if err := syncSettings(configDir, filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("syncSettings() should fail for a missing template")
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CIAllowEnvVar opts a CI runner or cloud development environment into launching: "1" (or
+// "true") allows its workspace, any other value is a list of allowed directories like
+// CLAUDE_SAFE_DIRS. It is ignored elsewhere.
+const CIAllowEnvVar = "CLAUDE_LAUNCHER_CI_ALLOW"
+
+// Environment is a detected CI runner or cloud development environment
+type Environment struct {
+	Name      string // e.g. "GitHub Actions"
+	Runner    bool   // A CI runner, where nobody answers prompts
+	Workspace string // The checkout or workspace folder, when the environment reports it
+}
+
+// environments are checked in order; the generic CI variable comes last
+var environments = []struct {
+	name, marker, workspace string
+	runner                  bool
+}{
+	{name: "GitHub Actions", marker: "GITHUB_ACTIONS", workspace: "GITHUB_WORKSPACE", runner: true},
+	{name: "GitLab CI", marker: "GITLAB_CI", workspace: "CI_PROJECT_DIR", runner: true},
+	{name: "Buildkite", marker: "BUILDKITE", workspace: "BUILDKITE_BUILD_CHECKOUT_PATH", runner: true},
+	{name: "CircleCI", marker: "CIRCLECI", workspace: "CIRCLE_WORKING_DIRECTORY", runner: true},
+	{name: "Azure Pipelines", marker: "TF_BUILD", workspace: "BUILD_SOURCESDIRECTORY", runner: true},
+	{name: "Jenkins", marker: "JENKINS_URL", workspace: "WORKSPACE", runner: true},
+	{name: "GitHub Codespaces", marker: "CODESPACES", workspace: "CODESPACE_VSCODE_FOLDER"},
+	{name: "Gitpod", marker: "GITPOD_WORKSPACE_ID", workspace: "GITPOD_REPO_ROOT"},
+	{name: "CI", marker: "CI", runner: true},
+}
+
+// DetectEnvironment returns the CI runner or cloud development environment the launcher runs
+// in, or nil on a regular machine
+func DetectEnvironment() *Environment {
+	for _, e := range environments {
+		switch os.Getenv(e.marker) {
+		case "", "0", "false", "False", "FALSE":
+			continue
+		}
+		env := &Environment{Name: e.name, Runner: e.runner}
+		if e.workspace != "" {
+			env.Workspace = os.Getenv(e.workspace)
+		}
+		return env
+	}
+	return nil
+}
+
+// CILoader loads the allowed directories of CI runners and cloud development environments
+// from CLAUDE_LAUNCHER_CI_ALLOW, since allowlists of local paths do not apply to ephemeral
+// machines
+type CILoader struct{}
+
+// Load implements the Loader interface for CILoader
+func (c *CILoader) Load() (*Config, error) {
+	env := DetectEnvironment()
+	if env == nil {
+		return nil, errors.New("not running in CI")
+	}
+	value := os.Getenv(CIAllowEnvVar)
+	if value == "" {
+		return nil, fmt.Errorf("%s environment variable not set", CIAllowEnvVar)
+	}
+
+	dirs := filepath.SplitList(value)
+	switch value {
+	case "1", "true":
+		workspace := env.Workspace
+		if workspace == "" {
+			var err error
+			if workspace, err = os.Getwd(); err != nil {
+				return nil, fmt.Errorf("failed to get working directory: %w", err)
+			}
+		}
+		dirs = []string{workspace}
+	}
+
+	expandedDirs := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		expanded, err := ExpandPath(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand path %s: %w", dir, err)
+		}
+		expandedDirs = append(expandedDirs, filepath.Clean(expanded))
+	}
+	if len(expandedDirs) == 0 {
+		return nil, fmt.Errorf("no valid directories in %s", CIAllowEnvVar)
+	}
+
+	return &Config{AllowedDirs: expandedDirs, DirSources: dirSources(expandedDirs, SourceCI)}, nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+// clearCIEnv unsets the variables of every known environment for the test, which may itself
+// run in CI
+func clearCIEnv(t *testing.T) {
+	t.Helper()
+	for _, e := range environments {
+		t.Setenv(e.marker, "")
+		if e.workspace != "" {
+			t.Setenv(e.workspace, "")
+		}
+	}
+	t.Setenv(CIAllowEnvVar, "")
+}
+
+func TestDetectEnvironment(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected *Environment
+	}{
+		{name: "none"},
+		{name: "CI disabled", env: map[string]string{"CI": "false"}},
+		{name: "generic", env: map[string]string{"CI": "true"}, expected: &Environment{Name: "CI", Runner: true}},
+		{
+			name:     "GitHub Actions",
+			env:      map[string]string{"CI": "true", "GITHUB_ACTIONS": "true", "GITHUB_WORKSPACE": "/home/runner/work/app/app"},
+			expected: &Environment{Name: "GitHub Actions", Runner: true, Workspace: "/home/runner/work/app/app"},
+		},
+		{
+			name:     "Codespaces",
+			env:      map[string]string{"CODESPACES": "true", "CODESPACE_VSCODE_FOLDER": "/workspaces/app"},
+			expected: &Environment{Name: "GitHub Codespaces", Workspace: "/workspaces/app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCIEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			got := DetectEnvironment()
+			if (got == nil) != (tt.expected == nil) || got != nil && *got != *tt.expected {
+				t.Errorf("DetectEnvironment() = %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCILoader(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "outside CI", env: map[string]string{CIAllowEnvVar: "1"}, wantErr: true},
+		{name: "not opted in", env: map[string]string{"CI": "true"}, wantErr: true},
+		{name: "workspace", env: map[string]string{"GITLAB_CI": "true", "CI_PROJECT_DIR": "/builds/app", CIAllowEnvVar: "1"}, expected: []string{"/builds/app"}},
+		{name: "working directory", env: map[string]string{"CI": "true", CIAllowEnvVar: "true"}, expected: []string{cwd}},
+		{name: "directory list", env: map[string]string{"CI": "true", CIAllowEnvVar: "/builds/a:/builds/b"}, expected: []string{"/builds/a", "/builds/b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCIEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := (&CILoader{}).Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CILoader.Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !slices.Equal(cfg.AllowedDirs, tt.expected) {
+				t.Errorf("AllowedDirs = %v, expected %v", cfg.AllowedDirs, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadConfigCIAllow(t *testing.T) {
+	clearCIEnv(t)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CLAUDE_SAFE_DIRS", "/home/user/projects")
+	t.Setenv("CI", "true")
+	t.Setenv(CIAllowEnvVar, "/builds/app")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !slices.Equal(cfg.AllowedDirs, []string{"/builds/app"}) {
+		t.Errorf("AllowedDirs = %v, expected the CI opt-in to replace CLAUDE_SAFE_DIRS", cfg.AllowedDirs)
+	}
+}
@@ -3,15 +3,674 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+
+	"github.com/23prime/claude-launcher/internal/home"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/plugin"
+	"github.com/23prime/claude-launcher/internal/security"
+	"github.com/23prime/claude-launcher/internal/snapshot"
+	"github.com/23prime/claude-launcher/internal/state"
 )
 
 // Config represents the configuration for claude-launcher
 type Config struct {
-	AllowedDirs []string
-	OtelEnv     map[string]string
+	AllowedDirs       []string
+	SharedDirs        []string             // Allowed directories passed to claude with --add-dir (shareWithClaude)
+	YoloAllowedDirs   []string             // Directories where --dangerously-skip-permissions may be forwarded
+	ConfirmNewDirs    bool                 // Ask before the first launch in each directory
+	DirSources        map[string]string    // Where each allowed directory comes from, e.g. "config.json"
+	DirExpiry         map[string]time.Time // The expires of allowed directories, reported but not enforced
+	OtelEnv           map[string]string
+	MCPServers        map[string]MCPServer
+	Tmux              bool // Launch in a tmux window by default
+	Banner            bool // Show a one-line summary of the launch context
+	Container         ContainerConfig
+	Limits            Limits
+	Landlock          Landlock
+	Env               EnvConfig
+	Tool              string     // Optional: agent CLI to launch ("claude" when empty)
+	MinClaudeVersion  string     // Optional: minimum claude version required to launch
+	ClaudePath        StringList // Optional: claude binary candidates, tried in order
+	ClaudeInstall     ClaudeInstall
+	Projects          []Project
+	Profiles          map[string]Profile
+	Aliases           map[string]Alias       // User-defined commands, e.g. `claude-launcher fix`
+	Bookmarks         map[string]Bookmark    // Short names of projects, launched as `claude-launcher @<name>`
+	PermissionPresets map[string]Permissions // Named permission rule sets selected with --preset
+	Workspaces        map[string]Workspace   // Named sets of directories opened together
+	RemoteHosts       map[string]RemoteHost  // SSH hosts `claude-launcher ssh` may launch on
+	Notifications     []Notification         // Webhooks called when a launch finishes
+	Devcontainer      string                 // "ask" (default), "always" or "never" launch in a project's devcontainer
+	Session           string                 // "ask" (default), "continue" or "new": the session of a launch without --continue or --new
+	GitCheck          string                 // "off" (default), "warn" or "block" launches in unclean git working trees
+	Snapshot          string                 // Optional: "stash", "commit" or "worktree" recovery point made before launching
+	Theme             Theme                  // Colors of the launcher's own messages
+	Prompts           map[string]PromptText  // Replacement wording of prompts, by prompt (PromptSession, ...)
+	Symbols           string                 // "auto" (default), "unicode" or "ascii"
+	Accessible        bool                   // Screen-reader-friendly output and prompts
+	Log               LogConfig              // Diagnostic logging
+	Retention         Retention              // Rotation of the audit log, launch history and log file
+	Plugins           []string               // Plugins (claude-launcher-<name> on PATH) whose hooks run
+	LaunchScript      string                 // Optional: Starlark script customizing launches
+	CollapseNested    bool                   // Drop allowed directories inside other allowed directories at load time
+	UpdateCheck       *bool                  // Optional: false disables the daily check for launcher updates
+	SessionSummary    *bool                  // Optional: false disables the summary printed when a session ends
+	AllowRoot         bool                   // Launch even when the launcher runs as root
+	Defaults          Defaults               // Arguments added to every claude invocation
+}
+
+// StringList is a list of strings that may be written in JSON as a single string or an array
+type StringList []string
+
+// UnmarshalJSON accepts either "value" or ["value1", "value2"]
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = StringList{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("expected a string or an array of strings: %w", err)
+	}
+	*l = list
+	return nil
+}
+
+// ClaudeInstall configures how the launcher offers to install or update claude
+type ClaudeInstall struct {
+	Method        string `json:"method,omitempty"`        // "npm" (default) or "native"
+	Command       string `json:"command,omitempty"`       // Overrides the install command
+	UpdateCommand string `json:"updateCommand,omitempty"` // Overrides the update command
+}
+
+// Project holds per-project settings applied when launching inside Path
+type Project struct {
+	Path             string
+	Model            string               // Optional: default model for this project
+	Tool             string               // Optional: overrides the global tool
+	Env              EnvSet               // Optional: merged over the global env.set
+	Permissions      Permissions          // Optional: permission rules for launches in this project
+	PermissionPreset string               // Optional: preset applied when --preset is not given
+	MCPServers       map[string]MCPServer // Optional: merged over the global MCP servers
+	Tmux             *bool                // Optional: overrides the global tmux default
+	Container        ContainerConfig      // Optional: merged over the global container settings
+	Session          string               // Optional: overrides the global session behavior
+	GitCheck         string               // Optional: overrides the global git working-tree check
+	Snapshot         string               // Optional: overrides the global snapshot mode ("off" disables it)
+	Branches         []BranchRule         // Optional: launch policies by git branch, first match wins
+	Requires         []Requirement        // Optional: checked before launching
+	LinkedDirs       []string             // Optional: directories passed to claude with --add-dir (e.g. sibling libraries)
+	Defaults         Defaults             // Optional: added after the global defaults
+}
+
+// Requirement is something a project needs from its environment, checked before a launch so a
+// session does not start in a broken one. Exactly one of Command, File and Run is set.
+type Requirement struct {
+	Command string `json:"command,omitempty"` // A command that must be on PATH
+	Version string `json:"version,omitempty"` // Minimum version of Command, as printed by `<command> --version`
+	File    string `json:"file,omitempty"`    // A file that must exist, relative to the launch directory
+	Run     string `json:"run,omitempty"`     // A shell command that must succeed, e.g. "docker info"
+	Name    string `json:"name,omitempty"`    // Shown instead of the check, e.g. "docker running"
+	Hint    string `json:"hint,omitempty"`    // How to fix a failure
+}
+
+// MinVersion returns Version without the optional ">=" in front of it
+func (r Requirement) MinVersion() string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(r.Version), ">="))
+}
+
+// String describes the requirement: its name, or what is checked
+func (r Requirement) String() string {
+	switch {
+	case r.Name != "":
+		return r.Name
+	case r.Command != "" && r.Version != "":
+		return r.Command + " >= " + r.MinVersion()
+	case r.Command != "":
+		return r.Command
+	case r.File != "":
+		return r.File
+	default:
+		return r.Run
+	}
+}
+
+// requirementVersion matches the versions a requirement may ask for, e.g. "20" or "3.11.2"
+var requirementVersion = regexp.MustCompile(`^v?\d+(\.\d+)*$`)
+
+// validate checks that the requirement checks exactly one thing
+func (r Requirement) validate() error {
+	set := 0
+	for _, field := range []string{r.Command, r.File, r.Run} {
+		if field != "" {
+			set++
+		}
+	}
+	switch {
+	case set != 1:
+		return fmt.Errorf("requirement %q: set exactly one of command, file and run", r.String())
+	case r.Version != "" && r.Command == "":
+		return fmt.Errorf("requirement %q: version needs a command", r.String())
+	case r.Version != "" && !requirementVersion.MatchString(r.MinVersion()):
+		return fmt.Errorf("requirement %q: invalid version %q (expected e.g. \">=20\" or \"3.11\")", r.String(), r.Version)
+	}
+	return nil
+}
+
+// BranchRule restricts launches in a project while a branch matching Pattern is checked out
+type BranchRule struct {
+	Pattern string `json:"pattern"`          // Branch name or glob, e.g. "main" or "release/*"
+	Deny    bool   `json:"deny,omitempty"`   // Refuse launches on the branch
+	Preset  string `json:"preset,omitempty"` // Permission preset launches on the branch must use
+}
+
+// validate checks the pattern and that the rule does one thing
+func (r BranchRule) validate(presets map[string]Permissions) error {
+	if r.Pattern == "" {
+		return fmt.Errorf("branch rule: pattern cannot be empty")
+	}
+	if _, err := path.Match(r.Pattern, ""); err != nil {
+		return fmt.Errorf("branch rule %q: invalid pattern: %w", r.Pattern, err)
+	}
+	switch {
+	case r.Deny == (r.Preset != ""):
+		return fmt.Errorf("branch rule %q: set either deny or preset", r.Pattern)
+	case r.Preset != "":
+		if _, ok := presets[r.Preset]; !ok {
+			return fmt.Errorf("branch rule %q: unknown permission preset %q", r.Pattern, r.Preset)
+		}
+	}
+	return nil
+}
+
+// BranchRuleFor returns the first rule of project matching branch, or nil.
+// A detached HEAD (empty branch) matches no rule.
+func BranchRuleFor(project *Project, branch string) *BranchRule {
+	if project == nil || branch == "" {
+		return nil
+	}
+	for i, rule := range project.Branches {
+		if ok, _ := path.Match(rule.Pattern, branch); ok { //nolint:errcheck // patterns are validated at load time
+			return &project.Branches[i]
+		}
+	}
+	return nil
+}
+
+// ContainerConfig holds settings for --container launches
+type ContainerConfig struct {
+	Runtime string   `json:"runtime,omitempty"` // "docker" or "podman"; auto-detected when empty
+	Image   string   `json:"image,omitempty"`
+	Mounts  []string `json:"mounts,omitempty"` // Extra "host:container[:ro]" bind mounts
+}
+
+// Session behaviors for a launch profile, a project or all launches
+const (
+	SessionAsk      = "ask"      // Prompt (default)
+	SessionContinue = "continue" // Continue the previous session
+	SessionNew      = "new"      // Start a new session
+)
+
+// Profile is a named launch context started with `claude-launcher up <name>`
+type Profile struct {
+	Dir       string   `json:"dir"`
+	Account   string   `json:"account,omitempty"`
+	Model     string   `json:"model,omitempty"`
+	Tool      string   `json:"tool,omitempty"`
+	Preset    string   `json:"preset,omitempty"` // Permission preset
+	Env       EnvSet   `json:"env"`
+	Session   string   `json:"session,omitempty"` // "ask" (default), "continue" or "new"
+	Args      []string `json:"args,omitempty"`    // Extra arguments passed to claude
+	Tmux      bool     `json:"tmux,omitempty"`
+	Detach    bool     `json:"detach,omitempty"`
+	Container bool     `json:"container,omitempty"`
+}
+
+// Alias is a user-defined command expanding into launch options, run as `claude-launcher <name>`.
+// Unlike a profile it needs no directory: without one it launches in the current directory.
+type Alias struct {
+	Dir       string   `json:"dir,omitempty"`
+	Account   string   `json:"account,omitempty"`
+	Model     string   `json:"model,omitempty"`
+	Tool      string   `json:"tool,omitempty"`
+	Preset    string   `json:"preset,omitempty"` // Permission preset
+	Env       EnvSet   `json:"env"`
+	Continue  bool     `json:"continue,omitempty"` // Continue the previous session without asking
+	New       bool     `json:"new,omitempty"`      // Start a new session without asking
+	Args      []string `json:"args,omitempty"`     // Extra arguments passed to claude
+	Tmux      bool     `json:"tmux,omitempty"`
+	Detach    bool     `json:"detach,omitempty"`
+	Container bool     `json:"container,omitempty"`
+}
+
+// Bookmark is a project directory with a short name, launched from anywhere as
+// `claude-launcher @<name>` (`bookmark add` writes them)
+type Bookmark struct {
+	Path    string `json:"path"`
+	Account string `json:"account,omitempty"`
+	Preset  string `json:"preset,omitempty"` // Permission preset
+}
+
+// Profile returns the launch profile equivalent to the bookmark
+func (b Bookmark) Profile() Profile {
+	return Profile{Dir: b.Path, Account: b.Account, Preset: b.Preset}
+}
+
+// ValidBookmarkName reports whether name can follow the @ of `claude-launcher @<name>`
+func ValidBookmarkName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, "-") && !strings.ContainsFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '@' || r == '/'
+	})
+}
+
+// Profile returns the launch profile equivalent to the alias
+func (a Alias) Profile() Profile {
+	session := ""
+	switch {
+	case a.Continue:
+		session = SessionContinue
+	case a.New:
+		session = SessionNew
+	}
+	return Profile{
+		Dir:       a.Dir,
+		Account:   a.Account,
+		Model:     a.Model,
+		Tool:      a.Tool,
+		Preset:    a.Preset,
+		Env:       a.Env,
+		Session:   session,
+		Args:      a.Args,
+		Tmux:      a.Tmux,
+		Detach:    a.Detach,
+		Container: a.Container,
+	}
+}
+
+// EnvConfig controls the environment passed to claude
+type EnvConfig struct {
+	Passthrough []string `json:"passthrough,omitempty"` // If set, only these inherited variables are passed (* wildcards allowed)
+	Block       []string `json:"block,omitempty"`       // Inherited variables that are never passed (* wildcards allowed)
+	EnvSet
+}
+
+// EnvSet holds environment variables added to the launch
+type EnvSet struct {
+	Set map[string]string `json:"set,omitempty"` // Overrides inherited values
+}
+
+// Permissions are Claude Code permission rules passed to a launch via --settings
+type Permissions struct {
+	Allow         []string `json:"allow,omitempty"`
+	Deny          []string `json:"deny,omitempty"`
+	Ask           []string `json:"ask,omitempty"`
+	DefaultMode   string   `json:"defaultMode,omitempty"`
+	DisabledTools []string `json:"disabledTools,omitempty"` // Tools denied entirely (e.g. "Edit", "Write")
+}
+
+// IsZero reports whether no permission rules are set
+func (p Permissions) IsZero() bool {
+	return len(p.Allow) == 0 && len(p.Deny) == 0 && len(p.Ask) == 0 && p.DefaultMode == "" && len(p.DisabledTools) == 0
+}
+
+// Merge returns p combined with other: rules are concatenated and other's defaultMode wins
+func (p Permissions) Merge(other Permissions) Permissions {
+	merged := Permissions{
+		Allow:         slices.Concat(p.Allow, other.Allow),
+		Deny:          slices.Concat(p.Deny, other.Deny),
+		Ask:           slices.Concat(p.Ask, other.Ask),
+		DefaultMode:   p.DefaultMode,
+		DisabledTools: slices.Concat(p.DisabledTools, other.DisabledTools),
+	}
+	if other.DefaultMode != "" {
+		merged.DefaultMode = other.DefaultMode
+	}
+	return merged
+}
+
+// ReadOnly are the permission rules added by --read-only: the file-editing tools are denied, and so
+// are the shell commands that commonly change files or the git repository. Deny rules win over
+// any allow rule, but a shell rule list cannot be complete; Landlock makes the restriction hard.
+var ReadOnly = Permissions{
+	DisabledTools: []string{"Edit", "MultiEdit", "Write", "NotebookEdit"},
+	Deny: []string{
+		"Bash(rm:*)", "Bash(rmdir:*)", "Bash(mv:*)", "Bash(cp:*)", "Bash(mkdir:*)", "Bash(touch:*)",
+		"Bash(ln:*)", "Bash(chmod:*)", "Bash(chown:*)", "Bash(tee:*)", "Bash(dd:*)", "Bash(truncate:*)",
+		"Bash(sed -i:*)", "Bash(perl -i:*)", "Bash(patch:*)",
+		"Bash(git add:*)", "Bash(git commit:*)", "Bash(git push:*)", "Bash(git checkout:*)", "Bash(git switch:*)",
+		"Bash(git reset:*)", "Bash(git restore:*)", "Bash(git clean:*)", "Bash(git stash:*)", "Bash(git merge:*)",
+		"Bash(git rebase:*)", "Bash(git apply:*)",
+		"Bash(npm install:*)", "Bash(pnpm install:*)", "Bash(yarn add:*)", "Bash(pip install:*)", "Bash(go get:*)",
+	},
+}
+
+// Defaults are settings applied to every launch in their scope (all launches, or a project's)
+type Defaults struct {
+	ExtraArgs []string `json:"extraArgs,omitempty"` // Added to claude's arguments, e.g. "--verbose"
+}
+
+// validate rejects extra arguments that would bypass the yoloAllowedDirs gate
+func (d Defaults) validate() error {
+	if err := CheckNoBypass(d.ExtraArgs); err != nil {
+		return fmt.Errorf("invalid defaults.extraArgs: %w", err)
+	}
+	return nil
+}
+
+// CheckNoBypass rejects claude arguments that would bypass the yoloAllowedDirs gate:
+// --dangerously-skip-permissions and the bypassPermissions mode in any form, e.g.
+// "--permission-mode bypassPermissions" or inline --settings JSON
+func CheckNoBypass(args []string) error {
+	for _, arg := range args {
+		if strings.Contains(arg, "dangerously-skip-permissions") || strings.Contains(arg, "bypassPermissions") {
+			return fmt.Errorf("%s is not allowed; use --dangerously-skip-permissions within yoloAllowedDirs", arg)
+		}
+	}
+	return nil
+}
+
+// ExtraArgsFor returns the defaults.extraArgs of a launch in project: the global ones, then the
+// project's. They go in front of the arguments of the profile and the command line, so for options
+// claude reads once the more specific value comes later and wins.
+func (c *Config) ExtraArgsFor(project *Project) []string {
+	var args []string
+	args = append(args, c.Defaults.ExtraArgs...)
+	if project != nil {
+		args = append(args, project.Defaults.ExtraArgs...)
+	}
+	return args
+}
+
+// PermissionModes are the Claude Code permission modes a launch may start in (defaultMode,
+// --permission-mode). bypassPermissions is left out: it would bypass the yoloAllowedDirs gate.
+var PermissionModes = []string{"default", "acceptEdits", "plan"}
+
+// ValidatePermissionMode rejects an unknown permission mode and bypassPermissions
+func ValidatePermissionMode(mode string) error {
+	if mode == "bypassPermissions" {
+		return fmt.Errorf("permission mode bypassPermissions is not allowed; use --dangerously-skip-permissions within yoloAllowedDirs")
+	}
+	if !slices.Contains(PermissionModes, mode) {
+		return fmt.Errorf("unknown permission mode %q (available: %s)", mode, strings.Join(PermissionModes, ", "))
+	}
+	return nil
+}
+
+// validate rejects a defaultMode that is unknown or would bypass the yoloAllowedDirs gate
+func (p Permissions) validate() error {
+	if p.DefaultMode == "" {
+		return nil
+	}
+	if err := ValidatePermissionMode(p.DefaultMode); err != nil {
+		return fmt.Errorf("invalid defaultMode: %w", err)
+	}
+	return nil
+}
+
+// Workspace is a named set of directories launched together with
+// `claude-launcher workspace open <name>`: one is the working directory, the others are
+// passed to claude with --add-dir
+type Workspace struct {
+	Dirs    []string `json:"dirs"`
+	Primary string   `json:"primary,omitempty"` // Working directory; picked interactively when empty
+}
+
+// RemoteHost configures launches on an SSH host (the key in remoteHosts, as given to ssh).
+// Paths are remote paths: they are absolute or start with ~ (the remote home directory) and are
+// not expanded locally.
+type RemoteHost struct {
+	AllowedDirs []string          `json:"allowedDirs"`
+	Accounts    map[string]string `json:"accounts,omitempty"` // Account name -> remote config directory
+	Claude      string            `json:"claude,omitempty"`   // Remote claude command (default: claude)
+}
+
+// Git working-tree checks: what happens when a launch directory has uncommitted changes, a
+// detached HEAD or an unfinished rebase, merge, cherry-pick, revert or bisect
+const (
+	GitCheckOff   = "off"   // Launch without looking (default)
+	GitCheckWarn  = "warn"  // Warn and ask whether to continue, stash the changes or abort
+	GitCheckBlock = "block" // Refuse the launch unless the changes are stashed
+)
+
+// Devcontainer modes: whether to launch inside the devcontainer of a project that has one
+const (
+	DevcontainerAsk    = "ask"
+	DevcontainerAlways = "always"
+	DevcontainerNever  = "never"
+)
+
+// Notification is a webhook called with the outcome of a finished launch
+type Notification struct {
+	URL         string   `json:"url"`                   // May be a secret reference (keychain:, op://)
+	Format      string   `json:"format,omitempty"`      // "json" (default) or "slack"
+	Modes       []string `json:"modes,omitempty"`       // Launch modes notified (default: detached, headless)
+	MinDuration string   `json:"minDuration,omitempty"` // Shorter launches are not notified, e.g. "10m"
+}
+
+// Notification formats
+const (
+	NotificationJSON  = "json"
+	NotificationSlack = "slack"
+)
+
+// notificationModes are the launch modes whose end is observed and can be notified
+var notificationModes = []string{"foreground", "headless", "detached"}
+
+// validate checks the format, modes and minimum duration
+func (n Notification) validate() error {
+	if n.URL == "" {
+		return fmt.Errorf("url cannot be empty")
+	}
+	switch n.Format {
+	case "", NotificationJSON, NotificationSlack:
+	default:
+		return fmt.Errorf("unknown format %q (available: %s, %s)", n.Format, NotificationJSON, NotificationSlack)
+	}
+	for _, mode := range n.Modes {
+		if !slices.Contains(notificationModes, mode) {
+			return fmt.Errorf("unknown mode %q (available: %s)", mode, strings.Join(notificationModes, ", "))
+		}
+	}
+	if n.MinDuration != "" {
+		if _, err := time.ParseDuration(n.MinDuration); err != nil {
+			return fmt.Errorf("invalid minDuration: %w", err)
+		}
+	}
+	return nil
+}
+
+// Theme overrides the colors of the launcher's messages.
+// Colors are names ("green", "hiblue") or 256-color codes ("208"); empty keeps the palette's color.
+type Theme struct {
+	Palette   string `json:"palette,omitempty"` // "default" or "colorblind"
+	Success   string `json:"success,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Warning   string `json:"warning,omitempty"`
+	Highlight string `json:"highlight,omitempty"` // Selected item in interactive prompts
+}
+
+// Prompts whose wording the config can replace
+const (
+	PromptSession = "session" // The question whether to continue the previous session
+	PromptAccount = "account" // The label of the account menu
+	PromptDenied  = "denied"  // The explanation when a directory is refused
+	PromptConfirm = "confirm" // The question before the first launch in a directory (confirmNewDirs)
+)
+
+// promptNames lists the prompts in the order of error messages
+var promptNames = []string{PromptSession, PromptAccount, PromptDenied, PromptConfirm}
+
+// PromptText is the wording of a prompt: a single text for every language, or one per language
+// code ({"en": "...", "ja": "..."}), with "en" for the languages not listed
+type PromptText map[string]string
+
+// UnmarshalJSON accepts either "text" or {"<lang>": "text", ...}
+func (t *PromptText) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = PromptText{"": single}
+		return nil
+	}
+
+	var texts map[string]string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return fmt.Errorf("expected a string or an object of language codes: %w", err)
+	}
+	*t = texts
+	return nil
+}
+
+// For returns the text for the language lang, and false when the config has none for it
+func (t PromptText) For(lang string) (string, bool) {
+	for _, key := range []string{"", lang, "en"} {
+		if text, ok := t[key]; ok {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// validatePrompts rejects unknown prompts and empty texts
+func validatePrompts(prompts map[string]PromptText) error {
+	for _, name := range slices.Sorted(maps.Keys(prompts)) {
+		if !slices.Contains(promptNames, name) {
+			return fmt.Errorf("invalid prompts: unknown prompt %q (available: %s)", name, strings.Join(promptNames, ", "))
+		}
+		for lang, text := range prompts[name] {
+			if strings.TrimSpace(text) == "" {
+				return fmt.Errorf("invalid prompts: %s cannot be empty", strings.TrimSuffix(name+"."+lang, "."))
+			}
+		}
+	}
+	return nil
+}
+
+// LogConfig configures the diagnostic log
+type LogConfig struct {
+	Level  string `json:"level,omitempty"`  // "debug", "info", "warn" (default) or "error"
+	Format string `json:"format,omitempty"` // "text" (default) or "json"
+	File   string `json:"file,omitempty"`   // Log file; stderr when empty
+}
+
+// Retention bounds the launcher's append-only logs: the audit log, the launch history and
+// log.file. Sizes are like "10M", ages like "90d"; "0" removes a bound.
+type Retention struct {
+	MaxSize      string `json:"maxSize,omitempty"`      // Rotate a log once it is this large (default 10M)
+	MaxAge       string `json:"maxAge,omitempty"`       // Rotate a log once it spans this long and delete rotated logs older than this (default: no limit)
+	MaxTotalSize string `json:"maxTotalSize,omitempty"` // Keep at most this much of each log, rotated logs included (default 100M)
+}
+
+// Default retention bounds
+const (
+	DefaultRetentionMaxSize      = 10 << 20
+	DefaultRetentionMaxTotalSize = 100 << 20
+)
+
+// Rotation returns the bounds as state.Rotation, with the defaults for unset ones
+func (r Retention) Rotation() state.Rotation {
+	maxSize, err := parseRetentionSize(r.MaxSize, DefaultRetentionMaxSize)
+	if err != nil {
+		maxSize = DefaultRetentionMaxSize
+	}
+	maxTotalSize, err := parseRetentionSize(r.MaxTotalSize, DefaultRetentionMaxTotalSize)
+	if err != nil {
+		maxTotalSize = DefaultRetentionMaxTotalSize
+	}
+	maxAge, err := parseRetentionAge(r.MaxAge)
+	if err != nil {
+		maxAge = 0
+	}
+	return state.Rotation{MaxSize: maxSize, MaxAge: maxAge, MaxTotalSize: maxTotalSize}
+}
+
+func (r Retention) validate() error {
+	if _, err := parseRetentionSize(r.MaxSize, 0); err != nil {
+		return fmt.Errorf("invalid retention.maxSize: %w", err)
+	}
+	if _, err := parseRetentionSize(r.MaxTotalSize, 0); err != nil {
+		return fmt.Errorf("invalid retention.maxTotalSize: %w", err)
+	}
+	if _, err := parseRetentionAge(r.MaxAge); err != nil {
+		return fmt.Errorf("invalid retention.maxAge: %w", err)
+	}
+	return nil
+}
+
+// parseRetentionSize parses a size such as "10M", "512K", "1G" or a number of bytes; "" is def
+func parseRetentionSize(s string, def int64) (int64, error) {
+	if s == "" {
+		return def, nil
+	}
+	units := map[byte]int64{'K': 1 << 10, 'M': 1 << 20, 'G': 1 << 30}
+	trimmed := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B")
+	multiplier := int64(1)
+	if n := len(trimmed); n > 0 {
+		if m, ok := units[trimmed[n-1]]; ok {
+			multiplier = m
+			trimmed = trimmed[:n-1]
+		}
+	}
+	value, err := strconv.ParseInt(trimmed, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%q is not a size (expected e.g. 10M or 1G)", s)
+	}
+	return value * multiplier, nil
+}
+
+// parseRetentionAge parses an age such as "90d", "2w" or "12h"; "" and "0" are no limit
+func parseRetentionAge(s string) (time.Duration, error) {
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				break
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%q is not an age (expected e.g. 90d, 2w or 12h)", s)
+	}
+	return d, nil
+}
+
+// Limits are resource limits applied to the claude process
+type Limits struct {
+	Nice   *int   `json:"nice,omitempty"`   // Scheduling niceness (-20..19)
+	CPUs   []int  `json:"cpus,omitempty"`   // CPU indexes claude may run on
+	Memory string `json:"memory,omitempty"` // Memory limit such as "4G"
+}
+
+// Landlock restricts where claude may write, using Landlock on Linux 5.13+
+type Landlock struct {
+	Enabled  bool     `json:"enabled,omitempty"`
+	Writable []string `json:"writable,omitempty"` // Files and directories writable besides the project, config dir and temp
+}
+
+// MCPServer is an MCP server definition in Claude Code's mcpServers format
+type MCPServer struct {
+	Type    string            `json:"type,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
 }
 
 // Loader is an interface for loading configuration
@@ -30,7 +689,8 @@ func (e *EnvLoader) Load() (*Config, error) {
 		return nil, fmt.Errorf("CLAUDE_SAFE_DIRS environment variable not set")
 	}
 
-	dirs := strings.Split(envValue, ":")
+	// Colon-separated like PATH (semicolon-separated on Windows, where paths contain colons)
+	dirs := filepath.SplitList(envValue)
 	expandedDirs := make([]string, 0, len(dirs))
 	for _, dir := range dirs {
 		if dir == "" {
@@ -40,21 +700,52 @@ func (e *EnvLoader) Load() (*Config, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to expand path %s: %w", dir, err)
 		}
-		expandedDirs = append(expandedDirs, expanded)
+		expandedDirs = append(expandedDirs, filepath.Clean(expanded))
 	}
 
 	if len(expandedDirs) == 0 {
 		return nil, fmt.Errorf("no valid directories in CLAUDE_SAFE_DIRS")
 	}
 
-	return &Config{AllowedDirs: expandedDirs}, nil
+	return &Config{AllowedDirs: expandedDirs, DirSources: dirSources(expandedDirs, SourceSafeDirs)}, nil
+}
+
+// Sources of the allowed directories, as reported by DirSources
+const (
+	SourceFile     = "config.json"
+	SourceSafeDirs = "CLAUDE_SAFE_DIRS"
+	SourceCI       = CIAllowEnvVar
+)
+
+// dirSources maps each of dirs to source
+func dirSources(dirs []string, source string) map[string]string {
+	sources := make(map[string]string, len(dirs))
+	for _, dir := range dirs {
+		sources[dir] = source
+	}
+	return sources
+}
+
+// configPath is the config file chosen with --config; "" for the default
+var configPath string
+
+// SetPath makes path the config file in place of the default one (--config)
+func SetPath(path string) {
+	configPath = path
 }
 
-// DefaultConfigPath returns the default configuration file path
+// DefaultConfigPath returns the configuration file path: the one set with --config, config.json
+// in $CLAUDE_LAUNCHER_HOME, or ~/.config/claude-launcher/config.json
 func DefaultConfigPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	if configPath != "" {
+		return configPath, nil
+	}
+	if dir := home.LauncherDir(); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+	homeDir, err := home.Dir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
 	return filepath.Join(homeDir, ".config", "claude-launcher", "config.json"), nil
 }
@@ -66,8 +757,103 @@ type FileLoader struct {
 
 // configJSON represents the structure of the config file
 type configJSON struct {
-	AllowedDirs []string          `json:"allowedDirs"`
-	OtelEnv     map[string]string `json:"otelEnv,omitempty"`
+	AllowedDirs       []allowedDirJSON       `json:"allowedDirs"`
+	YoloAllowedDirs   []string               `json:"yoloAllowedDirs,omitempty"`
+	ConfirmNewDirs    bool                   `json:"confirmNewDirs,omitempty"`
+	OtelEnv           map[string]string      `json:"otelEnv,omitempty"`
+	MCPServers        map[string]MCPServer   `json:"mcpServers,omitempty"`
+	Tmux              bool                   `json:"tmux,omitempty"`
+	Banner            bool                   `json:"banner,omitempty"`
+	Container         ContainerConfig        `json:"container"`
+	Limits            Limits                 `json:"limits"`
+	Landlock          Landlock               `json:"landlock"`
+	Env               EnvConfig              `json:"env"`
+	Tool              string                 `json:"tool,omitempty"`
+	MinClaudeVersion  string                 `json:"minClaudeVersion,omitempty"`
+	ClaudePath        StringList             `json:"claudePath,omitempty"`
+	ClaudeInstall     ClaudeInstall          `json:"claudeInstall"`
+	Projects          []projectJSON          `json:"projects,omitempty"`
+	Profiles          map[string]Profile     `json:"profiles,omitempty"`
+	Aliases           map[string]Alias       `json:"aliases,omitempty"`
+	Bookmarks         map[string]Bookmark    `json:"bookmarks,omitempty"`
+	PermissionPresets map[string]Permissions `json:"permissionPresets,omitempty"`
+	Workspaces        map[string]Workspace   `json:"workspaces,omitempty"`
+	RemoteHosts       map[string]RemoteHost  `json:"remoteHosts,omitempty"`
+	Notifications     []Notification         `json:"notifications,omitempty"`
+	Devcontainer      string                 `json:"devcontainer,omitempty"`
+	Session           string                 `json:"session,omitempty"`
+	GitCheck          string                 `json:"gitCheck,omitempty"`
+	Snapshot          string                 `json:"snapshot,omitempty"`
+	Theme             Theme                  `json:"theme"`
+	Prompts           map[string]PromptText  `json:"prompts,omitempty"`
+	Symbols           string                 `json:"symbols,omitempty"`
+	Accessible        bool                   `json:"accessible,omitempty"`
+	Log               LogConfig              `json:"log"`
+	Retention         Retention              `json:"retention"`
+	Plugins           []string               `json:"plugins,omitempty"`
+	LaunchScript      string                 `json:"launchScript,omitempty"`
+	CollapseNested    bool                   `json:"collapseNestedDirs,omitempty"`
+	UpdateCheck       *bool                  `json:"updateCheck,omitempty"`
+	SessionSummary    *bool                  `json:"sessionSummary,omitempty"`
+	AllowRoot         bool                   `json:"allowRoot,omitempty"`
+	Defaults          Defaults               `json:"defaults"`
+}
+
+// allowedDirJSON is an allowedDirs entry: either a path or
+// {"path": ..., "shareWithClaude": true, "expires": "2026-12-31"}
+type allowedDirJSON struct {
+	Path            string `json:"path"`
+	ShareWithClaude bool   `json:"shareWithClaude,omitempty"`
+	Expires         string `json:"expires,omitempty"` // A date (the end of that day) or an RFC 3339 time
+}
+
+// parseExpiry parses the expires of an allowedDirs entry
+func parseExpiry(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expires %q: expected a date (2026-12-31) or an RFC 3339 time", value)
+	}
+	return t, nil
+}
+
+// UnmarshalJSON accepts either "path" or an object
+func (d *allowedDirJSON) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*d = allowedDirJSON{Path: path}
+		return nil
+	}
+
+	type plain allowedDirJSON
+	var entry plain
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return fmt.Errorf("expected a path or an object: %w", err)
+	}
+	*d = allowedDirJSON(entry)
+	return nil
+}
+
+// projectJSON represents a project entry in the config file
+type projectJSON struct {
+	Path             string               `json:"path"`
+	Model            string               `json:"model,omitempty"`
+	Tool             string               `json:"tool,omitempty"`
+	Env              EnvSet               `json:"env"`
+	Permissions      Permissions          `json:"permissions"`
+	PermissionPreset string               `json:"permissionPreset,omitempty"`
+	MCPServers       map[string]MCPServer `json:"mcpServers,omitempty"`
+	Tmux             *bool                `json:"tmux,omitempty"`
+	Container        ContainerConfig      `json:"container"`
+	Session          string               `json:"session,omitempty"`
+	GitCheck         string               `json:"gitCheck,omitempty"`
+	Snapshot         string               `json:"snapshot,omitempty"`
+	Branches         []BranchRule         `json:"branches,omitempty"`
+	Requires         []Requirement        `json:"requires,omitempty"`
+	LinkedDirs       []string             `json:"linkedDirs,omitempty"`
+	Defaults         Defaults             `json:"defaults"`
 }
 
 // Load implements the Loader interface for FileLoader
@@ -92,25 +878,467 @@ func (f *FileLoader) Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config JSON: %w", err)
 	}
 
-	if len(cfg.AllowedDirs) == 0 {
-		return nil, fmt.Errorf("no allowedDirs found in config file")
-	}
-
+	// allowedDirs may be empty here: CLAUDE_SAFE_DIRS can provide them, so LoadConfig checks
+	// the merged list
 	expandedDirs := make([]string, 0, len(cfg.AllowedDirs))
-	for _, dir := range cfg.AllowedDirs {
-		expanded, err := ExpandPath(dir)
+	var sharedDirs []string
+	var expiry map[string]time.Time
+	permanent := map[string]bool{}
+	for _, entry := range cfg.AllowedDirs {
+		if entry.Path == "" {
+			return nil, fmt.Errorf("invalid allowedDirs entry: path cannot be empty")
+		}
+		expanded, err := ExpandPath(entry.Path)
 		if err != nil {
-			return nil, fmt.Errorf("failed to expand path %s: %w", dir, err)
+			return nil, fmt.Errorf("failed to expand path %s: %w", entry.Path, err)
+		}
+		expanded = filepath.Clean(expanded)
+		if entry.Expires == "" {
+			permanent[expanded] = true
+		} else {
+			expires, err := parseExpiry(entry.Expires)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowedDirs entry %s: %w", entry.Path, err)
+			}
+			if expiry == nil {
+				expiry = map[string]time.Time{}
+			}
+			if prev, ok := expiry[expanded]; !ok || expires.After(prev) {
+				expiry[expanded] = expires
+			}
 		}
 		expandedDirs = append(expandedDirs, expanded)
+		if entry.ShareWithClaude {
+			sharedDirs = append(sharedDirs, expanded)
+		}
+	}
+	// A directory listed twice keeps the expiry of its longest entry
+	for dir := range permanent {
+		delete(expiry, dir)
+	}
+
+	yoloDirs, err := expandPaths(cfg.YoloAllowedDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]Project, 0, len(cfg.Projects))
+	for _, proj := range cfg.Projects {
+		if proj.Path == "" {
+			return nil, fmt.Errorf("invalid project: path cannot be empty")
+		}
+		expanded, err := ExpandPath(proj.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand path %s: %w", proj.Path, err)
+		}
+		if err := validateMCPServers(proj.MCPServers); err != nil {
+			return nil, fmt.Errorf("invalid project %s: %w", proj.Path, err)
+		}
+		if err := proj.Env.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid project %s: %w", proj.Path, err)
+		}
+		if err := proj.Permissions.validate(); err != nil {
+			return nil, fmt.Errorf("invalid project %s: %w", proj.Path, err)
+		}
+		if _, ok := cfg.PermissionPresets[proj.PermissionPreset]; proj.PermissionPreset != "" && !ok {
+			return nil, fmt.Errorf("invalid project %s: unknown permission preset %q", proj.Path, proj.PermissionPreset)
+		}
+		if err := validateSession(proj.Session); err != nil {
+			return nil, fmt.Errorf("invalid project %s: %w", proj.Path, err)
+		}
+		if err := validateGitCheck(proj.GitCheck); err != nil {
+			return nil, fmt.Errorf("invalid project %s: %w", proj.Path, err)
+		}
+		if err := validateSnapshot(proj.Snapshot, true); err != nil {
+			return nil, fmt.Errorf("invalid project %s: %w", proj.Path, err)
+		}
+		for _, rule := range proj.Branches {
+			if err := rule.validate(cfg.PermissionPresets); err != nil {
+				return nil, fmt.Errorf("invalid project %s: %w", proj.Path, err)
+			}
+		}
+		for _, req := range proj.Requires {
+			if err := req.validate(); err != nil {
+				return nil, fmt.Errorf("invalid project %s: %w", proj.Path, err)
+			}
+		}
+		linkedDirs, err := expandLinkedDirs(expanded, proj.LinkedDirs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project %s: %w", proj.Path, err)
+		}
+		if err := proj.Defaults.validate(); err != nil {
+			return nil, fmt.Errorf("invalid project %s: %w", proj.Path, err)
+		}
+		projects = append(projects, Project{
+			Path:             expanded,
+			Model:            proj.Model,
+			Tool:             proj.Tool,
+			Env:              proj.Env,
+			Permissions:      proj.Permissions,
+			PermissionPreset: proj.PermissionPreset,
+			MCPServers:       proj.MCPServers,
+			Tmux:             proj.Tmux,
+			Container:        proj.Container,
+			Session:          proj.Session,
+			GitCheck:         proj.GitCheck,
+			Snapshot:         proj.Snapshot,
+			Branches:         proj.Branches,
+			Requires:         proj.Requires,
+			LinkedDirs:       linkedDirs,
+			Defaults:         proj.Defaults,
+		})
+	}
+
+	if err := validateMCPServers(cfg.MCPServers); err != nil {
+		return nil, err
+	}
+
+	if err := validateEnvPatterns(slices.Concat(cfg.Env.Passthrough, cfg.Env.Block)); err != nil {
+		return nil, err
+	}
+	if err := cfg.Env.Validate(); err != nil {
+		return nil, err
+	}
+
+	for name, preset := range cfg.PermissionPresets {
+		if err := preset.validate(); err != nil {
+			return nil, fmt.Errorf("invalid permission preset %s: %w", name, err)
+		}
+	}
+
+	workspaces, err := expandWorkspaces(cfg.Workspaces)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateRemoteHosts(cfg.RemoteHosts); err != nil {
+		return nil, err
+	}
+
+	for i, n := range cfg.Notifications {
+		if err := n.validate(); err != nil {
+			return nil, fmt.Errorf("invalid notification %d: %w", i+1, err)
+		}
+	}
+
+	if err := validateSession(cfg.Session); err != nil {
+		return nil, err
+	}
+
+	if err := validateGitCheck(cfg.GitCheck); err != nil {
+		return nil, err
+	}
+
+	if err := validateSnapshot(cfg.Snapshot, false); err != nil {
+		return nil, err
+	}
+
+	if err := validatePrompts(cfg.Prompts); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Devcontainer {
+	case "", DevcontainerAsk, DevcontainerAlways, DevcontainerNever:
+	default:
+		return nil, fmt.Errorf("invalid devcontainer %q (available: %s, %s, %s)", cfg.Devcontainer, DevcontainerAsk, DevcontainerAlways, DevcontainerNever)
+	}
+
+	profiles, err := expandProfiles(cfg.Profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := expandAliases(cfg.Aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks, err := expandBookmarks(cfg.Bookmarks)
+	if err != nil {
+		return nil, err
+	}
+
+	logCfg, err := expandLogConfig(cfg.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	landlock, err := expandLandlock(cfg.Landlock)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Retention.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Defaults.validate(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range cfg.Plugins {
+		if !plugin.ValidName(name) {
+			return nil, fmt.Errorf("invalid plugin name %q", name)
+		}
+	}
+
+	launchScript := cfg.LaunchScript
+	if launchScript != "" {
+		if launchScript, err = ExpandPath(launchScript); err != nil {
+			return nil, fmt.Errorf("failed to expand path %s: %w", cfg.LaunchScript, err)
+		}
 	}
 
 	return &Config{
-		AllowedDirs: expandedDirs,
-		OtelEnv:     cfg.OtelEnv,
+		AllowedDirs:       expandedDirs,
+		SharedDirs:        sharedDirs,
+		DirSources:        dirSources(expandedDirs, SourceFile),
+		DirExpiry:         expiry,
+		YoloAllowedDirs:   yoloDirs,
+		ConfirmNewDirs:    cfg.ConfirmNewDirs,
+		OtelEnv:           cfg.OtelEnv,
+		MCPServers:        cfg.MCPServers,
+		Tmux:              cfg.Tmux,
+		Banner:            cfg.Banner,
+		Container:         cfg.Container,
+		Limits:            cfg.Limits,
+		Landlock:          landlock,
+		Env:               cfg.Env,
+		Tool:              cfg.Tool,
+		MinClaudeVersion:  cfg.MinClaudeVersion,
+		ClaudePath:        cfg.ClaudePath,
+		ClaudeInstall:     cfg.ClaudeInstall,
+		Projects:          projects,
+		Profiles:          profiles,
+		Aliases:           aliases,
+		Bookmarks:         bookmarks,
+		PermissionPresets: cfg.PermissionPresets,
+		Workspaces:        workspaces,
+		RemoteHosts:       cfg.RemoteHosts,
+		Notifications:     cfg.Notifications,
+		Devcontainer:      cfg.Devcontainer,
+		Session:           cfg.Session,
+		GitCheck:          cfg.GitCheck,
+		Snapshot:          cfg.Snapshot,
+		Theme:             cfg.Theme,
+		Prompts:           cfg.Prompts,
+		Symbols:           cfg.Symbols,
+		Accessible:        cfg.Accessible,
+		Log:               logCfg,
+		Retention:         cfg.Retention,
+		Plugins:           cfg.Plugins,
+		LaunchScript:      launchScript,
+		UpdateCheck:       cfg.UpdateCheck,
+		SessionSummary:    cfg.SessionSummary,
+		AllowRoot:         cfg.AllowRoot,
+		Defaults:          cfg.Defaults,
+		CollapseNested:    cfg.CollapseNested,
 	}, nil
 }
 
+// expandLinkedDirs expands ~ in the linkedDirs of the project at projectPath and resolves relative
+// entries against it, so "../shared" names a sibling checkout
+func expandLinkedDirs(projectPath string, dirs []string) ([]string, error) {
+	var linked []string
+	for _, dir := range dirs {
+		if dir == "" {
+			return nil, fmt.Errorf("linkedDirs entries cannot be empty")
+		}
+		expanded, err := ExpandPath(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand path %s: %w", dir, err)
+		}
+		if !filepath.IsAbs(expanded) {
+			expanded = filepath.Join(projectPath, expanded)
+		}
+		linked = append(linked, filepath.Clean(expanded))
+	}
+	return linked, nil
+}
+
+// expandLandlock expands ~ in the extra writable paths
+func expandLandlock(ll Landlock) (Landlock, error) {
+	var writable []string
+	for _, path := range ll.Writable {
+		expanded, err := ExpandPath(path)
+		if err != nil {
+			return Landlock{}, fmt.Errorf("failed to expand path %s: %w", path, err)
+		}
+		writable = append(writable, expanded)
+	}
+	ll.Writable = writable
+	return ll, nil
+}
+
+// expandLogConfig validates the log settings and expands ~ in the log file path
+func expandLogConfig(lc LogConfig) (LogConfig, error) {
+	if lc.Level != "" {
+		if _, err := log.ParseLevel(lc.Level); err != nil {
+			return LogConfig{}, fmt.Errorf("invalid log: %w", err)
+		}
+	}
+	if lc.Format != "" && lc.Format != "text" && lc.Format != "json" {
+		return LogConfig{}, fmt.Errorf("invalid log: unknown format %q (available: text, json)", lc.Format)
+	}
+	if lc.File != "" {
+		expanded, err := ExpandPath(lc.File)
+		if err != nil {
+			return LogConfig{}, fmt.Errorf("failed to expand path %s: %w", lc.File, err)
+		}
+		lc.File = expanded
+	}
+	return lc, nil
+}
+
+// expandProfiles validates profiles and expands ~ in their directories
+func expandProfiles(profiles map[string]Profile) (map[string]Profile, error) {
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+
+	expanded := make(map[string]Profile, len(profiles))
+	for name, profile := range profiles {
+		if profile.Dir == "" {
+			return nil, fmt.Errorf("invalid profile %s: dir cannot be empty", name)
+		}
+		if err := profile.Env.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid profile %s: %w", name, err)
+		}
+		if err := validateSession(profile.Session); err != nil {
+			return nil, fmt.Errorf("invalid profile %s: %w", name, err)
+		}
+
+		dir, err := ExpandPath(profile.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand path %s: %w", profile.Dir, err)
+		}
+		profile.Dir = dir
+		expanded[name] = profile
+	}
+	return expanded, nil
+}
+
+// expandAliases validates aliases and expands ~ in their directories
+func expandAliases(aliases map[string]Alias) (map[string]Alias, error) {
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+
+	expanded := make(map[string]Alias, len(aliases))
+	for name, alias := range aliases {
+		if name == "" || strings.HasPrefix(name, "-") || strings.ContainsFunc(name, unicode.IsSpace) {
+			return nil, fmt.Errorf("invalid alias %q: names cannot be empty, start with - or contain spaces", name)
+		}
+		if alias.Continue && alias.New {
+			return nil, fmt.Errorf("invalid alias %s: continue and new cannot both be set", name)
+		}
+		if err := alias.Env.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid alias %s: %w", name, err)
+		}
+
+		if alias.Dir != "" {
+			dir, err := ExpandPath(alias.Dir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to expand path %s: %w", alias.Dir, err)
+			}
+			alias.Dir = dir
+		}
+		expanded[name] = alias
+	}
+	return expanded, nil
+}
+
+// expandBookmarks validates bookmarks and expands ~ in their paths
+func expandBookmarks(bookmarks map[string]Bookmark) (map[string]Bookmark, error) {
+	if len(bookmarks) == 0 {
+		return nil, nil
+	}
+
+	expanded := make(map[string]Bookmark, len(bookmarks))
+	for name, b := range bookmarks {
+		if !ValidBookmarkName(name) {
+			return nil, fmt.Errorf("invalid bookmark %q: names cannot be empty, start with - or contain spaces, @ or /", name)
+		}
+		if b.Path == "" {
+			return nil, fmt.Errorf("invalid bookmark %s: path cannot be empty", name)
+		}
+		path, err := ExpandPath(b.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand path %s: %w", b.Path, err)
+		}
+		b.Path = path
+		expanded[name] = b
+	}
+	return expanded, nil
+}
+
+// expandWorkspaces validates workspaces and expands ~ in their directories
+func expandWorkspaces(workspaces map[string]Workspace) (map[string]Workspace, error) {
+	if len(workspaces) == 0 {
+		return nil, nil
+	}
+
+	expanded := make(map[string]Workspace, len(workspaces))
+	for name, ws := range workspaces {
+		if len(ws.Dirs) == 0 {
+			return nil, fmt.Errorf("invalid workspace %s: dirs cannot be empty", name)
+		}
+
+		dirs, err := expandPaths(ws.Dirs)
+		if err != nil {
+			return nil, err
+		}
+
+		primary := ws.Primary
+		if primary != "" {
+			primary, err = ExpandPath(primary)
+			if err != nil {
+				return nil, fmt.Errorf("failed to expand path %s: %w", ws.Primary, err)
+			}
+			if !slices.Contains(dirs, primary) {
+				return nil, fmt.Errorf("invalid workspace %s: primary %s is not one of its dirs", name, ws.Primary)
+			}
+		}
+
+		expanded[name] = Workspace{Dirs: dirs, Primary: primary}
+	}
+	return expanded, nil
+}
+
+// validateRemoteHosts checks that remote hosts have allowed directories and that their paths
+// are absolute or relative to the remote home directory
+func validateRemoteHosts(hosts map[string]RemoteHost) error {
+	for name, host := range hosts {
+		if len(host.AllowedDirs) == 0 {
+			return fmt.Errorf("invalid remote host %s: allowedDirs cannot be empty", name)
+		}
+		for _, dir := range slices.Concat(host.AllowedDirs, slices.Collect(maps.Values(host.Accounts))) {
+			if !isRemotePath(dir) {
+				return fmt.Errorf("invalid remote host %s: %q must be absolute or start with ~/", name, dir)
+			}
+		}
+	}
+	return nil
+}
+
+// isRemotePath reports whether p is an absolute POSIX path or starts with the home directory
+func isRemotePath(p string) bool {
+	return strings.HasPrefix(p, "/") || p == "~" || strings.HasPrefix(p, "~/")
+}
+
+// expandPaths expands ~ in every path
+func expandPaths(paths []string) ([]string, error) {
+	expanded := make([]string, 0, len(paths))
+	for _, path := range paths {
+		p, err := ExpandPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand path %s: %w", path, err)
+		}
+		expanded = append(expanded, p)
+	}
+	return expanded, nil
+}
+
 // ChainLoader tries multiple loaders in order
 type ChainLoader struct {
 	Loaders []Loader
@@ -136,43 +1364,376 @@ func (c *ChainLoader) Load() (*Config, error) {
 }
 
 // LoadConfig loads configuration by merging both sources:
-//   - AllowedDirs: CLAUDE_SAFE_DIRS takes priority over config.json
-//   - OtelEnv: always read from config.json (not available via env var)
+//   - AllowedDirs: CLAUDE_LAUNCHER_CI_ALLOW (in CI only), then CLAUDE_SAFE_DIRS take priority
+//     over config.json
+//   - Everything else (OtelEnv, MCPServers, Projects, ...): always read from
+//     config.json (not available via env var)
 func LoadConfig() (*Config, error) {
 	fileCfg, fileErr := (&FileLoader{}).Load()
 	envCfg, envErr := (&EnvLoader{}).Load()
+	log.Debug("config loaded", "source", SourceSafeDirs, "result", loaderResult(envErr))
+	log.Debug("config loaded", "source", SourceFile, "result", loaderResult(fileErr))
+	if ciCfg, ciErr := (&CILoader{}).Load(); ciErr == nil {
+		log.Debug("config loaded", "source", CIAllowEnvVar, "result", "ok")
+		envCfg, envErr = ciCfg, nil
+	} else if DetectEnvironment() != nil {
+		log.Debug("config loaded", "source", CIAllowEnvVar, "result", loaderResult(ciErr))
+	}
 
+	var cfg *Config
 	switch {
 	case envErr == nil && fileErr == nil:
-		return &Config{
-			AllowedDirs: envCfg.AllowedDirs,
-			OtelEnv:     fileCfg.OtelEnv,
-		}, nil
+		merged := *fileCfg
+		merged.AllowedDirs = envCfg.AllowedDirs
+		merged.DirSources = envCfg.DirSources
+		merged.DirExpiry = nil
+		cfg = &merged
 	case envErr == nil:
-		return envCfg, nil
+		cfg = envCfg
 	case fileErr == nil:
-		return fileCfg, nil
+		cfg = fileCfg
 	default:
 		return nil, fmt.Errorf("all loaders failed: %w; %w", envErr, fileErr)
 	}
+	if len(cfg.AllowedDirs) == 0 {
+		return nil, fmt.Errorf("no allowed directories: set allowedDirs in the config file or %s", SourceSafeDirs)
+	}
+	cfg.normalizeDirs()
+	return cfg, nil
+}
+
+// normalizeDirs turns the allowed directories (and yoloAllowedDirs) into a minimal rule set:
+// paths are cleaned (e.g. trailing slashes dropped) and entries listed twice are kept once.
+// With collapseNestedDirs, entries inside another entry that covers them are dropped too.
+func (c *Config) normalizeDirs() {
+	c.AllowedDirs = c.minimalDirs("allowedDirs", c.AllowedDirs)
+	c.YoloAllowedDirs = c.minimalDirs("yoloAllowedDirs", c.YoloAllowedDirs)
+	c.SharedDirs = c.minimalDirs("sharedDirs", c.SharedDirs)
+}
+
+// minimalDirs normalizes the directories of list, tracing what it drops
+func (c *Config) minimalDirs(list string, dirs []string) []string {
+	unique := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		if slices.Contains(unique, dir) {
+			log.Debug("duplicate directory collapsed", "list", list, "dir", dir)
+			continue
+		}
+		unique = append(unique, dir)
+	}
+	if !c.CollapseNested || list == "sharedDirs" {
+		return unique
+	}
+
+	minimal := make([]string, 0, len(unique))
+	for _, dir := range unique {
+		parent := slices.IndexFunc(unique, func(other string) bool {
+			return other != dir && security.IsWithin(dir, other) && !keepsInnerDir(c, list, dir, other)
+		})
+		if parent >= 0 {
+			log.Debug("nested directory collapsed", "list", list, "dir", dir, "into", unique[parent])
+			continue
+		}
+		minimal = append(minimal, dir)
+	}
+	return minimal
+}
+
+// loaderResult describes the outcome of a loader for debug traces
+func loaderResult(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
+
+// FindProject returns the project entry that applies to dir, or nil if none does.
+// When several entries match, the most specific (deepest) path wins.
+func (c *Config) FindProject(dir string) *Project {
+	target := canonicalPath(dir)
+
+	var best *Project
+	for i := range c.Projects {
+		projPath := canonicalPath(c.Projects[i].Path)
+		if target != projPath && !strings.HasPrefix(target, projPath+string(filepath.Separator)) {
+			continue
+		}
+		if best == nil || len(projPath) > len(canonicalPath(best.Path)) {
+			best = &c.Projects[i]
+		}
+	}
+
+	return best
+}
+
+// SharedDirsFor returns the shareWithClaude directories to pass to claude when launching in currentDir.
+// Directories that are no longer allowed (e.g. CLAUDE_SAFE_DIRS overrides the config file),
+// that do not exist, or that already contain currentDir are skipped.
+func (c *Config) SharedDirsFor(currentDir string) []string {
+	allowed := security.NewDirectoryChecker(c.AllowedDirs)
+
+	var dirs []string
+	for _, dir := range c.SharedDirs {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if ok, err := allowed.IsAllowed(dir); err != nil || !ok {
+			continue
+		}
+		if inside, err := security.NewDirectoryChecker([]string{dir}).IsAllowed(currentDir); err != nil || inside {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// MCPServersFor returns the MCP servers to inject for project.
+// Project servers override global servers with the same name.
+func (c *Config) MCPServersFor(project *Project) map[string]MCPServer {
+	servers := make(map[string]MCPServer, len(c.MCPServers))
+	maps.Copy(servers, c.MCPServers)
+
+	if project != nil {
+		maps.Copy(servers, project.MCPServers)
+	}
+
+	return servers
+}
+
+// UseTmux reports whether to launch in tmux for project when no flag was given.
+// A project setting overrides the global default.
+func (c *Config) UseTmux(project *Project) bool {
+	if project != nil && project.Tmux != nil {
+		return *project.Tmux
+	}
+	return c.Tmux
+}
+
+// CheckForUpdates reports whether the launcher looks for newer releases of itself (unless
+// updateCheck is false)
+func (c *Config) CheckForUpdates() bool {
+	return c.UpdateCheck == nil || *c.UpdateCheck
+}
+
+// ShowsSessionSummary reports whether a summary is printed when a foreground session ends
+// (unless sessionSummary is false)
+func (c *Config) ShowsSessionSummary() bool {
+	return c.SessionSummary == nil || *c.SessionSummary
+}
+
+// EnvFor returns the variables from env.set to add for project.
+// Project variables override global ones with the same name.
+func (c *Config) EnvFor(project *Project) map[string]string {
+	env := make(map[string]string, len(c.Env.Set))
+	maps.Copy(env, c.Env.Set)
+
+	if project != nil {
+		maps.Copy(env, project.Env.Set)
+	}
+
+	return env
+}
+
+// PermissionsFor returns the permission rules for a launch in project.
+// preset (from --preset) takes priority over the project's permissionPreset; the preset's
+// rules are added to the project's own.
+func (c *Config) PermissionsFor(project *Project, preset string) (Permissions, error) {
+	var perms Permissions
+	if project != nil {
+		perms = project.Permissions
+	}
+	preset = PresetFor(project, preset)
+
+	if preset == "" {
+		return perms, nil
+	}
+
+	presetPerms, ok := c.PermissionPresets[preset]
+	if !ok {
+		return Permissions{}, fmt.Errorf("unknown permission preset %q", preset)
+	}
+	return perms.Merge(presetPerms), nil
+}
+
+// PresetFor returns the name of the permission preset in effect: preset (--preset) if set,
+// otherwise the project's permissionPreset
+func PresetFor(project *Project, preset string) string {
+	if preset == "" && project != nil {
+		return project.PermissionPreset
+	}
+	return preset
+}
+
+// SessionFor returns the session behavior of launches in project without --continue or --new.
+// A project setting overrides the global one; the default is to ask.
+func (c *Config) SessionFor(project *Project) string {
+	switch {
+	case project != nil && project.Session != "":
+		return project.Session
+	case c.Session != "":
+		return c.Session
+	default:
+		return SessionAsk
+	}
+}
+
+// validateSession rejects an unknown session behavior
+func validateSession(session string) error {
+	switch session {
+	case "", SessionAsk, SessionContinue, SessionNew:
+		return nil
+	}
+	return fmt.Errorf("session must be %q, %q or %q", SessionAsk, SessionContinue, SessionNew)
+}
+
+// GitCheckFor returns the git working-tree check of launches in project.
+// A project setting overrides the global one; the default is off.
+func (c *Config) GitCheckFor(project *Project) string {
+	switch {
+	case project != nil && project.GitCheck != "":
+		return project.GitCheck
+	case c.GitCheck != "":
+		return c.GitCheck
+	default:
+		return GitCheckOff
+	}
+}
+
+// validateGitCheck rejects an unknown git working-tree check
+func validateGitCheck(check string) error {
+	switch check {
+	case "", GitCheckOff, GitCheckWarn, GitCheckBlock:
+		return nil
+	}
+	return fmt.Errorf("gitCheck must be %q, %q or %q", GitCheckOff, GitCheckWarn, GitCheckBlock)
+}
+
+// SnapshotOff is the project snapshot setting that turns off a global snapshot mode
+const SnapshotOff = "off"
+
+// SnapshotFor returns the snapshot mode of launches in project, or "" when none is made.
+// A project setting overrides the global one.
+func (c *Config) SnapshotFor(project *Project) string {
+	mode := c.Snapshot
+	if project != nil && project.Snapshot != "" {
+		mode = project.Snapshot
+	}
+	if mode == SnapshotOff {
+		return ""
+	}
+	return mode
+}
+
+// validateSnapshot rejects an unknown snapshot mode; off is only meaningful for projects
+func validateSnapshot(mode string, project bool) error {
+	if mode == "" || snapshot.ValidMode(mode) || (project && mode == SnapshotOff) {
+		return nil
+	}
+	if project {
+		return fmt.Errorf("snapshot must be %q, %q, %q or %q", snapshot.ModeStash, snapshot.ModeCommit, snapshot.ModeWorktree, SnapshotOff)
+	}
+	return fmt.Errorf("snapshot must be %q, %q or %q", snapshot.ModeStash, snapshot.ModeCommit, snapshot.ModeWorktree)
+}
+
+// ToolFor returns the name of the agent CLI to launch for project.
+// A project setting overrides the global one; "" means the default (claude).
+func (c *Config) ToolFor(project *Project) string {
+	if project != nil && project.Tool != "" {
+		return project.Tool
+	}
+	return c.Tool
+}
+
+// ContainerFor returns the container settings for project.
+// Project runtime and image override the global ones; mounts are combined.
+func (c *Config) ContainerFor(project *Project) ContainerConfig {
+	result := ContainerConfig{
+		Runtime: c.Container.Runtime,
+		Image:   c.Container.Image,
+		Mounts:  slices.Clone(c.Container.Mounts),
+	}
+
+	if project == nil {
+		return result
+	}
+
+	if project.Container.Runtime != "" {
+		result.Runtime = project.Container.Runtime
+	}
+	if project.Container.Image != "" {
+		result.Image = project.Container.Image
+	}
+	result.Mounts = append(result.Mounts, project.Container.Mounts...)
+
+	return result
+}
+
+// validateEnvPatterns checks that env.passthrough and env.block entries are valid patterns
+func validateEnvPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("invalid env pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// Validate checks that every variable name is non-empty and contains no '='
+func (e EnvSet) Validate() error {
+	for name := range e.Set {
+		if name == "" || strings.Contains(name, "=") {
+			return fmt.Errorf("invalid env.set variable name %q", name)
+		}
+	}
+	return nil
+}
+
+// validateMCPServers checks that every server has either a command or a URL
+func validateMCPServers(servers map[string]MCPServer) error {
+	for name, server := range servers {
+		if name == "" {
+			return fmt.Errorf("invalid MCP server: name cannot be empty")
+		}
+		if server.Command == "" && server.URL == "" {
+			return fmt.Errorf("invalid MCP server %q: command or url is required", name)
+		}
+	}
+	return nil
+}
+
+// canonicalPath returns an absolute, symlink-resolved form of path.
+// Falls back to the cleaned absolute path if symlinks cannot be resolved.
+func canonicalPath(path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		return resolved
+	}
+	return absPath
 }
 
-// ExpandPath expands ~ to home directory
+// ExpandPath expands ~ to home directory. Paths written for the other side of WSL
+// (C:\... inside WSL, /mnt/c/... on Windows) are translated first.
 func ExpandPath(path string) (string, error) {
+	path = translateWSLPath(path)
 	if !strings.HasPrefix(path, "~") {
 		return path, nil
 	}
 
-	homeDir, err := os.UserHomeDir()
+	homeDir, err := home.Dir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
 
 	if path == "~" {
 		return homeDir, nil
 	}
 
-	if strings.HasPrefix(path, "~/") {
+	if strings.HasPrefix(path, "~/") || (filepath.Separator == '\\' && strings.HasPrefix(path, `~\`)) {
 		return filepath.Join(homeDir, path[2:]), nil
 	}
 
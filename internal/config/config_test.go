@@ -3,9 +3,45 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"testing"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/home"
+	"github.com/23prime/claude-launcher/internal/state"
 )
 
+func TestDefaultConfigPath(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("failed to get home directory: %v", err)
+	}
+
+	tests := []struct {
+		name, launcherHome, explicit, want string
+	}{
+		{"home", "", "", filepath.Join(homeDir, ".config", "claude-launcher", "config.json")},
+		{"CLAUDE_LAUNCHER_HOME", "/srv/launcher", "", filepath.Join("/srv/launcher", "config.json")},
+		{"--config wins", "/srv/launcher", "/etc/launcher.json", "/etc/launcher.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CLAUDE_LAUNCHER_HOME", tt.launcherHome)
+			SetPath(tt.explicit)
+			t.Cleanup(func() { SetPath("") })
+
+			got, err := DefaultConfigPath()
+			if err != nil {
+				t.Fatalf("DefaultConfigPath() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DefaultConfigPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestExpandPath(t *testing.T) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -161,7 +197,8 @@ func TestFileLoader(t *testing.T) {
 			jsonContent: `{
 				"allowedDirs": []
 			}`,
-			wantErr: true,
+			wantErr:     false,
+			expectedLen: 0,
 		},
 		{
 			name:        "invalid JSON",
@@ -173,7 +210,8 @@ func TestFileLoader(t *testing.T) {
 			jsonContent: `{
 				"otherConfig": {}
 			}`,
-			wantErr: true,
+			wantErr:     false,
+			expectedLen: 0,
 		},
 	}
 
@@ -304,6 +342,32 @@ func TestChainLoaderAllFail(t *testing.T) {
 	}
 }
 
+func TestLoadConfigSafeDirsWithoutFileAllowedDirs(t *testing.T) {
+	clearCIEnv(t)
+	launcherHome := t.TempDir()
+	t.Setenv(home.EnvVar, launcherHome)
+	if err := os.WriteFile(filepath.Join(launcherHome, "config.json"), []byte(`{"projects": [{"path": "/home/user/projects/app", "model": "opus"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CLAUDE_SAFE_DIRS", "/home/user/projects")
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !slices.Equal(cfg.AllowedDirs, []string{"/home/user/projects"}) {
+		t.Errorf("AllowedDirs = %v, expected CLAUDE_SAFE_DIRS", cfg.AllowedDirs)
+	}
+	if len(cfg.Projects) != 1 {
+		t.Errorf("Projects = %v, expected the project from the config file", cfg.Projects)
+	}
+
+	t.Setenv("CLAUDE_SAFE_DIRS", "")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("LoadConfig() without any allowed directory should fail")
+	}
+}
+
 func TestFileLoaderOtelEnv(t *testing.T) {
 	tmpDir := t.TempDir()
 	testFile := filepath.Join(tmpDir, "config.json")
@@ -367,3 +431,1219 @@ func TestFileLoaderOtelEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestFileLoaderProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "config.json")
+	jsonContent := `{
+		"allowedDirs": ["/home/user/projects"],
+		"projects": [
+			{"path": "/home/user/projects/scratch", "model": "haiku"}
+		]
+	}`
+	if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	cfg, err := (&FileLoader{Path: testFile}).Load()
+	if err != nil {
+		t.Fatalf("FileLoader.Load() error = %v", err)
+	}
+
+	if len(cfg.Projects) != 1 {
+		t.Fatalf("Projects length = %d, expected 1", len(cfg.Projects))
+	}
+	if cfg.Projects[0].Model != "haiku" {
+		t.Errorf("Projects[0].Model = %q, expected %q", cfg.Projects[0].Model, "haiku")
+	}
+}
+
+func TestFileLoaderProjectWithoutPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "config.json")
+	jsonContent := `{
+		"allowedDirs": ["/home/user/projects"],
+		"projects": [{"model": "haiku"}]
+	}`
+	if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	if _, err := (&FileLoader{Path: testFile}).Load(); err == nil {
+		t.Error("FileLoader.Load() should return error for project without path")
+	}
+}
+
+func TestFindProject(t *testing.T) {
+	cfg := &Config{
+		Projects: []Project{
+			{Path: "/home/user/work", Model: "sonnet"},
+			{Path: "/home/user/work/scratch", Model: "haiku"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		dir           string
+		expectedModel string
+		expectNil     bool
+	}{
+		{name: "exact match", dir: "/home/user/work", expectedModel: "sonnet"},
+		{name: "subdirectory", dir: "/home/user/work/api", expectedModel: "sonnet"},
+		{name: "deepest match wins", dir: "/home/user/work/scratch/tmp", expectedModel: "haiku"},
+		{name: "similar prefix does not match", dir: "/home/user/workshop", expectNil: true},
+		{name: "unrelated directory", dir: "/tmp", expectNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proj := cfg.FindProject(tt.dir)
+			if tt.expectNil {
+				if proj != nil {
+					t.Errorf("FindProject(%q) = %+v, expected nil", tt.dir, proj)
+				}
+				return
+			}
+			if proj == nil {
+				t.Fatalf("FindProject(%q) = nil, expected a project", tt.dir)
+			}
+			if proj.Model != tt.expectedModel {
+				t.Errorf("FindProject(%q).Model = %q, expected %q", tt.dir, proj.Model, tt.expectedModel)
+			}
+		})
+	}
+}
+
+func TestFileLoaderMCPServers(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "config.json")
+
+	tests := []struct {
+		name        string
+		jsonContent string
+		wantErr     bool
+	}{
+		{
+			name: "global and project servers",
+			jsonContent: `{
+				"allowedDirs": ["/home/user/projects"],
+				"mcpServers": {"github": {"command": "github-mcp-server"}},
+				"projects": [
+					{"path": "/home/user/projects/api", "mcpServers": {"db": {"type": "http", "url": "http://localhost:8080/mcp"}}}
+				]
+			}`,
+			wantErr: false,
+		},
+		{
+			name: "server without command or url",
+			jsonContent: `{
+				"allowedDirs": ["/home/user/projects"],
+				"mcpServers": {"broken": {"args": ["--flag"]}}
+			}`,
+			wantErr: true,
+		},
+		{
+			name: "project server without command or url",
+			jsonContent: `{
+				"allowedDirs": ["/home/user/projects"],
+				"projects": [{"path": "/home/user/projects/api", "mcpServers": {"broken": {}}}]
+			}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(testFile, []byte(tt.jsonContent), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			_, err := (&FileLoader{Path: testFile}).Load()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FileLoader.Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMCPServersFor(t *testing.T) {
+	cfg := &Config{
+		MCPServers: map[string]MCPServer{
+			"github": {Command: "github-mcp-server"},
+			"db":     {Command: "global-db"},
+		},
+	}
+	project := &Project{
+		Path:       "/home/user/projects/api",
+		MCPServers: map[string]MCPServer{"db": {Command: "project-db"}},
+	}
+
+	servers := cfg.MCPServersFor(project)
+	if len(servers) != 2 {
+		t.Fatalf("MCPServersFor() returned %d servers, expected 2", len(servers))
+	}
+	if servers["db"].Command != "project-db" {
+		t.Errorf("servers[db].Command = %q, expected project override", servers["db"].Command)
+	}
+
+	global := cfg.MCPServersFor(nil)
+	if global["db"].Command != "global-db" {
+		t.Errorf("servers[db].Command = %q, expected global value", global["db"].Command)
+	}
+	if _, ok := cfg.MCPServers["db"]; !ok || cfg.MCPServers["db"].Command != "global-db" {
+		t.Error("MCPServersFor() must not modify the global servers")
+	}
+}
+
+func TestUseTmux(t *testing.T) {
+	enabled := true
+	disabled := false
+
+	tests := []struct {
+		name     string
+		global   bool
+		project  *Project
+		expected bool
+	}{
+		{name: "global default off", global: false, expected: false},
+		{name: "global default on", global: true, expected: true},
+		{name: "project enables", global: false, project: &Project{Tmux: &enabled}, expected: true},
+		{name: "project disables", global: true, project: &Project{Tmux: &disabled}, expected: false},
+		{name: "project without setting inherits", global: true, project: &Project{}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Tmux: tt.global}
+			if got := cfg.UseTmux(tt.project); got != tt.expected {
+				t.Errorf("UseTmux() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToolFor(t *testing.T) {
+	cfg := &Config{Tool: "codex"}
+
+	if got := cfg.ToolFor(nil); got != "codex" {
+		t.Errorf("ToolFor(nil) = %q, expected global tool", got)
+	}
+	if got := cfg.ToolFor(&Project{}); got != "codex" {
+		t.Errorf("ToolFor() = %q, expected project without tool to inherit", got)
+	}
+	if got := cfg.ToolFor(&Project{Tool: "aider"}); got != "aider" {
+		t.Errorf("ToolFor() = %q, expected project tool to win", got)
+	}
+}
+
+func TestSessionFor(t *testing.T) {
+	if got := (&Config{}).SessionFor(nil); got != SessionAsk {
+		t.Errorf("SessionFor(nil) = %q, expected to ask by default", got)
+	}
+
+	cfg := &Config{Session: SessionContinue}
+	if got := cfg.SessionFor(&Project{}); got != SessionContinue {
+		t.Errorf("SessionFor() = %q, expected project without session to inherit", got)
+	}
+	if got := cfg.SessionFor(&Project{Session: SessionNew}); got != SessionNew {
+		t.Errorf("SessionFor() = %q, expected project session to win", got)
+	}
+
+	testFile := filepath.Join(t.TempDir(), "config.json")
+	jsonContent := `{"allowedDirs": ["/tmp"], "projects": [{"path": "/tmp/sandbox", "session": "alwaysNew"}]}`
+	if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if _, err := (&FileLoader{Path: testFile}).Load(); err == nil {
+		t.Error("FileLoader.Load() should reject an unknown project session")
+	}
+}
+
+func TestGitCheckFor(t *testing.T) {
+	if got := (&Config{}).GitCheckFor(nil); got != GitCheckOff {
+		t.Errorf("GitCheckFor(nil) = %q, expected off by default", got)
+	}
+
+	cfg := &Config{GitCheck: GitCheckWarn}
+	if got := cfg.GitCheckFor(&Project{}); got != GitCheckWarn {
+		t.Errorf("GitCheckFor() = %q, expected project without gitCheck to inherit", got)
+	}
+	if got := cfg.GitCheckFor(&Project{GitCheck: GitCheckBlock}); got != GitCheckBlock {
+		t.Errorf("GitCheckFor() = %q, expected project gitCheck to win", got)
+	}
+
+	testFile := filepath.Join(t.TempDir(), "config.json")
+	jsonContent := `{"allowedDirs": ["/tmp"], "gitCheck": "strict"}`
+	if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if _, err := (&FileLoader{Path: testFile}).Load(); err == nil {
+		t.Error("FileLoader.Load() should reject an unknown gitCheck")
+	}
+}
+
+func TestSnapshotFor(t *testing.T) {
+	cfg := &Config{Snapshot: "commit"}
+	tests := []struct {
+		project *Project
+		want    string
+	}{
+		{nil, "commit"},
+		{&Project{}, "commit"},
+		{&Project{Snapshot: "worktree"}, "worktree"},
+		{&Project{Snapshot: SnapshotOff}, ""},
+	}
+	for _, tt := range tests {
+		if got := cfg.SnapshotFor(tt.project); got != tt.want {
+			t.Errorf("SnapshotFor(%+v) = %q, want %q", tt.project, got, tt.want)
+		}
+	}
+
+	for _, content := range []string{
+		`{"allowedDirs": ["/tmp"], "snapshot": "off"}`,
+		`{"allowedDirs": ["/tmp"], "snapshot": "tarball"}`,
+		`{"allowedDirs": ["/tmp"], "projects": [{"path": "/tmp/x", "snapshot": "branch"}]}`,
+	} {
+		testFile := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		if _, err := (&FileLoader{Path: testFile}).Load(); err == nil {
+			t.Errorf("FileLoader.Load(%s) should reject the snapshot mode", content)
+		}
+	}
+}
+
+func TestBranchRuleFor(t *testing.T) {
+	project := &Project{Branches: []BranchRule{
+		{Pattern: "main", Deny: true},
+		{Pattern: "release/*", Preset: "readonly"},
+		{Pattern: "*", Preset: "default"},
+	}}
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{"main", "main"},
+		{"release/1.2", "release/*"},
+		{"feature", "*"},
+		{"feature/x", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if rule := BranchRuleFor(project, tt.branch); rule != nil {
+			got = rule.Pattern
+		}
+		if got != tt.want {
+			t.Errorf("BranchRuleFor(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+	if BranchRuleFor(nil, "main") != nil {
+		t.Error("BranchRuleFor(nil) should be nil")
+	}
+
+	for _, rules := range []string{
+		`[{"pattern": "main"}]`,
+		`[{"pattern": "main", "deny": true, "preset": "readonly"}]`,
+		`[{"pattern": "main", "preset": "unknown"}]`,
+		`[{"pattern": "[", "deny": true}]`,
+		`[{"deny": true}]`,
+	} {
+		testFile := filepath.Join(t.TempDir(), "config.json")
+		content := `{"allowedDirs": ["/tmp"], "permissionPresets": {"readonly": {}}, "projects": [{"path": "/tmp/x", "branches": ` + rules + `}]}`
+		if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		if _, err := (&FileLoader{Path: testFile}).Load(); err == nil {
+			t.Errorf("FileLoader.Load() should reject branches %s", rules)
+		}
+	}
+}
+
+func TestRequirementValidate(t *testing.T) {
+	valid := []Requirement{
+		{Command: "node", Version: ">=20"},
+		{Command: "python3", Version: ">= 3.11.2"},
+		{Command: "make"},
+		{File: ".env"},
+		{Run: "docker info", Name: "docker running"},
+	}
+	for _, r := range valid {
+		if err := r.validate(); err != nil {
+			t.Errorf("%+v.validate() = %v, want nil", r, err)
+		}
+	}
+
+	invalid := []Requirement{
+		{},
+		{Command: "node", File: ".env"},
+		{File: ".env", Version: "1"},
+		{Command: "node", Version: "latest"},
+		{Command: "node", Version: "<20"},
+	}
+	for _, r := range invalid {
+		if err := r.validate(); err == nil {
+			t.Errorf("%+v.validate() should fail", r)
+		}
+	}
+
+	if got := (Requirement{Command: "node", Version: ">=20"}).String(); got != "node >= 20" {
+		t.Errorf("String() = %q, want %q", got, "node >= 20")
+	}
+}
+
+func TestEnvFor(t *testing.T) {
+	cfg := &Config{Env: EnvConfig{EnvSet: EnvSet{Set: map[string]string{"A": "global", "B": "global"}}}}
+
+	env := cfg.EnvFor(&Project{Env: EnvSet{Set: map[string]string{"B": "project"}}})
+	if !reflect.DeepEqual(env, map[string]string{"A": "global", "B": "project"}) {
+		t.Errorf("EnvFor() = %v, expected project values to override global ones", env)
+	}
+
+	cfg.EnvFor(nil)["A"] = "changed"
+	if cfg.Env.Set["A"] != "global" {
+		t.Error("EnvFor() should not share the global map")
+	}
+}
+
+func TestPermissionsFor(t *testing.T) {
+	cfg := &Config{
+		PermissionPresets: map[string]Permissions{
+			"review": {DisabledTools: []string{"Edit", "Write"}, DefaultMode: "plan"},
+			"open":   {Allow: []string{"Bash(npm test:*)"}},
+		},
+	}
+	project := &Project{
+		Permissions:      Permissions{Deny: []string{"Read(./.env)"}, DefaultMode: "acceptEdits"},
+		PermissionPreset: "open",
+	}
+
+	tests := []struct {
+		name     string
+		project  *Project
+		preset   string
+		expected Permissions
+		wantErr  bool
+	}{
+		{name: "nothing configured", expected: Permissions{}},
+		{
+			name:     "project preset",
+			project:  project,
+			expected: Permissions{Allow: []string{"Bash(npm test:*)"}, Deny: []string{"Read(./.env)"}, DefaultMode: "acceptEdits"},
+		},
+		{
+			name:     "flag preset wins over project preset",
+			project:  project,
+			preset:   "review",
+			expected: Permissions{Deny: []string{"Read(./.env)"}, DisabledTools: []string{"Edit", "Write"}, DefaultMode: "plan"},
+		},
+		{name: "unknown preset", preset: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perms, err := cfg.PermissionsFor(tt.project, tt.preset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PermissionsFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(perms, tt.expected) {
+				t.Errorf("PermissionsFor() = %+v, expected %+v", perms, tt.expected)
+			}
+		})
+	}
+}
+
+func TestContainerFor(t *testing.T) {
+	cfg := &Config{
+		Container: ContainerConfig{
+			Runtime: "docker",
+			Image:   "claude:base",
+			Mounts:  []string{"/global:/global"},
+		},
+	}
+	project := &Project{
+		Container: ContainerConfig{
+			Image:  "claude:node",
+			Mounts: []string{"/project:/project"},
+		},
+	}
+
+	result := cfg.ContainerFor(project)
+	if result.Runtime != "docker" {
+		t.Errorf("Runtime = %q, expected inherited %q", result.Runtime, "docker")
+	}
+	if result.Image != "claude:node" {
+		t.Errorf("Image = %q, expected project override %q", result.Image, "claude:node")
+	}
+	if len(result.Mounts) != 2 {
+		t.Errorf("Mounts = %v, expected global and project mounts", result.Mounts)
+	}
+	if len(cfg.Container.Mounts) != 1 {
+		t.Error("ContainerFor() must not modify the global mounts")
+	}
+
+	if global := cfg.ContainerFor(nil); global.Image != "claude:base" {
+		t.Errorf("ContainerFor(nil).Image = %q, expected %q", global.Image, "claude:base")
+	}
+}
+
+func TestFileLoaderClaudePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "single string", json: `"claude-code"`, expected: []string{"claude-code"}},
+		{name: "list", json: `["claude", "~/.local/bin/claude"]`, expected: []string{"claude", "~/.local/bin/claude"}},
+		{name: "invalid", json: `42`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			jsonContent := `{"allowedDirs": ["/home/user"], "claudePath": ` + tt.json + `}`
+			if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FileLoader.Load() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileLoader.Load() error = %v", err)
+			}
+			if !reflect.DeepEqual([]string(cfg.ClaudePath), tt.expected) {
+				t.Errorf("ClaudePath = %v, expected %v", cfg.ClaudePath, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFileLoaderPlugins(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "names", json: `["vault", "jira"]`, expected: []string{"vault", "jira"}},
+		{name: "path", json: `["../vault"]`, wantErr: true},
+		{name: "empty name", json: `[""]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			jsonContent := `{"allowedDirs": ["/home/user"], "plugins": ` + tt.json + `}`
+			if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FileLoader.Load() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileLoader.Load() error = %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Plugins, tt.expected) {
+				t.Errorf("Plugins = %v, expected %v", cfg.Plugins, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFileLoaderLaunchScript(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("failed to get home dir: %v", err)
+	}
+
+	testFile := filepath.Join(t.TempDir(), "config.json")
+	jsonContent := `{"allowedDirs": ["/home/user"], "launchScript": "~/.config/claude-launcher/launch.star"}`
+	if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	cfg, err := (&FileLoader{Path: testFile}).Load()
+	if err != nil {
+		t.Fatalf("FileLoader.Load() error = %v", err)
+	}
+	if expected := filepath.Join(homeDir, ".config", "claude-launcher", "launch.star"); cfg.LaunchScript != expected {
+		t.Errorf("LaunchScript = %q, expected %q", cfg.LaunchScript, expected)
+	}
+}
+
+func TestConfigCheckForUpdates(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "config.json")
+	for _, tt := range []struct {
+		json string
+		want bool
+	}{
+		{`{"allowedDirs": ["/home/user"]}`, true},
+		{`{"allowedDirs": ["/home/user"], "updateCheck": true}`, true},
+		{`{"allowedDirs": ["/home/user"], "updateCheck": false}`, false},
+	} {
+		if err := os.WriteFile(testFile, []byte(tt.json), 0o644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		cfg, err := (&FileLoader{Path: testFile}).Load()
+		if err != nil {
+			t.Fatalf("FileLoader.Load() error = %v", err)
+		}
+		if got := cfg.CheckForUpdates(); got != tt.want {
+			t.Errorf("%s: CheckForUpdates() = %v, want %v", tt.json, got, tt.want)
+		}
+	}
+}
+
+func TestFileLoaderProfiles(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("failed to get home directory: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{name: "valid", json: `{"backend": {"dir": "~/api", "account": "Work", "session": "continue"}}`},
+		{name: "missing dir", json: `{"backend": {"account": "Work"}}`, wantErr: true},
+		{name: "invalid session", json: `{"backend": {"dir": "~/api", "session": "sometimes"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			jsonContent := `{"allowedDirs": ["/home/user"], "profiles": ` + tt.json + `}`
+			if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FileLoader.Load() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileLoader.Load() error = %v", err)
+			}
+
+			profile, ok := cfg.Profiles["backend"]
+			if !ok {
+				t.Fatal("profile 'backend' not loaded")
+			}
+			if profile.Dir != filepath.Join(homeDir, "api") {
+				t.Errorf("Dir = %q, expected ~ to be expanded", profile.Dir)
+			}
+			if profile.Session != SessionContinue {
+				t.Errorf("Session = %q, expected %q", profile.Session, SessionContinue)
+			}
+		})
+	}
+}
+
+func TestFileLoaderAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{name: "valid", json: `{"fix": {"preset": "yolo", "account": "Personal", "continue": true}}`},
+		{name: "continue and new", json: `{"fix": {"continue": true, "new": true}}`, wantErr: true},
+		{name: "flag-like name", json: `{"-x": {}}`, wantErr: true},
+		{name: "name with space", json: `{"my fix": {}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			jsonContent := `{"allowedDirs": ["/home/user"], "aliases": ` + tt.json + `}`
+			if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FileLoader.Load() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileLoader.Load() error = %v", err)
+			}
+
+			profile := cfg.Aliases["fix"].Profile()
+			if profile.Dir != "" || profile.Account != "Personal" || profile.Preset != "yolo" {
+				t.Errorf("Profile() = %+v, expected account Personal and preset yolo without a dir", profile)
+			}
+			if profile.Session != SessionContinue {
+				t.Errorf("Session = %q, expected %q", profile.Session, SessionContinue)
+			}
+		})
+	}
+}
+
+func TestFileLoaderEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{name: "valid", json: `{"passthrough": ["PATH", "LC_*"], "block": ["AWS_*"]}`},
+		{name: "invalid pattern", json: `{"block": ["AWS_["]}`, wantErr: true},
+		{name: "empty name", json: `{"passthrough": [""]}`, wantErr: true},
+		{name: "invalid set name", json: `{"passthrough": ["PATH", "LC_*"], "block": ["AWS_*"], "set": {"A=B": "1"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			jsonContent := `{"allowedDirs": ["/home/user"], "env": ` + tt.json + `}`
+			if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FileLoader.Load() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileLoader.Load() error = %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Env.Passthrough, []string{"PATH", "LC_*"}) || !reflect.DeepEqual(cfg.Env.Block, []string{"AWS_*"}) {
+				t.Errorf("Env = %+v, expected passthrough and block to be loaded", cfg.Env)
+			}
+		})
+	}
+}
+
+func TestFileLoaderSharedDirs(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "config.json")
+	jsonContent := `{"allowedDirs": ["/home/user/develop", {"path": "/home/user/libs", "shareWithClaude": true}, {"path": "/home/user/tmp"}]}`
+	if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	cfg, err := (&FileLoader{Path: testFile}).Load()
+	if err != nil {
+		t.Fatalf("FileLoader.Load() error = %v", err)
+	}
+
+	expectedAllowed := []string{"/home/user/develop", "/home/user/libs", "/home/user/tmp"}
+	if !reflect.DeepEqual(cfg.AllowedDirs, expectedAllowed) {
+		t.Errorf("AllowedDirs = %v, expected %v", cfg.AllowedDirs, expectedAllowed)
+	}
+	if !reflect.DeepEqual(cfg.SharedDirs, []string{"/home/user/libs"}) {
+		t.Errorf("SharedDirs = %v, expected [/home/user/libs]", cfg.SharedDirs)
+	}
+}
+
+func TestNormalizeDirs(t *testing.T) {
+	cfg := &Config{
+		AllowedDirs:     []string{"/work/", "/work/api", "/libs", "/work", "/work/api/"},
+		SharedDirs:      []string{"/libs/"},
+		YoloAllowedDirs: []string{"/work/api", "/work/api/tmp", "/work/api/"},
+	}
+	cfg.normalizeDirs()
+	if want := []string{"/work", "/work/api", "/libs"}; !slices.Equal(cfg.AllowedDirs, want) {
+		t.Errorf("AllowedDirs = %v, want %v", cfg.AllowedDirs, want)
+	}
+	if want := []string{"/libs"}; !slices.Equal(cfg.SharedDirs, want) {
+		t.Errorf("SharedDirs = %v, want %v", cfg.SharedDirs, want)
+	}
+
+	cfg = &Config{
+		AllowedDirs:     []string{"/work/api", "/work", "/work/libs", "/client", "/client/x"},
+		SharedDirs:      []string{"/work/libs"},
+		YoloAllowedDirs: []string{"/work/api", "/work/api/tmp"},
+		DirExpiry:       map[string]time.Time{"/client": time.Now().Add(time.Hour)},
+		CollapseNested:  true,
+	}
+	cfg.normalizeDirs()
+	// Shared entries are kept, an expiry does not keep a nested entry
+	if want := []string{"/work", "/work/libs", "/client"}; !slices.Equal(cfg.AllowedDirs, want) {
+		t.Errorf("AllowedDirs = %v, want %v", cfg.AllowedDirs, want)
+	}
+	if want := []string{"/work/api"}; !slices.Equal(cfg.YoloAllowedDirs, want) {
+		t.Errorf("YoloAllowedDirs = %v, want %v", cfg.YoloAllowedDirs, want)
+	}
+}
+
+func TestFileLoaderDirExpiry(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "config.json")
+	jsonContent := `{"allowedDirs": [
+		"/home/user/develop",
+		{"path": "/home/user/client", "expires": "2999-12-31"},
+		{"path": "/home/user/old", "expires": "2020-01-01T00:00:00Z", "shareWithClaude": true}
+	]}`
+	if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	cfg, err := (&FileLoader{Path: testFile}).Load()
+	if err != nil {
+		t.Fatalf("FileLoader.Load() error = %v", err)
+	}
+
+	// The expiry is reported, not enforced
+	expectedAllowed := []string{"/home/user/develop", "/home/user/client", "/home/user/old"}
+	if !reflect.DeepEqual(cfg.AllowedDirs, expectedAllowed) {
+		t.Errorf("AllowedDirs = %v, expected %v", cfg.AllowedDirs, expectedAllowed)
+	}
+	if !reflect.DeepEqual(cfg.SharedDirs, []string{"/home/user/old"}) {
+		t.Errorf("SharedDirs = %v, expected [/home/user/old]", cfg.SharedDirs)
+	}
+	wantExpiry := time.Date(3000, 1, 1, 0, 0, 0, 0, time.Local)
+	wantOld := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if len(cfg.DirExpiry) != 2 || !cfg.DirExpiry["/home/user/client"].Equal(wantExpiry) || !cfg.DirExpiry["/home/user/old"].Equal(wantOld) {
+		t.Errorf("DirExpiry = %v, expected the client directory until %v and the old one until %v", cfg.DirExpiry, wantExpiry, wantOld)
+	}
+	if cfg.DirSources["/home/user/develop"] != SourceFile {
+		t.Errorf("DirSources = %v", cfg.DirSources)
+	}
+
+	// A directory also listed without expires has no expiry
+	if err := os.WriteFile(testFile, []byte(`{"allowedDirs": [{"path": "/tmp/", "expires": "2999-12-31"}, "/tmp"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err := (&FileLoader{Path: testFile}).Load(); err != nil || len(cfg.DirExpiry) != 0 {
+		t.Errorf("FileLoader.Load() = %v, %v, expected no expiry", cfg, err)
+	}
+
+	if err := os.WriteFile(testFile, []byte(`{"allowedDirs": [{"path": "/tmp", "expires": "soon"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&FileLoader{Path: testFile}).Load(); err == nil {
+		t.Error("FileLoader.Load() should reject an invalid expires")
+	}
+}
+
+func TestFileLoaderPermissions(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			json: `"permissionPresets": {"review": {"disabledTools": ["Edit"]}}, "projects": [{"path": "/tmp", "permissionPreset": "review"}]`,
+		},
+		{name: "unknown project preset", json: `"projects": [{"path": "/tmp", "permissionPreset": "review"}]`, wantErr: true},
+		{name: "bypass in preset", json: `"permissionPresets": {"yolo": {"defaultMode": "bypassPermissions"}}`, wantErr: true},
+		{name: "bypass in project", json: `"projects": [{"path": "/tmp", "permissions": {"defaultMode": "bypassPermissions"}}]`, wantErr: true},
+		{name: "plan in project", json: `"projects": [{"path": "/tmp", "permissions": {"defaultMode": "plan"}}]`},
+		{name: "unknown mode in preset", json: `"permissionPresets": {"review": {"defaultMode": "planning"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			jsonContent := `{"allowedDirs": ["/home/user"], ` + tt.json + `}`
+			if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			_, err := (&FileLoader{Path: testFile}).Load()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FileLoader.Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileLoaderLinkedDirs(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("failed to get home directory: %v", err)
+	}
+
+	testFile := filepath.Join(t.TempDir(), "config.json")
+	jsonContent := `{"allowedDirs": ["/work"], "projects": [{"path": "/work/app", "linkedDirs": ["../shared", "/opt/sdk", "~/libs/util"]}]}`
+	if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	cfg, err := (&FileLoader{Path: testFile}).Load()
+	if err != nil {
+		t.Fatalf("FileLoader.Load() error = %v", err)
+	}
+	want := []string{"/work/shared", "/opt/sdk", filepath.Join(homeDir, "libs", "util")}
+	if got := cfg.Projects[0].LinkedDirs; !slices.Equal(got, want) {
+		t.Errorf("LinkedDirs = %v, want %v", got, want)
+	}
+}
+
+func TestFileLoaderDefaults(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "config.json")
+	jsonContent := `{"allowedDirs": ["/work"], "defaults": {"extraArgs": ["--verbose"]},
+		"projects": [{"path": "/work/app", "defaults": {"extraArgs": ["--settings", "/etc/org.json"]}}]}`
+	if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	cfg, err := (&FileLoader{Path: testFile}).Load()
+	if err != nil {
+		t.Fatalf("FileLoader.Load() error = %v", err)
+	}
+	if got, want := cfg.ExtraArgsFor(&cfg.Projects[0]), []string{"--verbose", "--settings", "/etc/org.json"}; !slices.Equal(got, want) {
+		t.Errorf("ExtraArgsFor(project) = %v, want %v", got, want)
+	}
+	if got, want := cfg.ExtraArgsFor(nil), []string{"--verbose"}; !slices.Equal(got, want) {
+		t.Errorf("ExtraArgsFor(nil) = %v, want %v", got, want)
+	}
+
+	for _, invalid := range []string{
+		`"defaults": {"extraArgs": ["--dangerously-skip-permissions"]}`,
+		`"projects": [{"path": "/work/app", "defaults": {"extraArgs": ["--permission-mode=bypassPermissions"]}}]`,
+	} {
+		if err := os.WriteFile(testFile, []byte(`{"allowedDirs": ["/work"], `+invalid+`}`), 0o644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		if _, err := (&FileLoader{Path: testFile}).Load(); err == nil {
+			t.Errorf("FileLoader.Load() should reject %s", invalid)
+		}
+	}
+}
+
+func TestRetentionRotation(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention Retention
+		want      state.Rotation
+	}{
+		{name: "defaults", want: state.Rotation{MaxSize: DefaultRetentionMaxSize, MaxTotalSize: DefaultRetentionMaxTotalSize}},
+		{
+			name:      "configured",
+			retention: Retention{MaxSize: "1M", MaxAge: "90d", MaxTotalSize: "0"},
+			want:      state.Rotation{MaxSize: 1 << 20, MaxAge: 90 * 24 * time.Hour},
+		},
+		{name: "hours", retention: Retention{MaxAge: "12h"}, want: state.Rotation{MaxSize: DefaultRetentionMaxSize, MaxAge: 12 * time.Hour, MaxTotalSize: DefaultRetentionMaxTotalSize}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.retention.validate(); err != nil {
+				t.Fatalf("validate() error = %v", err)
+			}
+			if got := tt.retention.Rotation(); got != tt.want {
+				t.Errorf("Rotation() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []Retention{{MaxSize: "big"}, {MaxTotalSize: "-1M"}, {MaxAge: "3 months"}} {
+		if err := invalid.validate(); err == nil {
+			t.Errorf("validate(%+v) should fail", invalid)
+		}
+	}
+}
+
+func TestFileLoaderWorkspaces(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("failed to get home directory: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{name: "valid", json: `{"backend": {"dirs": ["~/api", "~/libs"], "primary": "~/api"}}`},
+		{name: "no dirs", json: `{"backend": {"dirs": []}}`, wantErr: true},
+		{name: "primary not a member", json: `{"backend": {"dirs": ["~/api"], "primary": "~/web"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			jsonContent := `{"allowedDirs": ["/home/user"], "workspaces": ` + tt.json + `}`
+			if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FileLoader.Load() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileLoader.Load() error = %v", err)
+			}
+
+			ws := cfg.Workspaces["backend"]
+			expected := []string{filepath.Join(homeDir, "api"), filepath.Join(homeDir, "libs")}
+			if !reflect.DeepEqual(ws.Dirs, expected) || ws.Primary != expected[0] {
+				t.Errorf("workspace = %+v, expected ~ to be expanded in dirs and primary", ws)
+			}
+		})
+	}
+}
+
+func TestFileLoaderRemoteHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{name: "valid", json: `{"devbox": {"allowedDirs": ["~/src", "/srv"], "accounts": {"Work": "~/.claude-work"}}}`},
+		{name: "no dirs", json: `{"devbox": {"allowedDirs": []}}`, wantErr: true},
+		{name: "relative dir", json: `{"devbox": {"allowedDirs": ["src"]}}`, wantErr: true},
+		{name: "relative account dir", json: `{"devbox": {"allowedDirs": ["~"], "accounts": {"Work": ".claude"}}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			jsonContent := `{"allowedDirs": ["/home/user"], "remoteHosts": ` + tt.json + `}`
+			if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FileLoader.Load() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileLoader.Load() error = %v", err)
+			}
+
+			// Remote paths are kept as written
+			host := cfg.RemoteHosts["devbox"]
+			if !reflect.DeepEqual(host.AllowedDirs, []string{"~/src", "/srv"}) || host.Accounts["Work"] != "~/.claude-work" {
+				t.Errorf("remote host = %+v, expected the paths unchanged", host)
+			}
+		})
+	}
+}
+
+func TestFileLoaderDevcontainer(t *testing.T) {
+	for value, wantErr := range map[string]bool{"ask": false, "always": false, "never": false, "sometimes": true} {
+		testFile := filepath.Join(t.TempDir(), "config.json")
+		jsonContent := `{"allowedDirs": ["/home/user"], "devcontainer": "` + value + `"}`
+		if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+
+		cfg, err := (&FileLoader{Path: testFile}).Load()
+		if (err != nil) != wantErr {
+			t.Errorf("FileLoader.Load() with devcontainer %q error = %v, wantErr %v", value, err, wantErr)
+			continue
+		}
+		if err == nil && cfg.Devcontainer != value {
+			t.Errorf("Devcontainer = %q, expected %q", cfg.Devcontainer, value)
+		}
+	}
+}
+
+func TestFileLoaderNotifications(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{name: "valid", json: `[{"url": "https://example.com/hook", "format": "slack", "modes": ["detached"], "minDuration": "10m"}]`},
+		{name: "secret url", json: `[{"url": "op://dev/slack/webhook"}]`},
+		{name: "no url", json: `[{"format": "slack"}]`, wantErr: true},
+		{name: "unknown format", json: `[{"url": "https://example.com", "format": "teams"}]`, wantErr: true},
+		{name: "unobserved mode", json: `[{"url": "https://example.com", "modes": ["tmux"]}]`, wantErr: true},
+		{name: "invalid duration", json: `[{"url": "https://example.com", "minDuration": "10"}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			jsonContent := `{"allowedDirs": ["/home/user"], "notifications": ` + tt.json + `}`
+			if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FileLoader.Load() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileLoader.Load() error = %v", err)
+			}
+			if len(cfg.Notifications) != 1 {
+				t.Errorf("Notifications = %+v, expected one", cfg.Notifications)
+			}
+		})
+	}
+}
+
+func TestFileLoaderTheme(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "config.json")
+	jsonContent := `{"allowedDirs": ["/tmp"], "theme": {"palette": "colorblind", "error": "196"}}`
+	if err := os.WriteFile(testFile, []byte(jsonContent), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	cfg, err := (&FileLoader{Path: testFile}).Load()
+	if err != nil {
+		t.Fatalf("FileLoader.Load() error = %v", err)
+	}
+
+	expected := Theme{Palette: "colorblind", Error: "196"}
+	if cfg.Theme != expected {
+		t.Errorf("Theme = %+v, expected %+v", cfg.Theme, expected)
+	}
+}
+
+func TestFileLoaderPrompts(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    map[string]PromptText
+		wantErr bool
+	}{
+		{name: "unset", json: `{"allowedDirs": ["/tmp"]}`},
+		{
+			name: "text and languages",
+			json: `{"allowedDirs": ["/tmp"], "prompts": {"session": "Resume?", "denied": {"en": "Not approved.", "ja": "承認されていません。"}}}`,
+			want: map[string]PromptText{PromptSession: {"": "Resume?"}, PromptDenied: {"en": "Not approved.", "ja": "承認されていません。"}},
+		},
+		{name: "unknown prompt", json: `{"allowedDirs": ["/tmp"], "prompts": {"welcome": "Hi"}}`, wantErr: true},
+		{name: "empty text", json: `{"allowedDirs": ["/tmp"], "prompts": {"account": {"ja": " "}}}`, wantErr: true},
+		{name: "not a text", json: `{"allowedDirs": ["/tmp"], "prompts": {"account": 1}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(testFile, []byte(tt.json), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FileLoader.Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(cfg.Prompts, tt.want) {
+				t.Errorf("Prompts = %+v, expected %+v", cfg.Prompts, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptTextFor(t *testing.T) {
+	text := PromptText{"en": "Not approved.", "ja": "承認されていません。"}
+	for lang, want := range map[string]string{"ja": "承認されていません。", "en": "Not approved.", "fr": "Not approved."} {
+		if got, ok := text.For(lang); !ok || got != want {
+			t.Errorf("For(%q) = %q, %v, want %q", lang, got, ok, want)
+		}
+	}
+	if _, ok := (PromptText{"ja": "承認されていません。"}).For("en"); ok {
+		t.Error("For(en) should report no text when only ja is set")
+	}
+}
+
+func TestFileLoaderLog(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("failed to get home directory: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		json    string
+		want    LogConfig
+		wantErr bool
+	}{
+		{name: "unset", json: `{"allowedDirs": ["/tmp"]}`},
+		{
+			name: "file",
+			json: `{"allowedDirs": ["/tmp"], "log": {"level": "debug", "format": "json", "file": "~/launcher.log"}}`,
+			want: LogConfig{Level: "debug", Format: "json", File: filepath.Join(homeDir, "launcher.log")},
+		},
+		{name: "unknown level", json: `{"allowedDirs": ["/tmp"], "log": {"level": "loud"}}`, wantErr: true},
+		{name: "unknown format", json: `{"allowedDirs": ["/tmp"], "log": {"format": "xml"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(testFile, []byte(tt.json), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			cfg, err := (&FileLoader{Path: testFile}).Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FileLoader.Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.Log != tt.want {
+				t.Errorf("Log = %+v, expected %+v", cfg.Log, tt.want)
+			}
+		})
+	}
+}
+
+func TestSharedDirsFor(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	libs := filepath.Join(root, "libs")
+	outside := t.TempDir()
+	for _, dir := range []string{project, libs} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	cfg := &Config{
+		AllowedDirs: []string{root},
+		SharedDirs:  []string{libs, project, outside, filepath.Join(root, "missing")},
+	}
+
+	dirs := cfg.SharedDirsFor(project)
+	if !slices.Equal(dirs, []string{libs}) {
+		t.Errorf("SharedDirsFor() = %v, expected only %s", dirs, libs)
+	}
+}
+
+func BenchmarkFileLoaderLoad(b *testing.B) {
+	dir := b.TempDir()
+	testFile := filepath.Join(dir, "config.json")
+	content := `{
+		"allowedDirs": ["` + dir + `", {"path": "` + dir + `/libs", "shareWithClaude": true}],
+		"permissionPresets": {"readonly": {"deny": ["Edit", "Write"]}},
+		"projects": [
+			{"path": "` + dir + `/api", "preset": "readonly", "gitCheck": "warn", "branches": [{"pattern": "main", "deny": true}]},
+			{"path": "` + dir + `/web", "requires": [{"command": "node", "version": ">=20"}, {"file": ".env"}]}
+		]
+	}`
+	if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+		b.Fatal(err)
+	}
+
+	loader := &FileLoader{Path: testFile}
+	for b.Loop() {
+		if _, err := loader.Load(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
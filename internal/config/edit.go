@@ -0,0 +1,309 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// SetMCPServer adds or replaces an MCP server in the config file at path (the default path
+// when empty): a global one when project is "", otherwise one of the projects entry for that
+// directory, which is appended when there is none
+func SetMCPServer(path, project, name string, server MCPServer) error {
+	if err := validateMCPServers(map[string]MCPServer{name: server}); err != nil {
+		return err
+	}
+	value, err := marshal(server)
+	if err != nil {
+		return err
+	}
+	return editObject(path, project, "mcpServers", true, func(servers *object) error {
+		servers.set(name, value)
+		return nil
+	})
+}
+
+// RemoveMCPServer removes an MCP server from the config file at path (the default path when
+// empty), globally when project is "", otherwise from the projects entry for that directory
+func RemoveMCPServer(path, project, name string) error {
+	return editObject(path, project, "mcpServers", false, func(servers *object) error {
+		if _, ok := servers.values[name]; !ok {
+			return fmt.Errorf("MCP server %q not found", name)
+		}
+		servers.delete(name)
+		return nil
+	})
+}
+
+// SetBookmark adds or replaces a bookmark in the config file at path (the default path when empty)
+func SetBookmark(path, name string, b Bookmark) error {
+	if !ValidBookmarkName(name) {
+		return fmt.Errorf("invalid bookmark %q: names cannot be empty, start with - or contain spaces, @ or /", name)
+	}
+	value, err := marshal(b)
+	if err != nil {
+		return err
+	}
+	return editObject(path, "", "bookmarks", true, func(bookmarks *object) error {
+		bookmarks.set(name, value)
+		return nil
+	})
+}
+
+// RemoveBookmark removes a bookmark from the config file at path (the default path when empty)
+func RemoveBookmark(path, name string) error {
+	return editObject(path, "", "bookmarks", false, func(bookmarks *object) error {
+		if _, ok := bookmarks.values[name]; !ok {
+			return fmt.Errorf("bookmark %q not found", name)
+		}
+		bookmarks.delete(name)
+		return nil
+	})
+}
+
+// editObject applies edit to the object under key (e.g. mcpServers) of the config file, or of
+// the projects entry for project, and writes the file back. The other settings (including the
+// accounts) are kept with their keys in order; an object left empty is removed.
+func editObject(path, project, key string, create bool, edit func(o *object) error) error {
+	if path == "" {
+		var err error
+		if path, err = DefaultConfigPath(); err != nil {
+			return err
+		}
+	}
+	path = filepath.Clean(path)
+	// A missing file (e.g. allowedDirs come from CLAUDE_SAFE_DIRS) is created by the edit
+	perm, data := os.FileMode(0o600), []byte("{}")
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read config file: %w", err)
+	default:
+		perm = info.Mode().Perm()
+		if data, err = os.ReadFile(path); err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+	root, err := parseObject(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse config JSON: %w", err)
+	}
+
+	// The object holding the servers, and how to store it back
+	owner, save := root, func() error { return nil }
+	if project != "" {
+		owner, save, err = projectObject(root, project, create)
+		if err != nil {
+			return err
+		}
+	}
+
+	o := &object{values: map[string]json.RawMessage{}}
+	if raw, ok := owner.values[key]; ok {
+		if o, err = parseObject(raw); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", key, err)
+		}
+	}
+	if err := edit(o); err != nil {
+		return err
+	}
+	if err := owner.setJSON(key, o, len(o.keys) == 0); err != nil {
+		return err
+	}
+	if err := save(); err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return writeFileAtomic(path, out.Bytes(), perm)
+}
+
+// projectObject finds the projects entry for dir (comparing resolved paths), appending one
+// when create is set. save writes the entry back into root.
+func projectObject(root *object, dir string, create bool) (*object, func() error, error) {
+	expanded, err := ExpandPath(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to expand path %s: %w", dir, err)
+	}
+	target := canonicalPath(expanded)
+
+	var entries []json.RawMessage
+	if raw, ok := root.values["projects"]; ok {
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse projects: %w", err)
+		}
+	}
+
+	index := -1
+	var entry *object
+	for i, raw := range entries {
+		candidate, err := parseObject(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse projects: %w", err)
+		}
+		var path string
+		if err := json.Unmarshal(candidate.values["path"], &path); err != nil {
+			continue
+		}
+		if expanded, err := ExpandPath(path); err == nil && canonicalPath(expanded) == target {
+			index, entry = i, candidate
+			break
+		}
+	}
+	if entry == nil {
+		if !create {
+			return nil, nil, fmt.Errorf("no projects entry for %s", dir)
+		}
+		entry = &object{values: map[string]json.RawMessage{}}
+		if err := entry.setJSON("path", dir, false); err != nil {
+			return nil, nil, err
+		}
+		index = len(entries)
+		entries = append(entries, nil)
+	}
+
+	save := func() error {
+		value, err := marshal(entry)
+		if err != nil {
+			return err
+		}
+		entries[index] = value
+		return root.setJSON("projects", entries, false)
+	}
+	return entry, save, nil
+}
+
+// writeFileAtomic replaces path with data through a temporary file in the same directory
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }() //nolint:errcheck // already renamed on success
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close() //nolint:errcheck // the write error is reported
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// marshal encodes v like json.Marshal, but leaves <, > and & as written
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// object is a JSON object that keeps the order of its keys, so that edits leave the rest of
+// the file as the user wrote it
+type object struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// parseObject decodes a JSON object, keeping its values raw
+func parseObject(data []byte) (*object, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("expected a JSON object")
+	}
+	o := &object{values: map[string]json.RawMessage{}}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, errors.New("expected an object key")
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		o.set(key, value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// set stores value under key, appending the key when it is new
+func (o *object) set(key string, value json.RawMessage) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// setJSON stores v encoded under key, or removes the key when remove is set
+func (o *object) setJSON(key string, v any, remove bool) error {
+	if remove {
+		o.delete(key)
+		return nil
+	}
+	value, err := marshal(v)
+	if err != nil {
+		return err
+	}
+	o.set(key, value)
+	return nil
+}
+
+// delete removes key
+func (o *object) delete(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+	delete(o.values, key)
+	o.keys = slices.DeleteFunc(o.keys, func(k string) bool { return k == key })
+}
+
+// MarshalJSON encodes the object with its keys in order
+func (o *object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(o.values[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetAndRemoveMCPServer(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(t.TempDir(), "config.json")
+	original := `{
+  "allowedDirs": ["~/work"],
+  "accounts": [{"name": "Work", "configDir": "~/.claude-work"}],
+  "projects": [{"path": "~/work/api", "model": "opus"}],
+  "otelEnv": {"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4317?a=1&b=2"}
+}`
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetMCPServer(path, "", "github", MCPServer{Command: "github-mcp-server", Args: []string{"stdio"}}); err != nil {
+		t.Fatalf("SetMCPServer() error = %v", err)
+	}
+	if err := SetMCPServer(path, filepath.Join(home, "work", "api"), "db", MCPServer{Type: "http", URL: "http://localhost:8080/mcp"}); err != nil {
+		t.Fatalf("SetMCPServer() error = %v", err)
+	}
+	if err := SetMCPServer(path, filepath.Join(home, "blog"), "search", MCPServer{Command: "search-mcp"}); err != nil {
+		t.Fatalf("SetMCPServer() error = %v", err)
+	}
+
+	cfg, err := (&FileLoader{Path: path}).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.MCPServers["github"].Command != "github-mcp-server" {
+		t.Errorf("MCPServers = %+v, expected github", cfg.MCPServers)
+	}
+	if len(cfg.Projects) != 2 || cfg.Projects[0].Model != "opus" || cfg.Projects[0].MCPServers["db"].URL == "" {
+		t.Errorf("Projects = %+v, expected db added to the existing entry", cfg.Projects)
+	}
+	if cfg.Projects[1].Path != filepath.Join(home, "blog") || cfg.Projects[1].MCPServers["search"].Command != "search-mcp" {
+		t.Errorf("Projects = %+v, expected a new entry for the blog", cfg.Projects)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	// Unknown settings, key order and URLs are left as written
+	if !strings.Contains(content, `"accounts"`) || !strings.Contains(content, "a=1&b=2") {
+		t.Errorf("config file lost settings:\n%s", content)
+	}
+	if strings.Index(content, `"allowedDirs"`) > strings.Index(content, `"otelEnv"`) ||
+		strings.Index(content, `"otelEnv"`) > strings.LastIndex(content, `"mcpServers"`) {
+		t.Errorf("config file keys reordered:\n%s", content)
+	}
+
+	if err := RemoveMCPServer(path, "", "github"); err != nil {
+		t.Fatalf("RemoveMCPServer() error = %v", err)
+	}
+	if err := RemoveMCPServer(path, "~/work/api", "db"); err != nil {
+		t.Fatalf("RemoveMCPServer() error = %v", err)
+	}
+	if err := RemoveMCPServer(path, "", "github"); err == nil {
+		t.Error("RemoveMCPServer() should fail for a missing server")
+	}
+	if err := RemoveMCPServer(path, "~/elsewhere", "db"); err == nil {
+		t.Error("RemoveMCPServer() should fail for a missing project")
+	}
+
+	data, err = os.ReadFile(path) // #nosec G304 -- test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(data), `"mcpServers"`) != 1 {
+		t.Errorf("empty mcpServers should be removed:\n%s", data)
+	}
+
+	if err := SetMCPServer(path, "", "broken", MCPServer{}); err == nil {
+		t.Error("SetMCPServer() should reject a server without command or url")
+	}
+}
+
+func TestSetMCPServerCreatesConfigFile(t *testing.T) {
+	// allowedDirs may come from CLAUDE_SAFE_DIRS alone, with no config file yet
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := SetMCPServer(path, "", "github", MCPServer{Command: "github-mcp-server"}); err != nil {
+		t.Fatalf("SetMCPServer() error = %v", err)
+	}
+	cfg, err := (&FileLoader{Path: path}).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.MCPServers) != 1 || cfg.MCPServers["github"].Command != "github-mcp-server" {
+		t.Errorf("MCPServers = %+v, expected github", cfg.MCPServers)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("config file = %v, %v, expected mode 0600", info, err)
+	}
+}
+
+func TestSetAndRemoveBookmark(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"allowedDirs": ["~/work"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetBookmark(path, "api", Bookmark{Path: "~/work/api", Account: "Work", Preset: "review"}); err != nil {
+		t.Fatalf("SetBookmark() error = %v", err)
+	}
+	if err := SetBookmark(path, "blog", Bookmark{Path: "~/work/blog"}); err != nil {
+		t.Fatalf("SetBookmark() error = %v", err)
+	}
+	if err := SetBookmark(path, "my api", Bookmark{Path: "~/work/api"}); err == nil {
+		t.Error("SetBookmark() should refuse names with spaces")
+	}
+
+	cfg, err := (&FileLoader{Path: path}).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := Bookmark{Path: filepath.Join(home, "work", "api"), Account: "Work", Preset: "review"}
+	if len(cfg.Bookmarks) != 2 || cfg.Bookmarks["api"] != want {
+		t.Errorf("Bookmarks = %+v, expected api with ~ expanded and blog", cfg.Bookmarks)
+	}
+
+	if err := RemoveBookmark(path, "api"); err != nil {
+		t.Fatalf("RemoveBookmark() error = %v", err)
+	}
+	if err := RemoveBookmark(path, "api"); err == nil {
+		t.Error("RemoveBookmark() should fail for a missing bookmark")
+	}
+	if err := RemoveBookmark(path, "blog"); err != nil {
+		t.Fatalf("RemoveBookmark() error = %v", err)
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"bookmarks"`) {
+		t.Errorf("empty bookmarks should be removed:\n%s", data)
+	}
+}
+
+func TestSetBookmarkCreatesConfigFile(t *testing.T) {
+	// allowedDirs may come from CLAUDE_SAFE_DIRS alone, with no config file yet
+	path := filepath.Join(t.TempDir(), "claude-launcher", "config.json")
+	if err := RemoveBookmark(path, "api"); err == nil {
+		t.Error("RemoveBookmark() should fail without a config file")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("RemoveBookmark() should not create %s: %v", path, err)
+	}
+
+	if err := SetBookmark(path, "api", Bookmark{Path: "/work/api"}); err != nil {
+		t.Fatalf("SetBookmark() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("config file mode = %v, expected 0600", info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"bookmarks\": {\n    \"api\": {\n      \"path\": \"/work/api\"\n    }\n  }\n}\n"; string(data) != want {
+		t.Errorf("config file =\n%s\nexpected\n%s", data, want)
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/security"
+)
+
+// Lint rules
+const (
+	LintDuplicateDir       = "duplicate-dir"        // An entry listed twice
+	LintRedundantDir       = "redundant-dir"        // An entry inside another entry of the same list
+	LintUnreachableYoloDir = "unreachable-yolo-dir" // A yoloAllowedDirs entry outside allowedDirs
+	LintUnreachableProject = "unreachable-project"  // A project outside allowedDirs
+	LintShadowedAllow      = "shadowed-allow"       // An allow rule that is also denied
+	LintMissingDir         = "missing-dir"          // An allowed directory that does not exist
+	LintUnmatchedPattern   = "unmatched-pattern"    // An env.passthrough pattern no variable matches
+	LintDuplicateConfigDir = "duplicate-config-dir" // Accounts sharing a config directory
+)
+
+// Finding is a problem in a configuration that loads but does not do what it seems to
+type Finding struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Fix     string `json:"fix"` // A suggested change
+}
+
+// Lint looks for rules of cfg that overlap, are shadowed or can never match. environ is the
+// environment the launcher passes variables from (os.Environ()).
+func Lint(cfg *Config, environ []string) []Finding {
+	var findings []Finding
+	findings = append(findings, lintDirs(cfg, "allowedDirs", cfg.AllowedDirs)...)
+	findings = append(findings, lintDirs(cfg, "yoloAllowedDirs", cfg.YoloAllowedDirs)...)
+
+	for _, dir := range cfg.YoloAllowedDirs {
+		if !withinAny(dir, cfg.AllowedDirs) {
+			findings = append(findings, Finding{
+				Rule:    LintUnreachableYoloDir,
+				Message: fmt.Sprintf("yoloAllowedDirs entry %s is outside allowedDirs, so launches there are refused before it is checked", dir),
+				Fix:     fmt.Sprintf("add %s to allowedDirs, or remove it from yoloAllowedDirs", dir),
+			})
+		}
+	}
+	for _, project := range cfg.Projects {
+		if !withinAny(project.Path, cfg.AllowedDirs) && !slices.ContainsFunc(cfg.AllowedDirs, func(dir string) bool { return security.IsWithin(dir, project.Path) }) {
+			findings = append(findings, Finding{
+				Rule:    LintUnreachableProject,
+				Message: fmt.Sprintf("project %s is outside allowedDirs, so its settings never apply", project.Path),
+				Fix:     fmt.Sprintf("add %s to allowedDirs, or remove the project", project.Path),
+			})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.PermissionPresets)) {
+		findings = append(findings, lintPermissions("permission preset "+name, cfg.PermissionPresets[name])...)
+	}
+	for _, project := range cfg.Projects {
+		findings = append(findings, lintPermissions("project "+project.Path, project.Permissions)...)
+	}
+
+	for _, dir := range cfg.AllowedDirs {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			findings = append(findings, Finding{
+				Rule:    LintMissingDir,
+				Message: fmt.Sprintf("allowed directory %s does not exist", dir),
+				Fix:     fmt.Sprintf("create %s, or remove it from allowedDirs", dir),
+			})
+		}
+	}
+
+	for _, pattern := range cfg.Env.Passthrough {
+		if !slices.ContainsFunc(environ, func(kv string) bool { return matchesEnvPattern(pattern, kv) }) {
+			findings = append(findings, Finding{
+				Rule:    LintUnmatchedPattern,
+				Message: fmt.Sprintf("env.passthrough pattern %q matches no variable in the current environment", pattern),
+				Fix:     "check the spelling (names are case-sensitive), or remove the pattern",
+			})
+		}
+	}
+	return findings
+}
+
+// lintDirs finds the entries of a directory list that are listed twice or already covered by
+// another entry
+func lintDirs(cfg *Config, list string, dirs []string) []Finding {
+	var findings []Finding
+	for i, dir := range dirs {
+		if slices.ContainsFunc(dirs[:i], func(other string) bool { return canonicalPath(other) == canonicalPath(dir) }) {
+			findings = append(findings, Finding{
+				Rule:    LintDuplicateDir,
+				Message: fmt.Sprintf("%s lists %s twice", list, dir),
+				Fix:     "remove one of the entries",
+			})
+			continue
+		}
+		for _, other := range dirs {
+			if canonicalPath(other) != canonicalPath(dir) && security.IsWithin(dir, other) && !keepsInnerDir(cfg, list, dir, other) {
+				findings = append(findings, Finding{
+					Rule:    LintRedundantDir,
+					Message: fmt.Sprintf("%s entry %s is inside %s, which already covers it", list, dir, other),
+					Fix:     fmt.Sprintf("remove %s from %s", dir, list),
+				})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// keepsInnerDir reports whether the allowedDirs entry inner does something its parent outer
+// does not: it is shared with claude
+func keepsInnerDir(cfg *Config, list, inner, outer string) bool {
+	if list != "allowedDirs" {
+		return false
+	}
+	return slices.Contains(cfg.SharedDirs, inner) && !slices.Contains(cfg.SharedDirs, outer)
+}
+
+// lintPermissions finds the allow rules of owner that can never match because they are also
+// denied (Claude Code applies deny rules first)
+func lintPermissions(owner string, p Permissions) []Finding {
+	var findings []Finding
+	for _, rule := range p.Allow {
+		if slices.Contains(p.Deny, rule) {
+			findings = append(findings, Finding{
+				Rule:    LintShadowedAllow,
+				Message: fmt.Sprintf("allow rule %q of %s can never match: it is also denied", rule, owner),
+				Fix:     fmt.Sprintf("remove %q from allow or from deny", rule),
+			})
+		}
+	}
+	return findings
+}
+
+// withinAny reports whether dir is one of dirs or inside one of them
+func withinAny(dir string, dirs []string) bool {
+	return slices.ContainsFunc(dirs, func(d string) bool { return security.IsWithin(dir, d) })
+}
+
+// matchesEnvPattern reports whether the KEY=VALUE pair kv has a name matching pattern
+func matchesEnvPattern(pattern, kv string) bool {
+	key, _, _ := strings.Cut(kv, "=")
+	ok, _ := path.Match(pattern, key)
+	return ok
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLint(t *testing.T) {
+	root := t.TempDir()
+	work := filepath.Join(root, "work")
+	api := filepath.Join(work, "api")
+	libs := filepath.Join(work, "libs")
+	client := filepath.Join(work, "client")
+	cfg := &Config{
+		AllowedDirs:     []string{work, api, libs, client, work},
+		SharedDirs:      []string{libs},
+		YoloAllowedDirs: []string{api, filepath.Join(root, "elsewhere")},
+		DirExpiry:       map[string]time.Time{client: time.Now().Add(time.Hour)},
+		Projects: []Project{
+			{Path: root}, // Contains allowed directories
+			{Path: filepath.Join(root, "other"), Permissions: Permissions{Allow: []string{"Bash(git:*)"}, Deny: []string{"Bash(git:*)"}}},
+		},
+		Env: EnvConfig{Passthrough: []string{"HOME", "AWS_*"}},
+	}
+
+	var got []string
+	for _, f := range Lint(cfg, []string{"HOME=/home/user"}) {
+		got = append(got, f.Rule+" "+f.Message)
+	}
+	want := []string{
+		LintRedundantDir + " allowedDirs entry " + api + " is inside " + work + ", which already covers it",
+		LintRedundantDir + " allowedDirs entry " + client + " is inside " + work + ", which already covers it",
+		LintDuplicateDir + " allowedDirs lists " + work + " twice",
+		LintUnreachableYoloDir + " yoloAllowedDirs entry " + filepath.Join(root, "elsewhere") + " is outside allowedDirs, so launches there are refused before it is checked",
+		LintUnreachableProject + " project " + filepath.Join(root, "other") + " is outside allowedDirs, so its settings never apply",
+		LintShadowedAllow + ` allow rule "Bash(git:*)" of project ` + filepath.Join(root, "other") + " can never match: it is also denied",
+	}
+	for _, dir := range cfg.AllowedDirs {
+		want = append(want, LintMissingDir+" allowed directory "+dir+" does not exist")
+	}
+	want = append(want, LintUnmatchedPattern+` env.passthrough pattern "AWS_*" matches no variable in the current environment`)
+	if !slices.Equal(got, want) {
+		t.Errorf("Lint() =\n%q\nwant\n%q", got, want)
+	}
+
+	if findings := Lint(&Config{AllowedDirs: []string{root}}, nil); len(findings) != 0 {
+		t.Errorf("Lint() = %+v, expected no findings", findings)
+	}
+}
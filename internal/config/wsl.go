@@ -0,0 +1,106 @@
+package config
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// defaultWSLMountRoot is where WSL mounts the Windows drives unless wsl.conf says otherwise
+const defaultWSLMountRoot = "/mnt/"
+
+var (
+	// windowsDrivePath matches C:\Users\me and C:/Users/me
+	windowsDrivePath = regexp.MustCompile(`^([A-Za-z]):([\\/].*)?$`)
+	// wslSharePath matches the WSL file share seen from Windows: \\wsl$\Ubuntu\home\me or
+	// \\wsl.localhost\Ubuntu\home\me (either slash)
+	wslSharePath = regexp.MustCompile(`(?i)^[\\/]{2}(?:wsl\$|wsl\.localhost)[\\/][^\\/]+([\\/].*)?$`)
+)
+
+// inWSL reports whether the launcher runs inside the Windows Subsystem for Linux
+var inWSL = sync.OnceValue(func() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" || os.Getenv("WSL_INTEROP") != "" {
+		return true
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(release)), "microsoft")
+})
+
+// wslMountRoot returns the automount root from /etc/wsl.conf, or /mnt/
+var wslMountRoot = sync.OnceValue(func() string {
+	f, err := os.Open("/etc/wsl.conf")
+	if err != nil {
+		return defaultWSLMountRoot
+	}
+	defer func() { _ = f.Close() }() //nolint:errcheck // read-only file
+	return parseWSLMountRoot(f)
+})
+
+// parseWSLMountRoot reads the [automount] root setting of a wsl.conf
+func parseWSLMountRoot(r io.Reader) string {
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section != "automount" || strings.TrimSpace(key) != "root" {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		if strings.HasPrefix(value, "/") {
+			return strings.TrimSuffix(value, "/") + "/"
+		}
+	}
+	return defaultWSLMountRoot
+}
+
+// translateWSLPath converts a path written for the other side of WSL, so that a config shared
+// between Windows and WSL works on both: inside WSL, Windows paths become Linux paths; on
+// Windows, paths under the WSL drive mounts become drive paths. Other paths are unchanged.
+func translateWSLPath(p string) string {
+	switch {
+	case runtime.GOOS == "windows":
+		return wslToWindowsPath(p, defaultWSLMountRoot)
+	case inWSL():
+		return windowsToWSLPath(p, wslMountRoot())
+	default:
+		return p
+	}
+}
+
+// windowsToWSLPath converts C:\Users\me to /mnt/c/Users/me and \\wsl$\Ubuntu\home\me to /home/me
+func windowsToWSLPath(p, mountRoot string) string {
+	if m := windowsDrivePath.FindStringSubmatch(p); m != nil {
+		return path.Join(mountRoot, strings.ToLower(m[1]), strings.ReplaceAll(m[2], `\`, "/"))
+	}
+	if m := wslSharePath.FindStringSubmatch(p); m != nil {
+		return path.Join("/", strings.ReplaceAll(m[1], `\`, "/"))
+	}
+	return p
+}
+
+// wslToWindowsPath converts /mnt/c/Users/me to C:\Users\me
+func wslToWindowsPath(p, mountRoot string) string {
+	rest, ok := strings.CutPrefix(p, mountRoot)
+	if !ok || rest == "" || !isASCIILetter(rest[0]) || len(rest) > 1 && rest[1] != '/' {
+		return p
+	}
+	return strings.ToUpper(rest[:1]) + `:\` + strings.ReplaceAll(strings.TrimPrefix(path.Clean("/"+rest[1:]), "/"), "/", `\`)
+}
+
+// isASCIILetter reports whether c is a drive letter
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
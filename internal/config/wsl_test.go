@@ -0,0 +1,68 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWindowsToWSLPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{`C:\Users\me\proj`, "/mnt/c/Users/me/proj"},
+		{`d:/work/`, "/mnt/d/work"},
+		{`C:`, "/mnt/c"},
+		{`\\wsl$\Ubuntu\home\me\proj`, "/home/me/proj"},
+		{`\\wsl.localhost\Ubuntu`, "/"},
+		{`//wsl.localhost/Ubuntu/home/me`, "/home/me"},
+		{"/home/me/proj", "/home/me/proj"},
+		{"~/proj", "~/proj"},
+		{"CD:/x", "CD:/x"},
+	}
+	for _, tt := range tests {
+		if got := windowsToWSLPath(tt.path, "/mnt/"); got != tt.expected {
+			t.Errorf("windowsToWSLPath(%q) = %q, expected %q", tt.path, got, tt.expected)
+		}
+	}
+
+	if got := windowsToWSLPath(`C:\x`, "/"); got != "/c/x" {
+		t.Errorf("windowsToWSLPath with root / = %q, expected /c/x", got)
+	}
+}
+
+func TestWSLToWindowsPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/mnt/c/Users/me/proj", `C:\Users\me\proj`},
+		{"/mnt/d", `D:\`},
+		{"/mnt/c/Users/../x/", `C:\x`},
+		{"/mnt/wsl/x", "/mnt/wsl/x"},
+		{"/home/me", "/home/me"},
+		{`C:\Users`, `C:\Users`},
+	}
+	for _, tt := range tests {
+		if got := wslToWindowsPath(tt.path, "/mnt/"); got != tt.expected {
+			t.Errorf("wslToWindowsPath(%q) = %q, expected %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestParseWSLMountRoot(t *testing.T) {
+	tests := []struct {
+		conf     string
+		expected string
+	}{
+		{"", "/mnt/"},
+		{"[automount]\nenabled = true\nroot = /\n", "/"},
+		{"[automount]\nroot = \"/win\"\n", "/win/"},
+		{"[network]\nroot = /x/\n", "/mnt/"},
+	}
+	for _, tt := range tests {
+		if got := parseWSLMountRoot(strings.NewReader(tt.conf)); got != tt.expected {
+			t.Errorf("parseWSLMountRoot(%q) = %q, expected %q", tt.conf, got, tt.expected)
+		}
+	}
+}
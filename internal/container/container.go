@@ -0,0 +1,135 @@
+// Package container wraps a claude invocation so it runs inside a Docker or Podman container.
+package container
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+// Options controls how the container is started
+type Options struct {
+	Runtime   string   // "docker" or "podman"; empty auto-detects
+	Image     string   // Required
+	Mounts    []string // Extra "host:container[:ro]" bind mounts
+	ConfigDir string   // Claude config dir to mount (defaults to ~/.claude)
+	Memory    string   // Optional: passed to --memory
+	CPUs      []int    // Optional: passed to --cpuset-cpus
+}
+
+// Wrap returns a Command that runs c inside a container.
+// The project directory, the Claude config dir, and any temp files are bind-mounted
+// at the same paths so that arguments referring to them stay valid.
+func Wrap(c *launcher.Command, opts Options) (*launcher.Command, error) {
+	if opts.Image == "" {
+		return nil, fmt.Errorf("container image is not configured")
+	}
+
+	runtime, err := resolveRuntime(opts.Runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	configDir := opts.ConfigDir
+	if configDir == "" {
+		configDir, err = config.ExpandPath("~/.claude")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	args := []string{"run", "--rm", "-i"}
+	if isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd()) {
+		args = append(args, "-t")
+	}
+
+	args = append(args,
+		"-v", c.Dir+":"+c.Dir,
+		"-w", c.Dir,
+		"-v", configDir+":"+configDir,
+		"-e", "CLAUDE_CONFIG_DIR="+configDir,
+	)
+
+	for _, path := range c.TempFiles {
+		args = append(args, "-v", path+":"+path+":ro")
+	}
+
+	for _, mount := range opts.Mounts {
+		expanded, err := expandMount(mount)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "-v", expanded)
+	}
+
+	if opts.Memory != "" {
+		args = append(args, "--memory", opts.Memory)
+	}
+	if len(opts.CPUs) > 0 {
+		cpus := make([]string, len(opts.CPUs))
+		for i, cpu := range opts.CPUs {
+			cpus[i] = strconv.Itoa(cpu)
+		}
+		args = append(args, "--cpuset-cpus", strings.Join(cpus, ","))
+	}
+
+	// Only the names go on the command line, where every user can read them; the runtime takes
+	// the values from its own environment (c.Env)
+	for _, e := range launcher.EnvDelta(os.Environ(), c.Env) {
+		key, _, _ := strings.Cut(e, "=")
+		if key == "CLAUDE_CONFIG_DIR" {
+			continue
+		}
+		args = append(args, "-e", key)
+	}
+
+	args = append(args, opts.Image, filepath.Base(c.Path))
+	args = append(args, c.Args...)
+
+	return &launcher.Command{
+		Path:      runtime,
+		Args:      args,
+		Env:       c.Env,
+		Dir:       c.Dir,
+		TempFiles: c.TempFiles,
+	}, nil
+}
+
+// resolveRuntime returns the container runtime to use, auto-detecting docker then podman
+func resolveRuntime(runtime string) (string, error) {
+	candidates := []string{"docker", "podman"}
+	if runtime != "" {
+		candidates = []string{runtime}
+	}
+
+	for _, candidate := range candidates {
+		if path, err := exec.LookPath(candidate); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("container runtime not found (tried %s)", strings.Join(candidates, ", "))
+}
+
+// expandMount expands ~ in the host part of a "host:container[:ro]" mount
+func expandMount(mount string) (string, error) {
+	host, rest, ok := strings.Cut(mount, ":")
+	if !ok || host == "" || rest == "" {
+		return "", fmt.Errorf("invalid mount %q: expected host:container[:ro]", mount)
+	}
+
+	expanded, err := config.ExpandPath(host)
+	if err != nil {
+		return "", err
+	}
+
+	return expanded + ":" + rest, nil
+}
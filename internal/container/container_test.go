@@ -0,0 +1,101 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+// fakeRuntime puts an executable named name on PATH and returns its path
+func fakeRuntime(t *testing.T, name string) string {
+	t.Helper()
+	binDir := t.TempDir()
+	if runtime.GOOS == "windows" {
+		name += ".bat"
+	}
+	path := filepath.Join(binDir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("failed to create fake runtime: %v", err)
+	}
+	t.Setenv("PATH", binDir)
+	return path
+}
+
+func TestWrap(t *testing.T) {
+	runtimePath := fakeRuntime(t, "podman")
+
+	c := &launcher.Command{
+		Path:      "/usr/local/bin/claude",
+		Args:      []string{"--continue"},
+		Env:       append(os.Environ(), "CLAUDE_CONFIG_DIR=/home/user/.claude-work", "OTEL_SERVICE_NAME=claude", "ANTHROPIC_API_KEY=sk-ant-secret"),
+		Dir:       "/home/user/develop/api",
+		TempFiles: []string{"/tmp/mcp.json"},
+	}
+
+	wrapped, err := Wrap(c, Options{
+		Image:     "claude:latest",
+		Mounts:    []string{"/opt/shared:/opt/shared:ro"},
+		ConfigDir: "/home/user/.claude-work",
+		Memory:    "4g",
+		CPUs:      []int{0, 1},
+	})
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+
+	if wrapped.Path != runtimePath {
+		t.Errorf("Path = %q, expected auto-detected %q", wrapped.Path, runtimePath)
+	}
+
+	mustContainPair := func(flag, value string) {
+		t.Helper()
+		for i := 0; i+1 < len(wrapped.Args); i++ {
+			if wrapped.Args[i] == flag && wrapped.Args[i+1] == value {
+				return
+			}
+		}
+		t.Errorf("Args = %v, expected %s %s", wrapped.Args, flag, value)
+	}
+	mustContainPair("-v", "/home/user/develop/api:/home/user/develop/api")
+	mustContainPair("-w", "/home/user/develop/api")
+	mustContainPair("-v", "/home/user/.claude-work:/home/user/.claude-work")
+	mustContainPair("-v", "/tmp/mcp.json:/tmp/mcp.json:ro")
+	mustContainPair("-v", "/opt/shared:/opt/shared:ro")
+	mustContainPair("-e", "OTEL_SERVICE_NAME")
+	mustContainPair("-e", "ANTHROPIC_API_KEY")
+	for _, arg := range wrapped.Args {
+		if strings.Contains(arg, "sk-ant-secret") {
+			t.Errorf("argument %q contains the secret", arg)
+		}
+	}
+	if !slices.Contains(wrapped.Env, "ANTHROPIC_API_KEY=sk-ant-secret") {
+		t.Error("Env does not carry the value the runtime passes on")
+	}
+	mustContainPair("--memory", "4g")
+	mustContainPair("--cpuset-cpus", "0,1")
+
+	tail := wrapped.Args[len(wrapped.Args)-3:]
+	if !slices.Equal(tail, []string{"claude:latest", "claude", "--continue"}) {
+		t.Errorf("Args tail = %v, expected image, claude and its args", tail)
+	}
+}
+
+func TestWrapErrors(t *testing.T) {
+	fakeRuntime(t, "docker")
+	c := &launcher.Command{Path: "claude", Dir: "/tmp"}
+
+	if _, err := Wrap(c, Options{}); err == nil {
+		t.Error("Wrap() should fail without an image")
+	}
+	if _, err := Wrap(c, Options{Image: "img", Runtime: "nerdctl"}); err == nil {
+		t.Error("Wrap() should fail when the runtime is not installed")
+	}
+	if _, err := Wrap(c, Options{Image: "img", Mounts: []string{"/only-host"}}); err == nil {
+		t.Error("Wrap() should fail on an invalid mount")
+	}
+}
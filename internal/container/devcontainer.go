@@ -0,0 +1,165 @@
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+// devcontainerFiles mark the root of a workspace with a devcontainer
+var devcontainerFiles = []string{filepath.Join(".devcontainer", "devcontainer.json"), ".devcontainer.json"}
+
+// FindDevcontainer returns the nearest of dir and its parents with a devcontainer configuration,
+// or "" when there is none
+func FindDevcontainer(dir string) string {
+	for {
+		for _, name := range devcontainerFiles {
+			if info, err := os.Stat(filepath.Join(dir, name)); err == nil && !info.IsDir() {
+				return dir
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// DevcontainerOptions controls a launch inside a devcontainer
+type DevcontainerOptions struct {
+	Workspace string // Required: the folder with the devcontainer configuration
+	ConfigDir string // Claude config dir to mount (defaults to ~/.claude)
+}
+
+// upResult is the JSON line `devcontainer up` prints on stdout
+type upResult struct {
+	Outcome               string `json:"outcome"`
+	Message               string `json:"message"`
+	Description           string `json:"description"`
+	RemoteWorkspaceFolder string `json:"remoteWorkspaceFolder"`
+}
+
+// WrapDevcontainer returns a Command that runs c inside the devcontainer of the workspace with
+// the devcontainer CLI (`devcontainer exec`). It starts the container first (`devcontainer up`),
+// bind-mounting the Claude config dir at the same path.
+// The temp files (settings, MCP servers) are passed inline, as they do not exist in the
+// container, and paths inside the workspace are mapped to the remote workspace folder.
+func WrapDevcontainer(c *launcher.Command, opts DevcontainerOptions) (*launcher.Command, error) {
+	cli, err := exec.LookPath("devcontainer")
+	if err != nil {
+		return nil, errors.New("devcontainer CLI not found (install it with: npm install -g @devcontainers/cli)")
+	}
+
+	configDir := opts.ConfigDir
+	if configDir == "" {
+		configDir, err = config.ExpandPath("~/.claude")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	remoteWorkspace, err := devcontainerUp(cli, opts.Workspace, configDir)
+	if err != nil {
+		return nil, err
+	}
+	// The mount is only added when the container is created
+	// #nosec G204 -- the devcontainer CLI with the launch's own paths
+	if err := exec.Command(cli, "exec", "--workspace-folder", opts.Workspace, "test", "-d", configDir).Run(); err != nil {
+		return nil, fmt.Errorf("the devcontainer of %s was started without %s mounted; recreate it with: devcontainer up --workspace-folder %s --remove-existing-container",
+			opts.Workspace, configDir, opts.Workspace)
+	}
+
+	remotePath := func(p string) string {
+		rel, err := filepath.Rel(opts.Workspace, p)
+		if err != nil || !filepath.IsLocal(rel) {
+			return p
+		}
+		return path.Join(remoteWorkspace, filepath.ToSlash(rel))
+	}
+
+	command := []string{remotePath(c.Dir), filepath.Base(c.Path)}
+	for _, arg := range c.Args {
+		switch {
+		case slices.Contains(c.TempFiles, arg):
+			content, err := os.ReadFile(arg) // #nosec G304 -- temp file written by the launcher
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", arg, err)
+			}
+			arg = string(content)
+		case filepath.IsAbs(arg):
+			arg = remotePath(arg)
+		}
+		command = append(command, arg)
+	}
+
+	// --remote-env arguments carry values, secrets included, where every user can read them, so
+	// the variables go through a file in the config dir, which the container shares
+	var env []string
+	for _, e := range launcher.EnvDelta(os.Environ(), c.Env) {
+		if !strings.HasPrefix(e, "CLAUDE_CONFIG_DIR=") {
+			env = append(env, e)
+		}
+	}
+	envFile, err := launcher.WriteEnvFile(configDir, env)
+	if err != nil {
+		return nil, err
+	}
+
+	// devcontainer exec always starts in the workspace folder
+	args := []string{"exec", "--workspace-folder", opts.Workspace, "--remote-env", "CLAUDE_CONFIG_DIR=" + configDir,
+		"sh", "-c", launcher.SourceEnvFile + `cd "$1" && shift && exec "$@"`, "sh", envFile}
+	args = append(args, command...)
+
+	return &launcher.Command{
+		Path:      cli,
+		Args:      args,
+		Env:       c.Env,
+		Dir:       c.Dir,
+		TempFiles: append(slices.Clone(c.TempFiles), envFile), // In case sh did not get to delete it
+	}, nil
+}
+
+// devcontainerUp starts (or reuses) the devcontainer of workspace with configDir mounted and
+// returns the workspace folder inside it
+func devcontainerUp(cli, workspace, configDir string) (string, error) {
+	mount := fmt.Sprintf("type=bind,source=%s,target=%s", configDir, configDir)
+	// #nosec G204 -- the devcontainer CLI with the launch's own paths
+	cmd := exec.Command(cli, "up", "--workspace-folder", workspace, "--mount", mount)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	runErr := cmd.Run()
+
+	// The result is the last line of stdout, also on failure
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	var result upResult
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &result); err != nil || result.Outcome == "" {
+		if runErr == nil {
+			runErr = errors.New("unexpected output")
+		}
+		return "", fmt.Errorf("failed to start the devcontainer: %w: %s", runErr, lastLine(stderr.String()))
+	}
+	if result.Outcome != "success" {
+		return "", fmt.Errorf("failed to start the devcontainer: %s", strings.TrimSpace(result.Message+" "+result.Description))
+	}
+	if result.RemoteWorkspaceFolder == "" {
+		return "", errors.New("failed to start the devcontainer: no remote workspace folder reported")
+	}
+	return result.RemoteWorkspaceFolder, nil
+}
+
+// lastLine returns the last non-empty line of s
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
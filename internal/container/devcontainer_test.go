@@ -0,0 +1,150 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+func TestFindDevcontainer(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "api", "internal")
+	if err := os.MkdirAll(filepath.Join(root, ".devcontainer"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindDevcontainer(sub); got != "" {
+		t.Errorf("FindDevcontainer() = %q without devcontainer.json, expected none", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".devcontainer", "devcontainer.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindDevcontainer(sub); got != root {
+		t.Errorf("FindDevcontainer() = %q, expected %q", got, root)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "api", ".devcontainer.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindDevcontainer(sub); got != filepath.Join(root, "api") {
+		t.Errorf("FindDevcontainer() = %q, expected the nearest workspace", got)
+	}
+}
+
+// fakeDevcontainerCLI puts a devcontainer CLI on PATH that answers `up` with upOutput and
+// `exec test -d` with testStatus
+func fakeDevcontainerCLI(t *testing.T, upOutput string, testStatus int) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the devcontainer CLI")
+	}
+	script := "#!/bin/sh\ncase \"$1 $4\" in\n" +
+		"'up '*) echo 'log line' >&2; echo '" + upOutput + "' ;;\n" +
+		"'exec test') exit " + strconv.Itoa(testStatus) + " ;;\n" +
+		"esac\n"
+	return fakeScript(t, "devcontainer", script)
+}
+
+// fakeScript puts an executable script named name on PATH and returns its path
+func fakeScript(t *testing.T, name, script string) string {
+	t.Helper()
+	binDir := t.TempDir()
+	path := filepath.Join(binDir, name)
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil { // #nosec G306 -- test script
+		t.Fatalf("failed to create %s: %v", name, err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+"/usr/bin:/bin")
+	return path
+}
+
+func TestWrapDevcontainer(t *testing.T) {
+	cli := fakeDevcontainerCLI(t, `{"outcome":"success","containerId":"abc","remoteWorkspaceFolder":"/workspaces/app"}`, 0)
+
+	settings := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(settings, []byte(`{"permissions":{}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := &launcher.Command{
+		Path:      "/usr/local/bin/claude",
+		Args:      []string{"--settings", settings, "--add-dir", "/home/user/app/docs", "--continue"},
+		Env:       append(os.Environ(), "CLAUDE_CONFIG_DIR=/home/user/.claude-work", "OTEL_SERVICE_NAME=claude", "ANTHROPIC_API_KEY=sk-ant-secret"),
+		Dir:       "/home/user/app/api",
+		TempFiles: []string{settings},
+	}
+	configDir := t.TempDir()
+
+	wrapped, err := WrapDevcontainer(c, DevcontainerOptions{Workspace: "/home/user/app", ConfigDir: configDir})
+	if err != nil {
+		t.Fatalf("WrapDevcontainer() error = %v", err)
+	}
+	if wrapped.Path != cli {
+		t.Errorf("Path = %q, expected %q", wrapped.Path, cli)
+	}
+
+	args := strings.Join(wrapped.Args, " ")
+	if expected := "exec --workspace-folder /home/user/app --remote-env CLAUDE_CONFIG_DIR=" + configDir; !strings.Contains(args, expected) {
+		t.Errorf("Args = %v, expected %q", wrapped.Args, expected)
+	}
+	// The variables are passed in a file in the config dir, which is removed with the temp files
+	for _, arg := range wrapped.Args {
+		if strings.Contains(arg, "sk-ant-secret") {
+			t.Errorf("argument %q contains the secret", arg)
+		}
+	}
+	envFile := wrapped.Args[slices.Index(wrapped.Args, "sh")+4]
+	if filepath.Dir(envFile) != configDir || !slices.Contains(wrapped.TempFiles, envFile) {
+		t.Errorf("env file = %q, expected a temp file in %s", envFile, configDir)
+	}
+	content, err := os.ReadFile(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "ANTHROPIC_API_KEY='sk-ant-secret'") || strings.Contains(string(content), "CLAUDE_CONFIG_DIR") {
+		t.Errorf("env file = %q, expected the variables except CLAUDE_CONFIG_DIR", content)
+	}
+	// sh -c SCRIPT sh ENVFILE DIR CLAUDE ARGS...
+	tail := wrapped.Args[slices.Index(wrapped.Args, "sh")+5:]
+	expected := []string{"/workspaces/app/api", "claude", "--settings", `{"permissions":{}}`, "--add-dir", "/workspaces/app/docs", "--continue"}
+	if !slices.Equal(tail, expected) {
+		t.Errorf("command = %q, expected %q", tail, expected)
+	}
+}
+
+func TestWrapDevcontainerErrors(t *testing.T) {
+	c := &launcher.Command{Path: "claude", Dir: "/home/user/app"}
+	opts := DevcontainerOptions{Workspace: "/home/user/app", ConfigDir: "/home/user/.claude"}
+
+	tests := []struct {
+		name       string
+		upOutput   string
+		testStatus int
+		expected   string
+	}{
+		{name: "up failed", upOutput: `{"outcome":"error","message":"Command failed","description":"build error"}`, expected: "Command failed build error"},
+		{name: "no result", upOutput: "", expected: "log line"},
+		{name: "config dir not mounted", upOutput: `{"outcome":"success","remoteWorkspaceFolder":"/workspaces/app"}`, testStatus: 1, expected: "--remove-existing-container"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDevcontainerCLI(t, tt.upOutput, tt.testStatus)
+			_, err := WrapDevcontainer(c, opts)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("WrapDevcontainer() error = %v, expected %q", err, tt.expected)
+			}
+		})
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, err := WrapDevcontainer(c, opts); err == nil || !strings.Contains(err.Error(), "devcontainer CLI not found") {
+		t.Errorf("WrapDevcontainer() error = %v, expected the missing CLI", err)
+	}
+}
@@ -0,0 +1,122 @@
+// Package detach runs claude under a pseudo-terminal in a background server process
+// and lets clients attach to and detach from it over a unix socket.
+package detach
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/notify"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// ServeCommand is the hidden subcommand that runs the session server
+const ServeCommand = "__serve-detached"
+
+// DetachKey is the byte that detaches an attached client (Ctrl-\)
+const DetachKey = 0x1c
+
+// ErrUnsupported is returned on platforms without pty support
+var ErrUnsupported = errors.New("detached sessions are not supported on this platform")
+
+// Spec is handed from the launching process to the session server
+type Spec struct {
+	Command  *launcher.Command      `json:"command"`
+	Account  string                 `json:"account,omitempty"`
+	Record   *launcher.LaunchRecord `json:"record,omitempty"`   // Optional: completed and saved to the launch history on exit
+	Notifier *notify.Notifier       `json:"notifier,omitempty"` // Optional: notified of the completed record
+	// Optional: the claude config directory whose transcripts give the usage of the record
+	// (empty for the default one); nil for other tools
+	UsageDir *string `json:"usageDir,omitempty"`
+}
+
+// SocketPath returns the unix socket path for session id
+func SocketPath(store *state.Store, id string) string {
+	return filepath.Join(store.InstancesDir(), id+".sock")
+}
+
+// specName returns the state-relative path of the spec handed to the server for id
+func specName(id string) string {
+	return filepath.Join("detach", id+".json")
+}
+
+// Frame types sent from client to server
+const (
+	frameData   byte = 0
+	frameResize byte = 1
+)
+
+// maxFrameSize bounds a single client frame to guard against corrupt streams
+const maxFrameSize = 1 << 20
+
+// writeFrame writes a single [type][length][payload] frame
+func writeFrame(w io.Writer, typ byte, payload []byte) error {
+	header := make([]byte, 5)
+	header[0] = typ
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload))) // #nosec G115 -- payload size is bounded by read buffers
+	if _, err := w.Write(append(header, payload...)); err != nil {
+		return fmt.Errorf("failed to write frame: %w", err)
+	}
+	return nil
+}
+
+// readFrame reads a single frame written by writeFrame
+func readFrame(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxFrameSize {
+		return 0, nil, fmt.Errorf("frame too large: %d bytes", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return header[0], payload, nil
+}
+
+// encodeResize encodes a terminal size as a resize frame payload
+func encodeResize(rows, cols uint16) []byte {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint16(payload[0:], rows)
+	binary.BigEndian.PutUint16(payload[2:], cols)
+	return payload
+}
+
+// decodeResize decodes a resize frame payload
+func decodeResize(payload []byte) (uint16, uint16, error) {
+	if len(payload) != 4 {
+		return 0, 0, fmt.Errorf("invalid resize payload length: %d", len(payload))
+	}
+	return binary.BigEndian.Uint16(payload[0:]), binary.BigEndian.Uint16(payload[2:]), nil
+}
+
+// ringBuffer keeps the most recent output so a newly attached client can redraw
+type ringBuffer struct {
+	data []byte
+	size int
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{size: size}
+}
+
+func (b *ringBuffer) Write(p []byte) {
+	b.data = append(b.data, p...)
+	if len(b.data) > b.size {
+		b.data = b.data[len(b.data)-b.size:]
+	}
+}
+
+func (b *ringBuffer) Bytes() []byte {
+	return b.data
+}
@@ -0,0 +1,362 @@
+//go:build linux
+
+package detach
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// scrollbackSize is how much recent output is replayed to a newly attached client
+const scrollbackSize = 64 * 1024
+
+// Start hands spec to a new background session server and waits until it is ready.
+// It returns the session id.
+func Start(store *state.Store, spec *Spec) (string, error) {
+	id, err := state.NewID()
+	if err != nil {
+		return "", err
+	}
+	if spec.Record != nil {
+		spec.Record.ID = id // Match the launch history to the session id shown by `ps`
+	}
+
+	if err := store.WriteJSON(specName(id), spec); err != nil {
+		return "", err
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate launcher executable: %w", err)
+	}
+
+	// #nosec G204 -- self is the running launcher binary
+	cmd := exec.Command(self, ServeCommand, id)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start session server: %w", err)
+	}
+	_ = cmd.Process.Release() //nolint:errcheck // the server outlives this process
+
+	// Wait for the server to start claude and open its socket
+	socket := SocketPath(store, id)
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(socket); err == nil {
+			return id, nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return "", fmt.Errorf("session server did not become ready")
+}
+
+// Serve runs the session server for id until claude exits
+func Serve(store *state.Store, id string) error {
+	var spec Spec
+	if err := store.ReadJSON(specName(id), &spec); err != nil {
+		return err
+	}
+	_ = os.Remove(filepath.Join(store.Dir, specName(id))) //nolint:errcheck // spec may contain secrets; best-effort removal
+	defer spec.Command.Cleanup()
+
+	ptmx, tty, err := openPTY()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ptmx.Close() }() //nolint:errcheck // closing on exit
+
+	cmd := spec.Command.Cmd()
+	cmd.Stdin = tty
+	cmd.Stdout = tty
+	cmd.Stderr = tty
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+	if err := cmd.Start(); err != nil {
+		_ = tty.Close() //nolint:errcheck // already failing
+		recordLaunch(store, &spec, err)
+		return fmt.Errorf("failed to start claude: %w", err)
+	}
+	_ = tty.Close() //nolint:errcheck // the child holds its own reference
+
+	if err := os.MkdirAll(store.InstancesDir(), 0o700); err != nil {
+		_ = cmd.Process.Kill() //nolint:errcheck // already failing
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	socket := SocketPath(store, id)
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		_ = cmd.Process.Kill() //nolint:errcheck // already failing
+		return fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	defer func() { _ = os.Remove(socket) }() //nolint:errcheck // best-effort cleanup
+	_ = os.Chmod(socket, 0o600)              //nolint:errcheck // directory is already owner-only
+
+	inst := &state.Instance{
+		ID:           id,
+		PID:          cmd.Process.Pid,
+		Dir:          spec.Command.Dir,
+		Account:      spec.Account,
+		StartedAt:    time.Now(),
+		Socket:       socket,
+		ProcessStart: state.ProcessStartTime(cmd.Process.Pid),
+	}
+	if err := store.SaveInstance(inst); err != nil {
+		_ = cmd.Process.Kill() //nolint:errcheck // already failing
+		return err
+	}
+	defer func() { _ = store.RemoveInstance(id) }() //nolint:errcheck // best-effort cleanup
+
+	s := &server{ptmx: ptmx, scrollback: newRingBuffer(scrollbackSize)}
+	go s.acceptLoop(ln)
+	go s.pump()
+
+	recordLaunch(store, &spec, cmd.Wait())
+	_ = ln.Close() //nolint:errcheck // stops acceptLoop
+	s.disconnect()
+
+	return nil
+}
+
+// recordLaunch completes the record of spec with the result of running claude, saves it to the
+// launch history and sends the notifications
+func recordLaunch(store *state.Store, spec *Spec, err error) {
+	if spec.Record == nil {
+		return
+	}
+	spec.Record.Finish(err)
+	if spec.UsageDir != nil {
+		spec.Record.AddUsage(*spec.UsageDir)
+	}
+	recorders := launcher.Recorders{&launcher.StoreRecorder{Store: store}}
+	if spec.Notifier != nil {
+		recorders = append(recorders, spec.Notifier)
+	}
+	_ = recorders.Record(spec.Record) //nolint:errcheck // nobody is watching a detached session
+}
+
+// server relays between the pty and the currently attached client
+type server struct {
+	ptmx       *os.File
+	mu         sync.Mutex
+	client     net.Conn
+	scrollback *ringBuffer
+}
+
+// pump copies pty output into the scrollback and to the attached client
+func (s *server) pump() {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := s.ptmx.Read(buf)
+		if n > 0 {
+			s.mu.Lock()
+			s.scrollback.Write(buf[:n])
+			if s.client != nil {
+				if _, werr := s.client.Write(buf[:n]); werr != nil {
+					_ = s.client.Close() //nolint:errcheck // client went away
+					s.client = nil
+				}
+			}
+			s.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *server) acceptLoop(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle attaches conn, replacing any previously attached client
+func (s *server) handle(conn net.Conn) {
+	s.mu.Lock()
+	if s.client != nil {
+		_ = s.client.Close() //nolint:errcheck // replaced by the new client
+	}
+	s.client = conn
+	_, _ = conn.Write(s.scrollback.Bytes()) //nolint:errcheck // a failed replay surfaces on the next write
+	s.mu.Unlock()
+
+	for {
+		typ, payload, err := readFrame(conn)
+		if err != nil {
+			break
+		}
+
+		switch typ {
+		case frameData:
+			_, _ = s.ptmx.Write(payload) //nolint:errcheck // input to an exiting process can be dropped
+		case frameResize:
+			rows, cols, err := decodeResize(payload)
+			if err == nil {
+				_ = unix.IoctlSetWinsize(int(s.ptmx.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Row: rows, Col: cols}) //nolint:errcheck // resize is best-effort
+			}
+		}
+	}
+
+	s.mu.Lock()
+	if s.client == conn {
+		s.client = nil
+	}
+	s.mu.Unlock()
+	_ = conn.Close() //nolint:errcheck // already disconnected
+}
+
+func (s *server) disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client != nil {
+		_ = s.client.Close() //nolint:errcheck // session is over
+		s.client = nil
+	}
+}
+
+// Attach connects the terminal to the session listening on socket until the user
+// presses DetachKey or the session ends. It returns true if the session ended.
+func Attach(socket string, stdin *os.File, stdout *os.File) (bool, error) {
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to session: %w", err)
+	}
+	defer func() { _ = conn.Close() }() //nolint:errcheck // closing on exit
+
+	sendSize := func() {
+		ws, err := unix.IoctlGetWinsize(int(stdout.Fd()), unix.TIOCGWINSZ)
+		if err == nil {
+			_ = writeFrame(conn, frameResize, encodeResize(ws.Row, ws.Col)) //nolint:errcheck // resize is best-effort
+		}
+	}
+	sendSize()
+
+	restore, err := makeRaw(int(stdin.Fd()))
+	if err != nil {
+		return false, err
+	}
+	defer restore()
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+	go func() {
+		for range winch {
+			sendSize()
+		}
+	}()
+
+	detached := make(chan struct{})
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := stdin.Read(buf)
+			if n > 0 {
+				for i := range n {
+					if buf[i] == DetachKey {
+						if i > 0 {
+							_ = writeFrame(conn, frameData, buf[:i]) //nolint:errcheck // detaching anyway
+						}
+						close(detached)
+						return
+					}
+				}
+				if werr := writeFrame(conn, frameData, buf[:n]); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	ended := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(stdout, conn) //nolint:errcheck // ends when the session closes the connection
+		close(ended)
+	}()
+
+	select {
+	case <-detached:
+		return false, nil
+	case <-ended:
+		return true, nil
+	}
+}
+
+// openPTY allocates a pseudo-terminal pair
+func openPTY() (*os.File, *os.File, error) {
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open pty: %w", err)
+	}
+
+	fd := int(ptmx.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		_ = ptmx.Close() //nolint:errcheck // already failing
+		return nil, nil, fmt.Errorf("failed to unlock pty: %w", err)
+	}
+
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		_ = ptmx.Close() //nolint:errcheck // already failing
+		return nil, nil, fmt.Errorf("failed to get pty number: %w", err)
+	}
+
+	tty, err := os.OpenFile("/dev/pts/"+strconv.Itoa(n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		_ = ptmx.Close() //nolint:errcheck // already failing
+		return nil, nil, fmt.Errorf("failed to open pty slave: %w", err)
+	}
+
+	return ptmx, tty, nil
+}
+
+// makeRaw puts the terminal into raw mode and returns a function restoring it
+func makeRaw(fd int) (func(), error) {
+	orig, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		if errors.Is(err, unix.ENOTTY) {
+			return func() {}, nil
+		}
+		return nil, fmt.Errorf("failed to get terminal attributes: %w", err)
+	}
+
+	raw := *orig
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+
+	if err := unix.IoctlSetTermios(fd, unix.TCSETS, &raw); err != nil {
+		return nil, fmt.Errorf("failed to set raw mode: %w", err)
+	}
+
+	return func() {
+		_ = unix.IoctlSetTermios(fd, unix.TCSETS, orig) //nolint:errcheck // best-effort restore
+	}, nil
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package detach
+
+import (
+	"os"
+
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// Start is not supported on this platform
+func Start(_ *state.Store, _ *Spec) (string, error) {
+	return "", ErrUnsupported
+}
+
+// Serve is not supported on this platform
+func Serve(_ *state.Store, _ string) error {
+	return ErrUnsupported
+}
+
+// Attach is not supported on this platform
+func Attach(_ string, _ *os.File, _ *os.File) (bool, error) {
+	return false, ErrUnsupported
+}
@@ -0,0 +1,63 @@
+package detach
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := writeFrame(&buf, frameData, []byte("hello")); err != nil {
+		t.Fatalf("writeFrame() error = %v", err)
+	}
+	if err := writeFrame(&buf, frameResize, encodeResize(24, 80)); err != nil {
+		t.Fatalf("writeFrame() error = %v", err)
+	}
+
+	typ, payload, err := readFrame(&buf)
+	if err != nil {
+		t.Fatalf("readFrame() error = %v", err)
+	}
+	if typ != frameData || string(payload) != "hello" {
+		t.Errorf("readFrame() = (%d, %q), expected (%d, %q)", typ, payload, frameData, "hello")
+	}
+
+	typ, payload, err = readFrame(&buf)
+	if err != nil {
+		t.Fatalf("readFrame() error = %v", err)
+	}
+	if typ != frameResize {
+		t.Fatalf("readFrame() type = %d, expected %d", typ, frameResize)
+	}
+	rows, cols, err := decodeResize(payload)
+	if err != nil {
+		t.Fatalf("decodeResize() error = %v", err)
+	}
+	if rows != 24 || cols != 80 {
+		t.Errorf("decodeResize() = (%d, %d), expected (24, 80)", rows, cols)
+	}
+}
+
+func TestReadFrameTooLarge(t *testing.T) {
+	data := []byte{frameData, 0xff, 0xff, 0xff, 0xff}
+	if _, _, err := readFrame(bytes.NewReader(data)); err == nil {
+		t.Error("readFrame() should reject oversized frames")
+	}
+}
+
+func TestDecodeResizeInvalid(t *testing.T) {
+	if _, _, err := decodeResize([]byte{1, 2}); err == nil {
+		t.Error("decodeResize() should reject short payloads")
+	}
+}
+
+func TestRingBuffer(t *testing.T) {
+	b := newRingBuffer(5)
+	b.Write([]byte("abc"))
+	b.Write([]byte("defg"))
+
+	if got := string(b.Bytes()); got != "cdefg" {
+		t.Errorf("Bytes() = %q, expected %q", got, "cdefg")
+	}
+}
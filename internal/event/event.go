@@ -0,0 +1,136 @@
+// Package event defines the lifecycle events of a launch and a synchronous bus delivering them
+// to subscribers (launch history, audit log, notifications, plugin hooks, traces), so that
+// features hook into the launch flow instead of being wired into it one by one
+package event
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/23prime/claude-launcher/internal/account"
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+// Event is one of the lifecycle events below
+type Event interface {
+	Name() string // e.g. "launch-exited", for traces
+}
+
+// ConfigLoaded is published once the configuration has been loaded. Subscribers may add to it.
+type ConfigLoaded struct {
+	Config *config.Config
+}
+
+// DirectoryChecked is published with the outcome of the directory check
+type DirectoryChecked struct {
+	Dir             string
+	Rule            string   // The allowed directory that matched, "" when Dir is not allowed
+	SkipPermissions bool     // Args contain --dangerously-skip-permissions
+	YoloAllowed     bool     // Dir is in yoloAllowedDirs (only checked with SkipPermissions)
+	Args            []string // Arguments for claude
+}
+
+// Allowed reports whether the launch passed the check
+func (e DirectoryChecked) Allowed() bool {
+	return e.Rule != "" && (!e.SkipPermissions || e.YoloAllowed)
+}
+
+// AccountSelected is published once the account is chosen
+type AccountSelected struct {
+	Dir     string
+	Account *account.Account // Nil for the default configuration
+}
+
+// SessionChosen is published once it is decided whether the previous session continues
+type SessionChosen struct {
+	Dir      string
+	Continue bool
+	Pick     bool // The user chooses the session in the tool's picker
+}
+
+// LaunchStarted is published right before claude starts. An error from a subscriber (e.g. the
+// audit log cannot be written) refuses the launch.
+type LaunchStarted struct {
+	Options launcher.LaunchOptions
+}
+
+// LaunchExited is published with the record of a finished launch (for tmux launches, once
+// the window is opened)
+type LaunchExited struct {
+	Record *launcher.LaunchRecord
+}
+
+// Name implements Event
+func (ConfigLoaded) Name() string { return "config-loaded" }
+
+// Name implements Event
+func (DirectoryChecked) Name() string { return "directory-checked" }
+
+// Name implements Event
+func (AccountSelected) Name() string { return "account-selected" }
+
+// Name implements Event
+func (SessionChosen) Name() string { return "session-chosen" }
+
+// Name implements Event
+func (LaunchStarted) Name() string { return "launch-started" }
+
+// Name implements Event
+func (LaunchExited) Name() string { return "launch-exited" }
+
+// Handler receives the published events
+type Handler func(Event) error
+
+// Bus delivers events to its subscribers in the order they subscribed. The zero value is ready
+// to use.
+type Bus struct {
+	mu       sync.Mutex
+	handlers []Handler
+}
+
+// Subscribe adds h for every event
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// On subscribes fn to the events of type T
+func On[T Event](b *Bus, fn func(T) error) {
+	b.Subscribe(func(e Event) error {
+		if t, ok := e.(T); ok {
+			return fn(t)
+		}
+		return nil
+	})
+}
+
+// Publish calls every subscriber with e, even when some fail, and returns the failures
+// together. Publishing on a nil Bus does nothing.
+func (b *Bus) Publish(e Event) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	handlers := b.handlers
+	b.mu.Unlock()
+
+	var errs []error
+	for _, h := range handlers {
+		if err := h(e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Recorder is a launcher.Recorder publishing every record as LaunchExited
+type Recorder struct {
+	Bus *Bus
+}
+
+// Record implements launcher.Recorder
+func (r Recorder) Record(rec *launcher.LaunchRecord) error {
+	return r.Bus.Publish(LaunchExited{Record: rec})
+}
@@ -0,0 +1,76 @@
+package event
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+func TestBus(t *testing.T) {
+	var bus Bus
+	var got []string
+	bus.Subscribe(func(e Event) error {
+		got = append(got, "all:"+e.Name())
+		return nil
+	})
+	On(&bus, func(e SessionChosen) error {
+		got = append(got, "session:"+e.Dir)
+		return nil
+	})
+	On(&bus, func(e LaunchStarted) error {
+		return errors.New("audit log unavailable")
+	})
+	On(&bus, func(e LaunchStarted) error {
+		got = append(got, "started:"+e.Options.Dir)
+		return nil
+	})
+
+	if err := bus.Publish(SessionChosen{Dir: "/work"}); err != nil {
+		t.Errorf("Publish() error = %v", err)
+	}
+	if err := bus.Publish(LaunchStarted{Options: launcher.LaunchOptions{Dir: "/work"}}); err == nil {
+		t.Error("Publish() should return the subscriber's error")
+	}
+	want := []string{"all:session-chosen", "session:/work", "all:launch-started", "started:/work"}
+	if !slices.Equal(got, want) {
+		t.Errorf("delivered %q, want %q (in order, past failures)", got, want)
+	}
+
+	var nilBus *Bus
+	if err := nilBus.Publish(SessionChosen{}); err != nil {
+		t.Errorf("Publish() on a nil bus = %v", err)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var bus Bus
+	var exited *launcher.LaunchRecord
+	On(&bus, func(e LaunchExited) error {
+		exited = e.Record
+		return nil
+	})
+
+	rec := &launcher.LaunchRecord{ID: "abc"}
+	if err := (Recorder{Bus: &bus}).Record(rec); err != nil || exited != rec {
+		t.Errorf("Record() = %v, published %+v", err, exited)
+	}
+}
+
+func TestDirectoryCheckedAllowed(t *testing.T) {
+	tests := []struct {
+		event DirectoryChecked
+		want  bool
+	}{
+		{DirectoryChecked{Rule: "/work"}, true},
+		{DirectoryChecked{}, false},
+		{DirectoryChecked{Rule: "/work", SkipPermissions: true}, false},
+		{DirectoryChecked{Rule: "/work", SkipPermissions: true, YoloAllowed: true}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.event.Allowed(); got != tt.want {
+			t.Errorf("%+v.Allowed() = %v, want %v", tt.event, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Package home finds the user's home directory and the launcher's own directory. Containers
+// and restricted CI runners often have no HOME, so both have fallbacks.
+package home
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+)
+
+// EnvVar names a directory holding the launcher's own files (config.json and state/) in place
+// of ~/.config/claude-launcher and ~/.local/state/claude-launcher
+const EnvVar = "CLAUDE_LAUNCHER_HOME"
+
+// Dir returns the user's home directory: $HOME (%USERPROFILE% on Windows) or, when it is not
+// set, the home directory in the user database
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err == nil {
+		return home, nil
+	}
+	if u, userErr := user.Current(); userErr == nil && u.HomeDir != "" {
+		return u.HomeDir, nil
+	}
+	return "", fmt.Errorf("failed to get home directory: %w (set %s to keep the launcher's files elsewhere)", err, EnvVar)
+}
+
+// LauncherDir returns the directory set by CLAUDE_LAUNCHER_HOME, or "" when it is not set
+func LauncherDir() string {
+	return os.Getenv(EnvVar)
+}
@@ -0,0 +1,27 @@
+package home
+
+import (
+	"os/user"
+	"runtime"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("HOME is not the home directory variable")
+	}
+
+	t.Setenv("HOME", "/home/tester")
+	if got, err := Dir(); err != nil || got != "/home/tester" {
+		t.Errorf("Dir() = %q, %v, want $HOME", got, err)
+	}
+
+	t.Setenv("HOME", "")
+	u, err := user.Current()
+	if err != nil || u.HomeDir == "" {
+		t.Skip("no user database entry")
+	}
+	if got, err := Dir(); err != nil || got != u.HomeDir {
+		t.Errorf("Dir() without HOME = %q, %v, want %q from the user database", got, err, u.HomeDir)
+	}
+}
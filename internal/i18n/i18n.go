@@ -0,0 +1,76 @@
+// Package i18n translates user-facing messages based on the locale (LC_ALL, LC_MESSAGES, LANG).
+// Messages are looked up by their English text, which is also the fallback.
+package i18n
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// catalogs maps a language code to its translations, keyed by the English message
+var catalogs = map[string]map[string]string{
+	"ja": ja,
+}
+
+var (
+	langOnce sync.Once
+	lang     string
+
+	// overrides replace messages in every language, ahead of the catalogs
+	overrides map[string]string
+)
+
+// Lang returns the language of the messages ("en" when no translation applies)
+func Lang() string {
+	langOnce.Do(func() {
+		if lang == "" {
+			lang = detect(os.Getenv)
+		}
+	})
+	return lang
+}
+
+// SetLang overrides the detected language (e.g. "ja" or "en")
+func SetLang(l string) {
+	langOnce.Do(func() {})
+	lang = l
+}
+
+// SetOverrides replaces the text of messages, keyed by their English text, whatever the
+// language (e.g. an organization's own wording from the config file)
+func SetOverrides(texts map[string]string) {
+	overrides = texts
+}
+
+// T returns the override or translation of msg, or msg itself when there is none
+func T(msg string) string {
+	if text, ok := overrides[msg]; ok {
+		return text
+	}
+	if translated, ok := catalogs[Lang()][msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// detect reads the locale with the POSIX precedence: LC_ALL, then LC_MESSAGES, then LANG
+func detect(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := getenv(name); v != "" {
+			return language(v)
+		}
+	}
+	return "en"
+}
+
+// language extracts a supported language code from a locale such as "ja_JP.UTF-8"
+func language(locale string) string {
+	code, _, _ := strings.Cut(locale, "_")
+	code, _, _ = strings.Cut(code, ".")
+	code = strings.ToLower(code)
+	if _, ok := catalogs[code]; ok {
+		return code
+	}
+	return "en"
+}
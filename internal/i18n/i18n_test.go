@@ -0,0 +1,91 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"unset", nil, "en"},
+		{"LANG ja", map[string]string{"LANG": "ja_JP.UTF-8"}, "ja"},
+		{"LANG plain ja", map[string]string{"LANG": "ja"}, "ja"},
+		{"LANG en", map[string]string{"LANG": "en_US.UTF-8"}, "en"},
+		{"C locale", map[string]string{"LANG": "C.UTF-8"}, "en"},
+		{"unsupported", map[string]string{"LANG": "fr_FR.UTF-8"}, "en"},
+		{"LC_ALL wins", map[string]string{"LC_ALL": "en_US.UTF-8", "LANG": "ja_JP.UTF-8"}, "en"},
+		{"LC_MESSAGES before LANG", map[string]string{"LC_MESSAGES": "ja_JP.UTF-8", "LANG": "en_US.UTF-8"}, "ja"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detect(func(k string) string { return tt.env[k] })
+			if got != tt.want {
+				t.Errorf("detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLang("en")
+
+	SetLang("ja")
+	if got := T("Continue previous Claude session?"); got != "前回の Claude セッションを再開しますか?" {
+		t.Errorf("T() = %q", got)
+	}
+	if got := T("untranslated"); got != "untranslated" {
+		t.Errorf("T() should fall back to the message, got %q", got)
+	}
+
+	SetLang("en")
+	if got := T("Continue previous Claude session?"); got != "Continue previous Claude session?" {
+		t.Errorf("T() = %q", got)
+	}
+}
+
+func TestSetOverrides(t *testing.T) {
+	defer SetLang("en")
+	defer SetOverrides(nil)
+
+	SetOverrides(map[string]string{"Continue previous Claude session?": "Resume where you left off?"})
+	for _, l := range []string{"en", "ja"} {
+		SetLang(l)
+		if got := T("Continue previous Claude session?"); got != "Resume where you left off?" {
+			t.Errorf("T() in %s = %q, expected the override", l, got)
+		}
+	}
+	if got := T("Select Claude account"); got != "Claude アカウントを選択" {
+		t.Errorf("T() = %q, expected the translation of a message without override", got)
+	}
+}
+
+// Translations must keep the format verbs and the trailing newline of the message
+func TestCatalogsKeepFormat(t *testing.T) {
+	for code, catalog := range catalogs {
+		for msg, translated := range catalog {
+			if verbs(msg) != verbs(translated) {
+				t.Errorf("%s: verbs of %q differ in %q", code, msg, translated)
+			}
+			if strings.HasSuffix(msg, "\n") != strings.HasSuffix(translated, "\n") {
+				t.Errorf("%s: trailing newline of %q differs in %q", code, msg, translated)
+			}
+		}
+	}
+}
+
+// verbs returns the format verbs of s in order
+func verbs(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] == '%' {
+			b.WriteByte(s[i+1])
+			i++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,278 @@
+package i18n
+
+// ja is the Japanese translation
+var ja = map[string]string{
+	// Status messages
+	" Profile: %s\n":                                          " プロファイル: %s\n",
+	" Workspace: %s (%s)\n":                                   " ワークスペース: %s (%s)\n",
+	" Directory allowed\n":                                    " ディレクトリは許可されています\n",
+	" Continuing previous session...\n":                       " 前回のセッションを再開します...\n",
+	" Starting new session...\n":                              " 新しいセッションを開始します...\n",
+	" Opening the session picker...\n":                        " セッション選択画面を開きます...\n",
+	" Account: %s (%s)\n":                                     " アカウント: %s (%s)\n",
+	"Using default Claude configuration\n":                    "Claude のデフォルト設定を使用します\n",
+	" Account '%s' not found in configuration\n":              " アカウント '%s' が設定に見つかりません\n",
+	" Detached session %s\n":                                  " セッション %s をデタッチしました\n",
+	"  Reattach with: claude-launcher attach %s\n":            "  再接続: claude-launcher attach %s\n",
+	" Attaching to session %s (press Ctrl-\\ to detach)...\n": " セッション %s に接続します (Ctrl-\\ でデタッチ)...\n",
+	" Session %s ended\n":                                     " セッション %s は終了しました\n",
+	" Sent termination signal to session %s (PID %d)\n":       " セッション %s (PID %d) に終了シグナルを送信しました\n",
+
+	// Listings
+	"Allowed directories:\n":        "許可されたディレクトリ:\n",
+	"No profiles configured.\n":     "プロファイルが設定されていません。\n",
+	"Available profiles:\n":         "利用可能なプロファイル:\n",
+	"No bookmarks configured.\n":    "ブックマークが設定されていません。\n",
+	"Available bookmarks:\n":        "利用可能なブックマーク:\n",
+	"account %s":                    "アカウント %s",
+	"preset %s":                     "プリセット %s",
+	"✓ Added bookmark @%s for %s\n": "✓ ブックマーク @%s (%s) を追加しました\n",
+	"✓ Removed bookmark @%s\n":      "✓ ブックマーク @%s を削除しました\n",
+	"✗ %s not found\n":              "✗ %s が見つかりません\n",
+	"✗ Bookmark '%s' not found\n":   "✗ ブックマーク '%s' が見つかりません\n",
+	"✗ %s is not a directory\n":     "✗ %s はディレクトリではありません\n",
+	"⚠ %s is not an allowed directory: add it to allowedDirs before launching the bookmark\n": "⚠ %s は許可されたディレクトリではありません: ブックマークで起動する前に allowedDirs に追加してください\n",
+	"Saved preferences:\n":                                      "保存された設定:\n",
+	"(the config file sets it)":                                 "(設定ファイルで指定されています)",
+	"✓ Set %s to %s\n":                                          "✓ %s を %s に設定しました\n",
+	"✓ Unset %s\n":                                              "✓ %s の設定を解除しました\n",
+	"✗ Unknown preference %q (available: %s)\n":                 "✗ 不明な設定 %q です (利用可能: %s)\n",
+	"✗ Unknown value %q for %s (available: %s)\n":               "✗ 不明な値 %q です (%s、利用可能: %s)\n",
+	"No workspaces configured.\n":                               "ワークスペースが設定されていません。\n",
+	"Available workspaces:\n":                                   "利用可能なワークスペース:\n",
+	"No MCP servers configured.\n":                              "MCP サーバーが設定されていません。\n",
+	"Global MCP servers:\n":                                     "グローバルな MCP サーバー:\n",
+	"MCP servers of %s:\n":                                      "%s の MCP サーバー:\n",
+	"✓ Added global MCP server %s\n":                            "✓ グローバルな MCP サーバー %s を追加しました\n",
+	"✓ Added MCP server %s to %s\n":                             "✓ MCP サーバー %s を %s に追加しました\n",
+	"Check that it starts with: claude-launcher mcp test %s\n":  "起動できるか確認: claude-launcher mcp test %s\n",
+	"✓ Removed MCP server %s\n":                                 "✓ MCP サーバー %s を削除しました\n",
+	"✓ %s: %s %s (protocol %s)\n":                               "✓ %s: %s %s (プロトコル %s)\n",
+	"    Capabilities: %s\n":                                    "    機能: %s\n",
+	"✗ %s: not configured for %s\n":                             "✗ %s: %s では設定されていません\n",
+	"No plugins found on PATH.\n":                               "PATH にプラグインが見つかりません。\n",
+	"Plugins:\n":                                                "プラグイン:\n",
+	"(not found on PATH)":                                       "(PATH に見つかりません)",
+	"[hooks enabled]":                                           "[フック有効]",
+	"✗ Launch refused by plugin %s: %s\n":                       "✗ プラグイン %s が起動を拒否しました: %s\n",
+	"⚠ Failed to load allowed directories from plugin %s: %v\n": "⚠ プラグイン %s から許可ディレクトリを読み込めませんでした: %v\n",
+	"✗ Launch refused by the launch script: %s\n":               "✗ 起動スクリプトが起動を拒否しました: %s\n",
+	"✗ The launch script cannot add arguments: %v\n":            "✗ 起動スクリプトはこの引数を追加できません: %v\n",
+	"⚠ claude-launcher %s is available (you have %s): %s\n":     "⚠ claude-launcher %s が利用できます (現在 %s): %s\n",
+	"✓ No problems found in the configuration\n":                "✓ 設定に問題は見つかりませんでした\n",
+	"    Fix: %s\n":                                             "    修正案: %s\n",
+	"%d problem(s) found\n":                                     "%d 件の問題が見つかりました\n",
+	"(missing)":                                                 "(存在しません)",
+	"(cannot be checked: %v)":                                   "(確認できません: %v)",
+	"(not a directory)":                                         "(ディレクトリではありません)",
+	"(symlink → %s)":                                            "(シンボリックリンク → %s)",
+	"⚠ Profile '%s' from %s not found, ignoring it\n":           "⚠ プロファイル '%s' (%s) が見つからないため無視します\n",
+	"Run 'direnv allow %s' to load it.\n":                       "読み込むには 'direnv allow %s' を実行してください。\n",
+	"No launches recorded yet\n":                                "起動履歴はまだありません\n",
+	"Launches: %d\n":                                            "起動回数: %d\n",
+	"Session time: %.1fh this week, %.1fh this month\n":         "セッション時間: 今週 %.1f 時間、今月 %.1f 時間\n",
+	"Projects:\n":                                               "プロジェクト:\n",
+	"Accounts:\n":                                               "アカウント:\n",
+	"Presets:\n":                                                "プリセット:\n",
+	"(default)":                                                 "(デフォルト)",
+	"  ... and %d more\n":                                       "  ... ほか %d 件\n",
+	"No recent projects\n":                                      "最近のプロジェクトはありません\n",
+	"Select recent project":                                     "最近のプロジェクトを選択",
+	"new session":                                               "新規セッション",
+	"continued":                                                 "継続",
+	"No running Claude sessions\n":                              "実行中の Claude セッションはありません\n",
+	"ID\tPID\tMODE\tSTARTED\tACCOUNT\tDIRECTORY":                "ID\tPID\tモード\t開始\tアカウント\tディレクトリ",
+	"No accounts configured\n":                                  "アカウントが設定されていません\n",
+	"ACCOUNT\tLAST 5H\tLAST 7 DAYS\tLAST USED\tLIMIT REACHED":   "アカウント\t直近 5 時間\t直近 7 日\t最終使用\t上限到達",
+	"limit reached %s":                                          "%s に上限到達",
+	"%s tokens in the last 5h":                                  "直近 5 時間で %s トークン",
+	"No token usage recorded yet\n":                             "トークン使用量はまだ記録されていません\n",
+	"Sessions with usage: %d\n":                                 "使用量が記録されたセッション: %d\n",
+	"Total: %s tokens, $%.2f (estimated)\n":                     "合計: %s トークン、$%.2f (推定)\n",
+	" Session ended after %s\n":                                 " セッションは %s で終了しました\n",
+	"⚠ Session ended after %s with exit code %d\n":              "⚠ セッションは %s で終了しました (終了コード %d)\n",
+	"⚠ Session ended after %s\n":                                "⚠ セッションは %s で終了しました\n",
+	"  Files changed: %d (+%d -%d)\n":                           "  変更されたファイル: %d (+%d -%d)\n",
+	"  Tokens: %s ($%.2f estimated)\n":                          "  トークン: %s (推定 $%.2f)\n",
+	"  Resume: %s\n":                                            "  再開: %s\n",
+	"foreground":                                                "フォアグラウンド",
+	"detached":                                                  "デタッチ",
+	"just now":                                                  "たった今",
+	"%dm ago":                                                   "%d分前",
+	"%dh ago":                                                   "%d時間前",
+	"%dd ago":                                                   "%d日前",
+
+	// Errors and warnings
+	"✗ Access denied\n":                                            "✗ アクセスが拒否されました\n",
+	"Current directory: %s\n":                                      "現在のディレクトリ: %s\n",
+	"Claude Code is not allowed to run in this directory.":         "このディレクトリでは Claude Code を実行できません。",
+	"Run 'claude-launcher explain' to see why.\n":                  "理由は 'claude-launcher explain' で確認できます。\n",
+	"Remove it from linkedDirs of %s, or add it to allowedDirs.\n": "%s の linkedDirs から外すか、allowedDirs に追加してください。\n",
+	"⚠ Linked directory %s does not exist, launching without it\n": "⚠ リンクされたディレクトリ %s が存在しないため、これを除いて起動します\n",
+	"Path:     %s\n":                     "パス:     %s\n",
+	"Resolved: %s (symlinks followed)\n": "解決先:   %s (シンボリックリンクをたどった結果)\n",
+	"Allowed directories (from CLAUDE_SAFE_DIRS, which overrides allowedDirs):\n": "許可されたディレクトリ (allowedDirs より優先される CLAUDE_SAFE_DIRS から):\n",
+	"Allowed directories (from allowedDirs in %s):\n":                             "許可されたディレクトリ (%s の allowedDirs から):\n",
+	"✓ Allowed by %s\n": "✓ %s により許可されています\n",
+	"✗ Not allowed: no allowed directory contains %s\n":     "✗ 許可されていません: %s を含む許可ディレクトリがありません\n",
+	"✓ --dangerously-skip-permissions is allowed by %s\n":   "✓ --dangerously-skip-permissions は %s により許可されています\n",
+	"--dangerously-skip-permissions is not allowed here.\n": "ここでは --dangerously-skip-permissions は許可されていません。\n",
+	"  (none)\n":   "  (なし)\n",
+	"matched":      "一致",
+	"not matched":  "不一致",
+	"missing":      "存在しない",
+	"unresolvable": "解決不可",
+	"%s is inside %s as written, but symlinks lead to %s.\n":                                           "%s は表記上 %s の中にありますが、シンボリックリンクの先は %s です。\n",
+	"Directories are compared after following symlinks.\n":                                             "ディレクトリはシンボリックリンクをたどった後で比較されます。\n",
+	"Entries marked with ⚠ could not be resolved and are skipped.\n":                                   "⚠ の付いた項目は解決できないため無視されます。\n",
+	"To allow it, add it to CLAUDE_SAFE_DIRS:\n":                                                       "許可するには CLAUDE_SAFE_DIRS に追加してください:\n",
+	"To allow it, add it to allowedDirs in %s:\n":                                                      "許可するには %s の allowedDirs に追加してください:\n",
+	"✗ --dangerously-skip-permissions is not allowed in this directory\n":                              "✗ このディレクトリでは --dangerously-skip-permissions は許可されていません\n",
+	"No yoloAllowedDirs are configured.\n":                                                             "yoloAllowedDirs が設定されていません。\n",
+	"Allowed directories for --dangerously-skip-permissions:\n":                                        "--dangerously-skip-permissions が許可されたディレクトリ:\n",
+	"⚠ Permission checks are disabled (--dangerously-skip-permissions)\n":                              "⚠ 権限チェックが無効になっています (--dangerously-skip-permissions)\n",
+	"✗ Refusing to launch as root\n":                                                                   "✗ root として起動することは拒否されました\n",
+	"Claude could change or delete anything on this system. Run the launcher as a regular user,\n":     "Claude がこのシステム上のあらゆるものを変更・削除できてしまいます。一般ユーザーで実行するか、\n",
+	"or pass --allow-root (or set \"allowRoot\": true in config.json) if this is intended.\n":          "意図したものであれば --allow-root を指定してください (または config.json で \"allowRoot\": true を設定)。\n",
+	"⚠ Running as root: Claude can change or delete anything on this system\n":                         "⚠ root として実行中: Claude はこのシステム上のあらゆるものを変更・削除できます\n",
+	"Error: No allowed directories configured\n":                                                       "エラー: 許可されたディレクトリが設定されていません\n",
+	"Please set allowed directories using one of these methods:\n":                                     "次のいずれかの方法で許可するディレクトリを設定してください:\n",
+	"1. Environment variable (semicolon-separated, PowerShell):\n":                                     "1. 環境変数 (セミコロン区切り、PowerShell):\n",
+	"1. Environment variable (colon-separated):\n":                                                     "1. 環境変数 (コロン区切り):\n",
+	"2. Create ~/.config/claude-launcher/config.json:\n":                                               "2. ~/.config/claude-launcher/config.json を作成:\n",
+	"✗ claude not found (tried %s)\n":                                                                  "✗ claude が見つかりません (試したパス: %s)\n",
+	"✗ claude not found in PATH\n":                                                                     "✗ PATH に claude が見つかりません\n",
+	"Install Claude Code with:\n":                                                                      "Claude Code のインストール方法:\n",
+	"Running in %s: set %s=1 to allow its workspace, or to a list of directories.\n":                   "%s で実行中: %s=1 でワークスペースを許可するか、ディレクトリの一覧を設定してください。\n",
+	" Launching in the devcontainer of %s...\n":                                                        " %s の devcontainer で起動します...\n",
+	" Writes restricted to the project, Claude's config dir and temp (Landlock)\n":                     " 書き込みをプロジェクト、Claude の設定ディレクトリと一時ディレクトリに制限しています (Landlock)\n",
+	" Writes restricted to Claude's config dir and temp (Landlock)\n":                                  " 書き込みを Claude の設定ディレクトリと一時ディレクトリに制限しています (Landlock)\n",
+	" Read-only session: Claude may not edit files\n":                                                  " 読み取り専用セッション: Claude はファイルを編集できません\n",
+	"--read-only cannot be used with --dangerously-skip-permissions\n":                                 "--read-only は --dangerously-skip-permissions と併用できません\n",
+	"Launch Claude inside the project's devcontainer?":                                                 "プロジェクトの devcontainer 内で Claude を起動しますか?",
+	"✗ No devcontainer configuration found for %s\n":                                                   "✗ %s の devcontainer 設定が見つかりません\n",
+	" Launching in %s on %s...\n":                                                                      " %s (%s) で起動します...\n",
+	"✗ Host '%s' is not in remoteHosts\n":                                                              "✗ ホスト '%s' は remoteHosts にありません\n",
+	"✗ Account '%s' has no config directory on %s\n":                                                   "✗ アカウント '%s' には %s 上の設定ディレクトリがありません\n",
+	"✗ --dangerously-skip-permissions is not allowed on remote hosts\n":                                "✗ リモートホストでは --dangerously-skip-permissions は許可されていません\n",
+	"✗ %s not found in PATH\n":                                                                         "✗ PATH に %s が見つかりません\n",
+	" %s has no config directory setting; the account's configDir is ignored\n":                        " %s には設定ディレクトリの指定がないため、アカウントの configDir は無視されます\n",
+	"✗ claude %s is older than the required minimum %s\n":                                              "✗ claude %s は必要な最小バージョン %s より古いです\n",
+	"⚠ %s pins claude %s, but it cannot be resolved: %v\n":                                             "⚠ %s は claude %s を指定していますが、解決できません: %v\n",
+	"⚠ %s pins claude %s, but the resolved claude is %s\n":                                             "⚠ %s は claude %s を指定していますが、解決された claude は %s です\n",
+	"⚠ %s did not report a version\n":                                                                  "⚠ %s はバージョンを報告しませんでした\n",
+	"Runs: %s\n":                                                                                       "実行されるコマンド: %s\n",
+	"Update Claude Code with:\n":                                                                       "Claude Code の更新方法:\n",
+	"✗ No detached session with ID '%s'\n":                                                             "✗ ID '%s' のデタッチされたセッションはありません\n",
+	"✗ --detach needs a writable state directory: set CLAUDE_LAUNCHER_HOME or XDG_STATE_HOME to one\n": "✗ --detach には書き込み可能な状態ディレクトリが必要です。CLAUDE_LAUNCHER_HOME か XDG_STATE_HOME で指定してください\n",
+	"✗ No single detached session found; specify an ID\n":                                              "✗ デタッチされたセッションを 1 つに特定できません。ID を指定してください\n",
+
+	// Prompts
+	"Continue previous Claude session?":          "前回の Claude セッションを再開しますか?",
+	"Continue latest":                            "最新のセッションを再開",
+	"Choose a session…":                          "セッションを選択…",
+	"Start new":                                  "新しく開始",
+	"  [Y/n] (default: y): ":                     "  [Y/n] (デフォルト: y): ",
+	"  [y/N] (default: n): ":                     "  [y/N] (デフォルト: n): ",
+	"  [1-%d] (default: 1): ":                    "  [1-%d] (デフォルト: 1): ",
+	"invalid selection %q":                       "無効な選択です: %q",
+	"Select Claude account":                      "Claude アカウントを選択",
+	"Select working directory for workspace %s":  "ワークスペース %s の作業ディレクトリを選択",
+	"Select project (type to search)":            "プロジェクトを選択 (入力して検索)",
+	"✗ None of the allowed directories exists\n": "✗ 許可されたディレクトリがどれも存在しません\n",
+	"Run '%s' now?":                              "'%s' を今すぐ実行しますか?",
+	"⚠ A session is already running in %s (PID %d, started %s)\n": "⚠ %s ではすでにセッションが実行中です (PID %d、開始: %s)\n",
+	"What do you want to do?":            "どうしますか?",
+	"Attach to the running session":      "実行中のセッションにアタッチする",
+	"Launch another session anyway":      "それでも別のセッションを起動する",
+	"Abort":                              "中止する",
+	"Let Claude access this directory?":  "Claude にこのディレクトリへのアクセスを許可しますか?",
+	"⚠ First launch in this directory\n": "⚠ このディレクトリでの初めての起動です\n",
+	"  Path:       %s\n":                 "  パス:         %s\n",
+	"  Git remote: %s\n":                 "  Git リモート: %s\n",
+	"  Git remote: (none)\n":             "  Git リモート: (なし)\n",
+	"  Files:      %d\n":                 "  ファイル数:   %d\n",
+	"  Files:      %d+\n":                "  ファイル数:   %d+\n",
+	"✗ %s has not been confirmed yet: launch there once interactively, or pass --approve-dir to confirm it\n": "✗ %s はまだ確認されていません: 一度対話的に起動するか、--approve-dir で確認してください\n",
+	"✓ Confirmed %s (--approve-dir)\n":                               "✓ %s を確認済みにしました (--approve-dir)\n",
+	"Failed to read confirmed directories: %v\n":                     "確認済みディレクトリの読み込みに失敗しました: %v\n",
+	"Failed to record the confirmation: %v\n":                        "確認の記録に失敗しました: %v\n",
+	"✗ The git working tree of %s is not clean (gitCheck: block)\n":  "✗ %s の git 作業ツリーがクリーンではありません (gitCheck: block)\n",
+	"⚠ The git working tree of %s is not clean\n":                    "⚠ %s の git 作業ツリーがクリーンではありません\n",
+	"  - %d uncommitted change(s)\n":                                 "  - 未コミットの変更が %d 件あります\n",
+	"  - HEAD is detached\n":                                         "  - HEAD がデタッチされています\n",
+	"  - A %s is in progress\n":                                      "  - %s が進行中です\n",
+	"Continue anyway":                                                "それでも続行する",
+	"Stash the changes and continue":                                 "変更を stash して続行する",
+	"Failed to stash the changes: %v\n":                              "変更の stash に失敗しました: %v\n",
+	"✓ Changes stashed ('git stash pop' brings them back)\n":         "✓ 変更を stash しました ('git stash pop' で元に戻せます)\n",
+	"⚠ No snapshot taken: %v\n":                                      "⚠ スナップショットは作成されませんでした: %v\n",
+	"Failed to take a snapshot: %v\n":                                "スナップショットの作成に失敗しました: %v\n",
+	"Failed to record the snapshot: %v\n":                            "スナップショットの記録に失敗しました: %v\n",
+	"✓ Snapshot %s taken ('claude-launcher rollback' restores it)\n": "✓ スナップショット %s を作成しました ('claude-launcher rollback' で復元できます)\n",
+	"Failed to read snapshots: %v\n":                                 "スナップショットの読み込みに失敗しました: %v\n",
+	"✗ No snapshot with ID '%s'\n":                                   "✗ ID '%s' のスナップショットはありません\n",
+	"✗ Not in a git repository: pass the ID of a snapshot (see 'claude-launcher rollback --list')\n": "✗ git リポジトリの外です: スナップショットの ID を指定してください ('claude-launcher rollback --list' を参照)\n",
+	"✗ No snapshot of %s\n":                                                    "✗ %s のスナップショットはありません\n",
+	"⚠ Rolling back %s to snapshot %s (%s, taken %s)\n":                        "⚠ %s をスナップショット %s (%s、作成: %s) に戻します\n",
+	"Discard every change made in %s since then?":                              "%s でそれ以降に行われた変更をすべて破棄しますか?",
+	"Failed to save the current state: %v\n":                                   "現在の状態の保存に失敗しました: %v\n",
+	"Failed to roll back: %v\n":                                                "ロールバックに失敗しました: %v\n",
+	"✓ Rolled back to snapshot %s ('claude-launcher rollback %s' undoes it)\n": "✓ スナップショット %s に戻しました ('claude-launcher rollback %s' で取り消せます)\n",
+	"No snapshots\n": "スナップショットはありません\n",
+	"✗ Launches on branch %s are not allowed in %s: switch to another branch first\n": "✗ ブランチ %s では起動できません (%s): 先に別のブランチに切り替えてください\n",
+	"✗ Branch %s requires the %s preset, not %s\n":                                    "✗ ブランチ %s では %s プリセットが必要です (指定: %s)\n",
+	"✗ --dangerously-skip-permissions is not allowed on branch %s\n":                  "✗ ブランチ %s では --dangerously-skip-permissions は使えません\n",
+	" Branch %s: %s preset\n":                                                         " ブランチ %s: %s プリセット\n",
+	"ID\tMODE\tTAKEN\tBRANCH\tDIRECTORY":                                              "ID\tモード\t作成\tブランチ\tディレクトリ",
+	"✗ The environment of this project is not ready:\n":                               "✗ このプロジェクトの環境が整っていません:\n",
+	"  - %s: not found on PATH\n":                                                     "  - %s: PATH に見つかりません\n",
+	"  - %s: found %s\n":                                                              "  - %s: %s が見つかりました\n",
+	"  - %s: missing\n":                                                               "  - %s: ありません\n",
+	"  - %s: could not read its version\n":                                            "  - %s: バージョンを読み取れませんでした\n",
+	"  - %s: failed (%s)\n":                                                           "  - %s: 失敗しました (%s)\n",
+	"  - %s: failed\n":                                                                "  - %s: 失敗しました\n",
+	"Launch anyway?":                                                                  "このまま起動しますか?",
+
+	// integrate systemd
+	"Run 'systemctl --user daemon-reload && systemctl --user enable --now %s' to start it at login.\n": "ログイン時に起動するには 'systemctl --user daemon-reload && systemctl --user enable --now %s' を実行してください。\n",
+	"✗ integrate systemd needs Linux, the only platform with detached sessions\n":                      "✗ integrate systemd には Linux が必要です (デタッチされたセッションは Linux でのみ使えます)\n",
+	"✗ %s is not an allowed directory: add it to allowedDirs first\n":                                  "✗ %s は許可されたディレクトリではありません。先に allowedDirs に追加してください\n",
+
+	// Progress
+	"Looking for %s":                       "%s を探しています",
+	"Checking the %s version":              "%s のバージョンを確認しています",
+	"Checking %s":                          "%s を確認しています",
+	"Checking allowed directories (%d/%d)": "許可されたディレクトリを確認しています (%d/%d)",
+
+	// Startup timing
+	"Startup timing:\n":       "起動時間の内訳:\n",
+	"(+%s waiting for input)": "(+%s 入力待ち)",
+	"total":                   "合計",
+
+	// Full-screen menu
+	"Launch":                         "起動",
+	"Profiles":                       "プロファイル",
+	"Workspaces":                     "ワークスペース",
+	"Accounts":                       "アカウント",
+	"Sessions":                       "セッション",
+	"Here (%s)":                      "ここで起動 (%s)",
+	"allowed":                        "許可",
+	"not allowed":                    "許可されていません",
+	"↑/↓ move  enter select  q quit": "↑/↓ 移動  enter 選択  q 終了",
+	"Nothing to launch: the current directory is not allowed and no projects are configured": "起動できる対象がありません: 現在のディレクトリは許可されておらず、プロジェクトも設定されていません",
+	"Allowed by %s (from %s)":                         "%s により許可されています (出典: %s)",
+	"Project settings from the projects entry for %s": "%s の projects エントリの設定を使用します",
+	"Config sources: %s":                              "設定の読み込み元: %s",
+	"allowedDirs in %s":                               "%s の allowedDirs",
+	"CLAUDE_SAFE_DIRS, which overrides allowedDirs":   "CLAUDE_SAFE_DIRS (allowedDirs より優先)",
+	"saved preferences":                               "保存された設定",
+	"defaults only":                                   "既定値のみ",
+	"No account: none is configured, so claude uses its default configuration": "アカウントなし: 設定されていないため、claude の既定の設定を使用します",
+	"named by profile %s": "プロファイル %s で指定",
+	"named by --account":  "--account で指定",
+	"--account %s was not found, so it was chosen in the menu": "--account %s が見つからなかったため、メニューで選択",
+	"the only configured account":                              "設定されている唯一のアカウント",
+	"chosen in the account menu":                               "アカウントメニューで選択",
+	"Account %s: %s (accounts from %s)":                        "アカウント %s: %s (アカウントの設定元: %s)",
+}
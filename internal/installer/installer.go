@@ -0,0 +1,86 @@
+// Package installer installs or updates the Claude Code CLI.
+package installer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Install methods
+const (
+	MethodNPM    = "npm"
+	MethodNative = "native"
+)
+
+// Default commands per install method
+var defaultInstallCommands = map[string]string{
+	MethodNPM:    "npm install -g @anthropic-ai/claude-code",
+	MethodNative: "curl -fsSL https://claude.ai/install.sh | bash",
+}
+
+// windowsInstallCommands override defaultInstallCommands on Windows
+var windowsInstallCommands = map[string]string{
+	MethodNative: `powershell -NoProfile -ExecutionPolicy Bypass -Command "irm https://claude.ai/install.ps1 | iex"`,
+}
+
+// defaultUpdateCommand updates an existing installation regardless of how it was installed
+const defaultUpdateCommand = "claude update"
+
+// Options selects the install/update commands. Empty fields fall back to defaults.
+type Options struct {
+	Method        string // "npm" (default) or "native"
+	Command       string // Overrides the install command
+	UpdateCommand string // Overrides the update command
+}
+
+// InstallCommand returns the shell command used to install claude
+func (o Options) InstallCommand() (string, error) {
+	if o.Command != "" {
+		return o.Command, nil
+	}
+
+	method := o.Method
+	if method == "" {
+		method = MethodNPM
+	}
+
+	if runtime.GOOS == "windows" {
+		if cmd, ok := windowsInstallCommands[method]; ok {
+			return cmd, nil
+		}
+	}
+
+	cmd, ok := defaultInstallCommands[method]
+	if !ok {
+		return "", fmt.Errorf("unknown install method %q (expected %q or %q)", method, MethodNPM, MethodNative)
+	}
+	return cmd, nil
+}
+
+// UpdateCommandLine returns the shell command used to update claude
+func (o Options) UpdateCommandLine() string {
+	if o.UpdateCommand != "" {
+		return o.UpdateCommand
+	}
+	return defaultUpdateCommand
+}
+
+// Run executes a shell command line with the terminal attached
+func Run(commandLine string) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	// #nosec G204 -- the command line comes from the user's own config or a built-in default
+	cmd := exec.Command(shell, flag, commandLine)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run %q: %w", commandLine, err)
+	}
+	return nil
+}
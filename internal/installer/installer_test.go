@@ -0,0 +1,55 @@
+package installer
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestInstallCommand(t *testing.T) {
+	nativeCommand := defaultInstallCommands[MethodNative]
+	if runtime.GOOS == "windows" {
+		nativeCommand = windowsInstallCommands[MethodNative]
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+		wantErr  bool
+	}{
+		{name: "default is npm", opts: Options{}, expected: defaultInstallCommands[MethodNPM]},
+		{name: "native", opts: Options{Method: MethodNative}, expected: nativeCommand},
+		{name: "custom command wins", opts: Options{Method: MethodNative, Command: "brew install claude"}, expected: "brew install claude"},
+		{name: "unknown method", opts: Options{Method: "pip"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.opts.InstallCommand()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("InstallCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("InstallCommand() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateCommandLine(t *testing.T) {
+	if got := (Options{}).UpdateCommandLine(); got != defaultUpdateCommand {
+		t.Errorf("UpdateCommandLine() = %q, expected %q", got, defaultUpdateCommand)
+	}
+	if got := (Options{UpdateCommand: "npm update -g"}).UpdateCommandLine(); got != "npm update -g" {
+		t.Errorf("UpdateCommandLine() = %q, expected override", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if err := Run("exit 0"); err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+	if err := Run("exit 3"); err == nil {
+		t.Error("Run() should fail when the command fails")
+	}
+}
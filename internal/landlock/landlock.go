@@ -0,0 +1,11 @@
+// Package landlock confines the writes of the current process, and of every program it execs, to
+// a set of files and directories using Landlock (Linux 5.13+). Reads are never restricted.
+//
+// Landlock applies to the calling thread only, so RestrictWrites is meant to run on a locked OS
+// thread right before exec, like the launcher's resource-limit helper does.
+package landlock
+
+import "errors"
+
+// ErrUnsupported is returned when the kernel does not offer Landlock
+var ErrUnsupported = errors.New("landlock is not available (Linux 5.13 or later with Landlock enabled is required)")
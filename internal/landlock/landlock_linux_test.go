@@ -0,0 +1,53 @@
+//go:build linux
+
+package landlock
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// helperEnv makes the test binary run TestRestrictWritesHelper as the restricted child
+const helperEnv = "LANDLOCK_TEST_DIRS"
+
+func TestRestrictWrites(t *testing.T) {
+	if !Available() {
+		t.Skip("Landlock is not available")
+	}
+
+	allowed, denied := t.TempDir(), t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRestrictWritesHelper$") // #nosec G204 -- re-runs the test binary
+	cmd.Env = append(os.Environ(), helperEnv+"="+allowed+string(os.PathListSeparator)+denied)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("helper failed: %v\n%s", err, out)
+	}
+
+	if _, err := os.Stat(filepath.Join(allowed, "ok")); err != nil {
+		t.Errorf("file in the allowed directory was not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(denied, "blocked")); err == nil {
+		t.Error("file outside the allowed directory was written")
+	}
+}
+
+// TestRestrictWritesHelper runs in the child process of TestRestrictWrites: it restricts itself
+// to the first directory and execs a shell that writes to both
+func TestRestrictWritesHelper(t *testing.T) {
+	dirs := filepath.SplitList(os.Getenv(helperEnv))
+	if len(dirs) != 2 {
+		t.Skip("only run by TestRestrictWrites")
+	}
+
+	runtime.LockOSThread()
+	if err := RestrictWrites(dirs[:1]); err != nil {
+		t.Fatalf("RestrictWrites() error = %v", err)
+	}
+
+	script := `echo > "$1/ok" && ! (echo > "$2/blocked") 2>/dev/null`
+	if out, err := exec.Command("sh", "-c", script, "sh", dirs[0], dirs[1]).CombinedOutput(); err != nil {
+		t.Fatalf("restricted shell failed: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package landlock
+
+// ABI returns 0: Landlock only exists on Linux
+func ABI() int {
+	return 0
+}
+
+// Available reports whether the kernel offers Landlock, which is never the case here
+func Available() bool {
+	return false
+}
+
+// RestrictWrites is not supported on this platform
+func RestrictWrites(_ []string) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,159 @@
+package launcher
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// envKey returns the variable name of a "KEY=value" entry, normalized for comparison.
+// Windows treats variable names case-insensitively and has hidden entries such as "=C:=C:\",
+// whose name starts with '='.
+func envKey(entry string) string {
+	key, _, _ := strings.Cut(entry, "=")
+	if key == "" && len(entry) > 1 {
+		if i := strings.Index(entry[1:], "="); i >= 0 {
+			key = entry[:i+1]
+		} else {
+			key = entry
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return strings.ToUpper(key)
+	}
+	return key
+}
+
+// envEntry normalizes a "KEY=value" entry so that equal variables compare equal
+func envEntry(entry string) string {
+	key := envKey(entry)
+	return key + entry[len(key):]
+}
+
+// setEnv returns base with vars set, replacing any inherited values
+func setEnv(base []string, vars map[string]string) []string {
+	if len(vars) == 0 {
+		return base
+	}
+
+	keys := make(map[string]bool, len(vars))
+	for k := range vars {
+		keys[envKey(k+"=")] = true
+	}
+
+	result := make([]string, 0, len(base)+len(vars))
+	for _, e := range base {
+		if !keys[envKey(e)] {
+			result = append(result, e)
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		result = append(result, k+"="+vars[k])
+	}
+
+	return result
+}
+
+// EnvPolicy controls which inherited environment variables reach the launched tool.
+// Names may contain * wildcards (e.g. "LC_*").
+type EnvPolicy struct {
+	Passthrough []string // If non-empty, only matching variables are inherited
+	Block       []string // Matching variables are never inherited
+}
+
+// Filter returns the entries of env allowed by the policy
+func (p EnvPolicy) Filter(env []string) []string {
+	if len(p.Passthrough) == 0 && len(p.Block) == 0 {
+		return env
+	}
+
+	result := make([]string, 0, len(env))
+	for _, e := range env {
+		key := envKey(e)
+		if len(p.Passthrough) > 0 && !matchEnvName(p.Passthrough, key) {
+			continue
+		}
+		if matchEnvName(p.Block, key) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// matchEnvName reports whether key (as returned by envKey) matches any of patterns
+func matchEnvName(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(envKey(pattern+"="), key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvRemoved returns the names of variables in base that are missing from env.
+// Wrappers that inherit an environment they don't control (tmux) use it to unset them.
+func EnvRemoved(base, env []string) []string {
+	kept := make(map[string]bool, len(env))
+	for _, e := range env {
+		kept[envKey(e)] = true
+	}
+
+	removed := make([]string, 0)
+	for _, e := range base {
+		key, _, _ := strings.Cut(e, "=")
+		if key != "" && !kept[envKey(e)] {
+			removed = append(removed, key)
+		}
+	}
+	return removed
+}
+
+// SourceEnvFile is the sh -c script prefix wrappers run the command with when its variables are
+// in an env file: it exports the variables of the file "$1", deletes the file and drops it from
+// the arguments
+const SourceEnvFile = `set -a && . "$1" && set +a && rm -f "$1" && shift && `
+
+// WriteEnvFile writes env ("KEY=value" entries) as sh assignments to a new file in dir (the
+// temp directory when empty) that only the user can read, for SourceEnvFile. Wrappers (tmux,
+// devcontainers) pass variables this way because KEY=value arguments, secrets included, can
+// be read by every user with ps. Names sh cannot assign are left out.
+func WriteEnvFile(dir string, env []string) (string, error) {
+	var b strings.Builder
+	for _, e := range env {
+		key, value, _ := strings.Cut(e, "=")
+		if !shellName(key) {
+			continue
+		}
+		b.WriteString(key + "='" + strings.ReplaceAll(value, "'", `'\''`) + "'\n")
+	}
+
+	f, err := os.CreateTemp(dir, "claude-launcher-env-*") // Created with mode 0600
+	if err != nil {
+		return "", fmt.Errorf("failed to create env file: %w", err)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		_ = f.Close()           //nolint:errcheck // the write error is reported
+		_ = os.Remove(f.Name()) //nolint:errcheck // best-effort cleanup
+		return "", fmt.Errorf("failed to write env file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name()) //nolint:errcheck // best-effort cleanup
+		return "", fmt.Errorf("failed to write env file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// shellName reports whether name can be assigned in sh
+func shellName(name string) bool {
+	for i, r := range name {
+		if r != '_' && !('a' <= r && r <= 'z') && !('A' <= r && r <= 'Z') && (i == 0 || !('0' <= r && r <= '9')) {
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,130 @@
+package launcher
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEnvKey(t *testing.T) {
+	tests := []struct {
+		entry    string
+		expected string
+	}{
+		{entry: "HOME=/home/user", expected: "HOME"},
+		{entry: "EMPTY=", expected: "EMPTY"},
+		{entry: "A=b=c", expected: "A"},
+		{entry: "=C:=C:\\Users", expected: "=C:"},
+	}
+
+	for _, tt := range tests {
+		if got := envKey(tt.entry); got != tt.expected {
+			t.Errorf("envKey(%q) = %q, expected %q", tt.entry, got, tt.expected)
+		}
+	}
+}
+
+func TestEnvDeltaIgnoresKeyCaseOnWindows(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("environment variable names are case-sensitive on this platform")
+	}
+
+	delta := EnvDelta([]string{"Path=C:\\bin"}, []string{"PATH=C:\\bin", "NEW=1"})
+	if len(delta) != 1 || delta[0] != "NEW=1" {
+		t.Errorf("EnvDelta() = %v, expected [NEW=1]", delta)
+	}
+}
+
+func TestSetEnv(t *testing.T) {
+	base := []string{"HOME=/home/user", "HTTPS_PROXY=http://inherited:8080"}
+
+	result := setEnv(base, map[string]string{"HTTPS_PROXY": "http://proxy:3128", "NO_PROXY": "localhost"})
+	expected := []string{"HOME=/home/user", "HTTPS_PROXY=http://proxy:3128", "NO_PROXY=localhost"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("setEnv() = %v, expected %v", result, expected)
+	}
+
+	if result := setEnv(base, nil); !slices.Equal(result, base) {
+		t.Errorf("setEnv() with no vars = %v, expected base unchanged", result)
+	}
+}
+
+func TestEnvPolicyFilter(t *testing.T) {
+	env := []string{"PATH=/usr/bin", "HOME=/home/user", "LC_ALL=C", "AWS_SECRET_ACCESS_KEY=secret", "GITHUB_TOKEN=token"}
+
+	tests := []struct {
+		name     string
+		policy   EnvPolicy
+		expected []string
+	}{
+		{
+			name:     "no policy",
+			policy:   EnvPolicy{},
+			expected: env,
+		},
+		{
+			name:     "passthrough with wildcard",
+			policy:   EnvPolicy{Passthrough: []string{"PATH", "LC_*"}},
+			expected: []string{"PATH=/usr/bin", "LC_ALL=C"},
+		},
+		{
+			name:     "block",
+			policy:   EnvPolicy{Block: []string{"AWS_*", "GITHUB_TOKEN"}},
+			expected: []string{"PATH=/usr/bin", "HOME=/home/user", "LC_ALL=C"},
+		},
+		{
+			name:     "block wins over passthrough",
+			policy:   EnvPolicy{Passthrough: []string{"PATH", "GITHUB_TOKEN"}, Block: []string{"GITHUB_TOKEN"}},
+			expected: []string{"PATH=/usr/bin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.policy.Filter(env); !slices.Equal(result, tt.expected) {
+				t.Errorf("Filter() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEnvRemoved(t *testing.T) {
+	removed := EnvRemoved([]string{"PATH=/usr/bin", "TOKEN=x", "HOME=/home/user"}, []string{"PATH=/usr/bin", "HOME=/root"})
+	if !slices.Equal(removed, []string{"TOKEN"}) {
+		t.Errorf("EnvRemoved() = %v, expected [TOKEN]", removed)
+	}
+}
+
+func TestWriteEnvFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+	path, err := WriteEnvFile(t.TempDir(), []string{"API_KEY=it's $secret", "OTEL_SERVICE_NAME=claude", "not-a-name=x"})
+	if err != nil {
+		t.Fatalf("WriteEnvFile() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("mode = %o, want 600", mode)
+	}
+
+	out, err := exec.Command("sh", "-c", SourceEnvFile+`exec "$@"`, "sh", path, "env").Output()
+	if err != nil {
+		t.Fatalf("sourcing the env file: %v", err)
+	}
+	env := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for _, want := range []string{"API_KEY=it's $secret", "OTEL_SERVICE_NAME=claude"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("env = %q, expected %q", env, want)
+		}
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("the env file was not deleted after sourcing it")
+	}
+}
@@ -1,15 +1,39 @@
 package launcher
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/state"
 )
 
-// Launcher handles launching Claude Code
+// Launcher handles launching Claude Code (or another Tool)
 type Launcher struct {
+	Tool       Tool // Optional: the agent CLI to launch (defaults to Claude)
 	ClaudePath string
+	ClaudeArgs []string     // Optional: arguments placed before claude's own (e.g. the script behind an npm shim)
+	Candidates []string     // Optional: binaries tried in order by ResolveBinary (defaults to ClaudePath)
+	Tracker    Tracker      // Optional: notified when claude starts and exits
+	Recorder   Recorder     // Optional: receives launch metrics when claude exits
+	EnvPolicy  EnvPolicy    // Optional: restricts the environment inherited from the launcher
+	Versions   *state.Store // Optional: caches the versions Preflight reads between launches
+
+	// Wrap optionally rewrites the prepared command, e.g. to run claude inside a container
+	Wrap func(*Command) (*Command, error)
+	// Progress is optionally called with a printf-style description of each pre-flight step
+	Progress func(format string, args ...any)
+}
+
+// Tracker is notified about claude processes started by Launch
+type Tracker interface {
+	// Started is called once claude is running. The returned function is called after it exits.
+	Started(pid int, opts LaunchOptions) (finished func())
 }
 
 // NewLauncher creates a new Launcher
@@ -21,40 +45,257 @@ func NewLauncher() *Launcher {
 
 // LaunchOptions contains options for launching Claude
 type LaunchOptions struct {
-	Continue  bool
-	Args      []string
-	ConfigDir string            // Optional: Sets CLAUDE_CONFIG_DIR environment variable
-	OtelEnv   map[string]string // Optional: OpenTelemetry environment variables
+	Mode        string // Optional: Launch mode recorded in metrics (defaults to ModeForeground)
+	Account     string // Optional: Account name recorded in metrics
+	Preset      string // Optional: Permission preset name recorded in metrics
+	Rule        string // Optional: Allowed directory that authorized Dir, recorded in metrics
+	Continue    bool
+	Pick        bool     // Optional: Let the user choose the session to resume in the tool's picker
+	Dir         string   // Optional: Working directory for claude (defaults to the current directory)
+	Model       string   // Optional: Passed to claude as --model
+	AddDirs     []string // Optional: Extra directories claude may access (--add-dir)
+	Args        []string
+	ConfigDir   string                      // Optional: Sets CLAUDE_CONFIG_DIR environment variable
+	OtelEnv     map[string]string           // Optional: OpenTelemetry environment variables
+	Env         map[string]string           // Optional: Environment variables overriding inherited ones
+	SecretEnv   []string                    // Optional: Env names holding secrets, redacted in traces
+	MCPServers  map[string]config.MCPServer // Optional: Passed to claude via --mcp-config
+	Permissions config.Permissions          // Optional: Passed to claude via --settings
 }
 
-// Launch executes Claude Code with the specified options
-func (l *Launcher) Launch(opts LaunchOptions) error {
-	args := make([]string, 0)
+// Command is a fully resolved claude invocation.
+// It is JSON-serializable so it can be handed to another process (e.g. a detached session server).
+type Command struct {
+	Path      string   `json:"path"`
+	Args      []string `json:"args"`
+	Env       []string `json:"env"`
+	Dir       string   `json:"dir,omitempty"`
+	TempFiles []string `json:"tempFiles,omitempty"` // Removed by Cleanup once claude exits
+}
+
+// Prepare resolves opts into a Command without running it.
+// The caller must call Cleanup on the returned Command once claude has exited.
+func (l *Launcher) Prepare(opts LaunchOptions) (*Command, error) {
+	tool := l.tool()
+	c := &Command{
+		Path: l.ClaudePath,
+		Args: tool.Args(opts),
+		Dir:  opts.Dir,
+		Env:  setEnv(buildOtelEnv(l.EnvPolicy.Filter(os.Environ()), opts.OtelEnv), opts.Env),
+	}
+
+	if opts.ConfigDir != "" && tool.ConfigDirEnv() != "" {
+		c.Env = append(c.Env, tool.ConfigDirEnv()+"="+opts.ConfigDir)
+	}
+
+	if len(opts.MCPServers) > 0 && tool.MCPConfigFlag() != "" {
+		mcpConfigPath, err := writeMCPConfig(opts.MCPServers)
+		if err != nil {
+			return nil, err
+		}
+		c.TempFiles = append(c.TempFiles, mcpConfigPath)
+		c.Args = append([]string{tool.MCPConfigFlag(), mcpConfigPath}, c.Args...)
+	}
+
+	if !opts.Permissions.IsZero() {
+		if tool.SettingsFlag() == "" {
+			c.Cleanup()
+			return nil, fmt.Errorf("%s does not support permission settings", tool.Name())
+		}
+		settingsPath, err := writeTempJSON("settings", buildSettings(opts.Permissions))
+		if err != nil {
+			c.Cleanup()
+			return nil, err
+		}
+		c.TempFiles = append(c.TempFiles, settingsPath)
+		c.Args = append([]string{tool.SettingsFlag(), settingsPath}, c.Args...)
+	}
+
+	c.Args = slices.Concat(l.ClaudeArgs, c.Args)
 
-	if opts.Continue {
-		args = append(args, "--continue")
+	if l.Wrap != nil {
+		wrapped, err := l.Wrap(c)
+		if err != nil {
+			c.Cleanup()
+			return nil, err
+		}
+		c = wrapped
+	}
+
+	traceCommand(c, opts.SecretEnv)
+	return c, nil
+}
+
+// traceCommand writes the final command line and its environment changes to the debug trace,
+// with the values of secretEnv redacted
+func traceCommand(c *Command, secretEnv []string) {
+	if !log.DebugEnabled() {
+		return
 	}
 
-	args = append(args, opts.Args...)
+	log.Debug("command", "line", strings.Join(slices.Concat([]string{c.Path}, c.Args), " "), "dir", c.Dir)
+	for _, e := range EnvDelta(os.Environ(), c.Env) {
+		if key := envKey(e); slices.ContainsFunc(secretEnv, func(name string) bool { return envKey(name+"=") == key }) {
+			e = key + "=<redacted>"
+		}
+		log.Debug("env added", "var", e)
+	}
+	for _, key := range EnvRemoved(os.Environ(), c.Env) {
+		log.Debug("env removed", "key", key)
+	}
+}
 
-	// #nosec G204 -- ClaudePath defaults to "claude" and args are user-provided CLI arguments
-	cmd := exec.Command(l.ClaudePath, args...)
+// Cmd builds an exec.Cmd for c. Stdio is left unset.
+func (c *Command) Cmd() *exec.Cmd {
+	// #nosec G204 -- Path defaults to "claude" and args are user-provided CLI arguments
+	cmd := exec.Command(c.Path, c.Args...)
+	cmd.Dir = c.Dir
+	cmd.Env = c.Env
+	return cmd
+}
+
+// Cleanup removes temporary files created by Prepare
+func (c *Command) Cleanup() {
+	for _, path := range c.TempFiles {
+		_ = os.Remove(path) //nolint:errcheck // best-effort cleanup of temp file
+	}
+}
+
+// Launch executes Claude Code with the specified options
+func (l *Launcher) Launch(opts LaunchOptions) error {
+	c, err := l.Prepare(opts)
+	if err != nil {
+		return err
+	}
+	defer c.Cleanup()
+
+	cmd := c.Cmd()
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	cmd.Env = buildOtelEnv(os.Environ(), opts.OtelEnv)
 
-	if opts.ConfigDir != "" {
-		cmd.Env = append(cmd.Env, "CLAUDE_CONFIG_DIR="+opts.ConfigDir)
+	rec := NewRecord(opts)
+	if err := cmd.Start(); err != nil {
+		rec.Finish(err)
+		l.Record(rec)
+		return fmt.Errorf("failed to run %s: %w", l.tool().Name(), err)
+	}
+
+	if l.Tracker != nil {
+		finished := l.Tracker.Started(cmd.Process.Pid, opts)
+		defer finished()
 	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to run claude: %w", err)
+	restore := ignoreInterrupts()
+	err = cmd.Wait()
+	restore()
+	rec.Finish(err)
+	if l.tool() == Claude {
+		rec.AddUsage(opts.ConfigDir)
+	}
+	l.Record(rec)
+	if err != nil {
+		return fmt.Errorf("failed to run %s: %w", l.tool().Name(), err)
 	}
 
 	return nil
 }
 
+// Record passes rec to the Recorder, if any. Metrics and notifications are best-effort and never
+// fail a launch.
+func (l *Launcher) Record(rec *LaunchRecord) {
+	if l.Recorder == nil {
+		return
+	}
+	if err := l.Recorder.Record(rec); err != nil {
+		log.Warn("failed to record launch", "error", err)
+	}
+}
+
+// tool returns the Tool to launch
+func (l *Launcher) tool() Tool {
+	if l.Tool == nil {
+		return Claude
+	}
+	return l.Tool
+}
+
+// writeMCPConfig writes servers to a temporary file in Claude Code's --mcp-config format
+// and returns its path. The caller is responsible for removing the file.
+func writeMCPConfig(servers map[string]config.MCPServer) (string, error) {
+	return writeTempJSON("mcp", map[string]any{"mcpServers": servers})
+}
+
+// buildSettings converts permission rules into a Claude Code settings payload.
+// Disabled tools become deny rules.
+func buildSettings(p config.Permissions) map[string]any {
+	permissions := map[string]any{}
+	if len(p.Allow) > 0 {
+		permissions["allow"] = p.Allow
+	}
+	if deny := slices.Concat(p.Deny, p.DisabledTools); len(deny) > 0 {
+		permissions["deny"] = deny
+	}
+	if len(p.Ask) > 0 {
+		permissions["ask"] = p.Ask
+	}
+	if p.DefaultMode != "" {
+		permissions["defaultMode"] = p.DefaultMode
+	}
+	return map[string]any{"permissions": permissions}
+}
+
+// writeTempJSON writes v to a new temporary file named after kind and returns its path
+func writeTempJSON(kind string, v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s file: %w", kind, err)
+	}
+
+	f, err := os.CreateTemp("", "claude-launcher-"+kind+"-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s file: %w", kind, err)
+	}
+	defer func() { _ = f.Close() }() //nolint:errcheck // close error after successful write is not actionable
+
+	if _, err := f.Write(data); err != nil {
+		_ = os.Remove(f.Name()) //nolint:errcheck // best-effort cleanup of temp file
+		return "", fmt.Errorf("failed to write %s file: %w", kind, err)
+	}
+
+	return f.Name(), nil
+}
+
+// SkipPermissionsFlag is the claude flag that disables all permission prompts
+const SkipPermissionsFlag = "--dangerously-skip-permissions"
+
+// HasSkipPermissions reports whether args ask claude to skip permission prompts
+func HasSkipPermissions(args []string) bool {
+	for _, arg := range args {
+		if arg == SkipPermissionsFlag || strings.HasPrefix(arg, SkipPermissionsFlag+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvDelta returns the entries of env that are not present verbatim in base.
+// Useful when handing a Command to a wrapper (tmux, containers) that only inherits part of it.
+func EnvDelta(base, env []string) []string {
+	existing := make(map[string]bool, len(base))
+	for _, e := range base {
+		existing[envEntry(e)] = true
+	}
+
+	delta := make([]string, 0)
+	for _, e := range env {
+		if !existing[envEntry(e)] {
+			delta = append(delta, e)
+		}
+	}
+	return delta
+}
+
 // buildOtelEnv merges otelEnv into base, skipping keys already present in base.
 // Shell env vars (base) take highest priority.
 func buildOtelEnv(base []string, otelEnv map[string]string) []string {
@@ -64,15 +305,14 @@ func buildOtelEnv(base []string, otelEnv map[string]string) []string {
 
 	existing := make(map[string]bool, len(base))
 	for _, e := range base {
-		key := strings.SplitN(e, "=", 2)[0]
-		existing[key] = true
+		existing[envKey(e)] = true
 	}
 
 	result := make([]string, len(base), len(base)+len(otelEnv))
 	copy(result, base)
 
 	for k, v := range otelEnv {
-		if !existing[k] {
+		if !existing[envKey(k+"=")] {
 			result = append(result, k+"="+v)
 		}
 	}
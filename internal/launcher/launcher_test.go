@@ -1,7 +1,12 @@
 package launcher
 
 import (
+	"encoding/json"
+	"os"
+	"slices"
 	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
 )
 
 func TestBuildOtelEnv(t *testing.T) {
@@ -98,3 +103,130 @@ func splitEnv(e string) [2]string {
 	}
 	return [2]string{e, ""}
 }
+
+func TestBuildArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     LaunchOptions
+		expected []string
+	}{
+		{
+			name:     "no options",
+			opts:     LaunchOptions{},
+			expected: []string{},
+		},
+		{
+			name:     "continue with args",
+			opts:     LaunchOptions{Continue: true, Args: []string{"--verbose"}},
+			expected: []string{"--continue", "--verbose"},
+		},
+		{
+			name:     "model is placed before user args",
+			opts:     LaunchOptions{Continue: true, Model: "haiku", Args: []string{"--verbose"}},
+			expected: []string{"--continue", "--model", "haiku", "--verbose"},
+		},
+		{
+			name:     "extra directories use the = form",
+			opts:     LaunchOptions{AddDirs: []string{"/home/user/libs", "/home/user/docs"}, Args: []string{"prompt"}},
+			expected: []string{"--add-dir=/home/user/libs", "--add-dir=/home/user/docs", "prompt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Claude.Args(tt.opts)
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("Claude.Args() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteMCPConfig(t *testing.T) {
+	servers := map[string]config.MCPServer{
+		"github": {Command: "github-mcp-server", Args: []string{"stdio"}},
+	}
+
+	path, err := writeMCPConfig(servers)
+	if err != nil {
+		t.Fatalf("writeMCPConfig() error = %v", err)
+	}
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read MCP config: %v", err)
+	}
+
+	var got struct {
+		MCPServers map[string]config.MCPServer `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to parse MCP config: %v", err)
+	}
+
+	if got.MCPServers["github"].Command != "github-mcp-server" {
+		t.Errorf("mcpServers[github].Command = %q, expected %q", got.MCPServers["github"].Command, "github-mcp-server")
+	}
+}
+
+func TestEnvDelta(t *testing.T) {
+	base := []string{"PATH=/usr/bin", "HOME=/home/user"}
+	env := []string{"PATH=/usr/bin", "HOME=/home/user", "CLAUDE_CONFIG_DIR=/home/user/.claude-work", "PATH=/opt/bin"}
+
+	got := EnvDelta(base, env)
+	expected := []string{"CLAUDE_CONFIG_DIR=/home/user/.claude-work", "PATH=/opt/bin"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("EnvDelta() = %v, expected %v", got, expected)
+	}
+}
+
+func TestHasSkipPermissions(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{args: nil, expected: false},
+		{args: []string{"--verbose"}, expected: false},
+		{args: []string{"--verbose", "--dangerously-skip-permissions"}, expected: true},
+		{args: []string{"--dangerously-skip-permissions=true"}, expected: true},
+	}
+
+	for _, tt := range tests {
+		if got := HasSkipPermissions(tt.args); got != tt.expected {
+			t.Errorf("HasSkipPermissions(%v) = %v, expected %v", tt.args, got, tt.expected)
+		}
+	}
+}
+
+func TestPrepareWritesSettings(t *testing.T) {
+	l := &Launcher{ClaudePath: "claude"}
+	cmd, err := l.Prepare(LaunchOptions{
+		Permissions: config.Permissions{Deny: []string{"Bash(rm:*)"}, DisabledTools: []string{"Edit", "Write"}, DefaultMode: "plan"},
+	})
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	defer cmd.Cleanup()
+
+	if len(cmd.Args) != 2 || cmd.Args[0] != "--settings" {
+		t.Fatalf("Prepare() args = %v, expected --settings <file>", cmd.Args)
+	}
+
+	data, err := os.ReadFile(cmd.Args[1])
+	if err != nil {
+		t.Fatalf("failed to read settings: %v", err)
+	}
+	var settings struct {
+		Permissions struct {
+			Deny        []string `json:"deny"`
+			DefaultMode string   `json:"defaultMode"`
+		} `json:"permissions"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("failed to parse settings: %v", err)
+	}
+	if !slices.Equal(settings.Permissions.Deny, []string{"Bash(rm:*)", "Edit", "Write"}) || settings.Permissions.DefaultMode != "plan" {
+		t.Errorf("unexpected settings: %s", data)
+	}
+}
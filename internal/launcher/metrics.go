@@ -0,0 +1,159 @@
+package launcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// Launch modes recorded in LaunchRecord.Mode
+const (
+	ModeForeground = "foreground"
+	ModeHeadless   = "headless"
+	ModeDetached   = "detached"
+	ModeTmux       = "tmux"
+)
+
+// historyFile is the launch history in the state store, one LaunchRecord per line
+const historyFile = "history.jsonl"
+
+// LaunchRecord describes one claude launch and its outcome
+type LaunchRecord struct {
+	ID        string    `json:"id"`
+	Mode      string    `json:"mode"`
+	Dir       string    `json:"dir"`
+	Account   string    `json:"account,omitempty"`
+	Preset    string    `json:"preset,omitempty"`
+	Rule      string    `json:"rule,omitempty"` // The allowed directory that matched Dir
+	Model     string    `json:"model,omitempty"`
+	Continue  bool      `json:"continue,omitempty"`
+	Args      []string  `json:"args,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt,omitzero"`   // Zero when the end was not observed (e.g. tmux)
+	ExitCode  *int      `json:"exitCode,omitempty"` // Nil when claude did not run or the exit was not observed
+	Error     string    `json:"error,omitempty"`    // Set when claude could not be started
+	Usage     *Tokens   `json:"usage,omitempty"`    // Tokens of the session's API responses (claude only)
+}
+
+// Recorder receives a LaunchRecord for every finished launch.
+// The state store is the default sink; other implementations (e.g. a daemon) can expose them too.
+type Recorder interface {
+	Record(rec *LaunchRecord) error
+}
+
+// Recorders passes every record to each of its recorders
+type Recorders []Recorder
+
+// Record implements Recorder, returning the failures of all recorders together
+func (rs Recorders) Record(rec *LaunchRecord) error {
+	var errs []error
+	for _, r := range rs {
+		if err := r.Record(rec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewRecord starts a LaunchRecord for opts
+func NewRecord(opts LaunchOptions) *LaunchRecord {
+	mode := opts.Mode
+	if mode == "" {
+		mode = ModeForeground
+	}
+
+	id, err := state.NewID()
+	if err != nil {
+		id = fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+
+	return &LaunchRecord{
+		ID:        id,
+		Mode:      mode,
+		Dir:       opts.Dir,
+		Account:   opts.Account,
+		Preset:    opts.Preset,
+		Rule:      opts.Rule,
+		Model:     opts.Model,
+		Continue:  opts.Continue || opts.Pick, // The picked session is the latest one afterwards
+		Args:      opts.Args,
+		StartedAt: time.Now(),
+	}
+}
+
+// Finish records the end time and the exit status carried by err (the result of running claude)
+func (r *LaunchRecord) Finish(err error) {
+	r.EndedAt = time.Now()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		code := 0
+		r.ExitCode = &code
+	case errors.As(err, &exitErr):
+		code := exitErr.ExitCode()
+		r.ExitCode = &code
+	default:
+		r.Error = err.Error()
+	}
+}
+
+// AddUsage sets Usage from the transcripts Claude Code wrote in configDir (the account's, or
+// the default when empty) while the launch ran. Without responses, or when the end was not
+// observed, Usage stays unset.
+func (r *LaunchRecord) AddUsage(configDir string) {
+	if r.EndedAt.IsZero() {
+		return
+	}
+	u, err := ReadSessionUsage(ClaudeConfigDir(configDir), r.Dir, r.StartedAt, r.EndedAt)
+	if err != nil {
+		log.Debug("session usage unavailable", "error", err)
+		return
+	}
+	if u.Messages > 0 {
+		r.Usage = &u.Tokens
+	}
+}
+
+// Duration returns how long claude ran, or 0 if the end was not observed
+func (r *LaunchRecord) Duration() time.Duration {
+	if r.EndedAt.IsZero() {
+		return 0
+	}
+	return r.EndedAt.Sub(r.StartedAt)
+}
+
+// StoreRecorder appends launch records to the history file in a state store
+type StoreRecorder struct {
+	Store *state.Store
+}
+
+// Record implements Recorder
+func (r *StoreRecorder) Record(rec *LaunchRecord) error {
+	return r.Store.AppendJSONLine(historyFile, rec)
+}
+
+// HistoryPath returns the path of the launch history in store
+func HistoryPath(store *state.Store) string {
+	return filepath.Join(store.Dir, historyFile)
+}
+
+// History returns all launch records in the store, oldest first.
+// Unparseable lines are skipped.
+func History(store *state.Store) ([]LaunchRecord, error) {
+	var records []LaunchRecord
+	err := store.ReadJSONLines(historyFile, func(line []byte) error {
+		var rec LaunchRecord
+		if err := json.Unmarshal(line, &rec); err == nil {
+			records = append(records, rec)
+		}
+		return nil
+	})
+	return records, err
+}
@@ -0,0 +1,140 @@
+package launcher
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+func TestNewRecord(t *testing.T) {
+	rec := NewRecord(LaunchOptions{Dir: "/tmp/project", Account: "Work", Preset: "review", Model: "opus", Continue: true})
+
+	if rec.ID == "" {
+		t.Error("ID should not be empty")
+	}
+	if rec.Mode != ModeForeground {
+		t.Errorf("Mode = %q, expected %q", rec.Mode, ModeForeground)
+	}
+	if rec.Dir != "/tmp/project" || rec.Account != "Work" || rec.Preset != "review" || rec.Model != "opus" || !rec.Continue {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+	if rec.StartedAt.IsZero() {
+		t.Error("StartedAt should be set")
+	}
+	if rec.Duration() != 0 {
+		t.Errorf("Duration() = %v, expected 0 before Finish", rec.Duration())
+	}
+}
+
+func TestLaunchRecordFinish(t *testing.T) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	exitErr := exec.Command(shell, flag, "exit 3").Run()
+	zero, three := 0, 3
+
+	tests := []struct {
+		name         string
+		err          error
+		wantExitCode *int
+		wantError    bool
+	}{
+		{name: "success", err: nil, wantExitCode: &zero},
+		{name: "non-zero exit", err: exitErr, wantExitCode: &three},
+		{name: "failed to start", err: errors.New("exec: not found"), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewRecord(LaunchOptions{})
+			rec.Finish(tt.err)
+
+			if rec.EndedAt.IsZero() {
+				t.Error("EndedAt should be set")
+			}
+			switch {
+			case tt.wantExitCode == nil && rec.ExitCode != nil:
+				t.Errorf("ExitCode = %d, expected nil", *rec.ExitCode)
+			case tt.wantExitCode != nil && (rec.ExitCode == nil || *rec.ExitCode != *tt.wantExitCode):
+				t.Errorf("ExitCode = %v, expected %d", rec.ExitCode, *tt.wantExitCode)
+			}
+			if (rec.Error != "") != tt.wantError {
+				t.Errorf("Error = %q, wantError %v", rec.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+// recorderFunc adapts a function to Recorder
+type recorderFunc func(rec *LaunchRecord) error
+
+func (f recorderFunc) Record(rec *LaunchRecord) error { return f(rec) }
+
+func TestRecorders(t *testing.T) {
+	calls := 0
+	ok := recorderFunc(func(*LaunchRecord) error { calls++; return nil })
+	failing := recorderFunc(func(*LaunchRecord) error { calls++; return errors.New("unreachable") })
+
+	err := Recorders{failing, ok}.Record(NewRecord(LaunchOptions{Dir: "/tmp/a"}))
+	if err == nil || err.Error() != "unreachable" {
+		t.Errorf("Record() error = %v, expected the failure of the first recorder", err)
+	}
+	if calls != 2 {
+		t.Errorf("%d recorders were called, expected 2 despite the failure", calls)
+	}
+}
+
+func TestStoreRecorderHistory(t *testing.T) {
+	store := &state.Store{Dir: t.TempDir()}
+	recorder := &StoreRecorder{Store: store}
+
+	first := NewRecord(LaunchOptions{Dir: "/tmp/a", Mode: ModeHeadless})
+	first.Finish(nil)
+	second := NewRecord(LaunchOptions{Dir: "/tmp/b", Mode: ModeTmux})
+
+	for _, rec := range []*LaunchRecord{first, second} {
+		if err := recorder.Record(rec); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	// A corrupt line must not hide the other records
+	f, err := os.OpenFile(filepath.Join(store.Dir, historyFile), os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("failed to open history: %v", err)
+	}
+	if _, err := f.WriteString("not json\n"); err != nil {
+		t.Fatalf("failed to write history: %v", err)
+	}
+	_ = f.Close()
+
+	records, err := History(store)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("History() returned %d records, expected 2", len(records))
+	}
+	if records[0].Mode != ModeHeadless || records[0].ExitCode == nil || *records[0].ExitCode != 0 {
+		t.Errorf("unexpected first record: %+v", records[0])
+	}
+	if records[1].Mode != ModeTmux || records[1].ExitCode != nil || !records[1].EndedAt.IsZero() {
+		t.Errorf("unexpected second record: %+v", records[1])
+	}
+}
+
+func TestHistoryEmpty(t *testing.T) {
+	records, err := History(&state.Store{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("History() returned %d records, expected none", len(records))
+	}
+}
@@ -0,0 +1,164 @@
+package launcher
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pinFiles are the version manager files that can pin claude, nearest first in each directory.
+// .tool-versions is read by asdf and by mise.
+var pinFiles = []struct {
+	name     string
+	managers []string
+}{
+	{name: "mise.toml", managers: []string{"mise"}},
+	{name: ".mise.toml", managers: []string{"mise"}},
+	{name: ".tool-versions", managers: []string{"asdf", "mise"}},
+}
+
+// numericVersion matches the pins a version can be compared with
+var numericVersion = regexp.MustCompile(`^\d+(\.\d+)*$`)
+
+// Pin is a claude version pinned by a project for a version manager
+type Pin struct {
+	File     string   // The file pinning the version
+	Tool     string   // The tool name used in the file, e.g. "npm:@anthropic-ai/claude-code"
+	Version  string   // The pinned version, e.g. "1.0.30", "1" or "latest"
+	Managers []string // Version managers reading the file, in order of preference
+}
+
+// FindPin looks for a version manager file pinning claude in dir and its parents.
+// It returns nil when no file pins claude.
+func FindPin(dir string) (*Pin, error) {
+	for {
+		for _, f := range pinFiles {
+			path := filepath.Join(dir, f.name)
+			tool, version, err := readPin(path)
+			if err != nil {
+				return nil, err
+			}
+			if version != "" {
+				return &Pin{File: path, Tool: tool, Version: version, Managers: f.managers}, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// readPin returns the claude entry of a mise.toml or .tool-versions file, if any
+func readPin(path string) (tool, version string, err error) {
+	f, err := os.Open(path) // #nosec G304 -- version manager file of the launch directory
+	if errors.Is(err, os.ErrNotExist) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }() //nolint:errcheck // read-only file
+
+	toml := filepath.Ext(path) == ".toml"
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if !toml {
+			// .tool-versions: "<tool> <version> [fallback versions]"
+			fields := strings.Fields(line)
+			if len(fields) >= 2 && isClaudeTool(fields[0]) {
+				return fields[0], fields[1], nil
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section != "tools" {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		if isClaudeTool(key) {
+			return key, tomlVersion(value), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return "", "", nil
+}
+
+// isClaudeTool reports whether a version manager tool name designates Claude Code, with or
+// without a backend prefix ("claude", "claude-code", "npm:@anthropic-ai/claude-code")
+func isClaudeTool(name string) bool {
+	if _, rest, ok := strings.Cut(name, ":"); ok {
+		name = rest
+	}
+	name = name[strings.LastIndex(name, "/")+1:]
+	return name == "claude" || name == "claude-code"
+}
+
+// tomlVersion extracts the version of a mise tools entry: "1.0", ["1.0", "0.9"] (the first is
+// used) or { version = "1.0" }
+func tomlVersion(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "{") {
+		_, value, _ = strings.Cut(value, "version")
+		_, value, _ = strings.Cut(value, "=")
+	}
+	value = strings.TrimLeft(strings.TrimSpace(value), "[ ")
+	if quote := value[:min(1, len(value))]; quote == `"` || quote == "'" {
+		if end := strings.Index(value[1:], quote); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	return ""
+}
+
+// Which asks the first available version manager which claude binary it runs in dir.
+// It fails when no manager is installed or the pinned version is not.
+func (p *Pin) Which(dir string) (string, error) {
+	for _, manager := range p.Managers {
+		path, err := exec.LookPath(manager)
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, "which", "claude") // #nosec G204 -- fixed version manager command
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+				return "", fmt.Errorf("%s which claude: %s", manager, strings.TrimSpace(string(exitErr.Stderr)))
+			}
+			return "", fmt.Errorf("%s which claude: %w", manager, err)
+		}
+		if binary := strings.TrimSpace(string(out)); binary != "" {
+			return binary, nil
+		}
+		return "", fmt.Errorf("%s which claude: no binary", manager)
+	}
+	return "", fmt.Errorf("%s is not installed", strings.Join(p.Managers, " or "))
+}
+
+// Matches reports whether version satisfies the pin. A pin matches the versions it is a prefix
+// of ("1.0" matches "1.0.30"); "latest" and other non-numeric pins match any version.
+func (p *Pin) Matches(version string) bool {
+	pinned := strings.TrimPrefix(p.Version, "v")
+	if !numericVersion.MatchString(pinned) {
+		return true
+	}
+	version = strings.TrimPrefix(version, "v")
+	return version == pinned || strings.HasPrefix(version, pinned+".")
+}
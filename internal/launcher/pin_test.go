@@ -0,0 +1,120 @@
+package launcher
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFindPin(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		tool     string
+		version  string
+		managers []string
+	}{
+		{
+			name:     "mise string",
+			file:     "mise.toml",
+			content:  "[env]\nclaude = \"ignored\"\n\n[tools]\nnode = \"22\"\n\"npm:@anthropic-ai/claude-code\" = \"1.0.30\" # pinned\n",
+			tool:     "npm:@anthropic-ai/claude-code",
+			version:  "1.0.30",
+			managers: []string{"mise"},
+		},
+		{name: "mise list", file: ".mise.toml", content: "[tools]\nclaude-code = [\"2.0\", \"1.0\"]\n", tool: "claude-code", version: "2.0", managers: []string{"mise"}},
+		{name: "mise table", file: "mise.toml", content: "[tools]\nclaude = { version = 'latest' }\n", tool: "claude", version: "latest", managers: []string{"mise"}},
+		{name: "tool-versions", file: ".tool-versions", content: "nodejs 22.1.0\nclaude-code 1.0.30 1.0.29\n", tool: "claude-code", version: "1.0.30", managers: []string{"asdf", "mise"}},
+		{name: "other tools only", file: ".tool-versions", content: "nodejs 22.1.0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			dir := filepath.Join(root, "src", "pkg")
+			writeFile(t, filepath.Join(root, tt.file), tt.content)
+			writeFile(t, filepath.Join(dir, ".keep"), "")
+
+			pin, err := FindPin(dir)
+			if err != nil {
+				t.Fatalf("FindPin() error = %v", err)
+			}
+			if tt.version == "" {
+				if pin != nil {
+					t.Errorf("FindPin() = %+v, expected no pin", pin)
+				}
+				return
+			}
+			if pin == nil {
+				t.Fatal("FindPin() found no pin")
+			}
+			if pin.File != filepath.Join(root, tt.file) || pin.Tool != tt.tool || pin.Version != tt.version ||
+				strings.Join(pin.Managers, ",") != strings.Join(tt.managers, ",") {
+				t.Errorf("FindPin() = %+v", pin)
+			}
+		})
+	}
+}
+
+func TestFindPinNearest(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".tool-versions"), "claude-code 1.0.0\n")
+	writeFile(t, filepath.Join(root, "app", "mise.toml"), "[tools]\nclaude-code = \"2.0.0\"\n")
+
+	pin, err := FindPin(filepath.Join(root, "app"))
+	if err != nil || pin == nil || pin.Version != "2.0.0" {
+		t.Errorf("FindPin() = %+v, %v, expected the pin of the nearest directory", pin, err)
+	}
+}
+
+func TestPinMatches(t *testing.T) {
+	tests := []struct {
+		pinned, version string
+		expected        bool
+	}{
+		{"1.0.30", "1.0.30", true},
+		{"1.0", "1.0.30", true},
+		{"1", "1.0.30", true},
+		{"v1.0.30", "1.0.30", true},
+		{"1.0.3", "1.0.30", false},
+		{"2.0", "1.0.30", false},
+		{"latest", "1.0.30", true},
+		{"prefix:1.0", "1.0.30", true},
+	}
+	for _, tt := range tests {
+		if got := (&Pin{Version: tt.pinned}).Matches(tt.version); got != tt.expected {
+			t.Errorf("Pin{%q}.Matches(%q) = %v, expected %v", tt.pinned, tt.version, got, tt.expected)
+		}
+	}
+}
+
+func TestPinWhich(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the version manager")
+	}
+	bin := t.TempDir()
+	script := "#!/bin/sh\n[ \"$1 $2\" = 'which claude' ] && [ -f .tool-versions ] && echo /opt/mise/claude-code/1.0.30/bin/claude\n"
+	if err := os.WriteFile(filepath.Join(bin, "mise"), []byte(script), 0o755); err != nil { // #nosec G306 -- test script
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".tool-versions"), "claude-code 1.0.30\n")
+	pin := &Pin{Version: "1.0.30", Managers: []string{"asdf", "mise"}}
+
+	got, err := pin.Which(dir)
+	if err != nil || got != "/opt/mise/claude-code/1.0.30/bin/claude" {
+		t.Errorf("Which() = %q, %v, expected the binary from mise", got, err)
+	}
+
+	if _, err := pin.Which(t.TempDir()); err == nil {
+		t.Error("Which() should fail when the manager resolves nothing")
+	}
+	if _, err := (&Pin{Managers: []string{"asdf"}}).Which(dir); err == nil || !strings.Contains(err.Error(), "asdf is not installed") {
+		t.Errorf("Which() error = %v, expected the missing manager", err)
+	}
+}
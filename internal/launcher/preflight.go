@@ -0,0 +1,191 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/log"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// ErrClaudeNotFound is returned when the claude binary cannot be found or executed
+var ErrClaudeNotFound = errors.New("claude binary not found")
+
+// VersionTooOldError is returned when the installed claude is older than required
+type VersionTooOldError struct {
+	Found   string
+	Minimum string
+}
+
+func (e *VersionTooOldError) Error() string {
+	return fmt.Sprintf("claude %s is older than the required minimum %s", e.Found, e.Minimum)
+}
+
+// BinaryInfo describes the resolved claude binary
+type BinaryInfo struct {
+	Path    string
+	Version string // Empty unless the version was checked
+}
+
+// versionPattern matches the first dotted version number in `claude --version` output
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)+`)
+
+// ResolveBinary returns the first candidate that resolves to an executable.
+// Candidates may be command names (looked up in PATH) or paths (with ~ expanded).
+func (l *Launcher) ResolveBinary() (string, error) {
+	candidates := l.Candidates
+	if len(candidates) == 0 {
+		candidates = []string{l.ClaudePath}
+	}
+
+	for _, candidate := range candidates {
+		expanded, err := config.ExpandPath(candidate)
+		if err != nil {
+			log.Debug("binary candidate skipped", "candidate", candidate, "error", err)
+			continue
+		}
+		if path, err := exec.LookPath(expanded); err == nil {
+			log.Debug("binary resolved", "candidate", candidate, "path", path)
+			return path, nil
+		}
+		log.Debug("binary not found", "candidate", candidate)
+	}
+
+	return "", fmt.Errorf("%w (tried %s)", ErrClaudeNotFound, strings.Join(candidates, ", "))
+}
+
+// Preflight verifies that the claude binary exists and is executable, and makes the
+// resolved binary the one that will be launched.
+// If minVersion is non-empty, it also runs `claude --version` and checks the result.
+func (l *Launcher) Preflight(minVersion string) (*BinaryInfo, error) {
+	l.progress("Looking for %s", l.tool().Name())
+	path, err := l.ResolveBinary()
+	if err != nil {
+		return nil, err
+	}
+	l.ClaudePath, l.ClaudeArgs = resolveShim(path)
+
+	info := &BinaryInfo{Path: path}
+	if minVersion == "" {
+		return info, nil
+	}
+
+	l.progress("Checking the %s version", l.tool().Name())
+	version, err := CachedClaudeVersion(l.Versions, path)
+	if err != nil {
+		return nil, err
+	}
+	info.Version = version
+
+	if CompareVersions(version, minVersion) < 0 {
+		return nil, &VersionTooOldError{Found: version, Minimum: minVersion}
+	}
+
+	return info, nil
+}
+
+// progress reports a pre-flight step to Progress, if set
+func (l *Launcher) progress(format string, args ...any) {
+	if l.Progress != nil {
+		l.Progress(format, args...)
+	}
+}
+
+// ClaudeVersion runs `<path> --version` and extracts the version number
+func ClaudeVersion(path string) (string, error) {
+	// #nosec G204 -- path is the resolved claude binary
+	out, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s --version: %w", path, err)
+	}
+
+	version := versionPattern.FindString(string(out))
+	if version == "" {
+		return "", fmt.Errorf("failed to parse claude version from %q", strings.TrimSpace(string(out)))
+	}
+
+	return version, nil
+}
+
+// versionCacheFile remembers the versions of binaries in the state directory
+const versionCacheFile = "versions.json"
+
+// versionCacheEntry is the version of a binary, valid while the binary is unchanged
+type versionCacheEntry struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Version string    `json:"version"`
+}
+
+// CachedClaudeVersion is ClaudeVersion, remembered in store until the binary changes: starting
+// claude (a Node.js program) only to print its version takes longer than the rest of a launch.
+// Version manager shims, which run a different version depending on the directory, are not
+// cached; neither is anything without a store.
+func CachedClaudeVersion(store *state.Store, path string) (string, error) {
+	target, err := filepath.EvalSymlinks(path)
+	if store == nil || err != nil || filepath.Base(filepath.Dir(target)) == "shims" {
+		return ClaudeVersion(path)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return ClaudeVersion(path)
+	}
+
+	cache := map[string]versionCacheEntry{}
+	if err := store.ReadJSON(versionCacheFile, &cache); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Debug("version cache ignored", "error", err)
+	}
+	if e, ok := cache[target]; ok && e.Size == info.Size() && e.ModTime.Equal(info.ModTime()) {
+		log.Debug("binary version cached", "path", target, "version", e.Version)
+		return e.Version, nil
+	}
+
+	version, err := ClaudeVersion(path)
+	if err != nil {
+		return "", err
+	}
+	cache[target] = versionCacheEntry{Size: info.Size(), ModTime: info.ModTime(), Version: version}
+	if err := store.WriteJSON(versionCacheFile, cache); err != nil {
+		log.Debug("version cache not written", "error", err)
+	}
+	return version, nil
+}
+
+// CompareVersions compares dotted version numbers numerically.
+// It returns -1 if a < b, 0 if a == b, and 1 if a > b. Missing components count as 0.
+func CompareVersions(a, b string) int {
+	partsA := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	partsB := strings.Split(strings.TrimPrefix(b, "v"), ".")
+
+	for i := range max(len(partsA), len(partsB)) {
+		na, nb := versionPart(partsA, i), versionPart(partsB, i)
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func versionPart(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	n, err := strconv.Atoi(parts[i])
+	if err != nil {
+		return 0
+	}
+	return n
+}
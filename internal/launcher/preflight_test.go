@@ -0,0 +1,200 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// fakeClaude writes an executable script printing versionOutput and returns its path
+func fakeClaude(t *testing.T, versionOutput string) string {
+	t.Helper()
+	name, script := "claude", "#!/bin/sh\necho '"+versionOutput+"'\n"
+	if runtime.GOOS == "windows" {
+		name, script = "claude.cmd", "@echo off\r\necho "+versionOutput+"\r\n"
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatalf("failed to create fake claude: %v", err)
+	}
+	return path
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "1.0.0", b: "1.0.0", expected: 0},
+		{a: "1.0.10", b: "1.0.9", expected: 1},
+		{a: "1.2", b: "1.2.0", expected: 0},
+		{a: "0.9.9", b: "1.0.0", expected: -1},
+		{a: "v2.1.0", b: "2.0.5", expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			if got := CompareVersions(tt.a, tt.b); got != tt.expected {
+				t.Errorf("CompareVersions(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	path := fakeClaude(t, "2.0.14 (Claude Code)")
+
+	tests := []struct {
+		name        string
+		claudePath  string
+		minVersion  string
+		wantVersion string
+		wantErr     error
+		wantTooOld  bool
+	}{
+		{name: "found without version check", claudePath: path},
+		{name: "meets minimum", claudePath: path, minVersion: "2.0.0", wantVersion: "2.0.14"},
+		{name: "too old", claudePath: path, minVersion: "2.1.0", wantTooOld: true},
+		{name: "not found", claudePath: filepath.Join(t.TempDir(), "missing"), wantErr: ErrClaudeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Launcher{ClaudePath: tt.claudePath}
+			info, err := l.Preflight(tt.minVersion)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Preflight() error = %v, expected %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			var tooOld *VersionTooOldError
+			if tt.wantTooOld {
+				if !errors.As(err, &tooOld) {
+					t.Errorf("Preflight() error = %v, expected VersionTooOldError", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Preflight() error = %v", err)
+			}
+			if info.Path != tt.claudePath {
+				t.Errorf("Path = %q, expected %q", info.Path, tt.claudePath)
+			}
+			if info.Version != tt.wantVersion {
+				t.Errorf("Version = %q, expected %q", info.Version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestPreflightProgress(t *testing.T) {
+	var steps []string
+	l := &Launcher{ClaudePath: fakeClaude(t, "2.0.14 (Claude Code)")}
+	l.Progress = func(format string, args ...any) {
+		steps = append(steps, fmt.Sprintf(format, args...))
+	}
+
+	if _, err := l.Preflight("2.0.0"); err != nil {
+		t.Fatalf("Preflight() error = %v", err)
+	}
+
+	expected := []string{"Looking for claude", "Checking the claude version"}
+	if !reflect.DeepEqual(steps, expected) {
+		t.Errorf("progress steps = %v, expected %v", steps, expected)
+	}
+}
+
+func TestClaudeVersionUnparseable(t *testing.T) {
+	path := fakeClaude(t, "unknown")
+	if _, err := ClaudeVersion(path); err == nil {
+		t.Error("ClaudeVersion() should fail on output without a version number")
+	}
+}
+
+func TestResolveBinary(t *testing.T) {
+	path := fakeClaude(t, "2.0.14")
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	l := &Launcher{ClaudePath: "claude", Candidates: []string{missing, path}}
+	got, err := l.ResolveBinary()
+	if err != nil {
+		t.Fatalf("ResolveBinary() error = %v", err)
+	}
+	if got != path {
+		t.Errorf("ResolveBinary() = %q, expected %q", got, path)
+	}
+
+	l.Candidates = []string{missing}
+	if _, err := l.ResolveBinary(); !errors.Is(err, ErrClaudeNotFound) {
+		t.Errorf("ResolveBinary() error = %v, expected %v", err, ErrClaudeNotFound)
+	}
+}
+
+func TestCachedClaudeVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh scripts")
+	}
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	path := filepath.Join(dir, "claude")
+	write := func(version string) {
+		t.Helper()
+		script := "#!/bin/sh\necho run >> '" + runs + "'\necho '" + version + " (Claude Code)'\n"
+		if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	countRuns := func() int {
+		data, _ := os.ReadFile(runs) //nolint:errcheck // no file means no runs
+		return len(data) / len("run\n")
+	}
+	store := &state.Store{Dir: filepath.Join(dir, "state")}
+
+	write("1.0.30")
+	for range 2 {
+		version, err := CachedClaudeVersion(store, path)
+		if err != nil || version != "1.0.30" {
+			t.Fatalf("CachedClaudeVersion() = %q, %v, want 1.0.30", version, err)
+		}
+	}
+	if n := countRuns(); n != 1 {
+		t.Errorf("claude ran %d times, want once", n)
+	}
+
+	// An update replaces the binary
+	write("1.0.100")
+	if version, err := CachedClaudeVersion(store, path); err != nil || version != "1.0.100" {
+		t.Errorf("CachedClaudeVersion() after an update = %q, %v, want 1.0.100", version, err)
+	}
+
+	if version, err := CachedClaudeVersion(nil, path); err != nil || version != "1.0.100" || countRuns() != 3 {
+		t.Errorf("CachedClaudeVersion(nil) = %q, %v, want an uncached run", version, err)
+	}
+}
+
+func BenchmarkCachedClaudeVersion(b *testing.B) {
+	if runtime.GOOS == "windows" {
+		b.Skip("uses sh scripts")
+	}
+	dir := b.TempDir()
+	path := filepath.Join(dir, "claude")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho '1.0.30 (Claude Code)'\n"), 0o755); err != nil {
+		b.Fatal(err)
+	}
+	store := &state.Store{Dir: dir}
+	for b.Loop() {
+		if _, err := CachedClaudeVersion(store, path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
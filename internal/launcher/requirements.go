@@ -0,0 +1,120 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+)
+
+// requirementTimeout bounds each command a requirement check runs
+const requirementTimeout = 10 * time.Second
+
+// Problems of a requirement that is not met
+const (
+	ProblemNotFound  = "not found"  // The command is not on PATH
+	ProblemTooOld    = "too old"    // The command is older than the minimum version
+	ProblemNoVersion = "no version" // No version could be read from `<command> --version` or `<command> version`
+	ProblemMissing   = "missing"    // The file does not exist
+	ProblemFailed    = "failed"     // The run command failed or timed out
+)
+
+// RequirementFailure is a requirement of a project that is not met
+type RequirementFailure struct {
+	Requirement config.Requirement
+	Problem     string
+	Detail      string // The version found, or the last line the run command printed
+}
+
+// CheckRequirements checks reqs for a launch in dir and returns those not met, in order.
+// progress, when set, is told which requirement is being checked.
+func CheckRequirements(dir string, reqs []config.Requirement, progress func(format string, args ...any)) []RequirementFailure {
+	var failures []RequirementFailure
+	for _, req := range reqs {
+		if progress != nil {
+			progress("Checking %s", req.String())
+		}
+		if problem, detail := checkRequirement(dir, req); problem != "" {
+			failures = append(failures, RequirementFailure{Requirement: req, Problem: problem, Detail: detail})
+		}
+	}
+	return failures
+}
+
+// checkRequirement returns the problem with req and its detail, or "" when it is met
+func checkRequirement(dir string, req config.Requirement) (problem, detail string) {
+	switch {
+	case req.Command != "":
+		path, err := exec.LookPath(req.Command)
+		if err != nil {
+			return ProblemNotFound, ""
+		}
+		if req.Version == "" {
+			return "", ""
+		}
+		out, _ := runRequirement(dir, path, "--version") //nolint:errcheck // some tools exit non-zero after printing their version
+		version := versionPattern.FindString(out)
+		if version == "" {
+			// Tools like go only know a version subcommand
+			if alt, _ := runRequirement(dir, path, "version"); alt != "" { //nolint:errcheck // as above
+				out, version = alt, versionPattern.FindString(alt)
+			}
+		}
+		switch {
+		case version == "":
+			return ProblemNoVersion, lastLine(out)
+		case CompareVersions(version, req.MinVersion()) < 0:
+			return ProblemTooOld, version
+		}
+	case req.File != "":
+		path, err := config.ExpandPath(req.File)
+		if err != nil {
+			return ProblemMissing, err.Error()
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return ProblemMissing, ""
+		}
+	case req.Run != "":
+		shell, flag := "sh", "-c"
+		if runtime.GOOS == "windows" {
+			shell, flag = "cmd", "/C"
+		}
+		if out, err := runRequirement(dir, shell, flag, req.Run); err != nil {
+			if line := lastLine(out); line != "" {
+				return ProblemFailed, line
+			}
+			return ProblemFailed, err.Error()
+		}
+	}
+	return "", ""
+}
+
+// runRequirement runs a command of a requirement check in dir and returns what it printed
+func runRequirement(dir, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requirementTimeout)
+	defer cancel()
+
+	// #nosec G204 -- the command comes from the user's own config
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", ctx.Err()
+	}
+	return string(out), err
+}
+
+// lastLine returns the last non-empty line of s
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
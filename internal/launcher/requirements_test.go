@@ -0,0 +1,59 @@
+package launcher
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
+)
+
+func TestCheckRequirements(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh scripts")
+	}
+	bin := t.TempDir()
+	node := "#!/bin/sh\necho v18.19.0\n"
+	if err := os.WriteFile(filepath.Join(bin, "node"), []byte(node), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := []config.Requirement{
+		{Command: "node", Version: ">=18"},
+		{Command: "node", Version: ">= 20"},
+		{Command: "definitely-not-installed"},
+		{File: ".env"},
+		{File: ".env.local"},
+		{Run: "true"},
+		{Run: "echo starting; echo 'daemon not running' >&2; exit 1", Name: "docker running"},
+	}
+	var checked []string
+	failures := CheckRequirements(dir, reqs, func(format string, args ...any) {
+		checked = append(checked, args[0].(string))
+	})
+	if len(checked) != len(reqs) {
+		t.Errorf("progress reported %v, want one entry per requirement", checked)
+	}
+
+	want := []RequirementFailure{
+		{Requirement: reqs[1], Problem: ProblemTooOld, Detail: "18.19.0"},
+		{Requirement: reqs[2], Problem: ProblemNotFound},
+		{Requirement: reqs[4], Problem: ProblemMissing},
+		{Requirement: reqs[6], Problem: ProblemFailed, Detail: "daemon not running"},
+	}
+	if len(failures) != len(want) {
+		t.Fatalf("CheckRequirements() = %+v, want %+v", failures, want)
+	}
+	for i := range want {
+		if failures[i] != want[i] {
+			t.Errorf("failure %d = %+v, want %+v", i, failures[i], want[i])
+		}
+	}
+}
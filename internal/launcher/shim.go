@@ -0,0 +1,51 @@
+package launcher
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// npmShimScript matches the script started by an npm-generated .cmd shim, e.g.
+// "%dp0%\node_modules\@anthropic-ai\claude-code\cli.js"
+var npmShimScript = regexp.MustCompile(`"%~?dp0%?\\([^"]+\.[cm]?js)"`)
+
+// resolveShim looks through an npm .cmd/.bat shim on Windows and returns node plus the
+// script it would run. Running node directly avoids cmd.exe re-parsing claude's
+// arguments (quotes, &, | in prompts) and lets console signals reach claude.
+// Anything that is not a recognizable shim is returned unchanged.
+func resolveShim(path string) (string, []string) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".cmd" && ext != ".bat" {
+		return path, nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return path, nil
+	}
+
+	m := npmShimScript.FindSubmatch(data)
+	if m == nil {
+		return path, nil
+	}
+
+	dir := filepath.Dir(path)
+	script := filepath.Join(dir, filepath.FromSlash(strings.ReplaceAll(string(m[1]), `\`, "/")))
+	if _, err := os.Stat(script); err != nil {
+		return path, nil
+	}
+
+	// Like the shim: prefer a node.exe next to it, then node on PATH
+	node := filepath.Join(dir, "node.exe")
+	if _, err := os.Stat(node); err != nil {
+		node, err = exec.LookPath("node")
+		if err != nil {
+			return path, nil
+		}
+	}
+
+	return node, []string{script}
+}
@@ -0,0 +1,96 @@
+package launcher
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
+)
+
+const npmShim = `@ECHO off
+GOTO start
+:find_dp0
+SET dp0=%~dp0
+EXIT /b
+:start
+SETLOCAL
+CALL :find_dp0
+
+IF EXIST "%dp0%\node.exe" (
+  SET "_prog=%dp0%\node.exe"
+) ELSE (
+  SET "_prog=node"
+  SET PATHEXT=%PATHEXT:;.JS;=;%
+)
+
+endLocal & goto #_undefined_# 2>NUL || title %COMSPEC% & "%_prog%"  "%dp0%\node_modules\@anthropic-ai\claude-code\cli.js" %*
+`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestResolveShim(t *testing.T) {
+	dir := t.TempDir()
+	shim := filepath.Join(dir, "claude.cmd")
+	script := filepath.Join(dir, "node_modules", "@anthropic-ai", "claude-code", "cli.js")
+	node := filepath.Join(dir, "node.exe")
+	writeFile(t, shim, npmShim)
+	writeFile(t, script, "")
+	writeFile(t, node, "")
+
+	path, args := resolveShim(shim)
+	if path != node {
+		t.Errorf("path = %q, expected %q", path, node)
+	}
+	if !slices.Equal(args, []string{script}) {
+		t.Errorf("args = %v, expected [%s]", args, script)
+	}
+}
+
+func TestResolveShimUnchanged(t *testing.T) {
+	dir := t.TempDir()
+
+	// Not a shim
+	exe := filepath.Join(dir, "claude.exe")
+	writeFile(t, exe, "")
+
+	// A .cmd file that does not start a script
+	custom := filepath.Join(dir, "custom.cmd")
+	writeFile(t, custom, "@echo off\r\nclaude-real.exe %*\r\n")
+
+	// A shim whose script is missing
+	broken := filepath.Join(dir, "broken.cmd")
+	writeFile(t, broken, npmShim)
+
+	for _, path := range []string{exe, custom, broken} {
+		got, args := resolveShim(path)
+		if got != path || args != nil {
+			t.Errorf("resolveShim(%q) = %q, %v; expected it unchanged", path, got, args)
+		}
+	}
+}
+
+func TestPrepareKeepsClaudeArgsFirst(t *testing.T) {
+	l := &Launcher{ClaudePath: "node", ClaudeArgs: []string{"cli.js"}}
+	c, err := l.Prepare(LaunchOptions{
+		Continue:   true,
+		MCPServers: map[string]config.MCPServer{"github": {Command: "github-mcp-server"}},
+	})
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	defer c.Cleanup()
+
+	if len(c.Args) < 3 || c.Args[0] != "cli.js" || c.Args[1] != "--mcp-config" {
+		t.Errorf("Args = %v, expected the shim script before claude's arguments", c.Args)
+	}
+}
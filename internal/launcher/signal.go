@@ -0,0 +1,17 @@
+package launcher
+
+import (
+	"os"
+	"os/signal"
+)
+
+// ignoreInterrupts keeps Ctrl-C (and Ctrl-Break on Windows) from terminating the launcher
+// while claude runs in the foreground. The console delivers the event to claude as well,
+// which decides what to do with it; the launcher stays alive to clean up and report
+// claude's exit. The returned function restores the default behavior.
+func ignoreInterrupts() (restore func()) {
+	// Signals are delivered without blocking, so an unread channel simply swallows them
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt)
+	return func() { signal.Stop(ch) }
+}
@@ -0,0 +1,140 @@
+package launcher
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Stats summarizes a launch history
+type Stats struct {
+	Launches  int
+	Projects  []Count // Launches per project, most used first
+	Accounts  []Count // Launches per account ("" for the default configuration)
+	Presets   []Count // Launches per permission preset; launches without one are not counted
+	WeekTime  time.Duration
+	MonthTime time.Duration
+}
+
+// Count is the number of launches for one name
+type Count struct {
+	Name     string `json:"name"`
+	Launches int    `json:"launches"`
+}
+
+// Summarize computes Stats for records as of now. project maps a launch directory to the
+// project it is counted under. Session time counts the sessions started since Monday and since
+// the first of the month (local time) whose end was observed.
+func Summarize(records []LaunchRecord, now time.Time, project func(dir string) string) Stats {
+	year, month, day := now.Date()
+	monthStart := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	weekStart := time.Date(year, month, day-(int(now.Weekday())+6)%7, 0, 0, 0, 0, now.Location())
+
+	projects := map[string]int{}
+	accounts := map[string]int{}
+	presets := map[string]int{}
+	s := Stats{Launches: len(records)}
+	for _, rec := range records {
+		projects[project(rec.Dir)]++
+		accounts[rec.Account]++
+		if rec.Preset != "" {
+			presets[rec.Preset]++
+		}
+
+		if !rec.StartedAt.Before(weekStart) {
+			s.WeekTime += rec.Duration()
+		}
+		if !rec.StartedAt.Before(monthStart) {
+			s.MonthTime += rec.Duration()
+		}
+	}
+
+	s.Projects = sortedCounts(projects)
+	s.Accounts = sortedCounts(accounts)
+	s.Presets = sortedCounts(presets)
+	return s
+}
+
+// sortedCounts returns counts ordered by launches (descending), then by name
+func sortedCounts(counts map[string]int) []Count {
+	out := make([]Count, 0, len(counts))
+	for _, name := range slices.Sorted(maps.Keys(counts)) {
+		out = append(out, Count{Name: name, Launches: counts[name]})
+	}
+	slices.SortStableFunc(out, func(a, b Count) int { return cmp.Compare(b.Launches, a.Launches) })
+	return out
+}
+
+// CostStats sums the usage recorded in a launch history
+type CostStats struct {
+	Sessions int // Launches with recorded usage
+	Total    Tokens
+	Projects []TokenCount // Usage per project, most expensive first
+	Accounts []TokenCount // Usage per account ("" for the default configuration)
+}
+
+// TokenCount is the usage of the sessions of one name
+type TokenCount struct {
+	Name     string `json:"name"`
+	Sessions int    `json:"sessions"`
+	Tokens
+}
+
+// SummarizeCost computes CostStats for records. project maps a launch directory to the project
+// it is counted under. Launches without recorded usage (other tools, tmux) are left out.
+func SummarizeCost(records []LaunchRecord, project func(dir string) string) CostStats {
+	projects := map[string]*TokenCount{}
+	accounts := map[string]*TokenCount{}
+	add := func(counts map[string]*TokenCount, name string, t Tokens) {
+		c, ok := counts[name]
+		if !ok {
+			c = &TokenCount{Name: name}
+			counts[name] = c
+		}
+		c.Sessions++
+		c.Add(t)
+	}
+
+	var c CostStats
+	for _, rec := range records {
+		if rec.Usage == nil {
+			continue
+		}
+		c.Sessions++
+		c.Total.Add(*rec.Usage)
+		add(projects, project(rec.Dir), *rec.Usage)
+		add(accounts, rec.Account, *rec.Usage)
+	}
+	c.Projects = sortedTokenCounts(projects)
+	c.Accounts = sortedTokenCounts(accounts)
+	return c
+}
+
+// sortedTokenCounts returns counts ordered by cost, then by tokens (both descending), then by name
+func sortedTokenCounts(counts map[string]*TokenCount) []TokenCount {
+	out := make([]TokenCount, 0, len(counts))
+	for _, name := range slices.Sorted(maps.Keys(counts)) {
+		out = append(out, *counts[name])
+	}
+	slices.SortStableFunc(out, func(a, b TokenCount) int {
+		return cmp.Or(cmp.Compare(b.CostUSD, a.CostUSD), cmp.Compare(b.Total(), a.Total()))
+	})
+	return out
+}
+
+// Recent returns the latest launch of each directory, newest first, at most n of them.
+// Headless runs are left out: they are not sessions to go back to.
+func Recent(records []LaunchRecord, n int) []LaunchRecord {
+	var recent []LaunchRecord
+	for _, rec := range slices.Backward(records) {
+		if len(recent) == n {
+			break
+		}
+		if rec.Mode == ModeHeadless || slices.ContainsFunc(recent, func(r LaunchRecord) bool { return r.Dir == rec.Dir }) {
+			continue
+		}
+		recent = append(recent, rec)
+	}
+	return recent
+}
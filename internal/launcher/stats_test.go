@@ -0,0 +1,109 @@
+package launcher
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	session := func(dir, account, preset string, startedAt time.Time, d time.Duration) LaunchRecord {
+		rec := LaunchRecord{Dir: dir, Account: account, Preset: preset, StartedAt: startedAt}
+		if d > 0 {
+			rec.EndedAt = startedAt.Add(d)
+		}
+		return rec
+	}
+	records := []LaunchRecord{
+		session("/work/api", "Work", "review", now.Add(-time.Hour), 30*time.Minute),                         // This week
+		session("/work/api/internal", "Work", "", time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC), time.Hour), // Monday
+		session("/home/me/blog", "", "review", time.Date(2026, 10, 11, 9, 0, 0, 0, time.UTC), 2*time.Hour),  // Last Sunday
+		session("/home/me/blog", "Personal", "yolo", time.Date(2026, 9, 30, 9, 0, 0, 0, time.UTC), 4*time.Hour),
+		session("/work/api", "Work", "", now.Add(-time.Minute), 0), // tmux: end not observed
+	}
+
+	// Directories under /work/api belong to that project
+	project := func(dir string) string {
+		if dir == "/work/api" || filepath.Dir(dir) == "/work/api" {
+			return "/work/api"
+		}
+		return dir
+	}
+	s := Summarize(records, now, project)
+
+	if s.Launches != 5 {
+		t.Errorf("Launches = %d, expected 5", s.Launches)
+	}
+	expectCounts(t, "Projects", s.Projects, []Count{{"/work/api", 3}, {"/home/me/blog", 2}})
+	expectCounts(t, "Accounts", s.Accounts, []Count{{"Work", 3}, {"", 1}, {"Personal", 1}})
+	expectCounts(t, "Presets", s.Presets, []Count{{"review", 2}, {"yolo", 1}})
+
+	if s.WeekTime != 90*time.Minute {
+		t.Errorf("WeekTime = %v, expected 1h30m", s.WeekTime)
+	}
+	if s.MonthTime != 210*time.Minute {
+		t.Errorf("MonthTime = %v, expected 3h30m", s.MonthTime)
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	s := Summarize(nil, time.Now(), func(dir string) string { return dir })
+	if s.Launches != 0 || s.Projects == nil || len(s.Projects) != 0 || s.WeekTime != 0 {
+		t.Errorf("Summarize(nil) = %+v, expected zero stats with empty lists", s)
+	}
+}
+
+func expectCounts(t *testing.T, name string, got, expected []Count) {
+	t.Helper()
+	if !slices.Equal(got, expected) {
+		t.Errorf("%s = %v, expected %v", name, got, expected)
+	}
+}
+
+func TestRecent(t *testing.T) {
+	start := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	records := []LaunchRecord{
+		{Dir: "/work/api", Account: "Personal", StartedAt: start},
+		{Dir: "/home/me/blog", StartedAt: start.Add(time.Hour)},
+		{Dir: "/work/api", Account: "Work", Continue: true, StartedAt: start.Add(2 * time.Hour)},
+		{Dir: "/work/web", Mode: ModeHeadless, StartedAt: start.Add(3 * time.Hour)},
+		{Dir: "/work/cli", Mode: ModeTmux, StartedAt: start.Add(4 * time.Hour)},
+	}
+
+	var got []string
+	for _, rec := range Recent(records, 10) {
+		got = append(got, rec.Dir+" "+rec.Account)
+	}
+	if want := []string{"/work/cli ", "/work/api Work", "/home/me/blog "}; !slices.Equal(got, want) {
+		t.Errorf("Recent() = %q, want %q", got, want)
+	}
+	if recent := Recent(records, 1); len(recent) != 1 || recent[0].Dir != "/work/cli" {
+		t.Errorf("Recent(records, 1) = %+v, expected the latest launch only", recent)
+	}
+}
+
+func TestSummarizeCost(t *testing.T) {
+	usage := func(dir, account string, tokens, cost float64) LaunchRecord {
+		return LaunchRecord{Dir: dir, Account: account, Usage: &Tokens{Input: int(tokens), CostUSD: cost}}
+	}
+	records := []LaunchRecord{
+		usage("/work/api", "Work", 1000, 0.5),
+		usage("/work/api", "Work", 3000, 1.5),
+		usage("/home/me/blog", "", 5000, 1),
+		{Dir: "/work/api", Account: "Work"}, // tmux or another tool: no usage
+	}
+
+	c := SummarizeCost(records, func(dir string) string { return dir })
+	if c.Sessions != 3 || c.Total.Input != 9000 || c.Total.CostUSD != 3 {
+		t.Errorf("SummarizeCost() = %d sessions, %+v, expected 3 sessions, 9000 tokens and $3", c.Sessions, c.Total)
+	}
+	if len(c.Projects) != 2 || c.Projects[0].Name != "/work/api" || c.Projects[0].Sessions != 2 || c.Projects[0].Input != 4000 {
+		t.Errorf("Projects = %+v, expected /work/api first with 2 sessions", c.Projects)
+	}
+	if len(c.Accounts) != 2 || c.Accounts[0].Name != "Work" || c.Accounts[1].Name != "" {
+		t.Errorf("Accounts = %+v, expected Work before the default configuration", c.Accounts)
+	}
+}
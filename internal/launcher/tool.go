@@ -0,0 +1,132 @@
+package launcher
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Tool describes how to launch an agent CLI.
+// The directory security, account and session machinery is shared; a Tool maps its
+// concepts (continue, model, config dir, MCP config) onto the CLI's own flags.
+type Tool interface {
+	// Name is the identifier used with --tool and in config
+	Name() string
+	// Binary is the default executable name
+	Binary() string
+	// Args builds the command-line arguments for opts
+	Args(opts LaunchOptions) []string
+	// CanContinue reports whether the tool can resume its previous session
+	CanContinue() bool
+	// CanPick reports whether the tool can let the user choose the session to resume
+	CanPick() bool
+	// ConfigDirEnv is the environment variable selecting the config directory ("" if unsupported)
+	ConfigDirEnv() string
+	// MCPConfigFlag is the flag that loads an MCP config file ("" if unsupported)
+	MCPConfigFlag() string
+	// SettingsFlag is the flag that loads a Claude Code settings file ("" if unsupported)
+	SettingsFlag() string
+}
+
+// cliTool is a Tool described by its command-line conventions
+type cliTool struct {
+	name          string
+	binary        string
+	continueArgs  []string // Placed first, so they may start with a subcommand
+	pickArgs      []string // Like continueArgs, but open the tool's session picker
+	modelFlag     string
+	addDirFlag    string // Grants access to an extra directory; passed as flag=dir
+	configDirEnv  string
+	mcpConfigFlag string
+	settingsFlag  string
+}
+
+func (t *cliTool) Name() string          { return t.name }
+func (t *cliTool) Binary() string        { return t.binary }
+func (t *cliTool) CanContinue() bool     { return len(t.continueArgs) > 0 }
+func (t *cliTool) CanPick() bool         { return len(t.pickArgs) > 0 }
+func (t *cliTool) ConfigDirEnv() string  { return t.configDirEnv }
+func (t *cliTool) MCPConfigFlag() string { return t.mcpConfigFlag }
+func (t *cliTool) SettingsFlag() string  { return t.settingsFlag }
+
+func (t *cliTool) Args(opts LaunchOptions) []string {
+	args := make([]string, 0, len(opts.Args)+len(t.continueArgs)+2)
+
+	switch {
+	case opts.Pick && t.CanPick():
+		args = append(args, t.pickArgs...)
+	case opts.Continue:
+		args = append(args, t.continueArgs...)
+	}
+
+	if opts.Model != "" && t.modelFlag != "" {
+		args = append(args, t.modelFlag, opts.Model)
+	}
+
+	if t.addDirFlag != "" {
+		// The "=" form keeps variadic flags (claude's --add-dir) from consuming the user's arguments
+		for _, dir := range opts.AddDirs {
+			args = append(args, t.addDirFlag+"="+dir)
+		}
+	}
+
+	return append(args, opts.Args...)
+}
+
+// Claude is Claude Code, the default tool
+var Claude Tool = &cliTool{
+	name:          "claude",
+	binary:        "claude",
+	continueArgs:  []string{"--continue"},
+	pickArgs:      []string{"--resume"},
+	modelFlag:     "--model",
+	addDirFlag:    "--add-dir",
+	configDirEnv:  "CLAUDE_CONFIG_DIR",
+	mcpConfigFlag: "--mcp-config",
+	settingsFlag:  "--settings",
+}
+
+// tools are the supported tools by name
+var tools = map[string]Tool{
+	Claude.Name(): Claude,
+	"gemini": &cliTool{
+		name:         "gemini",
+		binary:       "gemini",
+		continueArgs: []string{"--resume", "latest"},
+		modelFlag:    "--model",
+		addDirFlag:   "--include-directories",
+	},
+	"codex": &cliTool{
+		name:         "codex",
+		binary:       "codex",
+		continueArgs: []string{"resume", "--last"},
+		pickArgs:     []string{"resume"},
+		modelFlag:    "--model",
+		addDirFlag:   "--add-dir",
+		configDirEnv: "CODEX_HOME",
+	},
+	"aider": &cliTool{
+		name:         "aider",
+		binary:       "aider",
+		continueArgs: []string{"--restore-chat-history"},
+		modelFlag:    "--model",
+	},
+}
+
+// LookupTool returns the tool called name. An empty name selects Claude.
+func LookupTool(name string) (Tool, error) {
+	if name == "" {
+		return Claude, nil
+	}
+	tool, ok := tools[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tool %q (available: %s)", name, strings.Join(ToolNames(), ", "))
+	}
+	return tool, nil
+}
+
+// ToolNames returns the names of all supported tools, sorted
+func ToolNames() []string {
+	return slices.Sorted(maps.Keys(tools))
+}
@@ -0,0 +1,117 @@
+package launcher
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/config"
+)
+
+func TestLookupTool(t *testing.T) {
+	tool, err := LookupTool("")
+	if err != nil || tool != Claude {
+		t.Errorf("LookupTool(\"\") = (%v, %v), expected Claude", tool, err)
+	}
+
+	for _, name := range ToolNames() {
+		tool, err := LookupTool(name)
+		if err != nil {
+			t.Fatalf("LookupTool(%q) error = %v", name, err)
+		}
+		if tool.Name() != name {
+			t.Errorf("LookupTool(%q).Name() = %q", name, tool.Name())
+		}
+	}
+
+	if _, err := LookupTool("unknown"); err == nil {
+		t.Error("LookupTool() should fail for an unknown tool")
+	}
+}
+
+func TestToolArgs(t *testing.T) {
+	opts := LaunchOptions{Continue: true, Model: "o3", Args: []string{"--verbose"}}
+
+	tests := []struct {
+		tool     string
+		expected []string
+	}{
+		{tool: "codex", expected: []string{"resume", "--last", "--model", "o3", "--verbose"}},
+		{tool: "aider", expected: []string{"--restore-chat-history", "--model", "o3", "--verbose"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			tool, err := LookupTool(tt.tool)
+			if err != nil {
+				t.Fatalf("LookupTool() error = %v", err)
+			}
+			if result := tool.Args(opts); !slices.Equal(result, tt.expected) {
+				t.Errorf("Args() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToolArgsPick(t *testing.T) {
+	opts := LaunchOptions{Continue: true, Pick: true}
+
+	tests := []struct {
+		tool     string
+		expected []string
+	}{
+		{tool: "claude", expected: []string{"--resume"}},
+		{tool: "codex", expected: []string{"resume"}},
+		{tool: "gemini", expected: []string{"--resume", "latest"}}, // No picker: continues the latest
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			tool, err := LookupTool(tt.tool)
+			if err != nil {
+				t.Fatalf("LookupTool() error = %v", err)
+			}
+			if result := tool.Args(opts); !slices.Equal(result, tt.expected) {
+				t.Errorf("Args() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrepareUsesToolConventions(t *testing.T) {
+	codex, err := LookupTool("codex")
+	if err != nil {
+		t.Fatalf("LookupTool() error = %v", err)
+	}
+	l := &Launcher{Tool: codex, ClaudePath: "codex"}
+
+	cmd, err := l.Prepare(LaunchOptions{
+		ConfigDir:  "/tmp/codex-home",
+		MCPServers: map[string]config.MCPServer{"server": {Command: "server"}},
+	})
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	defer cmd.Cleanup()
+
+	if slices.Contains(cmd.Args, "--mcp-config") {
+		t.Errorf("Prepare() args = %v, codex does not support --mcp-config", cmd.Args)
+	}
+	if !slices.Contains(cmd.Env, "CODEX_HOME=/tmp/codex-home") {
+		t.Error("Prepare() should set CODEX_HOME from the config dir")
+	}
+	if slices.Contains(cmd.Env, "CLAUDE_CONFIG_DIR=/tmp/codex-home") {
+		t.Error("Prepare() should not set CLAUDE_CONFIG_DIR for codex")
+	}
+}
+
+func TestPrepareRejectsPermissionsForToolsWithoutSettings(t *testing.T) {
+	aider, err := LookupTool("aider")
+	if err != nil {
+		t.Fatalf("LookupTool() error = %v", err)
+	}
+	l := &Launcher{Tool: aider, ClaudePath: "aider"}
+
+	if _, err := l.Prepare(LaunchOptions{Permissions: config.Permissions{DisabledTools: []string{"Edit"}}}); err == nil {
+		t.Error("Prepare() should fail when permission rules cannot be applied")
+	}
+}
@@ -0,0 +1,238 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/home"
+)
+
+// UsageWindow is the rolling window Claude plans count usage limits in
+const UsageWindow = 5 * time.Hour
+
+// Tokens counts the tokens of API responses and their estimated cost
+type Tokens struct {
+	Input         int     `json:"input"`
+	Output        int     `json:"output"`
+	CacheCreation int     `json:"cacheCreation"`
+	CacheRead     int     `json:"cacheRead"`
+	CostUSD       float64 `json:"costUSD"` // At list prices; responses of unknown models add nothing
+}
+
+// Total returns the input, output and cache-creation tokens; cache reads cost a tenth of input
+// tokens and are left out
+func (t Tokens) Total() int {
+	return t.Input + t.Output + t.CacheCreation
+}
+
+// Add adds the counts and cost of o to t
+func (t *Tokens) Add(o Tokens) {
+	t.Input += o.Input
+	t.Output += o.Output
+	t.CacheCreation += o.CacheCreation
+	t.CacheRead += o.CacheRead
+	t.CostUSD += o.CostUSD
+}
+
+// Usage is what Claude Code transcripts record about the use of a config directory
+type Usage struct {
+	Tokens
+	Messages     int       // Responses from the API
+	LastUsed     time.Time // Time of the latest response; zero when there is none
+	LimitReached time.Time // Time of the latest usage limit error; zero when there is none
+}
+
+// modelPrices are the list prices in USD per million tokens of the Claude models, by model ID
+// prefix; the first match applies
+var modelPrices = []struct {
+	prefix                                  string
+	input, output, cacheCreation, cacheRead float64
+}{
+	{"claude-opus-4-5", 5, 25, 6.25, 0.5},
+	{"claude-opus-4", 15, 75, 18.75, 1.5},
+	{"claude-3-opus", 15, 75, 18.75, 1.5},
+	{"claude-sonnet-4", 3, 15, 3.75, 0.3},
+	{"claude-3-7-sonnet", 3, 15, 3.75, 0.3},
+	{"claude-3-5-sonnet", 3, 15, 3.75, 0.3},
+	{"claude-haiku-4-5", 1, 5, 1.25, 0.1},
+	{"claude-3-5-haiku", 0.8, 4, 1, 0.08},
+	{"claude-3-haiku", 0.25, 1.25, 0.3, 0.03},
+}
+
+// estimateCost returns the cost of t at the list prices of model, or 0 for unknown models
+func estimateCost(model string, t Tokens) float64 {
+	for _, p := range modelPrices {
+		if strings.HasPrefix(model, p.prefix) {
+			return (float64(t.Input)*p.input + float64(t.Output)*p.output +
+				float64(t.CacheCreation)*p.cacheCreation + float64(t.CacheRead)*p.cacheRead) / 1e6
+		}
+	}
+	return 0
+}
+
+// ClaudeConfigDir returns the directory Claude Code keeps its data in for configDir (an
+// account's CLAUDE_CONFIG_DIR): configDir itself, or the default when it is empty
+func ClaudeConfigDir(configDir string) string {
+	if configDir != "" {
+		return configDir
+	}
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if homeDir, err := home.Dir(); err == nil {
+		return filepath.Join(homeDir, ".claude")
+	}
+	return ""
+}
+
+// transcriptLine is the part of a line of a Claude Code transcript (projects/*/*.jsonl) read for usage
+type transcriptLine struct {
+	Type       string    `json:"type"`
+	Timestamp  time.Time `json:"timestamp"`
+	Cwd        string    `json:"cwd"`
+	RequestID  string    `json:"requestId"`
+	IsAPIError bool      `json:"isApiErrorMessage"`
+	Message    struct {
+		ID      string          `json:"id"`
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"`
+		Usage   *struct {
+			InputTokens         int `json:"input_tokens"`
+			OutputTokens        int `json:"output_tokens"`
+			CacheCreationTokens int `json:"cache_creation_input_tokens"`
+			CacheReadTokens     int `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// usageFilter selects the responses counted by readUsage
+type usageFilter struct {
+	since, until time.Time // until is open when zero
+	dir          string    // Working directory of the session; any when empty
+}
+
+// ReadUsage sums the usage recorded in the transcripts under configDir since the given time.
+// Claude Code keeps no quota locally, so this is what was used, not what is left; a missing
+// projects directory is no usage.
+func ReadUsage(configDir string, since time.Time) (Usage, error) {
+	return readUsage(configDir, usageFilter{since: since})
+}
+
+// ReadSessionUsage sums the usage recorded in the transcripts under configDir for sessions
+// working in dir between start and end. Sessions overlapping in dir are counted together.
+func ReadSessionUsage(configDir, dir string, start, end time.Time) (Usage, error) {
+	return readUsage(configDir, usageFilter{since: start, until: end, dir: dir})
+}
+
+// readUsage sums the responses matching filter. Only transcripts modified since filter.since are read.
+func readUsage(configDir string, filter usageFilter) (Usage, error) {
+	var u Usage
+	seen := map[string]bool{}
+	root := filepath.Join(configDir, "projects")
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == root {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".jsonl" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.ModTime().Before(filter.since) {
+			return nil //nolint:nilerr // transcripts removed meanwhile are skipped
+		}
+		return readTranscriptUsage(path, filter, seen, &u)
+	})
+	if err != nil {
+		return Usage{}, fmt.Errorf("failed to read usage in %s: %w", configDir, err)
+	}
+	return u, nil
+}
+
+// readTranscriptUsage adds the responses of one transcript matching filter to u.
+// A response is written as several lines (one per content block) with the same usage,
+// so responses are counted once per message and request ID in seen.
+func readTranscriptUsage(path string, filter usageFilter, seen map[string]bool, u *Usage) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck // read-only
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		data := scanner.Bytes()
+		// Most lines are user messages and tool results; skip them before decoding
+		if !bytes.Contains(data, []byte(`"assistant"`)) {
+			continue
+		}
+		var line transcriptLine
+		if err := json.Unmarshal(data, &line); err != nil || line.Type != "assistant" || !filter.matches(&line) {
+			continue
+		}
+		if line.IsAPIError {
+			if isLimitError(line.Message.Content) && line.Timestamp.After(u.LimitReached) {
+				u.LimitReached = line.Timestamp
+			}
+			continue
+		}
+		if line.Message.Usage == nil {
+			continue
+		}
+		key := line.Message.ID + "/" + line.RequestID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		usage := line.Message.Usage
+		t := Tokens{
+			Input:         usage.InputTokens,
+			Output:        usage.OutputTokens,
+			CacheCreation: usage.CacheCreationTokens,
+			CacheRead:     usage.CacheReadTokens,
+		}
+		t.CostUSD = estimateCost(line.Message.Model, t)
+		u.Add(t)
+		u.Messages++
+		if line.Timestamp.After(u.LastUsed) {
+			u.LastUsed = line.Timestamp
+		}
+	}
+	return scanner.Err()
+}
+
+// matches reports whether line falls within the period and directory of the filter
+func (f usageFilter) matches(line *transcriptLine) bool {
+	if line.Timestamp.Before(f.since) || (!f.until.IsZero() && line.Timestamp.After(f.until)) {
+		return false
+	}
+	return f.dir == "" || line.Cwd == f.dir || strings.HasPrefix(line.Cwd, f.dir+string(filepath.Separator))
+}
+
+// isLimitError reports whether the content of an API error message says a usage limit was reached
+func isLimitError(content json.RawMessage) bool {
+	var blocks []struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(content, &blocks); err != nil {
+		return false
+	}
+	for _, b := range blocks {
+		if strings.Contains(strings.ToLower(b.Text), "limit reached") {
+			return true
+		}
+	}
+	return false
+}
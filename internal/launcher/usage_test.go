@@ -0,0 +1,70 @@
+package launcher
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadUsage(t *testing.T) {
+	configDir := t.TempDir()
+	project := filepath.Join(configDir, "projects", "-work-api")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := []string{
+		`{"type":"user","timestamp":"2026-10-16T10:00:00Z","message":{"content":"fix the build"}}`,
+		// One response written as two content blocks with the same usage
+		`{"type":"assistant","timestamp":"2026-10-16T10:00:05Z","cwd":"/work/api","requestId":"req_1","message":{"id":"msg_1","model":"claude-sonnet-4-5-20250929","usage":{"input_tokens":100,"output_tokens":50,"cache_creation_input_tokens":1000,"cache_read_input_tokens":9000}}}`,
+		`{"type":"assistant","timestamp":"2026-10-16T10:00:06Z","cwd":"/work/api","requestId":"req_1","message":{"id":"msg_1","model":"claude-sonnet-4-5-20250929","usage":{"input_tokens":100,"output_tokens":50,"cache_creation_input_tokens":1000,"cache_read_input_tokens":9000}}}`,
+		`{"type":"assistant","timestamp":"2026-10-16T11:00:00Z","cwd":"/work/api/src","requestId":"req_2","message":{"id":"msg_2","model":"unknown","usage":{"input_tokens":10,"output_tokens":20}}}`,
+		`{"type":"assistant","timestamp":"2026-10-16T11:30:00Z","isApiErrorMessage":true,"message":{"content":[{"type":"text","text":"Claude AI usage limit reached|1760619600"}]}}`,
+		// Before the period
+		`{"type":"assistant","timestamp":"2026-10-15T10:00:00Z","requestId":"req_0","message":{"id":"msg_0","usage":{"input_tokens":5000,"output_tokens":5000}}}`,
+		`not json`,
+	}
+	if err := os.WriteFile(filepath.Join(project, "session.jsonl"), []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	since := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	u, err := ReadUsage(configDir, since)
+	if err != nil {
+		t.Fatalf("ReadUsage() error = %v", err)
+	}
+	want := Usage{
+		Tokens:       Tokens{Input: 110, Output: 70, CacheCreation: 1000, CacheRead: 9000, CostUSD: 0.0075},
+		Messages:     2,
+		LastUsed:     time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC),
+		LimitReached: time.Date(2026, 10, 16, 11, 30, 0, 0, time.UTC),
+	}
+	u.CostUSD = math.Round(u.CostUSD*1e6) / 1e6
+	if u != want {
+		t.Errorf("ReadUsage() = %+v, want %+v", u, want)
+	}
+
+	// A session only counts the responses in its directory and period
+	s, err := ReadSessionUsage(configDir, "/work/api", since, time.Date(2026, 10, 16, 10, 30, 0, 0, time.UTC))
+	if err != nil || s.Messages != 1 || s.Total() != 1150 {
+		t.Errorf("ReadSessionUsage() = %+v, %v, want the first response only", s, err)
+	}
+
+	// Transcripts last modified before the period are not read
+	old := since.Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(project, "session.jsonl"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if u, err := ReadUsage(configDir, since); err != nil || u != (Usage{}) {
+		t.Errorf("ReadUsage() = %+v, %v, want no usage from an old transcript", u, err)
+	}
+}
+
+func TestReadUsageWithoutTranscripts(t *testing.T) {
+	if u, err := ReadUsage(t.TempDir(), time.Time{}); err != nil || u != (Usage{}) {
+		t.Errorf("ReadUsage() = %+v, %v, want no usage and no error", u, err)
+	}
+}
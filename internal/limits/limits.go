@@ -0,0 +1,176 @@
+// Package limits applies niceness, CPU affinity, memory limits and Landlock write
+// restrictions to the claude process.
+//
+// Limits are applied by re-executing the launcher through a hidden subcommand that
+// adjusts its own scheduling attributes and resource limits and then execs claude,
+// so that claude and every subprocess it spawns inherit them.
+package limits
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/landlock"
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+// ExecCommand is the hidden subcommand that applies limits and execs the real command
+const ExecCommand = "__exec-limited"
+
+// ErrUnsupported is returned when resource limits are not available on this platform
+var ErrUnsupported = errors.New("resource limits are not supported on this platform")
+
+// Options describes the limits to apply
+type Options struct {
+	Nice   *int   // Scheduling niceness (-20..19); nil leaves it unchanged
+	CPUs   []int  // CPU indexes the process may run on; empty leaves affinity unchanged
+	Memory string // Memory limit such as "4G" or "512M"; empty means unlimited
+	// Writable lists the files and directories claude may write (enforced with Landlock);
+	// empty leaves writes unrestricted
+	Writable []string
+}
+
+// IsZero reports whether no limit is configured
+func (o Options) IsZero() bool {
+	return o.Nice == nil && len(o.CPUs) == 0 && o.Memory == "" && len(o.Writable) == 0
+}
+
+// Wrap returns a Command that runs c with the limits applied
+func Wrap(c *launcher.Command, opts Options) (*launcher.Command, error) {
+	if opts.IsZero() {
+		return c, nil
+	}
+
+	if len(opts.Writable) > 0 && !landlock.Available() {
+		return nil, landlock.ErrUnsupported
+	}
+	if !supported {
+		return nil, ErrUnsupported
+	}
+
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate launcher executable: %w", err)
+	}
+
+	prefix, opts := platformPrefix(opts)
+
+	args := slices.Concat(prefix, []string{self, ExecCommand}, encodeArgs(opts), []string{"--", c.Path}, c.Args)
+
+	return &launcher.Command{
+		Path:      args[0],
+		Args:      args[1:],
+		Env:       c.Env,
+		Dir:       c.Dir,
+		TempFiles: c.TempFiles,
+	}, nil
+}
+
+// ParseExecArgs parses the arguments of ExecCommand into the limits and the command to exec
+func ParseExecArgs(args []string) (Options, []string, error) {
+	fs := flag.NewFlagSet(ExecCommand, flag.ContinueOnError)
+	nice := fs.String("nice", "", "")
+	cpus := fs.String("cpus", "", "")
+	memory := fs.String("memory", "", "")
+	var writable []string
+	fs.Func("writable", "", func(path string) error {
+		writable = append(writable, path)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return Options{}, nil, err
+	}
+
+	var opts Options
+	if *nice != "" {
+		n, err := strconv.Atoi(*nice)
+		if err != nil {
+			return Options{}, nil, fmt.Errorf("invalid nice value %q: %w", *nice, err)
+		}
+		opts.Nice = &n
+	}
+	if *cpus != "" {
+		for field := range strings.SplitSeq(*cpus, ",") {
+			cpu, err := strconv.Atoi(field)
+			if err != nil {
+				return Options{}, nil, fmt.Errorf("invalid cpu %q: %w", field, err)
+			}
+			opts.CPUs = append(opts.CPUs, cpu)
+		}
+	}
+	opts.Memory = *memory
+	opts.Writable = writable
+
+	if fs.NArg() == 0 {
+		return Options{}, nil, fmt.Errorf("no command to run")
+	}
+
+	return opts, fs.Args(), nil
+}
+
+func encodeArgs(opts Options) []string {
+	var args []string
+	if opts.Nice != nil {
+		args = append(args, "-nice", strconv.Itoa(*opts.Nice))
+	}
+	if len(opts.CPUs) > 0 {
+		cpus := make([]string, len(opts.CPUs))
+		for i, cpu := range opts.CPUs {
+			cpus[i] = strconv.Itoa(cpu)
+		}
+		args = append(args, "-cpus", strings.Join(cpus, ","))
+	}
+	if opts.Memory != "" {
+		args = append(args, "-memory", opts.Memory)
+	}
+	for _, path := range opts.Writable {
+		args = append(args, "-writable", path)
+	}
+	return args
+}
+
+func (o Options) validate() error {
+	if o.Nice != nil && (*o.Nice < -20 || *o.Nice > 19) {
+		return fmt.Errorf("invalid nice value %d: must be between -20 and 19", *o.Nice)
+	}
+	for _, cpu := range o.CPUs {
+		if cpu < 0 {
+			return fmt.Errorf("invalid cpu %d: must not be negative", cpu)
+		}
+	}
+	if o.Memory != "" {
+		if _, err := ParseMemory(o.Memory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseMemory parses a size such as "4G", "512M", "64k", or a plain number of bytes
+func ParseMemory(s string) (uint64, error) {
+	units := map[byte]uint64{'K': 1 << 10, 'M': 1 << 20, 'G': 1 << 30, 'T': 1 << 40}
+
+	trimmed := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B")
+	multiplier := uint64(1)
+	if n := len(trimmed); n > 0 {
+		if m, ok := units[trimmed[n-1]]; ok {
+			multiplier = m
+			trimmed = trimmed[:n-1]
+		}
+	}
+
+	value, err := strconv.ParseUint(trimmed, 10, 64)
+	if err != nil || value == 0 {
+		return 0, fmt.Errorf("invalid memory limit %q (expected e.g. 4G or 512M)", s)
+	}
+	return value * multiplier, nil
+}
@@ -0,0 +1,108 @@
+//go:build linux
+
+package limits
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/23prime/claude-launcher/internal/landlock"
+)
+
+// supported reports whether limits can be applied on this platform
+const supported = true
+
+// platformPrefix runs the command in a transient systemd scope when a memory limit is
+// requested and cgroup v2 with a user service manager is available. The scope enforces
+// MemoryMax for the whole process tree, so the memory option is dropped for the exec step.
+func platformPrefix(opts Options) ([]string, Options) {
+	if opts.Memory == "" || !systemdScopeAvailable() {
+		return nil, opts
+	}
+
+	systemdRun, err := exec.LookPath("systemd-run")
+	if err != nil {
+		return nil, opts
+	}
+
+	bytes, err := ParseMemory(opts.Memory)
+	if err != nil {
+		return nil, opts
+	}
+
+	prefix := []string{systemdRun, "--user", "--scope", "--quiet", "--collect",
+		"-p", "MemoryMax=" + strconv.FormatUint(bytes, 10), "--"}
+	opts.Memory = ""
+	return prefix, opts
+}
+
+// systemdScopeAvailable reports whether cgroup v2 is mounted and a user systemd instance is running
+func systemdScopeAvailable() bool {
+	if _, err := os.Stat("/sys/fs/cgroup/cgroup.controllers"); err != nil {
+		return false
+	}
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(runtimeDir, "systemd", "private"))
+	return err == nil
+}
+
+// Exec applies opts to the current process and replaces it with argv.
+// Without a cgroup, the memory limit falls back to RLIMIT_DATA.
+// The write restriction comes last, once nothing else needs to write.
+func Exec(opts Options, argv []string) error {
+	// Niceness and affinity are per-thread on Linux; exec keeps only the calling thread
+	runtime.LockOSThread()
+
+	if opts.Nice != nil {
+		if err := unix.Setpriority(unix.PRIO_PROCESS, 0, *opts.Nice); err != nil {
+			return fmt.Errorf("failed to set niceness: %w", err)
+		}
+	}
+
+	if len(opts.CPUs) > 0 {
+		var set unix.CPUSet
+		for _, cpu := range opts.CPUs {
+			set.Set(cpu)
+		}
+		if err := unix.SchedSetaffinity(0, &set); err != nil {
+			return fmt.Errorf("failed to set CPU affinity: %w", err)
+		}
+	}
+
+	if opts.Memory != "" {
+		bytes, err := ParseMemory(opts.Memory)
+		if err != nil {
+			return err
+		}
+		if err := unix.Setrlimit(unix.RLIMIT_DATA, &unix.Rlimit{Cur: bytes, Max: bytes}); err != nil {
+			return fmt.Errorf("failed to set memory limit: %w", err)
+		}
+	}
+
+	path, err := exec.LookPath(argv[0])
+	if err != nil {
+		return fmt.Errorf("failed to find %s: %w", argv[0], err)
+	}
+
+	if len(opts.Writable) > 0 {
+		if err := landlock.RestrictWrites(opts.Writable); err != nil {
+			return fmt.Errorf("failed to restrict writes: %w", err)
+		}
+	}
+
+	// #nosec G204 -- argv is the launch command built by the launcher itself
+	if err := syscall.Exec(path, argv, os.Environ()); err != nil {
+		return fmt.Errorf("failed to run %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package limits
+
+// supported reports whether limits can be applied on this platform
+const supported = false
+
+func platformPrefix(opts Options) ([]string, Options) {
+	return nil, opts
+}
+
+// Exec is not supported on this platform
+func Exec(_ Options, _ []string) error {
+	return ErrUnsupported
+}
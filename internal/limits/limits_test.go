@@ -0,0 +1,89 @@
+package limits
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+func TestParseMemory(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected uint64
+		wantErr  bool
+	}{
+		{input: "1024", expected: 1024},
+		{input: "64k", expected: 64 << 10},
+		{input: "512M", expected: 512 << 20},
+		{input: "4G", expected: 4 << 30},
+		{input: "4GB", expected: 4 << 30},
+		{input: "", wantErr: true},
+		{input: "0", wantErr: true},
+		{input: "lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMemory(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMemory(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseMemory(%q) = %d, expected %d", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestExecArgsRoundTrip(t *testing.T) {
+	nice := 10
+	opts := Options{Nice: &nice, CPUs: []int{0, 2}, Memory: "4G", Writable: []string{"/work/app", "/tmp"}}
+	command := []string{"claude", "--model", "opus"}
+
+	args := append(encodeArgs(opts), "--")
+	args = append(args, command...)
+
+	parsed, argv, err := ParseExecArgs(args)
+	if err != nil {
+		t.Fatalf("ParseExecArgs() error = %v", err)
+	}
+	if parsed.Nice == nil || *parsed.Nice != nice {
+		t.Errorf("Nice = %v, expected %d", parsed.Nice, nice)
+	}
+	if !slices.Equal(parsed.CPUs, opts.CPUs) {
+		t.Errorf("CPUs = %v, expected %v", parsed.CPUs, opts.CPUs)
+	}
+	if parsed.Memory != opts.Memory {
+		t.Errorf("Memory = %q, expected %q", parsed.Memory, opts.Memory)
+	}
+	if !slices.Equal(parsed.Writable, opts.Writable) {
+		t.Errorf("Writable = %v, expected %v", parsed.Writable, opts.Writable)
+	}
+	if !slices.Equal(argv, command) {
+		t.Errorf("argv = %v, expected %v", argv, command)
+	}
+}
+
+func TestParseExecArgsWithoutCommand(t *testing.T) {
+	if _, _, err := ParseExecArgs([]string{"-nice", "5"}); err == nil {
+		t.Error("ParseExecArgs() should fail without a command")
+	}
+}
+
+func TestWrapWithoutLimits(t *testing.T) {
+	c := &launcher.Command{Path: "claude", Args: []string{"--continue"}}
+	wrapped, err := Wrap(c, Options{})
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+	if wrapped != c {
+		t.Error("Wrap() should return the command unchanged when no limits are set")
+	}
+}
+
+func TestWrapInvalidNice(t *testing.T) {
+	nice := 40
+	if _, err := Wrap(&launcher.Command{Path: "claude"}, Options{Nice: &nice}); err == nil {
+		t.Error("Wrap() should reject an out-of-range nice value")
+	}
+}
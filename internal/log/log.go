@@ -0,0 +1,144 @@
+// Package log is the launcher's diagnostic logger, built on log/slog.
+// By default only warnings and errors are written to stderr; --verbose or CLAUDE_LAUNCHER_DEBUG
+// turn on debug traces, and the "log" config can raise the level, switch to JSON or write to a file.
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DebugEnvVar enables debug traces when set to a non-empty value other than "0"
+const DebugEnvVar = "CLAUDE_LAUNCHER_DEBUG"
+
+// Options configure the logger (the "log" object in config.json)
+type Options struct {
+	Level  string // "debug", "info", "warn" (default) or "error"
+	Format string // "text" (default) or "json"
+	File   string // Log file; stderr when empty
+}
+
+var (
+	level   = new(slog.LevelVar)
+	logger  = newLogger(os.Stderr, "text")
+	verbose bool
+)
+
+func init() {
+	level.Set(slog.LevelWarn)
+	if v := os.Getenv(DebugEnvVar); v != "" && v != "0" {
+		EnableDebug()
+	}
+}
+
+// EnableDebug turns on debug traces on stderr (--verbose). It takes precedence over Setup.
+func EnableDebug() {
+	verbose = true
+	level.Set(slog.LevelDebug)
+	logger = newLogger(os.Stderr, "text")
+}
+
+// DebugEnabled reports whether debug messages are written, so callers can skip expensive traces
+func DebugEnabled() bool {
+	return logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// Setup applies the configured level, format and destination.
+// With --verbose, only the format applies: debug traces always go to stderr.
+func Setup(opts Options) error {
+	lvl := level.Level()
+	if opts.Level != "" {
+		parsed, err := ParseLevel(opts.Level)
+		if err != nil {
+			return err
+		}
+		lvl = parsed
+	}
+
+	switch opts.Format {
+	case "", "text", "json":
+	default:
+		return fmt.Errorf("unknown log format %q (available: text, json)", opts.Format)
+	}
+
+	if verbose {
+		logger = newLogger(os.Stderr, opts.Format)
+		return nil
+	}
+
+	var w io.Writer = os.Stderr
+	if opts.File != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.File), 0o700); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+		// #nosec G304 -- the log file path comes from the user's own config
+		f, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		w = f
+	}
+
+	level.Set(lvl)
+	logger = newLogger(w, opts.Format)
+	return nil
+}
+
+// ParseLevel parses a level name
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q (available: debug, info, warn, error)", s)
+	}
+}
+
+// newLogger creates a logger writing to w. Times are omitted on stderr, where lines are read live.
+func newLogger(w io.Writer, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if w == os.Stderr {
+		opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		}
+	}
+
+	if format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// Debug logs a trace message with key-value pairs
+func Debug(msg string, args ...any) {
+	logger.Debug(msg, args...)
+}
+
+// Info logs an informational message with key-value pairs
+func Info(msg string, args ...any) {
+	logger.Info(msg, args...)
+}
+
+// Warn logs a problem the launcher recovered from
+func Warn(msg string, args ...any) {
+	logger.Warn(msg, args...)
+}
+
+// Error logs a failure
+func Error(msg string, args ...any) {
+	logger.Error(msg, args...)
+}
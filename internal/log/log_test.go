@@ -0,0 +1,82 @@
+package log
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// reset restores the default logger after a test
+func reset(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		verbose = false
+		level.Set(slog.LevelWarn)
+		logger = newLogger(os.Stderr, "text")
+	})
+}
+
+func TestSetupFileJSON(t *testing.T) {
+	reset(t)
+	path := filepath.Join(t.TempDir(), "logs", "launcher.log")
+
+	if err := Setup(Options{Level: "info", Format: "json", File: path}); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	Debug("hidden")
+	Info("launched", "dir", "/tmp")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %q", data)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("log line is not JSON: %v", err)
+	}
+	if entry["msg"] != "launched" || entry["dir"] != "/tmp" || entry["level"] != "INFO" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+	if _, ok := entry["time"]; !ok {
+		t.Error("file entries should have a time")
+	}
+}
+
+func TestSetupDefaultLevel(t *testing.T) {
+	reset(t)
+	if err := Setup(Options{}); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if DebugEnabled() {
+		t.Error("DebugEnabled() = true with the default level")
+	}
+}
+
+func TestEnableDebugWins(t *testing.T) {
+	reset(t)
+	EnableDebug()
+	if err := Setup(Options{Level: "error", File: filepath.Join(t.TempDir(), "launcher.log")}); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if !DebugEnabled() {
+		t.Error("DebugEnabled() = false after EnableDebug()")
+	}
+}
+
+func TestSetupInvalid(t *testing.T) {
+	reset(t)
+	if err := Setup(Options{Level: "loud"}); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+	if err := Setup(Options{Format: "xml"}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,306 @@
+// Package mcp checks MCP server definitions by performing the initialize handshake of the
+// Model Context Protocol with them, as Claude Code does when it connects
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+)
+
+// ProtocolVersion is the protocol revision requested in the handshake
+const ProtocolVersion = "2025-06-18"
+
+// Result is a server's answer to the initialize request
+type Result struct {
+	ProtocolVersion string                     `json:"protocolVersion"`
+	ServerInfo      ServerInfo                 `json:"serverInfo"`
+	Capabilities    map[string]json.RawMessage `json:"capabilities"`
+}
+
+// ServerInfo names the server implementation
+type ServerInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// CapabilityNames returns the capabilities the server announced (tools, resources, ...), sorted
+func (r *Result) CapabilityNames() []string {
+	return slices.Sorted(maps.Keys(r.Capabilities))
+}
+
+// message is a JSON-RPC request, notification or response
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  *Result         `json:"result,omitempty"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// initializeID identifies the response to the handshake among other messages
+const initializeID = "1"
+
+// Test starts (or connects to) server and performs the initialize handshake, giving up when ctx
+// is done. ${VAR} and ${VAR:-default} are expanded from the environment first, as Claude Code
+// does. version is reported as the client version.
+func Test(ctx context.Context, server config.MCPServer, version string) (*Result, error) {
+	server = expand(server)
+	request := message{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage(initializeID),
+		Method:  "initialize",
+		Params: map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]any{},
+			"clientInfo":      ServerInfo{Name: "claude-launcher", Version: version},
+		},
+	}
+
+	switch {
+	case server.Type == "sse":
+		return nil, errors.New("testing sse servers is not supported")
+	case server.URL != "":
+		return testHTTP(ctx, server, request)
+	default:
+		return testStdio(ctx, server, request)
+	}
+}
+
+// testStdio runs the server command and exchanges newline-delimited messages over its stdin
+// and stdout. The server is stopped afterwards.
+func testStdio(ctx context.Context, server config.MCPServer, request message) (*Result, error) {
+	cmd := exec.CommandContext(ctx, server.Command, server.Args...) // #nosec G204 -- server from the user's config
+	cmd.Env = os.Environ()
+	for _, key := range slices.Sorted(maps.Keys(server.Env)) {
+		cmd.Env = append(cmd.Env, key+"="+server.Env[key])
+	}
+	cmd.WaitDelay = time.Second
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", server.Command, err)
+	}
+	defer func() {
+		_ = stdin.Close()      //nolint:errcheck // the server is stopped anyway
+		_ = cmd.Process.Kill() //nolint:errcheck // it may have exited
+		_ = cmd.Wait()         //nolint:errcheck // the handshake decides the outcome
+	}()
+
+	// Read in the background: servers started through npx and the like can keep stdout open
+	// after the context kills the command
+	lines, done := make(chan []byte), make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- slices.Clone(scanner.Bytes()):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := writeMessage(stdin, request); err != nil {
+		// The server exited already; its stderr tells why
+		_ = cmd.Wait() //nolint:errcheck // stderr tells more than the exit status
+		return nil, exitedError(ctx, &stderr)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, timeoutError(ctx)
+		case line, ok := <-lines:
+			if !ok {
+				_ = cmd.Wait() //nolint:errcheck // stderr tells more than the exit status
+				return nil, exitedError(ctx, &stderr)
+			}
+			result, err := initializeResult(line)
+			if err != nil {
+				return nil, err
+			}
+			if result == nil {
+				continue
+			}
+			// Complete the handshake before disconnecting
+			_ = writeMessage(stdin, message{JSONRPC: "2.0", Method: "notifications/initialized"}) //nolint:errcheck // the server is stopped next
+			return result, nil
+		}
+	}
+}
+
+// writeMessage writes msg as one line
+func writeMessage(w io.Writer, msg message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// exitedError reports a server that closed stdout without answering, with its last words
+func exitedError(ctx context.Context, stderr *bytes.Buffer) error {
+	if ctx.Err() != nil {
+		return timeoutError(ctx)
+	}
+	lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return fmt.Errorf("the server exited without answering: %s", last)
+	}
+	return errors.New("the server exited without answering")
+}
+
+// timeoutError reports a handshake cut short by ctx
+func timeoutError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.New("no answer to initialize before the timeout")
+	}
+	return ctx.Err()
+}
+
+// testHTTP posts the request to a Streamable HTTP server, which answers with JSON or with an
+// event stream
+func testHTTP(ctx context.Context, server config.MCPServer, request message) (*Result, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	for key, value := range server.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, timeoutError(ctx)
+		}
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }() //nolint:errcheck // response already read
+
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("the server answered %s", resp.Status)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")) //nolint:errcheck // an empty type is handled as JSON
+	if mediaType != "text/event-stream" {
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 4*1024*1024))
+		if err != nil {
+			return nil, err
+		}
+		result, err := initializeResult(data)
+		if err == nil && result == nil {
+			err = errors.New("the server did not answer initialize")
+		}
+		return result, err
+	}
+
+	// Events are separated by blank lines; their data lines form the message
+	var data bytes.Buffer
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data.WriteString(strings.TrimPrefix(value, " "))
+			continue
+		}
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+		result, err := initializeResult(data.Bytes())
+		if err != nil || result != nil {
+			return result, err
+		}
+		data.Reset()
+	}
+	if ctx.Err() != nil {
+		return nil, timeoutError(ctx)
+	}
+	return nil, errors.New("the event stream ended without an answer to initialize")
+}
+
+// initializeResult decodes the answer to initialize from data. It returns nil and no error
+// for other messages (notifications, server requests, log lines).
+func initializeResult(data []byte) (*Result, error) {
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil || msg.Method != "" || string(msg.ID) != initializeID {
+		return nil, nil
+	}
+	if msg.Error != nil {
+		return nil, fmt.Errorf("initialize failed: %s (code %d)", msg.Error.Message, msg.Error.Code)
+	}
+	if msg.Result == nil {
+		return nil, errors.New("initialize returned no result")
+	}
+	return msg.Result, nil
+}
+
+// expand resolves ${VAR} and ${VAR:-default} in the command, arguments, environment, URL and
+// headers of server
+func expand(server config.MCPServer) config.MCPServer {
+	lookup := func(s string) string {
+		name, def, _ := strings.Cut(s, ":-")
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+		return def
+	}
+	expandMap := func(m map[string]string) map[string]string {
+		if m == nil {
+			return nil
+		}
+		out := make(map[string]string, len(m))
+		for key, value := range m {
+			out[key] = os.Expand(value, lookup)
+		}
+		return out
+	}
+
+	server.Command = os.Expand(server.Command, lookup)
+	args := make([]string, len(server.Args))
+	for i, arg := range server.Args {
+		args[i] = os.Expand(arg, lookup)
+	}
+	server.Args = args
+	server.Env = expandMap(server.Env)
+	server.URL = os.Expand(server.URL, lookup)
+	server.Headers = expandMap(server.Headers)
+	return server
+}
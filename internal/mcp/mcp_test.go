@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+)
+
+const initializeResponse = `{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-06-18","serverInfo":{"name":"demo","version":"0.1.0"},"capabilities":{"tools":{},"logging":{}}}}`
+
+// writeServer writes a stdio MCP server script
+func writeServer(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts")
+	}
+	path := filepath.Join(t.TempDir(), "server")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil { // #nosec G306 -- test script
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTestStdio(t *testing.T) {
+	// A log line and a notification come first; the initialize request must arrive on stdin
+	server := writeServer(t, `read -r request
+case "$request" in *'"method":"initialize"'*) ;; *) echo "unexpected: $request" >&2; exit 1 ;; esac
+echo "starting on $GREETING"
+echo '{"jsonrpc":"2.0","method":"notifications/message","params":{}}'
+echo '`+initializeResponse+`'
+read -r initialized
+sleep 10
+`)
+
+	t.Setenv("PORT", "8080")
+	result, err := Test(context.Background(), config.MCPServer{
+		Command: server,
+		Env:     map[string]string{"GREETING": "${PORT}"},
+	}, "1.0.0")
+	if err != nil {
+		t.Fatalf("Test() error = %v", err)
+	}
+	if result.ServerInfo.Name != "demo" || result.ProtocolVersion != "2025-06-18" {
+		t.Errorf("Test() = %+v, expected the demo server", result)
+	}
+	if got := result.CapabilityNames(); !slices.Equal(got, []string{"logging", "tools"}) {
+		t.Errorf("CapabilityNames() = %q", got)
+	}
+}
+
+func TestTestStdioFailures(t *testing.T) {
+	exiting := writeServer(t, "echo 'missing API key' >&2\nexit 1\n")
+	if _, err := Test(context.Background(), config.MCPServer{Command: exiting}, "1.0.0"); err == nil || !strings.Contains(err.Error(), "missing API key") {
+		t.Errorf("Test() error = %v, expected the server's stderr", err)
+	}
+
+	silent := writeServer(t, "sleep 10\n")
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := Test(ctx, config.MCPServer{Command: silent}, "1.0.0"); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("Test() error = %v, expected a timeout", err)
+	}
+
+	failing := writeServer(t, `read -r request
+echo '{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"unsupported protocol version"}}'
+`)
+	if _, err := Test(context.Background(), config.MCPServer{Command: failing}, "1.0.0"); err == nil || !strings.Contains(err.Error(), "unsupported protocol version") {
+		t.Errorf("Test() error = %v, expected the JSON-RPC error", err)
+	}
+}
+
+func TestTestHTTP(t *testing.T) {
+	for _, contentType := range []string{"application/json", "text/event-stream"} {
+		t.Run(contentType, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req message
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "initialize" {
+					http.Error(w, "bad request", http.StatusBadRequest)
+					return
+				}
+				if r.Header.Get("Authorization") != "Bearer secret" {
+					http.Error(w, "unauthorized", http.StatusUnauthorized)
+					return
+				}
+				w.Header().Set("Content-Type", contentType)
+				if contentType == "text/event-stream" {
+					_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", initializeResponse) //nolint:errcheck // test server
+					return
+				}
+				_, _ = w.Write([]byte(initializeResponse)) //nolint:errcheck // test server
+			}))
+			defer srv.Close()
+
+			t.Setenv("TOKEN", "secret")
+			result, err := Test(context.Background(), config.MCPServer{
+				Type:    "http",
+				URL:     srv.URL,
+				Headers: map[string]string{"Authorization": "Bearer ${TOKEN}"},
+			}, "1.0.0")
+			if err != nil {
+				t.Fatalf("Test() error = %v", err)
+			}
+			if result.ServerInfo.Name != "demo" {
+				t.Errorf("Test() = %+v, expected the demo server", result)
+			}
+
+			if _, err := Test(context.Background(), config.MCPServer{URL: srv.URL}, "1.0.0"); err == nil || !strings.Contains(err.Error(), "401") {
+				t.Errorf("Test() error = %v, expected the HTTP status", err)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	t.Setenv("HOST", "example.com")
+	t.Setenv("EMPTY", "")
+
+	got := expand(config.MCPServer{
+		Command: "server",
+		Args:    []string{"--host=${HOST}", "--port=${PORT:-80}", "${EMPTY:-fallback}"},
+		URL:     "https://${HOST}/mcp",
+	})
+	if !slices.Equal(got.Args, []string{"--host=example.com", "--port=80", "fallback"}) {
+		t.Errorf("Args = %q", got.Args)
+	}
+	if got.URL != "https://example.com/mcp" {
+		t.Errorf("URL = %q", got.URL)
+	}
+}
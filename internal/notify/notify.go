@@ -0,0 +1,171 @@
+// Package notify calls webhooks when launches finish, so that long headless or detached
+// sessions can report their outcome (e.g. to a Slack channel) without being watched.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/secrets"
+)
+
+// defaultModes are notified when a webhook lists none: the launches nobody is watching
+var defaultModes = []string{launcher.ModeHeadless, launcher.ModeDetached}
+
+// timeout bounds each webhook call, so a slow endpoint cannot hold up the exit
+const timeout = 10 * time.Second
+
+// client posts the payloads
+var client = &http.Client{Timeout: timeout}
+
+// Notifier is a launcher.Recorder posting finished launches to webhooks.
+// It is serialized into the spec of detached sessions, so URLs stay unresolved until sent.
+type Notifier struct {
+	Webhooks []config.Notification `json:"webhooks"`
+	Project  string                `json:"project,omitempty"` // Project path reported in the payloads
+}
+
+// New returns a Notifier for the configured webhooks, or nil when there are none
+func New(webhooks []config.Notification, project string) *Notifier {
+	if len(webhooks) == 0 {
+		return nil
+	}
+	return &Notifier{Webhooks: webhooks, Project: project}
+}
+
+// Payload is the body of the generic JSON format
+type Payload struct {
+	Event           string    `json:"event"`
+	ID              string    `json:"id"`
+	Mode            string    `json:"mode"`
+	Project         string    `json:"project"`
+	Dir             string    `json:"dir"`
+	Account         string    `json:"account,omitempty"`
+	StartedAt       time.Time `json:"startedAt"`
+	EndedAt         time.Time `json:"endedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	ExitCode        *int      `json:"exitCode,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	Text            string    `json:"text"` // One-line summary, as sent in the Slack format
+}
+
+// Record implements launcher.Recorder: it posts rec to every webhook that wants it and
+// returns the failures together
+func (n *Notifier) Record(rec *launcher.LaunchRecord) error {
+	if n == nil || rec.EndedAt.IsZero() {
+		return nil
+	}
+
+	payload := n.payload(rec)
+	var errs []error
+	for _, hook := range n.Webhooks {
+		if !wants(hook, rec) {
+			continue
+		}
+		if err := post(hook, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// wants reports whether hook is interested in rec, by mode and duration
+func wants(hook config.Notification, rec *launcher.LaunchRecord) bool {
+	modes := hook.Modes
+	if len(modes) == 0 {
+		modes = defaultModes
+	}
+	if !slices.Contains(modes, rec.Mode) {
+		return false
+	}
+	if hook.MinDuration != "" {
+		// Validated when the config was loaded
+		minDuration, err := time.ParseDuration(hook.MinDuration)
+		if err == nil && rec.Duration() < minDuration {
+			return false
+		}
+	}
+	return true
+}
+
+// payload builds the generic payload of rec
+func (n *Notifier) payload(rec *launcher.LaunchRecord) *Payload {
+	project := n.Project
+	if project == "" {
+		project = rec.Dir
+	}
+	return &Payload{
+		Event:           "launch.finished",
+		ID:              rec.ID,
+		Mode:            rec.Mode,
+		Project:         project,
+		Dir:             rec.Dir,
+		Account:         rec.Account,
+		StartedAt:       rec.StartedAt,
+		EndedAt:         rec.EndedAt,
+		DurationSeconds: rec.Duration().Seconds(),
+		ExitCode:        rec.ExitCode,
+		Error:           rec.Error,
+		Text:            summary(project, rec),
+	}
+}
+
+// summary describes the outcome of rec in one line
+func summary(project string, rec *launcher.LaunchRecord) string {
+	var outcome string
+	switch {
+	case rec.Error != "":
+		outcome = "failed to start: " + rec.Error
+	case rec.ExitCode != nil && *rec.ExitCode == 0:
+		outcome = "finished"
+	case rec.ExitCode != nil:
+		outcome = fmt.Sprintf("exited with status %d", *rec.ExitCode)
+	default:
+		outcome = "ended"
+	}
+	text := fmt.Sprintf("claude (%s) in %s %s after %s", rec.Mode, filepath.Base(project), outcome, rec.Duration().Round(time.Second))
+	if rec.Account != "" {
+		text += fmt.Sprintf(" [account: %s]", rec.Account)
+	}
+	return text
+}
+
+// post sends payload to hook in its format
+func post(hook config.Notification, payload *Payload) error {
+	var body any = payload
+	if hook.Format == config.NotificationSlack {
+		body = map[string]string{"text": payload.Text}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	target, err := secrets.Resolve(hook.URL)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(target, "application/json", bytes.NewReader(data)) // #nosec G107 -- URL from the user's config
+	if err != nil {
+		// Drop the URL from the error: webhook URLs usually embed their token
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	_ = resp.Body.Close() //nolint:errcheck // the body is not used
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("notification webhook returned %s", resp.Status)
+	}
+	return nil
+}
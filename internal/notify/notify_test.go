@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+// finished returns a record of a launch in mode that ran for d and exited with code
+func finished(mode string, d time.Duration, code int) *launcher.LaunchRecord {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &launcher.LaunchRecord{ID: "abc", Mode: mode, Dir: "/work/api/internal", Account: "Work", StartedAt: start, EndedAt: start.Add(d), ExitCode: &code}
+}
+
+// webhook starts a server collecting the bodies it receives
+func webhook(t *testing.T, status int) (*httptest.Server, *[]string) {
+	t.Helper()
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &bodies
+}
+
+func TestRecordPayloads(t *testing.T) {
+	srv, bodies := webhook(t, http.StatusOK)
+	n := New([]config.Notification{
+		{URL: srv.URL},
+		{URL: srv.URL, Format: config.NotificationSlack},
+	}, "/work/api")
+
+	if err := n.Record(finished(launcher.ModeDetached, 90*time.Second, 1)); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if len(*bodies) != 2 {
+		t.Fatalf("%d notifications were sent, expected 2", len(*bodies))
+	}
+
+	var payload Payload
+	if err := json.Unmarshal([]byte((*bodies)[0]), &payload); err != nil {
+		t.Fatalf("invalid JSON payload: %v", err)
+	}
+	if payload.Event != "launch.finished" || payload.Project != "/work/api" || payload.DurationSeconds != 90 ||
+		payload.ExitCode == nil || *payload.ExitCode != 1 {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+
+	expected := `{"text":"claude (detached) in api exited with status 1 after 1m30s [account: Work]"}`
+	if (*bodies)[1] != expected {
+		t.Errorf("Slack payload = %s, expected %s", (*bodies)[1], expected)
+	}
+}
+
+func TestRecordFilters(t *testing.T) {
+	srv, bodies := webhook(t, http.StatusOK)
+	n := New([]config.Notification{
+		{URL: srv.URL, MinDuration: "10m"},
+		{URL: srv.URL, Modes: []string{launcher.ModeForeground}},
+	}, "")
+
+	records := []*launcher.LaunchRecord{
+		finished(launcher.ModeHeadless, time.Minute, 0),    // Too short for the first, wrong mode for the second
+		finished(launcher.ModeForeground, time.Minute, 0),  // Second only
+		finished(launcher.ModeDetached, 20*time.Minute, 0), // First only
+		{Mode: launcher.ModeTmux, StartedAt: time.Now()},   // End not observed
+	}
+	for _, rec := range records {
+		if err := n.Record(rec); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	if len(*bodies) != 2 {
+		t.Errorf("%d notifications were sent, expected 2", len(*bodies))
+	}
+}
+
+func TestRecordErrors(t *testing.T) {
+	srv, _ := webhook(t, http.StatusForbidden)
+	n := New([]config.Notification{{URL: srv.URL + "/T0KEN"}}, "")
+
+	err := n.Record(finished(launcher.ModeHeadless, time.Minute, 0))
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Record() error = %v, expected the webhook status", err)
+	}
+
+	srv.Close()
+	err = n.Record(finished(launcher.ModeHeadless, time.Minute, 0))
+	if err == nil || strings.Contains(err.Error(), "T0KEN") {
+		t.Errorf("Record() error = %v, expected a failure without the URL", err)
+	}
+}
+
+func TestNewWithoutWebhooks(t *testing.T) {
+	n := New(nil, "/work")
+	if n != nil {
+		t.Errorf("New() = %+v, expected nil", n)
+	}
+	if err := n.Record(finished(launcher.ModeHeadless, time.Minute, 0)); err != nil {
+		t.Errorf("Record() on nil error = %v", err)
+	}
+}
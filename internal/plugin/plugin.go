@@ -0,0 +1,168 @@
+// Package plugin runs external claude-launcher-<name> executables found on PATH, like git and
+// kubectl plugins: as subcommands (`claude-launcher <name>`), and as hook providers answering
+// one JSON request on stdin with one JSON response on stdout
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Prefix starts the executable name of every plugin
+const Prefix = "claude-launcher-"
+
+// ProtocolVersion is sent with every hook request; it changes when responses would be
+// misunderstood by older plugins
+const ProtocolVersion = 1
+
+// Environment variables set for plugins
+const (
+	LauncherEnvVar = "CLAUDE_LAUNCHER"         // Path of the claude-launcher executable
+	VersionEnvVar  = "CLAUDE_LAUNCHER_VERSION" // Its version
+	HookEnvVar     = "CLAUDE_LAUNCHER_HOOK"    // The hook being called (unset for subcommands)
+)
+
+// Hooks a plugin listed in the "plugins" config option is called for. Plugins answer `{}` to
+// hooks they do not provide.
+const (
+	HookConfig    = "config"     // While loading the config: may add allowedDirs
+	HookPreLaunch = "pre-launch" // Before claude starts: may refuse the launch or add env
+)
+
+// Request is written to the plugin's stdin as a single JSON object
+type Request struct {
+	Protocol int      `json:"protocol"`
+	Hook     string   `json:"hook"`
+	Version  string   `json:"version"`           // claude-launcher version
+	Dir      string   `json:"dir,omitempty"`     // pre-launch: directory claude starts in
+	Project  string   `json:"project,omitempty"` // pre-launch: matching projects entry
+	Account  string   `json:"account,omitempty"` // pre-launch: selected account
+	Model    string   `json:"model,omitempty"`   // pre-launch: resolved model
+	Tool     string   `json:"tool,omitempty"`    // pre-launch: agent CLI
+	Args     []string `json:"args,omitempty"`    // pre-launch: arguments passed to claude
+}
+
+// Response is the JSON object a plugin writes to stdout. Empty output is the same as `{}`.
+type Response struct {
+	Deny        bool              `json:"deny,omitempty"`        // pre-launch: refuse the launch
+	Message     string            `json:"message,omitempty"`     // Shown to the user
+	Env         map[string]string `json:"env,omitempty"`         // pre-launch: extra variables for claude
+	AllowedDirs []string          `json:"allowedDirs,omitempty"` // config: directories added to allowedDirs
+}
+
+// Plugin is an executable found on PATH
+type Plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// Find returns the path of the plugin name, the first claude-launcher-<name> on PATH
+func Find(name string) (string, error) {
+	if !ValidName(name) {
+		return "", fmt.Errorf("invalid plugin name %q", name)
+	}
+	return exec.LookPath(Prefix + name)
+}
+
+// ValidName reports whether name can name a plugin: not empty, no path separators, and not
+// looking like an option
+func ValidName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, "-") && !strings.ContainsAny(name, `/\`) && name != "." && name != ".."
+}
+
+// Discover lists the plugins on PATH, sorted by name. When several directories provide the
+// same name, the one Find would run is listed.
+func Discover() []Plugin {
+	var plugins []Plugin
+	seen := map[string]bool{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), Prefix)
+			if !ok || entry.IsDir() {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if seen[name] {
+				continue
+			}
+			path, err := Find(name)
+			if err != nil {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+	slices.SortFunc(plugins, func(a, b Plugin) int { return strings.Compare(a.Name, b.Name) })
+	return plugins
+}
+
+// Env returns the environment plugins run with: the launcher's own plus the path and version
+// of the launcher, so that plugins can call it back
+func Env(version string) []string {
+	env := os.Environ()
+	if self, err := os.Executable(); err == nil {
+		env = append(env, LauncherEnvVar+"="+self)
+	}
+	return append(env, VersionEnvVar+"="+version)
+}
+
+// Call runs the hook of the plugin name with req, giving up when ctx is done. The plugin must
+// exit with status 0; its stderr is passed through to the user.
+func Call(ctx context.Context, name string, req Request) (*Response, error) {
+	path, err := Find(name)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Protocol = ProtocolVersion
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec G204 -- plugins are executables the user put on PATH and enabled in the config
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Env = append(Env(req.Version), HookEnvVar+"="+req.Hook)
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	cmd.Stderr = os.Stderr
+	cmd.WaitDelay = time.Second
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("no answer to the %s hook before the timeout", req.Hook)
+			}
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	var resp Response
+	if len(bytes.TrimSpace(output)) == 0 {
+		return &resp, nil
+	}
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return nil, fmt.Errorf("invalid answer to the %s hook: %w", req.Hook, err)
+	}
+	return &resp, nil
+}
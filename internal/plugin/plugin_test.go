@@ -0,0 +1,100 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writePlugin writes the plugin name into dir as a shell script
+func writePlugin(t *testing.T, dir, name, script string) {
+	t.Helper()
+	path := filepath.Join(dir, Prefix+name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil { // #nosec G306 -- test script
+		t.Fatal(err)
+	}
+}
+
+// pluginPath puts fresh directories first on PATH and returns them
+func pluginPath(t *testing.T, n int) []string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts")
+	}
+	var dirs []string
+	for range n {
+		dirs = append(dirs, t.TempDir())
+	}
+	t.Setenv("PATH", strings.Join(append(slices.Clone(dirs), os.Getenv("PATH")), string(os.PathListSeparator)))
+	return dirs
+}
+
+func TestDiscover(t *testing.T) {
+	dirs := pluginPath(t, 2)
+	writePlugin(t, dirs[0], "vault", "")
+	writePlugin(t, dirs[1], "vault", "")
+	writePlugin(t, dirs[1], "jira", "")
+	if err := os.WriteFile(filepath.Join(dirs[1], Prefix+"notes"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := slices.DeleteFunc(Discover(), func(p Plugin) bool { return !strings.HasPrefix(p.Path, dirs[0]) && !strings.HasPrefix(p.Path, dirs[1]) })
+	want := []Plugin{
+		{Name: "jira", Path: filepath.Join(dirs[1], Prefix+"jira")},
+		{Name: "vault", Path: filepath.Join(dirs[0], Prefix+"vault")},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Discover() = %+v, want %+v (first on PATH, executables only)", got, want)
+	}
+
+	if _, err := Find("../vault"); err == nil {
+		t.Error("Find() should reject names with path separators")
+	}
+}
+
+func TestCall(t *testing.T) {
+	dirs := pluginPath(t, 1)
+	// The request arrives on stdin and the hook in the environment
+	writePlugin(t, dirs[0], "policy", `read -r request
+case "$request" in *'"dir":"/work/api"'*) ;; *) echo "unexpected: $request" >&2; exit 1 ;; esac
+[ "$CLAUDE_LAUNCHER_HOOK" = pre-launch ] || exit 1
+echo '{"deny": true, "message": "ticket required", "env": {"TICKET": ""}}'
+`)
+	writePlugin(t, dirs[0], "quiet", "cat >/dev/null\n")
+	writePlugin(t, dirs[0], "broken", "echo not json\n")
+	writePlugin(t, dirs[0], "failing", "exit 3\n")
+	writePlugin(t, dirs[0], "slow", "exec sleep 10\n")
+
+	ctx := context.Background()
+	resp, err := Call(ctx, "policy", Request{Hook: HookPreLaunch, Version: "1.0.0", Dir: "/work/api"})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if !resp.Deny || resp.Message != "ticket required" {
+		t.Errorf("Call() = %+v, expected a denial", resp)
+	}
+
+	if resp, err := Call(ctx, "quiet", Request{Hook: HookConfig}); err != nil || resp.Deny || len(resp.AllowedDirs) != 0 {
+		t.Errorf("Call() = %+v, %v, expected empty output to mean {}", resp, err)
+	}
+	if _, err := Call(ctx, "broken", Request{Hook: HookConfig}); err == nil {
+		t.Error("Call() should reject invalid JSON")
+	}
+	if _, err := Call(ctx, "failing", Request{Hook: HookConfig}); err == nil {
+		t.Error("Call() should fail when the plugin exits with an error")
+	}
+	if _, err := Call(ctx, "missing", Request{Hook: HookConfig}); err == nil {
+		t.Error("Call() should fail for a plugin not on PATH")
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	if _, err := Call(timeout, "slow", Request{Hook: HookConfig}); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("Call() error = %v, expected a timeout", err)
+	}
+}
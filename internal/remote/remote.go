@@ -0,0 +1,94 @@
+// Package remote launches claude on SSH hosts.
+// The directory check runs on the host itself, after resolving symlinks there, so the
+// allowlist cannot be bypassed through remote links.
+package remote
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Exit codes of the remote script, matching the launcher's own
+const (
+	ExitNoDir    = 1 // The directory cannot be entered
+	ExitDenied   = 3 // The directory is outside the allowed directories
+	ExitNotFound = 5 // The claude command was not found on the host
+)
+
+// ParseTarget splits "host:path" (as in scp) into the host and the remote path.
+// A missing path is the remote home directory; a relative one is relative to it.
+func ParseTarget(target string) (host, dir string, err error) {
+	host, dir, _ = strings.Cut(target, ":")
+	if host == "" {
+		return "", "", fmt.Errorf("invalid target %q: expected HOST:PATH", target)
+	}
+	if dir == "" {
+		dir = "~"
+	}
+	return host, dir, nil
+}
+
+// Launch describes a claude launch on a host
+type Launch struct {
+	Dir         string   // Remote working directory
+	AllowedDirs []string // Remote directories the launch must be inside
+	ConfigDir   string   // Optional: remote CLAUDE_CONFIG_DIR
+	Claude      string   // Optional: remote claude command (default: claude)
+	Args        []string // Arguments passed to claude
+}
+
+// Script returns the POSIX shell script that checks the directory on the host and execs claude
+func (l Launch) Script() string {
+	claude := l.Claude
+	if claude == "" {
+		claude = "claude"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "dir=$(cd -- %s 2>/dev/null && pwd -P) || { printf 'claude-launcher: cannot enter %%s\\n' %s >&2; exit %d; }\n",
+		word(l.Dir), quote(l.Dir), ExitNoDir)
+	fmt.Fprintf(&b, "command -v -- %s >/dev/null 2>&1 || { printf 'claude-launcher: %%s not found on %%s\\n' %s \"$(hostname)\" >&2; exit %d; }\n",
+		quote(claude), quote(claude), ExitNotFound)
+
+	words := make([]string, 0, len(l.AllowedDirs))
+	for _, d := range l.AllowedDirs {
+		words = append(words, word(d))
+	}
+	fmt.Fprintf(&b, "for allowed in %s; do\n", strings.Join(words, " "))
+	b.WriteString("  allowed=$(cd -- \"$allowed\" 2>/dev/null && pwd -P) || continue\n")
+	b.WriteString("  case \"$dir/\" in \"${allowed%/}\"/*)\n")
+	b.WriteString("    cd -- \"$dir\" || exit 1\n")
+	b.WriteString("    exec")
+	if l.ConfigDir != "" {
+		b.WriteString(" env CLAUDE_CONFIG_DIR=" + word(l.ConfigDir))
+	}
+	b.WriteString(" " + quote(claude))
+	for _, arg := range l.Args {
+		b.WriteString(" " + quote(arg))
+	}
+	b.WriteString(" ;;\n  esac\ndone\n")
+	fmt.Fprintf(&b, "printf 'claude-launcher: %%s is not in the allowed directories of %%s\\n' \"$dir\" \"$(hostname)\" >&2\nexit %d\n", ExitDenied)
+	return b.String()
+}
+
+// SSHArgs returns the arguments of the ssh command running the launch on host with a pty.
+// The script is run by sh, whatever the login shell of the remote user.
+func (l Launch) SSHArgs(host string) []string {
+	return []string{"-t", "--", host, "sh -c " + quote(l.Script())}
+}
+
+// word returns p as a shell word, with a leading ~ expanded to the remote $HOME
+func word(p string) string {
+	if p == "~" {
+		return `"$HOME"`
+	}
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		return `"$HOME"/` + quote(rest)
+	}
+	return quote(p)
+}
+
+// quote quotes s for a POSIX shell
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
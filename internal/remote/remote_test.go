@@ -0,0 +1,116 @@
+package remote
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		host    string
+		dir     string
+		wantErr bool
+	}{
+		{target: "devbox:~/src/app", host: "devbox", dir: "~/src/app"},
+		{target: "me@devbox:/srv/app", host: "me@devbox", dir: "/srv/app"},
+		{target: "devbox", host: "devbox", dir: "~"},
+		{target: "devbox:", host: "devbox", dir: "~"},
+		{target: ":/srv", wantErr: true},
+	}
+	for _, tt := range tests {
+		host, dir, err := ParseTarget(tt.target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTarget(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			continue
+		}
+		if host != tt.host || dir != tt.dir {
+			t.Errorf("ParseTarget(%q) = %q, %q, expected %q, %q", tt.target, host, dir, tt.host, tt.dir)
+		}
+	}
+}
+
+func TestScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the script runs on POSIX hosts")
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+
+	// A fake home with a project, an outside directory and a link out of the allowed one
+	home := t.TempDir()
+	for _, dir := range []string{"src/app", "other", "bin"} {
+		if err := os.MkdirAll(filepath.Join(home, dir), 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(home, "other"), filepath.Join(home, "src", "escape")); err != nil {
+		t.Fatal(err)
+	}
+	fake := "#!/bin/sh\necho \"$PWD|$CLAUDE_CONFIG_DIR|$*\"\n"
+	if err := os.WriteFile(filepath.Join(home, "bin", "claude"), []byte(fake), 0o700); err != nil { // #nosec G306 -- test script
+		t.Fatal(err)
+	}
+	resolvedHome, err := filepath.EvalSymlinks(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		launch   Launch
+		expected string
+		code     int
+	}{
+		{
+			name:     "allowed",
+			launch:   Launch{Dir: "~/src/app", AllowedDirs: []string{"~/src"}, ConfigDir: "~/.claude-work", Args: []string{"--model", "it's"}},
+			expected: resolvedHome + "/src/app|" + home + "/.claude-work|--model it's\n",
+		},
+		{
+			name:     "relative to home",
+			launch:   Launch{Dir: "src/app", AllowedDirs: []string{"~"}},
+			expected: resolvedHome + "/src/app||\n",
+		},
+		{name: "outside", launch: Launch{Dir: "~/other", AllowedDirs: []string{"~/src"}}, code: ExitDenied},
+		{name: "symlink out", launch: Launch{Dir: "~/src/escape", AllowedDirs: []string{"~/src"}}, code: ExitDenied},
+		{name: "missing", launch: Launch{Dir: "~/missing", AllowedDirs: []string{"~"}}, code: ExitNoDir},
+		{name: "no claude", launch: Launch{Dir: "~", AllowedDirs: []string{"~"}, Claude: "no-such-claude"}, code: ExitNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(sh, "-c", tt.launch.Script()) // #nosec G204 -- test script
+			cmd.Dir = home
+			cmd.Env = []string{"HOME=" + home, "PATH=" + filepath.Join(home, "bin") + ":/usr/bin:/bin"}
+			out, err := cmd.Output()
+
+			code := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if code != tt.code {
+				t.Fatalf("exit code = %d, expected %d", code, tt.code)
+			}
+			if string(out) != tt.expected {
+				t.Errorf("output = %q, expected %q", out, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSSHArgs(t *testing.T) {
+	args := Launch{Dir: "~", AllowedDirs: []string{"~"}}.SSHArgs("devbox")
+	if len(args) != 4 || args[0] != "-t" || args[2] != "devbox" || !strings.HasPrefix(args[3], "sh -c '") {
+		t.Errorf("SSHArgs() = %q, expected -t -- devbox 'sh -c SCRIPT'", args)
+	}
+}
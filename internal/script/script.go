@@ -0,0 +1,193 @@
+// Package script runs the user's Starlark launch script, which inspects the launch context and
+// may change claude's arguments and environment or refuse the launch. Starlark has no access to
+// files, the network or other processes; scripts are further limited in steps and time.
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// EntryPoint is the function the script must define; it is called with the launch context
+const EntryPoint = "launch"
+
+// maxSteps bounds the work of a script, so a runaway loop fails instead of hanging the launch
+const maxSteps = 10_000_000
+
+// Launch is the context a script receives as ctx
+type Launch struct {
+	Dir     string            // ctx.dir: directory claude starts in
+	Project string            // ctx.project: matching projects entry ("" if none)
+	Account string            // ctx.account: selected account ("" without accounts)
+	Model   string            // ctx.model: resolved model ("" for claude's default)
+	Tool    string            // ctx.tool: agent CLI
+	Args    []string          // ctx.args: arguments passed to claude (mutable list)
+	Env     map[string]string // ctx.env: variables the launch adds (mutable dict)
+	Git     Git               // ctx.git
+}
+
+// Git describes the repository containing the launch directory
+type Git struct {
+	Root   string // ctx.git.root: top-level directory ("" outside a repository)
+	Branch string // ctx.git.branch: current branch ("HEAD" when detached)
+	Remote string // ctx.git.remote: URL of origin
+	Dirty  bool   // ctx.git.dirty: uncommitted changes
+}
+
+// Result is the launch after the script ran
+type Result struct {
+	Args   []string
+	Env    map[string]string
+	Denied bool   // deny() was called
+	Reason string // The message given to deny()
+}
+
+// Run executes the script at path and calls its launch(ctx) function, giving up when ctx is
+// done. print() output goes to output.
+func Run(ctx context.Context, path string, launch Launch, output func(string)) (*Result, error) {
+	src, err := os.ReadFile(path) // #nosec G304 -- the script path is from the user's config
+	if err != nil {
+		return nil, fmt.Errorf("failed to read launch script: %w", err)
+	}
+
+	result := &Result{}
+	thread := &starlark.Thread{
+		Name:  "launch",
+		Print: func(_ *starlark.Thread, msg string) { output(msg) },
+	}
+	thread.SetMaxExecutionSteps(maxSteps)
+	stop := context.AfterFunc(ctx, func() { thread.Cancel(ctx.Err().Error()) })
+	defer stop()
+
+	predeclared := starlark.StringDict{
+		"deny": starlark.NewBuiltin("deny", func(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var reason string
+			if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "reason?", &reason); err != nil {
+				return nil, err
+			}
+			result.Denied, result.Reason = true, reason
+			return starlark.None, nil
+		}),
+	}
+
+	opts := &syntax.FileOptions{Set: true, While: true, TopLevelControl: true, GlobalReassign: true}
+	globals, err := starlark.ExecFileOptions(opts, thread, path, src, predeclared)
+	if err != nil {
+		return nil, scriptError(err)
+	}
+	fn, ok := globals[EntryPoint].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("%s defines no %s(ctx) function", path, EntryPoint)
+	}
+
+	args := starlark.NewList(nil)
+	for _, arg := range launch.Args {
+		_ = args.Append(starlark.String(arg)) //nolint:errcheck // a new list is not frozen
+	}
+	env := starlark.NewDict(len(launch.Env))
+	for _, key := range slices.Sorted(maps.Keys(launch.Env)) {
+		_ = env.SetKey(starlark.String(key), starlark.String(launch.Env[key])) //nolint:errcheck // a new dict is not frozen
+	}
+	value := starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"dir":     starlark.String(launch.Dir),
+		"project": starlark.String(launch.Project),
+		"account": starlark.String(launch.Account),
+		"model":   starlark.String(launch.Model),
+		"tool":    starlark.String(launch.Tool),
+		"os":      starlark.String(runtime.GOOS),
+		"args":    args,
+		"env":     env,
+		"git": starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+			"root":   starlark.String(launch.Git.Root),
+			"branch": starlark.String(launch.Git.Branch),
+			"remote": starlark.String(launch.Git.Remote),
+			"dirty":  starlark.Bool(launch.Git.Dirty),
+		}),
+	})
+
+	if _, err := starlark.Call(thread, fn, starlark.Tuple{value}, nil); err != nil {
+		return nil, scriptError(err)
+	}
+
+	if result.Args, err = toStrings(args); err != nil {
+		return nil, fmt.Errorf("ctx.args: %w", err)
+	}
+	if result.Env, err = toStringMap(env); err != nil {
+		return nil, fmt.Errorf("ctx.env: %w", err)
+	}
+	return result, nil
+}
+
+// scriptError includes the Starlark backtrace, which points at the failing line
+func scriptError(err error) error {
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		return errors.New(evalErr.Backtrace())
+	}
+	return err
+}
+
+// toStrings converts a list of strings
+func toStrings(list *starlark.List) ([]string, error) {
+	out := make([]string, 0, list.Len())
+	for i := range list.Len() {
+		s, ok := starlark.AsString(list.Index(i))
+		if !ok {
+			return nil, fmt.Errorf("element %d is a %s, not a string", i, list.Index(i).Type())
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// toStringMap converts a dict of strings
+func toStringMap(dict *starlark.Dict) (map[string]string, error) {
+	out := make(map[string]string, dict.Len())
+	for _, item := range dict.Items() {
+		key, ok := starlark.AsString(item[0])
+		if !ok || key == "" || strings.Contains(key, "=") {
+			return nil, fmt.Errorf("invalid variable name %s", item[0])
+		}
+		value, ok := starlark.AsString(item[1])
+		if !ok {
+			return nil, fmt.Errorf("%s is a %s, not a string", key, item[1].Type())
+		}
+		out[key] = value
+	}
+	return out, nil
+}
+
+// GitInfo describes the repository containing dir; the zero value outside a repository or
+// without git
+func GitInfo(dir string) Git {
+	output := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		out, err := cmd.Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	root := output("rev-parse", "--show-toplevel")
+	if root == "" {
+		return Git{}
+	}
+	return Git{
+		Root:   root,
+		Branch: output("rev-parse", "--abbrev-ref", "HEAD"),
+		Remote: output("config", "--get", "remote.origin.url"),
+		Dirty:  output("status", "--porcelain") != "",
+	}
+}
@@ -0,0 +1,104 @@
+package script
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeScript writes a launch script and returns its path
+func writeScript(t *testing.T, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "launch.star")
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	path := writeScript(t, `
+TICKET_PREFIX = "feature/"
+
+def launch(ctx):
+    if ctx.git.branch.startswith(TICKET_PREFIX):
+        ctx.env["TICKET"] = ctx.git.branch[len(TICKET_PREFIX):]
+    if ctx.account == "Work" and "--verbose" not in ctx.args:
+        ctx.args.append("--verbose")
+    ctx.env.pop("DEBUG", None)
+    print("checked", ctx.dir)
+`)
+
+	var printed []string
+	result, err := Run(context.Background(), path, Launch{
+		Dir:     "/work/api",
+		Account: "Work",
+		Args:    []string{"-p", "hi"},
+		Env:     map[string]string{"DEBUG": "1"},
+		Git:     Git{Root: "/work/api", Branch: "feature/PROJ-7"},
+	}, func(msg string) { printed = append(printed, msg) })
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Denied {
+		t.Errorf("Run() denied the launch: %q", result.Reason)
+	}
+	if !slices.Equal(result.Args, []string{"-p", "hi", "--verbose"}) {
+		t.Errorf("Args = %q", result.Args)
+	}
+	if len(result.Env) != 1 || result.Env["TICKET"] != "PROJ-7" {
+		t.Errorf("Env = %v", result.Env)
+	}
+	if !slices.Equal(printed, []string{"checked /work/api"}) {
+		t.Errorf("printed %q", printed)
+	}
+}
+
+func TestRunDeny(t *testing.T) {
+	path := writeScript(t, `
+def launch(ctx):
+    if ctx.git.dirty:
+        deny("commit or stash your changes first")
+`)
+
+	result, err := Run(context.Background(), path, Launch{Git: Git{Dirty: true}}, func(string) {})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Denied || result.Reason != "commit or stash your changes first" {
+		t.Errorf("Run() = %+v, expected a denial", result)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"no entry point", "x = 1\n", "no launch(ctx) function"},
+		{"runtime error", "def launch(ctx):\n    ctx.args.append(1)\n", "ctx.args"},
+		{"bad env", "def launch(ctx):\n    ctx.env['A=B'] = 'x'\n", "invalid variable name"},
+		{"failing script", "def launch(ctx):\n    fail('boom')\n", "boom"},
+		{"no load", "load('other.star', 'x')\n", "load"},
+		{"runaway", "def launch(ctx):\n    while True:\n        pass\n", "too many steps"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Run(context.Background(), writeScript(t, tt.src), Launch{}, func(string) {})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Run() error = %v, expected %q", err, tt.want)
+			}
+		})
+	}
+
+	// The context stops a script like the step limit
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	slow := writeScript(t, "def launch(ctx):\n    for i in range(1000000000):\n        pass\n")
+	if _, err := Run(ctx, slow, Launch{}, func(string) {}); err == nil {
+		t.Error("Run() should stop when the context is done")
+	}
+}
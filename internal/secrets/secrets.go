@@ -0,0 +1,158 @@
+// Package secrets resolves secret references in configured environment values at launch
+// time, so API keys and proxy credentials need not be stored in plain text:
+//
+//   - keychain:ITEM reads the password of ITEM from the OS keychain (macOS Keychain, or the
+//     Secret Service on Linux via secret-tool)
+//   - op://VAULT/ITEM/FIELD reads a field with the 1Password CLI
+//   - env:NAME reads the variable NAME from the launcher's own environment
+//
+// Any other value is used as is.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Provider resolves the secret references of one scheme
+type Provider interface {
+	// Prefix is the start of the references the provider handles, e.g. "op://"
+	Prefix() string
+	// Resolve returns the secret a reference points to
+	Resolve(ref string) (string, error)
+}
+
+// providers are the supported secret backends
+var providers = []Provider{keychain{}, onePassword{}, envVar{}}
+
+// runCommand runs a helper CLI and returns its stdout; replaced in tests
+var runCommand = func(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).Output() // #nosec G204 -- fixed helper binaries
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s: %s", name, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return string(out), nil
+}
+
+// provider returns the provider handling value, or nil when it is not a secret reference
+func provider(value string) Provider {
+	for _, p := range providers {
+		if strings.HasPrefix(value, p.Prefix()) {
+			return p
+		}
+	}
+	return nil
+}
+
+// IsReference reports whether value is a secret reference
+func IsReference(value string) bool {
+	return provider(value) != nil
+}
+
+// Resolve returns the secret value refers to, or value itself when it is not a reference
+func Resolve(value string) (string, error) {
+	p := provider(value)
+	if p == nil {
+		return value, nil
+	}
+	secret, err := p.Resolve(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", value, err)
+	}
+	return secret, nil
+}
+
+// ResolveEnv replaces the secret references among the values of env with their secrets and
+// returns the names of the variables that held one. Each reference is resolved once.
+func ResolveEnv(env map[string]string) ([]string, error) {
+	var names []string
+	resolved := map[string]string{}
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		ref := env[name]
+		if !IsReference(ref) {
+			continue
+		}
+		secret, ok := resolved[ref]
+		if !ok {
+			var err error
+			if secret, err = Resolve(ref); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			resolved[ref] = secret
+		}
+		env[name] = secret
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// trimNewline removes the line break CLIs print after a secret
+func trimNewline(s string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(s, "\n"), "\r")
+}
+
+// keychain reads generic passwords from the OS keychain
+type keychain struct{}
+
+func (keychain) Prefix() string { return "keychain:" }
+
+func (k keychain) Resolve(ref string) (string, error) {
+	item := strings.TrimPrefix(ref, k.Prefix())
+	if item == "" {
+		return "", errors.New("empty keychain item")
+	}
+
+	var out string
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		// Stored with: security add-generic-password -s ITEM -a "$USER" -w
+		out, err = runCommand("security", "find-generic-password", "-s", item, "-w")
+	case "windows":
+		return "", errors.New("keychain references are not supported on Windows; use op:// instead")
+	default:
+		// Stored with: secret-tool store --label=ITEM service ITEM
+		out, err = runCommand("secret-tool", "lookup", "service", item)
+	}
+	if err != nil {
+		return "", err
+	}
+	return trimNewline(out), nil
+}
+
+// onePassword reads secret references with the 1Password CLI
+type onePassword struct{}
+
+func (onePassword) Prefix() string { return "op://" }
+
+func (onePassword) Resolve(ref string) (string, error) {
+	out, err := runCommand("op", "read", "--no-newline", ref)
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// envVar reads secrets from the launcher's environment, e.g. one exported by a password manager
+type envVar struct{}
+
+func (envVar) Prefix() string { return "env:" }
+
+func (e envVar) Resolve(ref string) (string, error) {
+	name := strings.TrimPrefix(ref, e.Prefix())
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return "", fmt.Errorf("%s is not set", name)
+	}
+	return value, nil
+}
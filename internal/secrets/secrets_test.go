@@ -0,0 +1,106 @@
+package secrets
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// fakeCommands replaces runCommand for the test, answering with results keyed by the command line
+func fakeCommands(t *testing.T, results map[string]string) *[][]string {
+	t.Helper()
+	var calls [][]string
+	original := runCommand
+	runCommand = func(name string, args ...string) (string, error) {
+		call := append([]string{name}, args...)
+		calls = append(calls, call)
+		for key, out := range results {
+			if slices.Contains(call, key) {
+				return out, nil
+			}
+		}
+		return "", errors.New("item not found")
+	}
+	t.Cleanup(func() { runCommand = original })
+	return &calls
+}
+
+func TestResolve(t *testing.T) {
+	calls := fakeCommands(t, map[string]string{"op://dev/anthropic/key": "sk-op"})
+
+	tests := []struct {
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{value: "plain", expected: "plain"},
+		{value: "", expected: ""},
+		{value: "op://dev/anthropic/key", expected: "sk-op"},
+		{value: "op://dev/missing/key", wantErr: true},
+		{value: "keychain:", wantErr: true},
+		{value: "env:LAUNCHER_TEST_KEY", expected: "sk-env"},
+		{value: "env:LAUNCHER_TEST_UNSET", wantErr: true},
+	}
+	t.Setenv("LAUNCHER_TEST_KEY", "sk-env")
+	for _, tt := range tests {
+		got, err := Resolve(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Resolve(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Resolve(%q) = %q, expected %q", tt.value, got, tt.expected)
+		}
+	}
+
+	expected := []string{"op", "read", "--no-newline", "op://dev/anthropic/key"}
+	if len(*calls) == 0 || !reflect.DeepEqual((*calls)[0], expected) {
+		t.Errorf("first call = %q, expected %q", *calls, expected)
+	}
+}
+
+func TestResolveKeychain(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("keychain references are not supported on Windows")
+	}
+	fakeCommands(t, map[string]string{"proxy-password": "s3cret\n"})
+
+	got, err := Resolve("keychain:proxy-password")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got != "s3cret" {
+		t.Errorf("Resolve() = %q, expected the trailing newline removed", got)
+	}
+}
+
+func TestResolveEnv(t *testing.T) {
+	calls := fakeCommands(t, map[string]string{"op://dev/anthropic/key": "sk-op"})
+
+	env := map[string]string{
+		"ANTHROPIC_API_KEY": "op://dev/anthropic/key",
+		"OTHER_KEY":         "op://dev/anthropic/key",
+		"LANG":              "C",
+	}
+	names, err := ResolveEnv(env)
+	if err != nil {
+		t.Fatalf("ResolveEnv() error = %v", err)
+	}
+
+	expected := map[string]string{"ANTHROPIC_API_KEY": "sk-op", "OTHER_KEY": "sk-op", "LANG": "C"}
+	if !reflect.DeepEqual(env, expected) {
+		t.Errorf("env = %v, expected %v", env, expected)
+	}
+	if !reflect.DeepEqual(names, []string{"ANTHROPIC_API_KEY", "OTHER_KEY"}) {
+		t.Errorf("names = %v, expected both references", names)
+	}
+	if len(*calls) != 1 {
+		t.Errorf("op was run %d times, expected the reference to be resolved once", len(*calls))
+	}
+
+	if _, err := ResolveEnv(map[string]string{"KEY": "op://dev/missing/key"}); err == nil {
+		t.Error("ResolveEnv() should fail for an unknown item")
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/23prime/claude-launcher/internal/log"
 )
 
 // DirectoryChecker checks if a directory is allowed
 type DirectoryChecker struct {
 	AllowedDirs []string
+	// Progress, when set, is called before each allowed directory is resolved
+	// (resolving can be slow on network filesystems)
+	Progress func(done, total int, dir string)
 }
 
 // NewDirectoryChecker creates a new DirectoryChecker
@@ -21,32 +26,127 @@ func NewDirectoryChecker(allowedDirs []string) *DirectoryChecker {
 
 // IsAllowed checks if the current directory is allowed
 func (dc *DirectoryChecker) IsAllowed(currentDir string) (bool, error) {
+	match, err := dc.Match(currentDir)
+	return match != "", err
+}
+
+// Match returns the first allowed directory containing the current directory, or "" if none does
+func (dc *DirectoryChecker) Match(currentDir string) (string, error) {
 	// Resolve the current directory path
 	resolvedCurrent, err := ResolvePath(currentDir)
 	if err != nil {
-		return false, fmt.Errorf("failed to resolve current directory: %w", err)
+		return "", fmt.Errorf("failed to resolve current directory: %w", err)
 	}
+	log.Debug("directory check", "dir", currentDir, "resolved", resolvedCurrent)
 
-	for _, allowedDir := range dc.AllowedDirs {
-		// Skip if the allowed directory doesn't exist
-		if _, err := os.Stat(allowedDir); os.IsNotExist(err) {
-			continue
+	for i, allowedDir := range dc.AllowedDirs {
+		if dc.Progress != nil {
+			dc.Progress(i, len(dc.AllowedDirs), allowedDir)
 		}
 
-		// Resolve the allowed directory path
-		resolvedAllowed, err := ResolvePath(allowedDir)
-		if err != nil {
-			// Skip this allowed directory if we can't resolve it
-			continue
+		rule := checkRule(allowedDir, resolvedCurrent)
+		switch rule.Status {
+		case RuleMissing:
+			log.Debug("allowed directory skipped", "allowed", allowedDir, "reason", "does not exist")
+		case RuleUnresolvable:
+			log.Debug("allowed directory skipped", "allowed", allowedDir, "error", rule.Err)
+		case RuleMatched:
+			log.Debug("allowed directory matched", "allowed", allowedDir, "resolved", rule.Resolved)
+			return allowedDir, nil
+		default:
+			log.Debug("allowed directory did not match", "allowed", allowedDir, "resolved", rule.Resolved)
 		}
+	}
 
-		// Check if current directory is the allowed directory or a subdirectory
-		if isPathEqual(resolvedCurrent, resolvedAllowed) || isSubdirectory(resolvedCurrent, resolvedAllowed) {
-			return true, nil
+	log.Debug("directory not allowed", "resolved", resolvedCurrent)
+	return "", nil
+}
+
+// RuleStatus is the outcome of one allowed directory for a path
+type RuleStatus string
+
+// Rule outcomes reported by Explain
+const (
+	RuleMatched      RuleStatus = "matched"      // The path is the directory or inside it
+	RuleNotMatched   RuleStatus = "not matched"  // The path is outside the directory
+	RuleMissing      RuleStatus = "missing"      // The directory does not exist and is skipped
+	RuleUnresolvable RuleStatus = "unresolvable" // The directory cannot be resolved and is skipped
+)
+
+// RuleResult describes how one allowed directory was evaluated
+type RuleResult struct {
+	Dir      string // As configured
+	Resolved string // After resolving symlinks; empty when skipped
+	Status   RuleStatus
+	Err      error // Why the directory could not be resolved
+}
+
+// Explanation is the full reasoning behind a directory decision
+type Explanation struct {
+	Path     string // Absolute path, before resolving symlinks
+	Resolved string // The path the rules are compared against
+	Rules    []RuleResult
+}
+
+// Match returns the first matching rule, or nil if none matched
+func (e *Explanation) Match() *RuleResult {
+	for i := range e.Rules {
+		if e.Rules[i].Status == RuleMatched {
+			return &e.Rules[i]
 		}
 	}
+	return nil
+}
+
+// Explain evaluates every allowed directory for path. Unlike Match, it does not stop at the
+// first match, so the result shows why each rule applies or not.
+func (dc *DirectoryChecker) Explain(path string) (*Explanation, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	resolved, err := ResolvePath(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve current directory: %w", err)
+	}
+
+	e := &Explanation{Path: absPath, Resolved: resolved}
+	for _, allowedDir := range dc.AllowedDirs {
+		e.Rules = append(e.Rules, checkRule(allowedDir, resolved))
+	}
+	return e, nil
+}
+
+// checkRule evaluates allowedDir for an already resolved path
+func checkRule(allowedDir, resolvedPath string) RuleResult {
+	rule := RuleResult{Dir: allowedDir}
+
+	// Skip if the allowed directory doesn't exist
+	if _, err := os.Stat(allowedDir); os.IsNotExist(err) {
+		rule.Status = RuleMissing
+		return rule
+	}
+
+	// Resolve the allowed directory path
+	resolvedAllowed, err := ResolvePath(allowedDir)
+	if err != nil {
+		rule.Status, rule.Err = RuleUnresolvable, err
+		return rule
+	}
+	rule.Resolved = resolvedAllowed
+
+	// Check if the path is the allowed directory or a subdirectory
+	if isPathEqual(resolvedPath, resolvedAllowed) || isSubdirectory(resolvedPath, resolvedAllowed) {
+		rule.Status = RuleMatched
+	} else {
+		rule.Status = RuleNotMatched
+	}
+	return rule
+}
 
-	return false, nil
+// IsWithin reports whether path is dir or inside it, comparing the paths as written
+func IsWithin(path, dir string) bool {
+	return isPathEqual(path, dir) || isSubdirectory(path, dir)
 }
 
 // ResolvePath resolves symlinks and returns the absolute path
@@ -1,8 +1,10 @@
 package security
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -342,3 +344,135 @@ func TestDirectoryChecker_IsAllowed_NonExistentAllowedDir(t *testing.T) {
 		t.Error("DirectoryChecker.IsAllowed() should return true for existing allowed dir")
 	}
 }
+
+func TestDirectoryChecker_Match(t *testing.T) {
+	tmpDir := t.TempDir()
+	outer := filepath.Join(tmpDir, "develop")
+	inner := filepath.Join(outer, "api")
+	if err := os.MkdirAll(inner, 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	checker := NewDirectoryChecker([]string{"/non/existent/path", outer, inner})
+
+	match, err := checker.Match(inner)
+	if err != nil {
+		t.Fatalf("DirectoryChecker.Match() error = %v", err)
+	}
+	if match != outer {
+		t.Errorf("DirectoryChecker.Match() = %q, expected the first matching entry %q", match, outer)
+	}
+
+	match, err = checker.Match(tmpDir)
+	if err != nil {
+		t.Fatalf("DirectoryChecker.Match() error = %v", err)
+	}
+	if match != "" {
+		t.Errorf("DirectoryChecker.Match() = %q, expected no match", match)
+	}
+}
+
+func TestDirectoryChecker_Progress(t *testing.T) {
+	tmpDir := t.TempDir()
+	missing := filepath.Join(tmpDir, "missing")
+
+	var reported []string
+	checker := NewDirectoryChecker([]string{missing, tmpDir})
+	checker.Progress = func(done, total int, dir string) {
+		reported = append(reported, fmt.Sprintf("%d/%d %s", done, total, dir))
+	}
+
+	if _, err := checker.Match(tmpDir); err != nil {
+		t.Fatalf("DirectoryChecker.Match() error = %v", err)
+	}
+
+	expected := []string{"0/2 " + missing, "1/2 " + tmpDir}
+	if !reflect.DeepEqual(reported, expected) {
+		t.Errorf("Progress calls = %v, expected %v", reported, expected)
+	}
+}
+
+func TestDirectoryChecker_Explain(t *testing.T) {
+	tmpDir := t.TempDir()
+	allowed := filepath.Join(tmpDir, "allowed")
+	outside := filepath.Join(tmpDir, "outside")
+	for _, dir := range []string{allowed, outside} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+	}
+	// A symlink inside the allowed directory pointing outside of it
+	link := filepath.Join(allowed, "link")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	missing := filepath.Join(tmpDir, "missing")
+
+	dc := NewDirectoryChecker([]string{missing, outside, allowed})
+
+	e, err := dc.Explain(link)
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if e.Path != link {
+		t.Errorf("Path = %q, expected %q", e.Path, link)
+	}
+	resolvedOutside, _ := ResolvePath(outside) //nolint:errcheck // the directory exists
+	if e.Resolved != resolvedOutside {
+		t.Errorf("Resolved = %q, expected %q", e.Resolved, resolvedOutside)
+	}
+
+	expected := []RuleStatus{RuleMissing, RuleMatched, RuleNotMatched}
+	for i, rule := range e.Rules {
+		if rule.Status != expected[i] {
+			t.Errorf("Rules[%d] (%s) = %s, expected %s", i, rule.Dir, rule.Status, expected[i])
+		}
+	}
+	if match := e.Match(); match == nil || match.Dir != outside {
+		t.Errorf("Match() = %+v, expected %s", match, outside)
+	}
+
+	// All rules are evaluated even after a match, unlike Match
+	if len(e.Rules) != 3 {
+		t.Errorf("expected 3 rules, got %d", len(e.Rules))
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	tests := []struct {
+		path, dir string
+		expected  bool
+	}{
+		{"/home/user/work", "/home/user/work", true},
+		{"/home/user/work/api", "/home/user/work", true},
+		{"/home/user/workspace", "/home/user/work", false},
+		{"/home/user", "/home/user/work", false},
+	}
+	for _, tt := range tests {
+		if got := IsWithin(tt.path, tt.dir); got != tt.expected {
+			t.Errorf("IsWithin(%q, %q) = %v, expected %v", tt.path, tt.dir, got, tt.expected)
+		}
+	}
+}
+
+func BenchmarkDirectoryChecker_IsAllowed(b *testing.B) {
+	root := b.TempDir()
+	dirs := make([]string, 20)
+	for i := range dirs {
+		dirs[i] = filepath.Join(root, fmt.Sprintf("allowed-%d", i))
+		if err := os.MkdirAll(dirs[i], 0o755); err != nil {
+			b.Fatal(err)
+		}
+	}
+	target := filepath.Join(dirs[len(dirs)-1], "project", "src")
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		b.Fatal(err)
+	}
+
+	checker := NewDirectoryChecker(dirs)
+	for b.Loop() {
+		if allowed, err := checker.IsAllowed(target); err != nil || !allowed {
+			b.Fatalf("IsAllowed() = %v, %v", allowed, err)
+		}
+	}
+}
@@ -1,17 +1,35 @@
 package session
 
 import (
-	"bufio"
 	"fmt"
 	"io"
+	"os"
+	"strconv"
 	"strings"
 
+	"github.com/23prime/claude-launcher/internal/i18n"
 	"github.com/23prime/claude-launcher/internal/ui"
 )
 
 // Prompter is an interface for asking user about session continuation
 type Prompter interface {
-	AskContinue() (bool, error)
+	AskSession(pick bool) (Choice, error)
+}
+
+// Choice is the answer to the session question
+type Choice int
+
+const (
+	ChoiceContinue Choice = iota // Continue the latest session
+	ChoicePick                   // Choose the session in the tool's picker
+	ChoiceNew                    // Start a new session
+)
+
+// choiceLabels are the menu items of the choices
+var choiceLabels = map[Choice]string{
+	ChoiceContinue: "Continue latest",
+	ChoicePick:     "Choose a session…",
+	ChoiceNew:      "Start new",
 }
 
 // InteractivePrompter prompts the user interactively
@@ -28,30 +46,76 @@ func NewInteractivePrompter(reader io.Reader, printer *ui.Printer) *InteractiveP
 	}
 }
 
-// AskContinue asks the user if they want to continue the previous session
-func (p *InteractivePrompter) AskContinue() (bool, error) {
-	p.Printer.Warning("Continue previous Claude session?\n")
-	p.Printer.Print("  [Y/n] (default: y): ")
+// AskSession asks the user whether to continue the latest session, choose one (offered when
+// pick is true) or start a new one. It shows an arrow-key menu when reading a terminal, and a
+// numbered list otherwise, where "y" and "n" still answer as they did for the yes/no question.
+func (p *InteractivePrompter) AskSession(pick bool) (Choice, error) {
+	choices := []Choice{ChoiceContinue, ChoicePick, ChoiceNew}
+	if !pick {
+		choices = []Choice{ChoiceContinue, ChoiceNew}
+	}
+	items := make([]string, len(choices))
+	for i, c := range choices {
+		items[i] = i18n.T(choiceLabels[c])
+	}
+	question := i18n.T("Continue previous Claude session?")
 
-	scanner := bufio.NewScanner(p.Reader)
-	if !scanner.Scan() {
-		if err := scanner.Err(); err != nil {
-			return false, fmt.Errorf("failed to read input: %w", err)
+	if p.Reader == os.Stdin && ui.ArrowMenus() {
+		idx, err := ui.Select(question, items)
+		if err != nil {
+			return ChoiceContinue, fmt.Errorf("session selection failed: %w", err)
 		}
-		// EOF or no input, use default (yes)
-		return true, nil
+		return choices[idx], nil
+	}
+
+	p.Printer.Question("%s\n", question)
+	for i, item := range items {
+		p.Printer.Print("  %d) %s\n", i+1, item)
 	}
+	p.Printer.Print("  [1-%d] (default: 1): ", len(items))
+	p.Printer.Flush()
 
-	response := strings.TrimSpace(scanner.Text())
-	response = strings.ToLower(response)
+	response, err := ui.ReadLine(p.Reader)
+	if err != nil {
+		return ChoiceContinue, fmt.Errorf("failed to read input: %w", err)
+	}
+	switch strings.ToLower(response) {
+	case "n", "no":
+		return ChoiceNew, nil
+	case "y", "yes":
+		return ChoiceContinue, nil
+	}
+	if n, err := strconv.Atoi(response); err == nil && n >= 1 && n <= len(choices) {
+		return choices[n-1], nil
+	}
+	// For empty or any other input, continue as the yes/no question did
+	return ChoiceContinue, nil
+}
 
-	switch response {
+// Confirm asks a yes/no question, translated with i18n.
+// Empty input, EOF, or unrecognized input selects defaultYes.
+func (p *InteractivePrompter) Confirm(question string, defaultYes bool) (bool, error) {
+	p.Printer.Question("%s\n", i18n.T(question))
+	if defaultYes {
+		p.Printer.Print("  [Y/n] (default: y): ")
+	} else {
+		p.Printer.Print("  [y/N] (default: n): ")
+	}
+	p.Printer.Flush()
+
+	// Read unbuffered so input for later prompts is not lost
+	response, err := ui.ReadLine(p.Reader)
+	if err != nil {
+		return false, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	switch strings.ToLower(response) {
 	case "n", "no":
 		return false, nil
-	case "", "y", "yes":
+	case "y", "yes":
 		return true, nil
 	default:
-		// For any other input, default to yes
-		return true, nil
+		// For empty or any other input, use default
+		return defaultYes, nil
 	}
 }
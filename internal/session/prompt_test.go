@@ -0,0 +1,83 @@
+package session
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		defaultYes bool
+		expected   bool
+	}{
+		{name: "yes", input: "y\n", defaultYes: false, expected: true},
+		{name: "no", input: "NO\n", defaultYes: true, expected: false},
+		{name: "empty uses default yes", input: "\n", defaultYes: true, expected: true},
+		{name: "empty uses default no", input: "\n", defaultYes: false, expected: false},
+		{name: "EOF uses default", input: "", defaultYes: true, expected: true},
+		{name: "other input uses default", input: "maybe\n", defaultYes: false, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewInteractivePrompter(strings.NewReader(tt.input), ui.NewPrinter(&bytes.Buffer{}))
+			got, err := p.Confirm("Proceed?", tt.defaultYes)
+			if err != nil {
+				t.Fatalf("Confirm() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Confirm() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConsecutivePromptsShareInput(t *testing.T) {
+	p := NewInteractivePrompter(strings.NewReader("y\nn\n"), ui.NewPrinter(&bytes.Buffer{}))
+
+	first, err := p.Confirm("First?", false)
+	if err != nil || !first {
+		t.Fatalf("first Confirm() = (%v, %v), expected (true, nil)", first, err)
+	}
+
+	second, err := p.AskSession(true)
+	if err != nil || second != ChoiceNew {
+		t.Errorf("AskSession() = (%v, %v), expected (%v, nil)", second, err, ChoiceNew)
+	}
+}
+
+func TestAskSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		pick     bool
+		expected Choice
+	}{
+		{name: "empty continues", input: "\n", pick: true, expected: ChoiceContinue},
+		{name: "EOF continues", input: "", pick: true, expected: ChoiceContinue},
+		{name: "pick", input: "2\n", pick: true, expected: ChoicePick},
+		{name: "new", input: "3\n", pick: true, expected: ChoiceNew},
+		{name: "new without picker", input: "2\n", pick: false, expected: ChoiceNew},
+		{name: "out of range continues", input: "3\n", pick: false, expected: ChoiceContinue},
+		{name: "yes", input: "Y\n", pick: true, expected: ChoiceContinue},
+		{name: "no", input: "no\n", pick: true, expected: ChoiceNew},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewInteractivePrompter(strings.NewReader(tt.input), ui.NewPrinter(&bytes.Buffer{}))
+			got, err := p.AskSession(tt.pick)
+			if err != nil {
+				t.Fatalf("AskSession() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("AskSession() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
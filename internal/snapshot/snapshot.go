@@ -0,0 +1,216 @@
+// Package snapshot records the git working tree of a launch directory before an agent works in
+// it, so the launcher can roll it back afterwards.
+//
+// A snapshot is a commit shaped like a git stash entry: its tree is the working tree (untracked,
+// non-ignored files included), its first parent HEAD and its second parent a commit of the
+// index. Depending on the mode, it is kept as a stash entry, under a side ref or checked out in
+// a separate worktree.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// Snapshot modes: how the snapshot commit is kept
+const (
+	ModeStash    = "stash"    // An entry in the stash list
+	ModeCommit   = "commit"   // A ref under RefPrefix
+	ModeWorktree = "worktree" // A detached worktree in the state directory
+)
+
+// RefPrefix is where commit snapshots are kept
+const RefPrefix = "refs/claude-launcher/snapshots/"
+
+// snapshotsFile lists the snapshots in the state store, one Snapshot per line
+const snapshotsFile = "snapshots.jsonl"
+
+// ErrNotRepository is returned by Create for directories outside git repositories
+var ErrNotRepository = errors.New("not in a git repository")
+
+// ErrNoCommit is returned by Create for repositories without a commit to go back to
+var ErrNoCommit = errors.New("the repository has no commit yet")
+
+// Snapshot is a recovery point of a git working tree
+type Snapshot struct {
+	ID        string    `json:"id"`
+	Mode      string    `json:"mode"`
+	Root      string    `json:"root"`             // Top-level directory of the working tree
+	Branch    string    `json:"branch,omitempty"` // Checked-out branch; empty when HEAD was detached
+	Head      string    `json:"head"`
+	Index     string    `json:"index"`              // Commit of the index
+	Commit    string    `json:"commit"`             // Commit of the working tree
+	Worktree  string    `json:"worktree,omitempty"` // Set in worktree mode
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ValidMode reports whether mode is a snapshot mode
+func ValidMode(mode string) bool {
+	return mode == ModeStash || mode == ModeCommit || mode == ModeWorktree
+}
+
+// Create snapshots the working tree containing dir and keeps it according to mode. worktreeDir
+// is where worktree mode checks it out. Neither the working tree nor the index is modified.
+func Create(dir, mode, id, worktreeDir string) (*Snapshot, error) {
+	root, err := git(dir, nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, ErrNotRepository
+	}
+	head, err := git(root, nil, "rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+	if err != nil {
+		return nil, ErrNoCommit
+	}
+	branch, _ := git(root, nil, "symbolic-ref", "--quiet", "--short", "HEAD") //nolint:errcheck // fails when detached
+	gitDir, err := git(root, nil, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{ID: id, Mode: mode, Root: root, Branch: branch, Head: head, CreatedAt: time.Now()}
+	indexTree, err := git(root, nil, "write-tree")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write the index (unresolved conflicts?): %w", err)
+	}
+	if s.Index, err = commitTree(root, indexTree, "index of "+message(id), head); err != nil {
+		return nil, err
+	}
+	workTree, err := writeWorkTree(root, gitDir)
+	if err != nil {
+		return nil, err
+	}
+	if s.Commit, err = commitTree(root, workTree, message(id), head, s.Index); err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case ModeStash:
+		_, err = git(root, nil, "stash", "store", "--message", message(id), s.Commit)
+	case ModeCommit:
+		_, err = git(root, nil, "update-ref", "-m", message(id), RefPrefix+id, s.Commit)
+	case ModeWorktree:
+		s.Worktree = worktreeDir
+		_, err = git(root, nil, "worktree", "add", "--detach", worktreeDir, s.Commit)
+	default:
+		err = fmt.Errorf("unknown snapshot mode %q", mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// writeWorkTree writes a tree of the working tree, untracked files included, through a copy of
+// the index so the real one is left alone
+func writeWorkTree(root, gitDir string) (string, error) {
+	tmp, err := os.CreateTemp("", "claude-launcher-index-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }() //nolint:errcheck // best-effort cleanup of temp file
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	// Starting from the index lets git skip hashing the files that did not change
+	if index, err := os.ReadFile(filepath.Join(gitDir, "index")); err == nil {
+		if err := os.WriteFile(tmp.Name(), index, 0o600); err != nil {
+			return "", err
+		}
+	}
+
+	env := []string{"GIT_INDEX_FILE=" + tmp.Name()}
+	if _, err := git(root, env, "add", "--all"); err != nil {
+		return "", err
+	}
+	return git(root, env, "write-tree")
+}
+
+// commitTree writes a commit of tree with parents. Snapshot commits are made by the launcher,
+// which also keeps them working in repositories without a configured identity.
+func commitTree(root, tree, msg string, parents ...string) (string, error) {
+	args := []string{"commit-tree", tree, "-m", msg}
+	for _, parent := range parents {
+		args = append(args, "-p", parent)
+	}
+	return git(root, []string{
+		"GIT_AUTHOR_NAME=claude-launcher", "GIT_AUTHOR_EMAIL=claude-launcher@localhost",
+		"GIT_COMMITTER_NAME=claude-launcher", "GIT_COMMITTER_EMAIL=claude-launcher@localhost",
+	}, args...)
+}
+
+// Restore brings the working tree of s back to the snapshot: HEAD (and the branch it was on) to
+// the commit it pointed at, the files to their snapshot contents and the index to its snapshot
+// state. Changes made since, and untracked files created since, are discarded.
+func Restore(s *Snapshot) error {
+	if _, err := git(s.Root, nil, "cat-file", "-e", s.Commit+"^{commit}"); err != nil {
+		return fmt.Errorf("snapshot %s is gone (its stash entry, ref or worktree was removed)", s.ID)
+	}
+
+	steps := [][]string{
+		{"read-tree", "--reset", "-u", s.Commit},
+		{"clean", "--force", "-d"},
+		{"read-tree", s.Index},
+	}
+	if s.Branch != "" {
+		steps = append([][]string{
+			{"symbolic-ref", "HEAD", "refs/heads/" + s.Branch},
+			{"update-ref", "-m", "claude-launcher rollback " + s.ID, "refs/heads/" + s.Branch, s.Head},
+		}, steps...)
+	} else {
+		steps = append([][]string{{"update-ref", "--no-deref", "HEAD", s.Head}}, steps...)
+	}
+	for _, args := range steps {
+		if _, err := git(s.Root, nil, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save appends s to the snapshots in store
+func Save(store *state.Store, s *Snapshot) error {
+	return store.AppendJSONLine(snapshotsFile, s)
+}
+
+// List returns the snapshots in store, oldest first. Unparseable lines are skipped.
+func List(store *state.Store) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	err := store.ReadJSONLines(snapshotsFile, func(line []byte) error {
+		var s Snapshot
+		if err := json.Unmarshal(line, &s); err == nil {
+			snapshots = append(snapshots, s)
+		}
+		return nil
+	})
+	return snapshots, err
+}
+
+// message is the subject of a snapshot commit and stash entry
+func message(id string) string {
+	return "claude-launcher snapshot " + id
+}
+
+// git runs git in dir with extra environment variables and returns its trimmed output.
+// Errors carry what git printed on stderr.
+func git(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,169 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// testRepo is a repository with a commit, a staged change, an unstaged change and an untracked file
+type testRepo struct {
+	t    *testing.T
+	root string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	r := &testRepo{t: t, root: t.TempDir()}
+	r.git("init", "-q", "-b", "main")
+	r.write("a.txt", "a1\n")
+	r.write("b.txt", "b1\n")
+	r.git("add", ".")
+	r.git("commit", "-q", "-m", "init")
+
+	r.write("a.txt", "a2\n")
+	r.git("add", "a.txt")
+	r.write("b.txt", "b2\n")
+	r.write("notes.txt", "todo\n")
+	return r
+}
+
+func (r *testRepo) git(args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", r.root, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func (r *testRepo) write(name, content string) {
+	r.t.Helper()
+	if err := os.WriteFile(filepath.Join(r.root, name), []byte(content), 0o600); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+func (r *testRepo) read(name string) string {
+	r.t.Helper()
+	data, err := os.ReadFile(filepath.Join(r.root, name))
+	if err != nil {
+		return "<missing>"
+	}
+	return string(data)
+}
+
+// mess simulates an agent: it commits, edits, deletes and creates files and switches branches
+func (r *testRepo) mess() {
+	r.t.Helper()
+	r.git("commit", "-q", "-am", "agent")
+	r.git("checkout", "-q", "-b", "agent")
+	r.write("a.txt", "agent\n")
+	if err := os.Remove(filepath.Join(r.root, "b.txt")); err != nil {
+		r.t.Fatal(err)
+	}
+	r.write("new.txt", "agent\n")
+	r.git("add", "new.txt")
+	r.write("scratch.txt", "agent\n")
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	r := newTestRepo(t)
+	head := r.git("rev-parse", "HEAD")
+	status := r.git("status", "--porcelain")
+
+	s, err := Create(r.root, ModeCommit, "a1b2c3d4", "")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if s.Branch != "main" || s.Head != head {
+		t.Errorf("Create() = branch %q head %s, want main %s", s.Branch, s.Head, head)
+	}
+	if got := r.git("status", "--porcelain"); got != status {
+		t.Errorf("Create() changed the status to %q, want %q", got, status)
+	}
+	if got := r.git("rev-parse", RefPrefix+"a1b2c3d4"); got != s.Commit {
+		t.Errorf("snapshot ref = %s, want %s", got, s.Commit)
+	}
+
+	r.mess()
+	if err := Restore(s); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := r.git("symbolic-ref", "--short", "HEAD"); got != "main" {
+		t.Errorf("branch after Restore() = %s, want main", got)
+	}
+	if got := r.git("rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD after Restore() = %s, want %s", got, head)
+	}
+	for name, want := range map[string]string{
+		"a.txt": "a2\n", "b.txt": "b2\n", "notes.txt": "todo\n",
+		"new.txt": "<missing>", "scratch.txt": "<missing>",
+	} {
+		if got := r.read(name); got != want {
+			t.Errorf("%s after Restore() = %q, want %q", name, got, want)
+		}
+	}
+	if got := r.git("status", "--porcelain"); got != status {
+		t.Errorf("status after Restore() = %q, want %q", got, status)
+	}
+}
+
+func TestCreateModes(t *testing.T) {
+	r := newTestRepo(t)
+
+	s, err := Create(r.root, ModeStash, "stash1", "")
+	if err != nil {
+		t.Fatalf("Create(stash) error = %v", err)
+	}
+	if got := r.git("stash", "list"); !strings.Contains(got, "claude-launcher snapshot stash1") {
+		t.Errorf("stash list = %q, want the snapshot", got)
+	}
+	if got := r.git("rev-parse", "stash@{0}"); got != s.Commit {
+		t.Errorf("stash@{0} = %s, want %s", got, s.Commit)
+	}
+
+	worktree := filepath.Join(t.TempDir(), "snapshot")
+	if _, err := Create(r.root, ModeWorktree, "tree1", worktree); err != nil {
+		t.Fatalf("Create(worktree) error = %v", err)
+	}
+	for name, want := range map[string]string{"a.txt": "a2\n", "b.txt": "b2\n", "notes.txt": "todo\n"} {
+		if data, err := os.ReadFile(filepath.Join(worktree, name)); err != nil || string(data) != want {
+			t.Errorf("worktree %s = %q, %v; want %q", name, data, err, want)
+		}
+	}
+}
+
+func TestCreateOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	if _, err := Create(t.TempDir(), ModeCommit, "x", ""); !errors.Is(err, ErrNotRepository) {
+		t.Errorf("Create() error = %v, want ErrNotRepository", err)
+	}
+}
+
+func TestSaveAndList(t *testing.T) {
+	store := &state.Store{Dir: t.TempDir()}
+	for _, id := range []string{"one", "two"} {
+		if err := Save(store, &Snapshot{ID: id, Mode: ModeCommit}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshots, err := List(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[0].ID != "one" || snapshots[1].ID != "two" {
+		t.Errorf("List() = %+v, want one and two", snapshots)
+	}
+}
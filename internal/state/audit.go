@@ -0,0 +1,55 @@
+package state
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"time"
+)
+
+// auditFile is the append-only audit log, relative to Dir
+const auditFile = "audit.log"
+
+// Audit events
+const (
+	AuditSkipPermissions       = "skip-permissions"
+	AuditSkipPermissionsDenied = "skip-permissions-denied"
+	AuditExec                  = "exec"        // A command run through `exec`
+	AuditExecDenied            = "exec-denied" // An `exec` refused by the directory check
+	AuditRoot                  = "root"        // A launch as root, permitted by --allow-root or allowRoot
+	AuditRootDenied            = "root-denied" // A launch as root refused
+)
+
+// AuditEntry is one line of the audit log
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Event   string    `json:"event"`
+	Dir     string    `json:"dir"`
+	Account string    `json:"account,omitempty"`
+	Args    []string  `json:"args,omitempty"`
+}
+
+// AuditPath returns the path of the audit log
+func (s *Store) AuditPath() string {
+	return filepath.Join(s.Dir, auditFile)
+}
+
+// AppendAudit appends entry to the audit log as a JSON line
+func (s *Store) AppendAudit(entry AuditEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	return s.AppendJSONLine(auditFile, entry)
+}
+
+// ReadAudit returns the entries of the audit log, oldest first. Corrupt lines are skipped.
+func (s *Store) ReadAudit() ([]AuditEntry, error) {
+	var entries []AuditEntry
+	err := s.ReadJSONLines(auditFile, func(line []byte) error {
+		var entry AuditEntry
+		if err := json.Unmarshal(line, &entry); err == nil {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	return entries, err
+}
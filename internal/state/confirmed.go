@@ -0,0 +1,29 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// confirmedFile records the directories the user confirmed before their first launch, relative to Dir
+const confirmedFile = "confirmed-dirs.json"
+
+// ConfirmedDirs returns the confirmed directories and when they were confirmed
+func (s *Store) ConfirmedDirs() (map[string]time.Time, error) {
+	confirmed := map[string]time.Time{}
+	if err := s.ReadJSON(confirmedFile, &confirmed); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return confirmed, nil
+}
+
+// ConfirmDir records that the user confirmed launches in dir at t
+func (s *Store) ConfirmDir(dir string, t time.Time) error {
+	confirmed, err := s.ConfirmedDirs()
+	if err != nil {
+		return err
+	}
+	confirmed[dir] = t
+	return s.WriteJSON(confirmedFile, confirmed)
+}
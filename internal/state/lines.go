@@ -0,0 +1,80 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// AppendJSONLine appends v as a single JSON line to name (relative to Dir)
+func (s *Store) AppendJSONLine(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	path := filepath.Join(s.Dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Clean(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close() //nolint:errcheck // the write error below is what matters
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// ReadJSONLines calls fn with each non-empty line of name (relative to Dir), starting with the
+// rotated logs RotateLog kept of it. A missing file has no lines.
+func (s *Store) ReadJSONLines(name string, fn func(line []byte) error) error {
+	path := filepath.Join(s.Dir, name)
+	segments, err := Segments(path)
+	if err != nil {
+		return err
+	}
+	for _, seg := range segments {
+		if err := readLines(seg.Path, fn); err != nil {
+			return err
+		}
+	}
+	return readLines(path, fn)
+}
+
+// readLines calls fn with each non-empty line of the file at path. A missing file has no lines.
+func readLines(path string, fn func(line []byte) error) error {
+	name := filepath.Base(path)
+	f, err := os.Open(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close() //nolint:errcheck // read-only
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package state
+
+import (
+	"errors"
+	"os"
+)
+
+// prefsFile records the user's interactive preferences, relative to Dir
+const prefsFile = "ui-prefs.json"
+
+// Prefs are interactive preferences remembered across invocations. Empty fields are unset.
+type Prefs struct {
+	Prompts  string `json:"prompts,omitempty"`  // Prompt style: "menu" or "numbered"
+	Palette  string `json:"palette,omitempty"`  // Color palette, used when the config has none
+	Symbols  string `json:"symbols,omitempty"`  // Symbol style, used when the config has none
+	PickSort string `json:"pickSort,omitempty"` // Sort order last chosen in the pick menu
+}
+
+// Prefs returns the saved preferences; none are set before the first SavePrefs
+func (s *Store) Prefs() (Prefs, error) {
+	var prefs Prefs
+	if err := s.ReadJSON(prefsFile, &prefs); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Prefs{}, err
+	}
+	return prefs, nil
+}
+
+// SavePrefs replaces the saved preferences with prefs
+func (s *Store) SavePrefs(prefs Prefs) error {
+	return s.WriteJSON(prefsFile, prefs)
+}
@@ -0,0 +1,49 @@
+//go:build !windows
+
+package state
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ProcessAlive reports whether a process with pid exists
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	// EPERM means the process exists but belongs to another user
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// ProcessStartTime returns an opaque token for when the process with pid started (its start
+// time in /proc/<pid>/stat), or "" when it cannot be read, e.g. on systems without /proc
+func ProcessStartTime(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return ""
+	}
+	// The command name in parentheses may contain spaces, so count fields after it
+	i := strings.LastIndexByte(string(data), ')')
+	if i < 0 {
+		return ""
+	}
+	fields := strings.Fields(string(data[i+1:]))
+	// starttime is field 22; the fields after the name start at field 3
+	if len(fields) < 20 {
+		return ""
+	}
+	return fields[19]
+}
+
+// Terminate asks the process with pid to exit
+func Terminate(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
@@ -0,0 +1,58 @@
+//go:build windows
+
+package state
+
+import (
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/windows"
+)
+
+// ProcessAlive reports whether a process with pid exists
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid)) // #nosec G115 -- pid is positive
+	if err != nil {
+		return false
+	}
+	defer func() { _ = windows.CloseHandle(h) }() //nolint:errcheck // closing a query handle
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == 259 // STILL_ACTIVE
+}
+
+// ProcessStartTime returns an opaque token for when the process with pid started (its creation
+// time), or "" when it cannot be read
+func ProcessStartTime(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid)) // #nosec G115 -- pid is positive
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = windows.CloseHandle(h) }() //nolint:errcheck // closing a query handle
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return ""
+	}
+	return strconv.FormatInt(creation.Nanoseconds(), 10)
+}
+
+// Terminate asks the process with pid to exit
+func Terminate(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
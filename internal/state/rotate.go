@@ -0,0 +1,138 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// startsFile records when each log RotateLog looks after was started, by path, relative to Dir
+const startsFile = "log-starts.json"
+
+// segmentLayout is the rotation time in the names of rotated logs: audit.log.20261016T150405Z
+const segmentLayout = "20060102T150405Z"
+
+// Rotation bounds the size and age of an append-only log. Zero fields are no bound.
+type Rotation struct {
+	MaxSize      int64         // Rotate the log once it is this large
+	MaxAge       time.Duration // Rotate the log once it spans this long, and delete rotated logs older than this
+	MaxTotalSize int64         // Delete the oldest rotated logs while the log and its rotated logs take more
+}
+
+// Segment is a rotated log
+type Segment struct {
+	Path      string
+	RotatedAt time.Time
+}
+
+// Segments returns the rotated logs of the log at path, oldest first
+func Segments(path string) ([]Segment, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rotated logs of %s: %w", path, err)
+	}
+	prefix := filepath.Base(path) + "."
+	var segments []Segment
+	for _, entry := range entries {
+		suffix, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(segmentLayout, suffix)
+		if err != nil {
+			continue // Another file sharing the prefix, e.g. a temporary file
+		}
+		segments = append(segments, Segment{Path: filepath.Join(filepath.Dir(path), entry.Name()), RotatedAt: t})
+	}
+	slices.SortFunc(segments, func(a, b Segment) int { return a.RotatedAt.Compare(b.RotatedAt) })
+	return segments, nil
+}
+
+// RotateLog applies r to the log at path, which need not be in Dir: the log is renamed to a
+// rotated log when it outgrew r, then the rotated logs r no longer keeps are deleted. A log counts
+// as started when RotateLog first sees it or rotates it; the starts are recorded in the store.
+func (s *Store) RotateLog(path string, r Rotation, now time.Time) error {
+	starts := map[string]time.Time{}
+	if err := s.ReadJSON(startsFile, &starts); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	changed := false
+
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if _, ok := starts[path]; ok {
+			delete(starts, path)
+			changed = true
+		}
+	case err != nil:
+		return fmt.Errorf("failed to check %s: %w", path, err)
+	default:
+		start, ok := starts[path]
+		if !ok {
+			start = now
+			starts[path] = now
+			changed = true
+		}
+		tooLarge := r.MaxSize > 0 && info.Size() >= r.MaxSize
+		tooOld := r.MaxAge > 0 && now.Sub(start) >= r.MaxAge
+		if (tooLarge || tooOld) && info.Size() > 0 {
+			rotated := path + "." + now.UTC().Format(segmentLayout)
+			if _, err := os.Stat(rotated); errors.Is(err, os.ErrNotExist) {
+				if err := os.Rename(path, rotated); err != nil {
+					return fmt.Errorf("failed to rotate %s: %w", path, err)
+				}
+				starts[path] = now
+				changed = true
+			}
+		}
+	}
+
+	if changed {
+		if err := s.WriteJSON(startsFile, starts); err != nil {
+			return err
+		}
+	}
+	return prune(path, r, now)
+}
+
+// prune deletes the rotated logs of path that are older than r.MaxAge, then the oldest ones
+// while the log and its rotated logs are larger than r.MaxTotalSize
+func prune(path string, r Rotation, now time.Time) error {
+	segments, err := Segments(path)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	if info, err := os.Stat(path); err == nil {
+		total = info.Size()
+	}
+	sizes := make([]int64, len(segments))
+	for i, seg := range segments {
+		if info, err := os.Stat(seg.Path); err == nil {
+			sizes[i] = info.Size()
+			total += sizes[i]
+		}
+	}
+
+	for i, seg := range segments {
+		expired := r.MaxAge > 0 && now.Sub(seg.RotatedAt) > r.MaxAge
+		overSize := r.MaxTotalSize > 0 && total > r.MaxTotalSize
+		if !expired && !overSize {
+			break // Later segments are newer
+		}
+		if err := os.Remove(seg.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to delete rotated log %s: %w", seg.Path, err)
+		}
+		total -= sizes[i]
+	}
+	return nil
+}
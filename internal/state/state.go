@@ -0,0 +1,276 @@
+package state
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/home"
+	"github.com/23prime/claude-launcher/internal/log"
+)
+
+// Store persists launcher state (running instances, etc.) as JSON files under Dir
+type Store struct {
+	Dir string
+}
+
+// NewStore creates a Store rooted at the default state directory.
+// When that directory is unknown or not writable (no HOME, a read-only home), state is kept in a
+// temporary directory for this run instead, so the launcher still works without remembering anything.
+func NewStore() (*Store, error) {
+	dir, err := DefaultDir()
+	if err == nil && writable(dir) {
+		return &Store{Dir: dir}, nil
+	}
+
+	tmp, tmpErr := ephemeralDir(dir, err)
+	if tmpErr != nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, tmpErr
+	}
+	return &Store{Dir: tmp}, nil
+}
+
+// DefaultDir returns the default state directory:
+// $CLAUDE_LAUNCHER_HOME/state, $XDG_STATE_HOME/claude-launcher, or ~/.local/state/claude-launcher
+func DefaultDir() (string, error) {
+	if dir := home.LauncherDir(); dir != "" {
+		return filepath.Join(dir, "state"), nil
+	}
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "claude-launcher"), nil
+	}
+
+	homeDir, err := home.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".local", "state", "claude-launcher"), nil
+}
+
+// writableDirs memoizes writable per directory, since every NewStore call would otherwise probe again
+var writableDirs sync.Map
+
+// writable reports whether files can be created in dir, creating it if needed
+func writable(dir string) bool {
+	if ok, found := writableDirs.Load(dir); found {
+		return ok.(bool)
+	}
+
+	ok := false
+	if err := os.MkdirAll(dir, 0o700); err == nil {
+		if f, err := os.CreateTemp(dir, ".probe-*"); err == nil {
+			_ = f.Close()           //nolint:errcheck // the probe file is removed right away
+			_ = os.Remove(f.Name()) //nolint:errcheck // best-effort cleanup of the probe file
+			ok = true
+		}
+	}
+	writableDirs.Store(dir, ok)
+	return ok
+}
+
+var (
+	ephemeralOnce sync.Once
+	ephemeralPath string
+	ephemeralErr  error
+)
+
+// ephemeralDir returns the temporary state directory of this process, creating it on first use.
+// dir and cause describe why the default directory could not be used.
+func ephemeralDir(dir string, cause error) (string, error) {
+	ephemeralOnce.Do(func() {
+		ephemeralPath, ephemeralErr = os.MkdirTemp("", "claude-launcher-state-*")
+		if ephemeralErr != nil {
+			ephemeralErr = fmt.Errorf("failed to create temporary state directory: %w", ephemeralErr)
+			return
+		}
+		if cause != nil {
+			log.Warn("keeping state in a temporary directory for this run", "error", cause)
+		} else {
+			log.Warn("keeping state in a temporary directory for this run", "reason", "state directory is not writable", "dir", dir)
+		}
+	})
+	return ephemeralPath, ephemeralErr
+}
+
+// Ephemeral reports whether s is the temporary directory NewStore fell back to, which is removed
+// when the launcher exits
+func (s *Store) Ephemeral() bool {
+	return ephemeralPath != "" && s.Dir == ephemeralPath
+}
+
+// RemoveEphemeral deletes the temporary state directory NewStore fell back to, if any.
+// Call it once the launcher is about to exit.
+func RemoveEphemeral() {
+	if ephemeralPath != "" {
+		_ = os.RemoveAll(ephemeralPath) //nolint:errcheck // best-effort cleanup of temporary state
+	}
+}
+
+// NewID returns a short random identifier for an instance
+func NewID() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate id: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Instance describes a claude process started by the launcher
+type Instance struct {
+	ID        string    `json:"id"`
+	PID       int       `json:"pid"`
+	Dir       string    `json:"dir"`
+	Account   string    `json:"account,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	Socket    string    `json:"socket,omitempty"` // Set for detached sessions
+	// ProcessStart is ProcessStartTime(PID) when the record was written, so that a new process
+	// reusing the PID is not taken for this one
+	ProcessStart string `json:"processStart,omitempty"`
+}
+
+// Running reports whether the process of the record is still running. When the start time was
+// recorded, a process with the same PID but another start time does not count.
+func (inst *Instance) Running() bool {
+	if !ProcessAlive(inst.PID) {
+		return false
+	}
+	return inst.ProcessStart == "" || ProcessStartTime(inst.PID) == inst.ProcessStart
+}
+
+// InstancesDir returns the directory holding instance records and sockets
+func (s *Store) InstancesDir() string {
+	return filepath.Join(s.Dir, "instances")
+}
+
+// SaveInstance writes (or overwrites) the record for inst
+func (s *Store) SaveInstance(inst *Instance) error {
+	return s.writeJSON(filepath.Join(s.InstancesDir(), inst.ID+".json"), inst)
+}
+
+// RemoveInstance deletes the record for id. Missing records are not an error.
+func (s *Store) RemoveInstance(id string) error {
+	err := os.Remove(filepath.Join(s.InstancesDir(), id+".json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove instance %s: %w", id, err)
+	}
+	return nil
+}
+
+// FindInstance returns the record for id, or (nil, nil) if it does not exist
+func (s *Store) FindInstance(id string) (*Instance, error) {
+	var inst Instance
+	if err := s.readJSON(filepath.Join(s.InstancesDir(), id+".json"), &inst); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &inst, nil
+}
+
+// ListInstances returns all instance records ordered by start time
+func (s *Store) ListInstances() ([]Instance, error) {
+	entries, err := os.ReadDir(s.InstancesDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read instances directory: %w", err)
+	}
+
+	instances := make([]Instance, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		var inst Instance
+		if err := s.readJSON(filepath.Join(s.InstancesDir(), entry.Name()), &inst); err != nil || inst.ID == "" {
+			// Skip unreadable records rather than failing the whole listing
+			continue
+		}
+		instances = append(instances, inst)
+	}
+
+	slices.SortFunc(instances, func(a, b Instance) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+
+	return instances, nil
+}
+
+// PruneInstances removes records whose process is no longer running
+// and returns the remaining (live) instances
+func (s *Store) PruneInstances() ([]Instance, error) {
+	instances, err := s.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	live := make([]Instance, 0, len(instances))
+	for _, inst := range instances {
+		if inst.Running() {
+			live = append(live, inst)
+			continue
+		}
+		if err := s.RemoveInstance(inst.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return live, nil
+}
+
+// WriteJSON writes v as JSON to name (relative to Dir) with owner-only permissions
+func (s *Store) WriteJSON(name string, v any) error {
+	return s.writeJSON(filepath.Join(s.Dir, name), v)
+}
+
+// ReadJSON reads JSON from name (relative to Dir) into v
+func (s *Store) ReadJSON(name string, v any) error {
+	return s.readJSON(filepath.Join(s.Dir, name), v)
+}
+
+func (s *Store) writeJSON(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	// Write to a temp file and rename so readers never observe a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) readJSON(path string, v any) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	return nil
+}
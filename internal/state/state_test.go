@@ -0,0 +1,389 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv("CLAUDE_LAUNCHER_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "/tmp/xdg-state")
+
+	dir, err := DefaultDir()
+	if err != nil {
+		t.Fatalf("DefaultDir() error = %v", err)
+	}
+	if dir != filepath.Join("/tmp/xdg-state", "claude-launcher") {
+		t.Errorf("DefaultDir() = %q, expected XDG_STATE_HOME based path", dir)
+	}
+}
+
+func TestDefaultDirLauncherHome(t *testing.T) {
+	t.Setenv("CLAUDE_LAUNCHER_HOME", "/srv/launcher")
+	t.Setenv("XDG_STATE_HOME", "/tmp/xdg-state")
+
+	dir, err := DefaultDir()
+	if err != nil {
+		t.Fatalf("DefaultDir() error = %v", err)
+	}
+	if dir != filepath.Join("/srv/launcher", "state") {
+		t.Errorf("DefaultDir() = %q, expected CLAUDE_LAUNCHER_HOME based path", dir)
+	}
+}
+
+func TestNewStoreFallsBackWhenNotWritable(t *testing.T) {
+	// A directory below a regular file can never be created, even by root
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CLAUDE_LAUNCHER_HOME", blocker)
+	t.Cleanup(RemoveEphemeral)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	if strings.HasPrefix(store.Dir, blocker) {
+		t.Fatalf("NewStore().Dir = %q, expected a temporary directory", store.Dir)
+	}
+	if !store.Ephemeral() {
+		t.Error("Ephemeral() = false for the temporary directory")
+	}
+	if (&Store{Dir: t.TempDir()}).Ephemeral() {
+		t.Error("Ephemeral() = true for another directory")
+	}
+
+	inst := &Instance{ID: "aaaa", PID: 100, Dir: "/tmp/a", StartedAt: time.Now()}
+	if err := store.SaveInstance(inst); err != nil {
+		t.Fatalf("SaveInstance() error = %v", err)
+	}
+
+	again, err := NewStore()
+	if err != nil || again.Dir != store.Dir {
+		t.Errorf("second NewStore() = %v, %v, expected the same temporary directory %q", again, err, store.Dir)
+	}
+}
+
+func TestInstances(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	now := time.Now()
+	first := &Instance{ID: "aaaa", PID: 100, Dir: "/tmp/a", StartedAt: now.Add(-time.Minute)}
+	second := &Instance{ID: "bbbb", PID: 200, Dir: "/tmp/b", StartedAt: now, Socket: "/tmp/b.sock"}
+
+	for _, inst := range []*Instance{second, first} {
+		if err := store.SaveInstance(inst); err != nil {
+			t.Fatalf("SaveInstance() error = %v", err)
+		}
+	}
+
+	instances, err := store.ListInstances()
+	if err != nil {
+		t.Fatalf("ListInstances() error = %v", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("ListInstances() returned %d instances, expected 2", len(instances))
+	}
+	if instances[0].ID != "aaaa" || instances[1].ID != "bbbb" {
+		t.Errorf("ListInstances() not ordered by start time: %v", instances)
+	}
+
+	found, err := store.FindInstance("bbbb")
+	if err != nil {
+		t.Fatalf("FindInstance() error = %v", err)
+	}
+	if found == nil || found.Socket != "/tmp/b.sock" {
+		t.Errorf("FindInstance() = %+v, expected socket /tmp/b.sock", found)
+	}
+
+	if err := store.RemoveInstance("bbbb"); err != nil {
+		t.Fatalf("RemoveInstance() error = %v", err)
+	}
+	if err := store.RemoveInstance("bbbb"); err != nil {
+		t.Errorf("RemoveInstance() on missing record should not fail: %v", err)
+	}
+
+	missing, err := store.FindInstance("bbbb")
+	if err != nil || missing != nil {
+		t.Errorf("FindInstance() = (%+v, %v), expected (nil, nil)", missing, err)
+	}
+}
+
+func TestListInstancesEmpty(t *testing.T) {
+	store := &Store{Dir: filepath.Join(t.TempDir(), "missing")}
+
+	instances, err := store.ListInstances()
+	if err != nil {
+		t.Fatalf("ListInstances() error = %v", err)
+	}
+	if len(instances) != 0 {
+		t.Errorf("ListInstances() returned %d instances, expected 0", len(instances))
+	}
+}
+
+func TestWriteJSONPermissions(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	if err := store.WriteJSON("data.json", map[string]string{"key": "value"}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(store.Dir, "data.json"))
+	if err != nil {
+		t.Fatalf("failed to stat state file: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("state file mode = %v, expected 0600", info.Mode().Perm())
+	}
+
+	var got map[string]string
+	if err := store.ReadJSON("data.json", &got); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if got["key"] != "value" {
+		t.Errorf("ReadJSON() = %v, expected key=value", got)
+	}
+}
+
+func TestNewID(t *testing.T) {
+	id1, err := NewID()
+	if err != nil {
+		t.Fatalf("NewID() error = %v", err)
+	}
+	id2, err := NewID()
+	if err != nil {
+		t.Fatalf("NewID() error = %v", err)
+	}
+
+	if len(id1) != 8 {
+		t.Errorf("NewID() length = %d, expected 8", len(id1))
+	}
+	if id1 == id2 {
+		t.Error("NewID() returned the same id twice")
+	}
+}
+
+func TestPruneInstances(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	live := &Instance{ID: "live", PID: os.Getpid(), StartedAt: time.Now()}
+	// PIDs are bounded well below this value on all supported platforms
+	dead := &Instance{ID: "dead", PID: 1 << 30, StartedAt: time.Now()}
+	for _, inst := range []*Instance{live, dead} {
+		if err := store.SaveInstance(inst); err != nil {
+			t.Fatalf("SaveInstance() error = %v", err)
+		}
+	}
+
+	instances, err := store.PruneInstances()
+	if err != nil {
+		t.Fatalf("PruneInstances() error = %v", err)
+	}
+	if len(instances) != 1 || instances[0].ID != "live" {
+		t.Errorf("PruneInstances() = %v, expected only the live instance", instances)
+	}
+
+	if found, _ := store.FindInstance("dead"); found != nil {
+		t.Error("PruneInstances() should remove records of dead processes")
+	}
+}
+
+func TestPruneInstancesReusedPID(t *testing.T) {
+	start := ProcessStartTime(os.Getpid())
+	if start == "" {
+		t.Skip("process start time not available")
+	}
+	store := &Store{Dir: t.TempDir()}
+
+	live := &Instance{ID: "live", PID: os.Getpid(), StartedAt: time.Now(), ProcessStart: start}
+	// The same PID, but recorded for a process that started at another time
+	reused := &Instance{ID: "reused", PID: os.Getpid(), StartedAt: time.Now(), ProcessStart: start + "0"}
+	for _, inst := range []*Instance{live, reused} {
+		if err := store.SaveInstance(inst); err != nil {
+			t.Fatalf("SaveInstance() error = %v", err)
+		}
+	}
+
+	instances, err := store.PruneInstances()
+	if err != nil {
+		t.Fatalf("PruneInstances() error = %v", err)
+	}
+	if len(instances) != 1 || instances[0].ID != "live" {
+		t.Errorf("PruneInstances() = %v, expected only the instance whose start time matches", instances)
+	}
+}
+
+func TestAppendAudit(t *testing.T) {
+	store := &Store{Dir: filepath.Join(t.TempDir(), "state")}
+
+	for _, event := range []string{AuditSkipPermissions, AuditSkipPermissionsDenied} {
+		if err := store.AppendAudit(AuditEntry{Event: event, Dir: "/tmp/sandbox"}); err != nil {
+			t.Fatalf("AppendAudit() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(store.AuditPath())
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("audit log has %d lines, expected 2", len(lines))
+	}
+
+	var entry AuditEntry
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("failed to parse audit entry: %v", err)
+	}
+	if entry.Event != AuditSkipPermissionsDenied || entry.Dir != "/tmp/sandbox" || entry.Time.IsZero() {
+		t.Errorf("unexpected audit entry: %+v", entry)
+	}
+}
+
+func TestReadAudit(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	entries, err := store.ReadAudit()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("ReadAudit() = %v, %v, expected no entries without a log", entries, err)
+	}
+
+	if err := store.AppendAudit(AuditEntry{Event: AuditSkipPermissions, Dir: "/tmp/a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(store.AuditPath(), append(mustRead(t, store.AuditPath()), "not json\n"...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AppendAudit(AuditEntry{Event: AuditSkipPermissionsDenied, Dir: "/tmp/b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = store.ReadAudit()
+	if err != nil {
+		t.Fatalf("ReadAudit() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Dir != "/tmp/a" || entries[1].Event != AuditSkipPermissionsDenied {
+		t.Errorf("ReadAudit() = %+v, expected both entries around the corrupt line", entries)
+	}
+}
+
+func TestConfirmDir(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	confirmed, err := store.ConfirmedDirs()
+	if err != nil || len(confirmed) != 0 {
+		t.Fatalf("ConfirmedDirs() = %v, %v, expected none without a file", confirmed, err)
+	}
+
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	for _, dir := range []string{"/work/api", "/work/web"} {
+		if err := store.ConfirmDir(dir, at); err != nil {
+			t.Fatalf("ConfirmDir(%q) error = %v", dir, err)
+		}
+	}
+
+	confirmed, err = store.ConfirmedDirs()
+	if err != nil {
+		t.Fatalf("ConfirmedDirs() error = %v", err)
+	}
+	if len(confirmed) != 2 || !confirmed["/work/api"].Equal(at) {
+		t.Errorf("ConfirmedDirs() = %v, expected both directories confirmed at %v", confirmed, at)
+	}
+}
+
+func TestPrefs(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	prefs, err := store.Prefs()
+	if err != nil || prefs != (Prefs{}) {
+		t.Fatalf("Prefs() = %+v, %v, expected none without a file", prefs, err)
+	}
+
+	want := Prefs{Palette: "colorblind", PickSort: "recent"}
+	if err := store.SavePrefs(want); err != nil {
+		t.Fatalf("SavePrefs() error = %v", err)
+	}
+	if prefs, err := store.Prefs(); err != nil || prefs != want {
+		t.Errorf("Prefs() = %+v, %v, expected %+v", prefs, err, want)
+	}
+}
+
+func TestRotateLog(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	r := Rotation{MaxSize: 60, MaxAge: 30 * 24 * time.Hour, MaxTotalSize: 250}
+
+	appendEntries := func(n int) {
+		for range n {
+			if err := store.AppendAudit(AuditEntry{Time: now, Event: AuditExec, Dir: "/w"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	countEntries := func() int {
+		entries, err := store.ReadAudit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(entries)
+	}
+
+	appendEntries(1)
+	if err := store.RotateLog(store.AuditPath(), r, now); err != nil {
+		t.Fatalf("RotateLog() error = %v", err)
+	}
+	if segments, _ := Segments(store.AuditPath()); len(segments) != 0 {
+		t.Fatalf("Segments() = %v, expected a small log to stay", segments)
+	}
+
+	// Large logs are rotated, and their entries are still read
+	appendEntries(2)
+	if err := store.RotateLog(store.AuditPath(), r, now); err != nil {
+		t.Fatalf("RotateLog() error = %v", err)
+	}
+	segments, _ := Segments(store.AuditPath())
+	if len(segments) != 1 || !segments[0].RotatedAt.Equal(now) {
+		t.Fatalf("Segments() = %v, expected one rotated at %v", segments, now)
+	}
+	appendEntries(1)
+	if got := countEntries(); got != 4 {
+		t.Errorf("ReadAudit() returned %d entries, expected 4 across the rotated log", got)
+	}
+
+	// Beyond MaxTotalSize the oldest rotated logs go
+	appendEntries(3)
+	if err := store.RotateLog(store.AuditPath(), r, now.Add(time.Hour)); err != nil {
+		t.Fatalf("RotateLog() error = %v", err)
+	}
+	if segments, _ := Segments(store.AuditPath()); len(segments) != 1 || !segments[0].RotatedAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Segments() = %v, expected only the newest rotated log", segments)
+	}
+
+	// Past MaxAge the log is rotated however small, and old rotated logs are deleted
+	appendEntries(1)
+	later := now.Add(31 * 24 * time.Hour)
+	if err := store.RotateLog(store.AuditPath(), r, later); err != nil {
+		t.Fatalf("RotateLog() error = %v", err)
+	}
+	if segments, _ := Segments(store.AuditPath()); len(segments) != 1 || !segments[0].RotatedAt.Equal(later) {
+		t.Errorf("Segments() = %v, expected only the log rotated at %v", segments, later)
+	}
+	if got := countEntries(); got != 1 {
+		t.Errorf("ReadAudit() returned %d entries, expected 1", got)
+	}
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
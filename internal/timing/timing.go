@@ -0,0 +1,61 @@
+// Package timing measures the phases of the launcher's startup for --timing: how long each
+// step took until claude starts, apart from the time spent waiting for the user to answer
+package timing
+
+import "time"
+
+// Phase is one measured step of the startup
+type Phase struct {
+	Name     string
+	Duration time.Duration // Time spent working, without Waited
+	Waited   time.Duration // Time spent waiting for input
+}
+
+// Recorder collects phases one after another. The nil Recorder records nothing, so callers
+// mark phases whether or not --timing was given.
+type Recorder struct {
+	// Idle returns the total time spent waiting for input so far; nil when nothing is waited for
+	Idle func() time.Duration
+
+	start    time.Time
+	last     time.Time
+	lastIdle time.Duration
+	phases   []Phase
+}
+
+// New creates a Recorder measuring from start, e.g. the start of the process
+func New(start time.Time) *Recorder {
+	return &Recorder{start: start, last: start}
+}
+
+// Mark ends the current phase as name: it took the time since the previous Mark (or the start)
+func (r *Recorder) Mark(name string) {
+	if r == nil {
+		return
+	}
+	now := time.Now()
+	var idle time.Duration
+	if r.Idle != nil {
+		idle = r.Idle()
+	}
+	waited := idle - r.lastIdle
+	r.phases = append(r.phases, Phase{Name: name, Duration: now.Sub(r.last) - waited, Waited: waited})
+	r.last, r.lastIdle = now, idle
+}
+
+// Phases returns the phases marked so far
+func (r *Recorder) Phases() []Phase {
+	if r == nil {
+		return nil
+	}
+	return r.phases
+}
+
+// Total returns the time spent working in all phases marked so far
+func (r *Recorder) Total() time.Duration {
+	var total time.Duration
+	for _, p := range r.Phases() {
+		total += p.Duration
+	}
+	return total
+}
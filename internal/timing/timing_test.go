@@ -0,0 +1,43 @@
+package timing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	var idle time.Duration
+	start := time.Now().Add(-10 * time.Millisecond)
+	r := New(start)
+	r.Idle = func() time.Duration { return idle }
+
+	r.Mark("config")
+	idle = time.Hour // A prompt was answered in the second phase
+	r.Mark("account")
+	r.Mark("launch")
+
+	phases := r.Phases()
+	if len(phases) != 3 {
+		t.Fatalf("Phases() = %+v, want 3 phases", phases)
+	}
+	if phases[0].Name != "config" || phases[0].Duration < 10*time.Millisecond || phases[0].Waited != 0 {
+		t.Errorf("phase 0 = %+v, want config of at least 10ms", phases[0])
+	}
+	if phases[1].Waited != time.Hour || phases[1].Duration > time.Second {
+		t.Errorf("phase 1 = %+v, want the input wait left out of its duration", phases[1])
+	}
+	if phases[2].Waited != 0 {
+		t.Errorf("phase 2 = %+v, want no wait", phases[2])
+	}
+	if total := r.Total(); total != phases[0].Duration+phases[1].Duration+phases[2].Duration {
+		t.Errorf("Total() = %v, want the sum of the phases", total)
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.Mark("config")
+	if r.Phases() != nil || r.Total() != 0 {
+		t.Error("the nil Recorder should record nothing")
+	}
+}
@@ -0,0 +1,107 @@
+// Package tmux launches claude inside tmux windows named after the project.
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+// Launch runs c in a tmux window named after its directory.
+// If a window with that name already exists it is selected instead of starting a new claude.
+// Inside tmux a new window is created in the current session; outside tmux a new
+// session is created (or an existing one attached).
+func Launch(c *launcher.Command) error {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return fmt.Errorf("tmux not found in PATH: %w", err)
+	}
+
+	name := WindowName(c.Dir)
+	insideTmux := os.Getenv("TMUX") != ""
+
+	if target, ok := findWindow(name); ok {
+		if insideTmux {
+			return run("switch-client", "-t", target)
+		}
+		return run("attach-session", "-t", target)
+	}
+
+	// The variables go through a file only the user can read: tmux arguments are visible to all
+	envFile, err := launcher.WriteEnvFile("", launcher.EnvDelta(os.Environ(), c.Env))
+	if err != nil {
+		return err
+	}
+	if err := run(windowArgs(name, insideTmux, c, envFile)...); err != nil {
+		_ = os.Remove(envFile) //nolint:errcheck // the window did not start to delete it
+		return err
+	}
+	return nil
+}
+
+// windowArgs returns the tmux arguments starting c in a new window (or, outside tmux, a new
+// session) called name. The window sources and deletes envFile before running c.
+func windowArgs(name string, insideTmux bool, c *launcher.Command, envFile string) []string {
+	args := []string{"new-session", "-s", name}
+	if insideTmux {
+		args = []string{"new-window", "-n", name}
+	}
+	args = append(args, "-c", c.Dir, "--", "sh", "-c", launcher.SourceEnvFile+`exec "$@"`, "sh", envFile)
+	// The window inherits the tmux server's environment, so variables the launcher
+	// filtered out have to be removed explicitly
+	if removed := launcher.EnvRemoved(os.Environ(), c.Env); len(removed) > 0 {
+		args = append(args, "env")
+		for _, key := range removed {
+			args = append(args, "-u", key)
+		}
+	}
+	args = append(args, c.Path)
+	return append(args, c.Args...)
+}
+
+// WindowName derives a tmux-safe window/session name from a project directory
+func WindowName(dir string) string {
+	name := filepath.Base(filepath.Clean(dir))
+	if name == "" || name == "." || name == string(filepath.Separator) {
+		name = "claude"
+	}
+	// tmux treats '.' and ':' as target separators
+	return strings.NewReplacer(".", "_", ":", "_").Replace(name)
+}
+
+// findWindow returns the target ("session:index") of a window called name
+func findWindow(name string) (string, bool) {
+	// #nosec G204 -- fixed tmux arguments
+	out, err := exec.Command("tmux", "list-windows", "-a", "-F", "#{session_name}:#{window_index} #{window_name}").Output()
+	if err != nil {
+		// No server running means no windows
+		return "", false
+	}
+	return parseWindowList(string(out), name)
+}
+
+// parseWindowList finds name in `tmux list-windows` output formatted as "target name" lines
+func parseWindowList(out, name string) (string, bool) {
+	for line := range strings.SplitSeq(out, "\n") {
+		target, windowName, ok := strings.Cut(line, " ")
+		if ok && windowName == name {
+			return target, true
+		}
+	}
+	return "", false
+}
+
+func run(args ...string) error {
+	// #nosec G204 -- args are built from the resolved launch command
+	cmd := exec.Command("tmux", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run tmux %s: %w", args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package tmux
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+)
+
+func TestWindowName(t *testing.T) {
+	tests := []struct {
+		dir      string
+		expected string
+	}{
+		{dir: "/home/user/develop/api", expected: "api"},
+		{dir: "/home/user/develop/api/", expected: "api"},
+		{dir: "/home/user/my.project", expected: "my_project"},
+		{dir: "/", expected: "claude"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			if got := WindowName(tt.dir); got != tt.expected {
+				t.Errorf("WindowName(%q) = %q, expected %q", tt.dir, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseWindowList(t *testing.T) {
+	out := "main:0 zsh\nmain:1 api\nwork:0 web\n"
+
+	target, ok := parseWindowList(out, "api")
+	if !ok || target != "main:1" {
+		t.Errorf("parseWindowList(api) = (%q, %v), expected (main:1, true)", target, ok)
+	}
+
+	if _, ok := parseWindowList(out, "missing"); ok {
+		t.Error("parseWindowList(missing) should not find a window")
+	}
+}
+
+func TestWindowArgsKeepSecretsOffTheCommandLine(t *testing.T) {
+	c := &launcher.Command{
+		Path: "/usr/local/bin/claude",
+		Args: []string{"--continue"},
+		Env:  append(os.Environ(), "ANTHROPIC_API_KEY=sk-ant-secret"),
+		Dir:  "/home/user/develop/api",
+	}
+
+	args := windowArgs("api", true, c, "/tmp/claude-launcher-env-1")
+	for _, arg := range args {
+		if strings.Contains(arg, "sk-ant-secret") {
+			t.Errorf("argument %q contains the secret", arg)
+		}
+	}
+	if !slices.Contains(args, "/tmp/claude-launcher-env-1") {
+		t.Errorf("Args = %v, expected the env file", args)
+	}
+	if tail := args[len(args)-2:]; !slices.Equal(tail, []string{"/usr/local/bin/claude", "--continue"}) {
+		t.Errorf("Args tail = %v, expected claude and its args", tail)
+	}
+}
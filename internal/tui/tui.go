@@ -0,0 +1,193 @@
+// Package tui implements the full-screen launcher menu (`claude-launcher tui`)
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+// Action is what the user picked in the menu
+type Action int
+
+const (
+	// ActionNone means the menu was closed without a choice
+	ActionNone Action = iota
+	// ActionLaunch launches in Choice.Dir
+	ActionLaunch
+	// ActionProfile launches the profile Choice.Name
+	ActionProfile
+	// ActionWorkspace opens the workspace Choice.Name
+	ActionWorkspace
+	// ActionAttach reattaches to the detached session Choice.Name
+	ActionAttach
+)
+
+// Choice is the result of the menu
+type Choice struct {
+	Action  Action
+	Dir     string
+	Name    string // Profile, workspace or session ID
+	Account string // Selected account; empty when no accounts are configured
+}
+
+// Session is a detached session that can be reattached
+type Session struct {
+	ID      string
+	Dir     string
+	Account string
+	Age     string
+}
+
+// State is what the menu shows
+type State struct {
+	CurrentDir string
+	Allowed    bool // Whether CurrentDir is an allowed directory
+	Projects   []string
+	Profiles   []string
+	Workspaces []string
+	Accounts   []string
+	Sessions   []Session
+}
+
+// item is a selectable line of the menu
+type item struct {
+	section string
+	label   string
+	choice  Choice // For launch targets
+	account string // For account lines
+}
+
+// model is the bubbletea model of the menu
+type model struct {
+	state   State
+	items   []item
+	cursor  int
+	account string
+	choice  Choice
+	printer *ui.Printer
+}
+
+// newModel builds the menu items from st. The first account is selected initially.
+func newModel(st State, printer *ui.Printer) model {
+	var items []item
+	if st.Allowed {
+		items = append(items, item{section: "Launch", label: fmt.Sprintf(i18n.T("Here (%s)"), st.CurrentDir),
+			choice: Choice{Action: ActionLaunch, Dir: st.CurrentDir}})
+	}
+	for _, dir := range st.Projects {
+		if st.Allowed && dir == st.CurrentDir {
+			continue
+		}
+		items = append(items, item{section: "Launch", label: dir, choice: Choice{Action: ActionLaunch, Dir: dir}})
+	}
+	for _, name := range st.Profiles {
+		items = append(items, item{section: "Profiles", label: name, choice: Choice{Action: ActionProfile, Name: name}})
+	}
+	for _, name := range st.Workspaces {
+		items = append(items, item{section: "Workspaces", label: name, choice: Choice{Action: ActionWorkspace, Name: name}})
+	}
+	for _, name := range st.Accounts {
+		items = append(items, item{section: "Accounts", label: name, account: name})
+	}
+	for _, s := range st.Sessions {
+		label := fmt.Sprintf("%s  %s  %s", s.ID, s.Dir, s.Age)
+		if s.Account != "" {
+			label += "  (" + s.Account + ")"
+		}
+		items = append(items, item{section: "Sessions", label: label, choice: Choice{Action: ActionAttach, Name: s.ID}})
+	}
+
+	m := model{state: st, items: items, printer: printer}
+	if len(st.Accounts) > 0 {
+		m.account = st.Accounts[0]
+	}
+	return m
+}
+
+// Init implements tea.Model
+func (m model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model: arrow keys (or j/k) move, enter picks, q/esc quits
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch key.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+	case "enter":
+		if len(m.items) == 0 {
+			return m, tea.Quit
+		}
+		it := m.items[m.cursor]
+		if it.account != "" {
+			m.account = it.account
+			return m, nil
+		}
+		m.choice = it.choice
+		m.choice.Account = m.account
+		return m, tea.Quit
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// View implements tea.Model
+func (m model) View() string {
+	var b strings.Builder
+
+	status := i18n.T("not allowed")
+	if m.state.Allowed {
+		status = i18n.T("allowed")
+	}
+	fmt.Fprintf(&b, "claude-launcher  %s (%s)\n", m.state.CurrentDir, status)
+
+	section := ""
+	for i, it := range m.items {
+		if it.section != section {
+			section = it.section
+			fmt.Fprintf(&b, "\n%s\n", i18n.T(section))
+		}
+
+		line := "  " + it.label
+		if it.account != "" && it.account == m.account {
+			line += " ✓"
+		}
+		if i == m.cursor {
+			line = "\U0001F449" + m.printer.Highlight(line[1:])
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if len(m.items) == 0 {
+		b.WriteString("\n" + i18n.T("Nothing to launch: the current directory is not allowed and no projects are configured") + "\n")
+	}
+	b.WriteString("\n" + i18n.T("↑/↓ move  enter select  q quit") + "\n")
+	return ui.Symbols(b.String())
+}
+
+// Run shows the menu on out until the user picks an entry or quits
+func Run(st State, out io.Writer) (Choice, error) {
+	final, err := tea.NewProgram(newModel(st, ui.NewPrinter(out)), tea.WithAltScreen(), tea.WithOutput(out)).Run()
+	if err != nil {
+		return Choice{}, fmt.Errorf("failed to run the menu: %w", err)
+	}
+	return final.(model).choice, nil
+}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/23prime/claude-launcher/internal/ui"
+)
+
+func press(t *testing.T, m model, keys ...string) model {
+	t.Helper()
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+	return m
+}
+
+func testState() State {
+	return State{
+		CurrentDir: "/home/user/develop/api",
+		Allowed:    true,
+		Projects:   []string{"/home/user/develop/api", "/home/user/develop/web"},
+		Profiles:   []string{"review"},
+		Accounts:   []string{"personal", "work"},
+		Sessions:   []Session{{ID: "a1b2", Dir: "/home/user/develop/web", Age: "5m ago"}},
+	}
+}
+
+func TestNewModelItems(t *testing.T) {
+	m := newModel(testState(), ui.NewPrinter(nil))
+
+	var labels []string
+	for _, it := range m.items {
+		labels = append(labels, it.section+":"+it.label)
+	}
+	want := []string{
+		"Launch:Here (/home/user/develop/api)",
+		"Launch:/home/user/develop/web",
+		"Profiles:review",
+		"Accounts:personal",
+		"Accounts:work",
+		"Sessions:a1b2  /home/user/develop/web  5m ago",
+	}
+	if strings.Join(labels, "|") != strings.Join(want, "|") {
+		t.Errorf("items = %v, want %v", labels, want)
+	}
+	if m.account != "personal" {
+		t.Errorf("initial account = %q, want personal", m.account)
+	}
+}
+
+func TestNewModelNotAllowed(t *testing.T) {
+	st := testState()
+	st.Allowed = false
+	m := newModel(st, ui.NewPrinter(nil))
+
+	if m.items[0].label != "/home/user/develop/api" {
+		t.Errorf("first item = %q, expected the project instead of the current directory", m.items[0].label)
+	}
+}
+
+func TestUpdateChoice(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want Choice
+	}{
+		{"launch here", []string{"enter"}, Choice{Action: ActionLaunch, Dir: "/home/user/develop/api", Account: "personal"}},
+		{"project", []string{"down", "enter"}, Choice{Action: ActionLaunch, Dir: "/home/user/develop/web", Account: "personal"}},
+		{"profile with vim keys", []string{"j", "j", "enter"}, Choice{Action: ActionProfile, Name: "review", Account: "personal"}},
+		{"select account then launch", []string{"down", "down", "down", "down", "enter", "up", "up", "up", "up", "enter"},
+			Choice{Action: ActionLaunch, Dir: "/home/user/develop/api", Account: "work"}},
+		{"attach", []string{"down", "down", "down", "down", "down", "down", "down", "enter"}, Choice{Action: ActionAttach, Name: "a1b2", Account: "personal"}},
+		{"quit", []string{"down", "q"}, Choice{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := press(t, newModel(testState(), ui.NewPrinter(nil)), tt.keys...)
+			if m.choice != tt.want {
+				t.Errorf("choice = %+v, want %+v", m.choice, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/23prime/claude-launcher/internal/i18n"
+)
+
+// Kind is the severity of a printed fragment
+type Kind int
+
+const (
+	// KindInfo is a normal message
+	KindInfo Kind = iota
+	// KindSuccess is a success mark or message
+	KindSuccess
+	// KindQuestion is a question waiting for an answer
+	KindQuestion
+	// KindWarning is a warning or a question
+	KindWarning
+	// KindError is an error
+	KindError
+)
+
+// String returns the name of the kind as used in JSON events
+func (k Kind) String() string {
+	switch k {
+	case KindSuccess:
+		return "success"
+	case KindQuestion:
+		return "question"
+	case KindWarning:
+		return "warning"
+	case KindError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// Backend renders the messages of a Printer. Text is already formatted and translated,
+// and a message may arrive in several fragments (e.g. a colored mark, then the text).
+type Backend interface {
+	Emit(kind Kind, text string)
+}
+
+// Modes selectable with SetMode (--ui)
+const (
+	ModeAuto   = "auto"   // Colored on a terminal, plain otherwise
+	ModeColor  = "color"  // Always colored
+	ModePlain  = "plain"  // Never colored
+	ModeJSON   = "json"   // One JSON event per line
+	ModeSilent = "silent" // Nothing
+	// ModeAccessible is plain text for screen readers: severity in words, no symbols,
+	// and numbered menus instead of redrawn widgets (see Interactive)
+	ModeAccessible = "accessible"
+)
+
+// backendFactory creates the backend of new printers, set by SetMode or SetBackend
+var backendFactory = autoBackend
+
+// SetMode selects the backend of new printers by name (--ui)
+func SetMode(mode string) error {
+	accessible = false
+	switch mode {
+	case ModeAuto, "":
+		backendFactory = autoBackend
+	case ModeColor:
+		backendFactory = func(w io.Writer) Backend { return &ColorBackend{Writer: w} }
+	case ModePlain:
+		backendFactory = func(w io.Writer) Backend { return &PlainBackend{Writer: w} }
+	case ModeJSON:
+		backendFactory = func(w io.Writer) Backend { return &JSONBackend{Writer: w} }
+	case ModeSilent:
+		backendFactory = func(io.Writer) Backend { return SilentBackend{} }
+	case ModeAccessible:
+		SetAccessible()
+	default:
+		return fmt.Errorf("unknown ui mode %q (available: auto, color, plain, json, silent, accessible)", mode)
+	}
+	return nil
+}
+
+// accessible is set by SetAccessible
+var accessible bool
+
+// SetAccessible switches to the screen-reader-friendly mode (--accessible, --ui accessible)
+func SetAccessible() {
+	accessible = true
+	backendFactory = func(w io.Writer) Backend { return &AccessibleBackend{Writer: w} }
+}
+
+// SetBackend makes new printers use the backend returned by factory for their writer.
+// Programs embedding the launcher use it to redirect messages into their own UI.
+func SetBackend(factory func(w io.Writer) Backend) {
+	backendFactory = factory
+}
+
+// autoBackend colors output only when w supports it (see useColor)
+func autoBackend(w io.Writer) Backend {
+	if useColor(w) {
+		return &ColorBackend{Writer: w}
+	}
+	return &PlainBackend{Writer: w}
+}
+
+// ColorBackend writes text in the theme's colors
+type ColorBackend struct {
+	Writer io.Writer
+}
+
+// Emit implements Backend
+func (b *ColorBackend) Emit(kind Kind, text string) {
+	var attrs []color.Attribute
+	switch kind {
+	case KindSuccess:
+		attrs = theme.Success
+	case KindWarning, KindQuestion:
+		attrs = theme.Warning
+	case KindError:
+		attrs = theme.Error
+	default:
+		_, _ = io.WriteString(b.Writer, text) //nolint:errcheck // UI output errors are not critical
+		return
+	}
+
+	c := color.New(attrs...)
+	// fatih/color decides based on stdout, but printers usually write to stderr
+	c.EnableColor()
+	_, _ = c.Fprint(b.Writer, text) //nolint:errcheck // UI output errors are not critical
+}
+
+// PlainBackend writes text without colors
+type PlainBackend struct {
+	Writer io.Writer
+}
+
+// Emit implements Backend
+func (b *PlainBackend) Emit(_ Kind, text string) {
+	_, _ = io.WriteString(b.Writer, text) //nolint:errcheck // UI output errors are not critical
+}
+
+// lineBuffer joins fragments into lines, remembering the most severe kind of each line
+type lineBuffer struct {
+	line strings.Builder
+	kind Kind
+}
+
+// write adds text and passes every completed line to emit
+func (l *lineBuffer) write(kind Kind, text string, emit func(kind Kind, line string, complete bool)) {
+	for {
+		before, after, found := strings.Cut(text, "\n")
+		if before != "" {
+			l.line.WriteString(before)
+			l.kind = max(l.kind, kind)
+		}
+		if !found {
+			return
+		}
+		l.take(emit, true)
+		text = after
+	}
+}
+
+// take passes the pending line to emit and resets the buffer
+func (l *lineBuffer) take(emit func(kind Kind, line string, complete bool), complete bool) {
+	line, kind := l.line.String(), l.kind
+	l.line.Reset()
+	l.kind = KindInfo
+	emit(kind, line, complete)
+}
+
+// JSONBackend writes one JSON object per line of text: {"level": "...", "message": "..."}.
+// The level is the most severe kind among the line's fragments.
+type JSONBackend struct {
+	Writer io.Writer
+
+	buf lineBuffer
+}
+
+// jsonEvent is a line written by JSONBackend
+type jsonEvent struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// Emit implements Backend. Empty lines are dropped.
+func (b *JSONBackend) Emit(kind Kind, text string) {
+	b.buf.write(kind, text, b.writeEvent)
+}
+
+// Flush writes the pending partial line, e.g. a prompt waiting for input
+func (b *JSONBackend) Flush() {
+	b.buf.take(b.writeEvent, false)
+}
+
+// writeEvent writes a line as a JSON event
+func (b *JSONBackend) writeEvent(kind Kind, line string, _ bool) {
+	message := strings.TrimSpace(line)
+	if message == "" {
+		return
+	}
+
+	data, err := json.Marshal(jsonEvent{Level: kind.String(), Message: message})
+	if err != nil {
+		return
+	}
+	_, _ = b.Writer.Write(append(data, '\n')) //nolint:errcheck // UI output errors are not critical
+}
+
+// AccessibleBackend writes plain lines for screen readers: symbols are dropped, the severity
+// is spelled out ("Error: Access denied") and blank lines are skipped
+type AccessibleBackend struct {
+	Writer io.Writer
+
+	buf lineBuffer
+}
+
+// spokenSymbols removes the glyphs that screen readers read out or skip inconsistently
+var spokenSymbols = strings.NewReplacer(
+	"✓", "",
+	"✔", "",
+	"✗", "",
+	"⚠", "",
+	"→", "",
+	"\U0001F449", "",
+	"[OK]", "",
+	"[X]", "",
+	"[!]", "",
+	" ▸ ", ", ",
+)
+
+// severityWords prefix the lines of each kind
+var severityWords = map[Kind]string{
+	KindSuccess:  "OK: ",
+	KindQuestion: "Question: ",
+	KindWarning:  "Warning: ",
+	KindError:    "Error: ",
+}
+
+// Emit implements Backend
+func (b *AccessibleBackend) Emit(kind Kind, text string) {
+	b.buf.write(kind, text, b.writeLine)
+}
+
+// Flush writes the pending partial line (a prompt) without ending it
+func (b *AccessibleBackend) Flush() {
+	b.buf.take(b.writeLine, false)
+}
+
+// writeLine writes a line with its severity
+func (b *AccessibleBackend) writeLine(kind Kind, line string, complete bool) {
+	line = strings.TrimLeft(spokenSymbols.Replace(line), " ")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	// Drop a prefix the message already has, e.g. "Error: No allowed directories configured"
+	line = strings.TrimPrefix(line, strings.TrimSpace(severityWords[kind])+" ")
+
+	text := i18n.T(severityWords[kind]) + line
+	if complete {
+		text = strings.TrimRight(text, " ") + "\n"
+	}
+	_, _ = io.WriteString(b.Writer, text) //nolint:errcheck // UI output errors are not critical
+}
+
+// SilentBackend drops every message
+type SilentBackend struct{}
+
+// Emit implements Backend
+func (SilentBackend) Emit(Kind, string) {}
@@ -0,0 +1,203 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/mattn/go-isatty"
+
+	"github.com/23prime/claude-launcher/internal/i18n"
+)
+
+// isTerminal reports whether f is a terminal
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Interactive reports whether prompts can use arrow-key menus: stdin and stderr must be
+// terminals, TERM must not be "dumb" and the accessible mode must be off. Otherwise
+// (editors, CI logs, `script` captures, screen readers) prompts fall back to plain line input.
+func Interactive() bool {
+	return !accessible && os.Getenv("TERM") != "dumb" && isTerminal(os.Stdin) && isTerminal(os.Stderr)
+}
+
+// Prompt styles selectable with SetPromptStyle
+const (
+	PromptsMenu     = "menu"     // Arrow-key menus when Interactive (default)
+	PromptsNumbered = "numbered" // Always numbered lists
+)
+
+// numberedPrompts is set by SetPromptStyle
+var numberedPrompts bool
+
+// SetPromptStyle selects how Select and Search ask ("menu" or "numbered")
+func SetPromptStyle(style string) error {
+	switch style {
+	case PromptsMenu, "":
+		numberedPrompts = false
+	case PromptsNumbered:
+		numberedPrompts = true
+	default:
+		return fmt.Errorf("unknown prompt style %q (available: menu, numbered)", style)
+	}
+	return nil
+}
+
+// ArrowMenus reports whether prompts show arrow-key menus: they are Interactive and the
+// numbered prompt style is not selected
+func ArrowMenus() bool {
+	return !numberedPrompts && Interactive()
+}
+
+// StdinPiped reports whether stdin is a pipe or a redirected file, i.e. carries data
+// rather than a terminal or /dev/null
+func StdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeNamedPipe != 0 || info.Mode().IsRegular()
+}
+
+// inputWait is the time prompts spent waiting for the user, in nanoseconds
+var inputWait atomic.Int64
+
+// InputWait returns how long prompts have waited for the user's input so far, which --timing
+// leaves out of the startup phases
+func InputWait() time.Duration {
+	return time.Duration(inputWait.Load())
+}
+
+// waitedForInput adds the time since start to InputWait
+func waitedForInput(start time.Time) {
+	inputWait.Add(int64(time.Since(start)))
+}
+
+// ErrAborted is returned by Select when the user cancels the menu (Ctrl-C, Ctrl-D)
+var ErrAborted = errors.New("selection aborted")
+
+// Select asks the user to pick one of items and returns its index.
+// It shows an arrow-key menu when ArrowMenus, and a numbered list otherwise.
+func Select(label string, items []string) (int, error) {
+	if !ArrowMenus() {
+		return SelectPlain(os.Stdin, os.Stderr, label, items)
+	}
+
+	prompt := promptui.Select{
+		Label:     label,
+		Items:     items,
+		Templates: selectTemplates(),
+		Stdout:    os.Stderr,
+	}
+	start := time.Now()
+	idx, _, err := prompt.Run()
+	waitedForInput(start)
+	if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+		return 0, ErrAborted
+	}
+	return idx, err
+}
+
+// Search is Select for long lists: the arrow-key menu starts in search mode and narrows the
+// items to those FuzzyMatch finds the typed text in. Without a terminal it is a numbered list.
+func Search(label string, items []string) (int, error) {
+	if !ArrowMenus() {
+		return SelectPlain(os.Stdin, os.Stderr, label, items)
+	}
+
+	prompt := promptui.Select{
+		Label:             label,
+		Items:             items,
+		Size:              10,
+		Templates:         selectTemplates(),
+		Stdout:            os.Stderr,
+		Searcher:          func(input string, index int) bool { return FuzzyMatch(input, items[index]) },
+		StartInSearchMode: true,
+	}
+	start := time.Now()
+	idx, _, err := prompt.Run()
+	waitedForInput(start)
+	if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+		return 0, ErrAborted
+	}
+	return idx, err
+}
+
+// FuzzyMatch reports whether the characters of query appear in s in order, ignoring case and
+// spaces in query, so that "dvap" finds "~/develop/api"
+func FuzzyMatch(query, s string) bool {
+	rest := []rune(strings.ToLower(s))
+	for _, r := range strings.ToLower(query) {
+		if r == ' ' {
+			continue
+		}
+		i := slices.Index(rest, r)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+1:]
+	}
+	return true
+}
+
+// SelectPlain prints items as a numbered list to w and reads the number from r.
+// Empty input or EOF selects the first item.
+func SelectPlain(r io.Reader, w io.Writer, label string, items []string) (int, error) {
+	if len(items) == 0 {
+		return 0, fmt.Errorf("nothing to select")
+	}
+
+	p := NewPrinter(w)
+	p.Question("%s\n", label)
+	for i, item := range items {
+		p.Print("  %d) %s\n", i+1, item)
+	}
+	p.Print("  [1-%d] (default: 1): ", len(items))
+	p.Flush()
+
+	line, err := ReadLine(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read input: %w", err)
+	}
+	if line == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(line)
+	if err != nil || n < 1 || n > len(items) {
+		return 0, fmt.Errorf(i18n.T("invalid selection %q"), line)
+	}
+	return n - 1, nil
+}
+
+// ReadLine reads one trimmed line from r. It reads byte by byte so that input meant for
+// later prompts stays in r, even when they read it differently. EOF without input returns "".
+func ReadLine(r io.Reader) (string, error) {
+	defer waitedForInput(time.Now())
+	var b strings.Builder
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			b.WriteByte(buf[0])
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(b.String()), nil
+}
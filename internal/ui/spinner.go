@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/i18n"
+)
+
+const (
+	// spinnerDelay hides the spinner for steps that finish quickly
+	spinnerDelay = 300 * time.Millisecond
+	// spinnerInterval is the time between two frames
+	spinnerInterval = 100 * time.Millisecond
+)
+
+var (
+	spinnerFrames      = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	spinnerASCIIFrames = []string{"|", "/", "-", "\\"}
+)
+
+// Spinner shows a progress line (a spinning mark and the current step) while slow
+// startup steps run. It draws nothing unless the printer writes plain or colored text
+// to a terminal, and nothing for steps that finish within spinnerDelay.
+// The zero value and nil are inactive spinners.
+type Spinner struct {
+	w      io.Writer
+	frames []string
+
+	mu      sync.Mutex
+	message string
+	drawn   bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// Spinner starts a progress line on the printer's writer. Call Update to describe the
+// current step and Stop before printing anything else.
+func (p *Printer) Spinner() *Spinner {
+	if !p.showsProgress() {
+		return nil
+	}
+
+	s := &Spinner{
+		w:      p.Writer,
+		frames: spinnerFrames,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if useASCII() {
+		s.frames = spinnerASCIIFrames
+	}
+	go s.run()
+	return s
+}
+
+// showsProgress reports whether a spinner can be drawn: only for text backends on a
+// terminal, and never in quiet or accessible mode (screen readers read every redraw)
+func (p *Printer) showsProgress() bool {
+	if quiet || accessible || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	switch p.backend().(type) {
+	case *ColorBackend, *PlainBackend:
+	default:
+		return false
+	}
+	f, ok := p.Writer.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// Update sets the step shown next to the spinner. The format is translated with i18n.
+func (s *Spinner) Update(format string, args ...any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.message = Symbols(fmt.Sprintf(i18n.T(format), args...))
+}
+
+// Stop removes the progress line
+func (s *Spinner) Stop() {
+	if s == nil {
+		return
+	}
+	select {
+	case <-s.stop:
+		return
+	default:
+		close(s.stop)
+	}
+	<-s.done
+}
+
+// run draws the frames until Stop is called
+func (s *Spinner) run() {
+	defer close(s.done)
+
+	delay := time.NewTimer(spinnerDelay)
+	defer delay.Stop()
+	select {
+	case <-s.stop:
+		return
+	case <-delay.C:
+	}
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		s.draw(s.frames[frame%len(s.frames)])
+		select {
+		case <-s.stop:
+			s.clear()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw rewrites the progress line with frame
+func (s *Spinner) draw(frame string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = fmt.Fprintf(s.w, "\r\033[K%s %s", frame, s.message) //nolint:errcheck // UI output errors are not critical
+	s.drawn = true
+}
+
+// clear erases the progress line if it was drawn
+func (s *Spinner) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.drawn {
+		_, _ = io.WriteString(s.w, "\r\033[K") //nolint:errcheck // UI output errors are not critical
+	}
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Symbol styles selectable with SetSymbols
+const (
+	SymbolsAuto    = "auto"    // ASCII on limited terminals and non-UTF-8 locales
+	SymbolsUnicode = "unicode" // Always ✓ / ✗ / 👉
+	SymbolsASCII   = "ascii"   // Always [OK] / [X] / ->
+)
+
+// asciiSymbols replaces the Unicode glyphs used in messages and menus
+var asciiSymbols = strings.NewReplacer(
+	"✓", "[OK]",
+	"✔", "[OK]",
+	"✗", "[X]",
+	"⚠", "[!]",
+	"→", "->",
+	"▸", ">",
+	"\U0001F449", "->",
+	"↑", "up",
+	"↓", "down",
+)
+
+// asciiForced is set by ForceASCII and wins over SetSymbols
+var asciiForced bool
+
+// ascii reports whether glyphs are replaced; nil means auto-detect
+var ascii *bool
+
+// ForceASCII always uses ASCII symbols (--ascii)
+func ForceASCII() {
+	asciiForced = true
+}
+
+// SetSymbols selects the symbol style from config ("auto", "unicode" or "ascii")
+func SetSymbols(style string) error {
+	switch style {
+	case SymbolsAuto, "":
+		ascii = nil
+	case SymbolsUnicode, SymbolsASCII:
+		v := style == SymbolsASCII
+		ascii = &v
+	default:
+		return fmt.Errorf("unknown symbols style %q (available: auto, unicode, ascii)", style)
+	}
+	return nil
+}
+
+// useASCII reports whether messages use ASCII instead of Unicode symbols
+func useASCII() bool {
+	if asciiForced {
+		return true
+	}
+	if ascii != nil {
+		return *ascii
+	}
+	return limitedTerminal(os.Getenv)
+}
+
+// limitedTerminal detects terminals that usually cannot show the glyphs: the Linux console,
+// dumb and VT terminals, and non-UTF-8 locales
+func limitedTerminal(getenv func(string) string) bool {
+	switch getenv("TERM") {
+	case "linux", "dumb", "vt100", "vt102", "vt220", "cons25":
+		return true
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := getenv(name); v != "" {
+			v = strings.ToLower(v)
+			return !strings.Contains(v, "utf-8") && !strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
+
+// Symbols returns s with Unicode symbols replaced by ASCII when ASCII symbols are in use
+func Symbols(s string) string {
+	if useASCII() {
+		return asciiSymbols.Replace(s)
+	}
+	return s
+}
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+
+	"github.com/23prime/claude-launcher/internal/config"
+)
+
+// Theme holds the color attributes of each kind of message
+type Theme struct {
+	Success   []color.Attribute
+	Error     []color.Attribute
+	Warning   []color.Attribute
+	Highlight []color.Attribute
+}
+
+// DefaultTheme is the built-in green/red/yellow palette
+var DefaultTheme = Theme{
+	Success:   []color.Attribute{color.FgGreen},
+	Error:     []color.Attribute{color.FgRed},
+	Warning:   []color.Attribute{color.FgYellow, color.Bold},
+	Highlight: []color.Attribute{color.FgCyan},
+}
+
+// ColorblindTheme avoids red/green: blue for success, orange for errors (Okabe-Ito based)
+var ColorblindTheme = Theme{
+	Success:   color256(33),
+	Error:     color256(208),
+	Warning:   append(color256(220), color.Bold),
+	Highlight: color256(39),
+}
+
+// palettes are the built-in themes selectable with theme.palette
+var palettes = map[string]Theme{
+	"default":    DefaultTheme,
+	"colorblind": ColorblindTheme,
+}
+
+// Palettes returns the names of the built-in palettes, sorted
+func Palettes() []string {
+	return slices.Sorted(maps.Keys(palettes))
+}
+
+// colorNames are the named colors accepted in the theme
+var colorNames = map[string]color.Attribute{
+	"black":     color.FgBlack,
+	"red":       color.FgRed,
+	"green":     color.FgGreen,
+	"yellow":    color.FgYellow,
+	"blue":      color.FgBlue,
+	"magenta":   color.FgMagenta,
+	"cyan":      color.FgCyan,
+	"white":     color.FgWhite,
+	"hiblack":   color.FgHiBlack,
+	"hired":     color.FgHiRed,
+	"higreen":   color.FgHiGreen,
+	"hiyellow":  color.FgHiYellow,
+	"hiblue":    color.FgHiBlue,
+	"himagenta": color.FgHiMagenta,
+	"hicyan":    color.FgHiCyan,
+	"hiwhite":   color.FgHiWhite,
+}
+
+// theme is the active theme, set by SetTheme
+var theme = DefaultTheme
+
+// SetTheme activates the configured palette and color overrides for all printers
+func SetTheme(t config.Theme) error {
+	palette := t.Palette
+	if palette == "" {
+		palette = "default"
+	}
+	active, ok := palettes[palette]
+	if !ok {
+		return fmt.Errorf("unknown palette %q (available: default, colorblind)", t.Palette)
+	}
+
+	overrides := []struct {
+		name  string
+		value string
+		attrs *[]color.Attribute
+	}{
+		{"success", t.Success, &active.Success},
+		{"error", t.Error, &active.Error},
+		{"warning", t.Warning, &active.Warning},
+		{"highlight", t.Highlight, &active.Highlight},
+	}
+	for _, o := range overrides {
+		if o.value == "" {
+			continue
+		}
+		attrs, err := parseColor(o.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s color: %w", o.name, err)
+		}
+		*o.attrs = attrs
+	}
+
+	theme = active
+	return nil
+}
+
+// parseColor parses a color name or a 256-color code (0-255)
+func parseColor(s string) ([]color.Attribute, error) {
+	if attr, ok := colorNames[strings.ToLower(s)]; ok {
+		return []color.Attribute{attr}, nil
+	}
+	code, err := strconv.Atoi(s)
+	if err != nil || code < 0 || code > 255 {
+		return nil, fmt.Errorf("%q is neither a color name nor a 256-color code (0-255)", s)
+	}
+	return color256(code), nil
+}
+
+// color256 returns the attributes selecting a foreground color from the 256-color palette
+func color256(code int) []color.Attribute {
+	return []color.Attribute{38, 5, color.Attribute(code)}
+}
+
+// selectTemplates returns the promptui templates for selection lists, colored with the theme.
+// Select renders them to stderr, so colors follow whether stderr is a terminal.
+func selectTemplates() *promptui.SelectTemplates {
+	funcs := template.FuncMap{}
+	maps.Copy(funcs, promptui.FuncMap)
+	funcs["highlight"] = colorSprint(theme.Highlight, os.Stderr)
+	funcs["success"] = colorSprint(theme.Success, os.Stderr)
+
+	return &promptui.SelectTemplates{
+		Label:    "{{ . }}",
+		Active:   Symbols("\U0001F449") + " {{ . | highlight }}",
+		Inactive: "  {{ . }}",
+		Selected: Symbols("\U00002714") + " {{ . | success }}",
+		FuncMap:  funcs,
+	}
+}
+
+// colorSprint returns a function formatting its argument in the given colors when w supports them
+func colorSprint(attrs []color.Attribute, w io.Writer) func(any) string {
+	c := color.New(attrs...)
+	if useColor(w) {
+		c.EnableColor()
+	} else {
+		c.DisableColor()
+	}
+	return func(v any) string {
+		return c.Sprint(v)
+	}
+}
+
+// Highlight returns s in the theme's highlight color when the printer's writer supports it
+func (p *Printer) Highlight(s string) string {
+	return colorSprint(theme.Highlight, p.Writer)(s)
+}
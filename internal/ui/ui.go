@@ -3,17 +3,34 @@ package ui
 import (
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/fatih/color"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	"github.com/23prime/claude-launcher/internal/i18n"
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/security"
+	"github.com/23prime/claude-launcher/internal/snapshot"
+	"github.com/23prime/claude-launcher/internal/state"
+	"github.com/23prime/claude-launcher/internal/timing"
 )
 
-// Printer handles formatted output with colors
+// Printer formats the launcher's messages and hands them to a Backend
 type Printer struct {
-	Writer io.Writer
+	Writer  io.Writer
+	Backend Backend // Chosen from Writer by the mode (--ui, config) on first use when nil
 }
 
-// NewPrinter creates a new Printer
+// NewPrinter creates a new Printer. Its backend is picked when it first prints, so a
+// printer created before the config is loaded still follows the configured mode.
 func NewPrinter(writer io.Writer) *Printer {
 	if writer == nil {
 		writer = os.Stderr
@@ -21,46 +38,436 @@ func NewPrinter(writer io.Writer) *Printer {
 	return &Printer{Writer: writer}
 }
 
-// Success prints a success message in green
+// colorDisabled is set by DisableColor
+var colorDisabled bool
+
+// DisableColor turns off colored output for all printers (--no-color)
+func DisableColor() {
+	colorDisabled = true
+}
+
+// quiet is set by SetQuiet
+var quiet bool
+
+// SetQuiet suppresses informational messages for all printers (--quiet).
+// Errors, warnings, prompts and requested listings are still printed.
+func SetQuiet() {
+	quiet = true
+}
+
+// useColor reports whether output to w is colored: only when w is a terminal, and
+// never with --no-color, NO_COLOR (https://no-color.org) or TERM=dumb
+func useColor(w io.Writer) bool {
+	if colorDisabled || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// emit formats a message and passes it to the backend.
+// The format is translated with i18n, so it is the catalog key, and symbols follow SetSymbols.
+func (p *Printer) emit(kind Kind, format string, args ...any) {
+	p.backend().Emit(kind, Symbols(fmt.Sprintf(i18n.T(format), args...)))
+}
+
+// backend returns the printer's backend, creating it for the current mode on first use
+func (p *Printer) backend() Backend {
+	if p.Backend == nil {
+		p.Backend = backendFactory(p.Writer)
+	}
+	return p.Backend
+}
+
+// Flush writes a pending partial line (a prompt) for backends that buffer lines
+func (p *Printer) Flush() {
+	if f, ok := p.Backend.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+}
+
+// Success prints a success message in the theme's success color (green by default)
 func (p *Printer) Success(format string, args ...any) {
-	green := color.New(color.FgGreen)
-	_, _ = green.Fprintf(p.Writer, format, args...) //nolint:errcheck // UI output errors are not critical
+	p.emit(KindSuccess, format, args...)
 }
 
-// Error prints an error message in red
+// Error prints an error message in the theme's error color (red by default)
 func (p *Printer) Error(format string, args ...any) {
-	red := color.New(color.FgRed)
-	_, _ = red.Fprintf(p.Writer, format, args...) //nolint:errcheck // UI output errors are not critical
+	p.emit(KindError, format, args...)
 }
 
-// Warning prints a warning message in yellow
+// Question prints a question the user is asked to answer (in the warning color)
+func (p *Printer) Question(format string, args ...any) {
+	p.emit(KindQuestion, format, args...)
+}
+
+// Warning prints a warning message in the theme's warning color (bold yellow by default)
 func (p *Printer) Warning(format string, args ...any) {
-	yellow := color.New(color.FgYellow, color.Bold)
-	_, _ = yellow.Fprintf(p.Writer, format, args...) //nolint:errcheck // UI output errors are not critical
+	p.emit(KindWarning, format, args...)
 }
 
 // Print prints a normal message
 func (p *Printer) Print(format string, args ...any) {
-	_, _ = fmt.Fprintf(p.Writer, format, args...) //nolint:errcheck // UI output errors are not critical
+	p.emit(KindInfo, format, args...)
 }
 
-// ShowAllowedDirs displays the list of allowed directories
+// ShowAllowedDirs displays the list of allowed directories, flagging those the directory check
+// skips (missing, unreadable) or resolves elsewhere (symlinks)
 func (p *Printer) ShowAllowedDirs(dirs []string) {
 	p.Print("Allowed directories:\n")
 	for _, dir := range dirs {
-		p.Print("  - %s\n", dir)
+		note, problem := dirNote(dir)
+		line := p.fitPath(dir, 4+runewidth.StringWidth(note)) + note
+		if problem {
+			p.Warning("  - %s\n", line)
+		} else {
+			p.Print("  - %s\n", line)
+		}
+	}
+}
+
+// dirNote describes what the directory check finds at an allowed directory: "" when it is a
+// plain directory. problem is set when the entry cannot allow anything.
+func dirNote(dir string) (note string, problem bool) {
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		return " " + i18n.T("(missing)"), true
+	case err != nil:
+		return " " + fmt.Sprintf(i18n.T("(cannot be checked: %v)"), err), true
+	case !info.IsDir():
+		return " " + i18n.T("(not a directory)"), true
+	}
+	if resolved, err := security.ResolvePath(dir); err == nil && resolved != filepath.Clean(dir) {
+		return " " + fmt.Sprintf(i18n.T("(symlink → %s)"), resolved), false
+	}
+	return "", false
+}
+
+// ShowProfiles lists the configured launch profiles
+func (p *Printer) ShowProfiles(profiles map[string]config.Profile) {
+	if len(profiles) == 0 {
+		p.Print("No profiles configured.\n")
+		return
+	}
+
+	p.Print("Available profiles:\n")
+	var rows [][]string
+	for _, name := range slices.Sorted(maps.Keys(profiles)) {
+		rows = append(rows, []string{"  - " + name, profiles[name].Dir})
+	}
+	for _, line := range AlignColumns(rows, p.lineWidth()) {
+		p.Print("%s\n", line)
+	}
+}
+
+// ShowBookmarks lists the bookmarks with their directories and bindings
+func (p *Printer) ShowBookmarks(bookmarks map[string]config.Bookmark) {
+	if len(bookmarks) == 0 {
+		p.Print("No bookmarks configured.\n")
+		return
+	}
+
+	p.Print("Available bookmarks:\n")
+	var rows [][]string
+	for _, name := range slices.Sorted(maps.Keys(bookmarks)) {
+		b := bookmarks[name]
+		var bindings []string
+		if b.Account != "" {
+			bindings = append(bindings, fmt.Sprintf(i18n.T("account %s"), b.Account))
+		}
+		if b.Preset != "" {
+			bindings = append(bindings, fmt.Sprintf(i18n.T("preset %s"), b.Preset))
+		}
+		rows = append(rows, []string{"  - @" + name, strings.Join(bindings, ", "), b.Path})
+	}
+	for _, line := range AlignColumns(rows, p.lineWidth()) {
+		p.Print("%s\n", line)
+	}
+}
+
+// ShowProfileSelected shows which launch profile is used
+func (p *Printer) ShowProfileSelected(name string) {
+	if quiet {
+		return
+	}
+	p.Success("✓")
+	p.Print(" Profile: %s\n", name)
+}
+
+// ShowBanner shows the launch context on one line, e.g. "work ▸ Work ▸ ~/work/api ▸ sonnet"
+func (p *Printer) ShowBanner(parts []string) {
+	if quiet || len(parts) == 0 {
+		return
+	}
+	p.Print("%s\n\n", strings.Join(parts, " ▸ "))
+}
+
+// ShowWorkspaces lists the configured workspaces and their directories
+func (p *Printer) ShowWorkspaces(workspaces map[string]config.Workspace) {
+	if len(workspaces) == 0 {
+		p.Print("No workspaces configured.\n")
+		return
+	}
+
+	p.Print("Available workspaces:\n")
+	for _, name := range slices.Sorted(maps.Keys(workspaces)) {
+		p.Print("  - %s\n", name)
+		for _, dir := range workspaces[name].Dirs {
+			p.Print("      %s\n", p.fitPath(dir, 6))
+		}
 	}
 }
 
+// ShowMCPServers lists the global MCP servers and those of each project for `mcp list`
+func (p *Printer) ShowMCPServers(global map[string]config.MCPServer, projects []config.Project) {
+	count := len(global)
+	for _, proj := range projects {
+		count += len(proj.MCPServers)
+	}
+	if count == 0 {
+		p.Print("No MCP servers configured.\n")
+		return
+	}
+
+	if len(global) > 0 {
+		p.Print("Global MCP servers:\n")
+		p.showMCPServers(global)
+	}
+	for _, proj := range projects {
+		if len(proj.MCPServers) > 0 {
+			p.Print("MCP servers of %s:\n", p.fitPath(proj.Path, 17))
+			p.showMCPServers(proj.MCPServers)
+		}
+	}
+}
+
+// showMCPServers prints one line per server: its name and what it runs or connects to
+func (p *Printer) showMCPServers(servers map[string]config.MCPServer) {
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		server := servers[name]
+		target := strings.Join(append([]string{server.Command}, server.Args...), " ")
+		if server.URL != "" {
+			target = server.URL
+		}
+		if server.Type != "" {
+			target = server.Type + " " + target
+		}
+		p.Print("  - %s: %s\n", name, target)
+	}
+}
+
+// ShowPlugins lists the plugins for `plugins`: name and path, marking those whose hooks the
+// config enables. paths maps each name to its executable, empty when it is not on PATH.
+func (p *Printer) ShowPlugins(paths map[string]string, enabled []string) {
+	if len(paths) == 0 {
+		p.Print("No plugins found on PATH.\n")
+		return
+	}
+
+	p.Print("Plugins:\n")
+	var rows [][]string
+	for _, name := range slices.Sorted(maps.Keys(paths)) {
+		path := paths[name]
+		if path == "" {
+			path = i18n.T("(not found on PATH)")
+		}
+		if slices.Contains(enabled, name) {
+			path += " " + i18n.T("[hooks enabled]")
+		}
+		rows = append(rows, []string{"  - " + name, path})
+	}
+	for _, line := range AlignColumns(rows, p.lineWidth()) {
+		p.Print("%s\n", line)
+	}
+}
+
+// ShowFindings displays the problems found by the config lint, each with its suggested fix
+func (p *Printer) ShowFindings(findings []config.Finding) {
+	if len(findings) == 0 {
+		p.Success("✓ No problems found in the configuration\n")
+		return
+	}
+	for _, f := range findings {
+		p.Warning("⚠ %s\n", f.Message)
+		p.Print("    Fix: %s\n", f.Fix)
+	}
+	p.Print("%d problem(s) found\n", len(findings))
+}
+
+// ShowWorkspaceOpened shows which workspace is opened and its working directory
+func (p *Printer) ShowWorkspaceOpened(name, dir string) {
+	if quiet {
+		return
+	}
+	p.Success("✓")
+	p.Print(" Workspace: %s (%s)\n", name, dir)
+}
+
 // ShowAccessDenied shows an access denied message with details
 func (p *Printer) ShowAccessDenied(currentDir string, allowedDirs []string) {
 	p.Error("✗ Access denied\n")
 	p.Print("\n")
 	p.Print("Current directory: %s\n", currentDir)
 	p.Print("\n")
-	p.Print("Claude Code is not allowed to run in this directory.\n")
+	// The wording may be replaced by the config (prompts.denied), so it is not a format
+	p.Print("%s\n", i18n.T("Claude Code is not allowed to run in this directory."))
 	p.ShowAllowedDirs(allowedDirs)
 	p.Print("\n")
+	p.Print("Run 'claude-launcher explain' to see why.\n")
+	p.Print("\n")
+}
+
+// ShowDirectoryExplanation walks through a directory decision for `explain`: how the path
+// resolved, the outcome of every allowed directory and what would change the result.
+// yolo, when set, is the same for yoloAllowedDirs.
+func (p *Printer) ShowDirectoryExplanation(e, yolo *security.Explanation, configPath string, fromEnv bool) {
+	p.Print("Path:     %s\n", e.Path)
+	if e.Resolved != e.Path {
+		p.Print("Resolved: %s (symlinks followed)\n", e.Resolved)
+	}
+	p.Print("\n")
+
+	if fromEnv {
+		p.Print("Allowed directories (from CLAUDE_SAFE_DIRS, which overrides allowedDirs):\n")
+	} else {
+		p.Print("Allowed directories (from allowedDirs in %s):\n", configPath)
+	}
+	p.showRules(e.Rules)
+	p.Print("\n")
+
+	if match := e.Match(); match != nil {
+		p.Success("✓ Allowed by %s\n", match.Dir)
+	} else {
+		p.Error("✗ Not allowed: no allowed directory contains %s\n", e.Resolved)
+		p.showAllowHint(e, configPath, fromEnv)
+	}
+
+	if yolo != nil {
+		p.Print("\n")
+		p.Print("Allowed directories for --dangerously-skip-permissions:\n")
+		p.showRules(yolo.Rules)
+		p.Print("\n")
+		if match := yolo.Match(); match != nil {
+			p.Success("✓ --dangerously-skip-permissions is allowed by %s\n", match.Dir)
+		} else {
+			p.Print("--dangerously-skip-permissions is not allowed here.\n")
+		}
+	}
+}
+
+// showRules lists the outcome of each allowed directory
+func (p *Printer) showRules(rules []security.RuleResult) {
+	if len(rules) == 0 {
+		p.Print("  (none)\n")
+		return
+	}
+
+	symbols := map[security.RuleStatus]string{
+		security.RuleMatched:      "✓",
+		security.RuleNotMatched:   "✗",
+		security.RuleMissing:      "⚠",
+		security.RuleUnresolvable: "⚠",
+	}
+	rows := make([][]string, 0, len(rules))
+	for _, rule := range rules {
+		dir := rule.Dir
+		if rule.Resolved != "" && rule.Resolved != rule.Dir {
+			dir += " → " + rule.Resolved
+		}
+		rows = append(rows, []string{"  " + symbols[rule.Status], i18n.T(string(rule.Status)), dir})
+	}
+	for _, line := range AlignColumns(rows, p.lineWidth()) {
+		p.Print("%s\n", line)
+	}
+}
+
+// showAllowHint explains a denial: symlinks leading out of an allowed directory, skipped
+// entries, and the config change that would allow the path
+func (p *Printer) showAllowHint(e *security.Explanation, configPath string, fromEnv bool) {
+	p.Print("\n")
+	for _, rule := range e.Rules {
+		if rule.Status == security.RuleNotMatched && security.IsWithin(e.Path, rule.Dir) {
+			p.Print("%s is inside %s as written, but symlinks lead to %s.\n", e.Path, rule.Dir, e.Resolved)
+			p.Print("Directories are compared after following symlinks.\n")
+			p.Print("\n")
+			break
+		}
+	}
+	for _, rule := range e.Rules {
+		if rule.Status == security.RuleMissing || rule.Status == security.RuleUnresolvable {
+			p.Print("Entries marked with ⚠ could not be resolved and are skipped.\n")
+			p.Print("\n")
+			break
+		}
+	}
+
+	if fromEnv {
+		p.Print("To allow it, add it to CLAUDE_SAFE_DIRS:\n")
+		p.Print("  export CLAUDE_SAFE_DIRS=\"$CLAUDE_SAFE_DIRS%c%s\"\n", os.PathListSeparator, e.Resolved)
+	} else {
+		p.Print("To allow it, add it to allowedDirs in %s:\n", configPath)
+		p.Print("  \"allowedDirs\": [..., %s]\n", strconv.Quote(e.Resolved))
+	}
+}
+
+// ShowSkipPermissionsDenied shows that --dangerously-skip-permissions is not allowed here
+func (p *Printer) ShowSkipPermissionsDenied(currentDir string, yoloDirs []string) {
+	p.Error("✗ --dangerously-skip-permissions is not allowed in this directory\n")
+	p.Print("\n")
+	p.Print("Current directory: %s\n", currentDir)
+	p.Print("\n")
+	if len(yoloDirs) == 0 {
+		p.Print("No yoloAllowedDirs are configured.\n")
+	} else {
+		p.Print("Allowed directories for --dangerously-skip-permissions:\n")
+		for _, dir := range yoloDirs {
+			p.Print("  - %s\n", p.fitPath(dir, 4))
+		}
+	}
+	p.Print("\n")
+}
+
+// ShowReadOnly notes that claude may not change files (--read-only)
+func (p *Printer) ShowReadOnly() {
+	if quiet {
+		return
+	}
+	p.Success("→")
+	p.Print(" Read-only session: Claude may not edit files\n")
+}
+
+// ShowLandlockEnabled notes that claude may only write to the project (unless readOnly), its
+// config dir and temp
+func (p *Printer) ShowLandlockEnabled(readOnly bool) {
+	if quiet {
+		return
+	}
+	p.Success("→")
+	if readOnly {
+		p.Print(" Writes restricted to Claude's config dir and temp (Landlock)\n")
+		return
+	}
+	p.Print(" Writes restricted to the project, Claude's config dir and temp (Landlock)\n")
+}
+
+// ShowRootDenied explains why a launch as root is refused
+func (p *Printer) ShowRootDenied() {
+	p.Error("✗ Refusing to launch as root\n")
+	p.Print("\n")
+	p.Print("Claude could change or delete anything on this system. Run the launcher as a regular user,\n")
+	p.Print("or pass --allow-root (or set \"allowRoot\": true in config.json) if this is intended.\n")
+	p.Print("\n")
+}
+
+// ShowRootAllowed warns that claude runs as root
+func (p *Printer) ShowRootAllowed() {
+	p.Warning("⚠ Running as root: Claude can change or delete anything on this system\n")
+}
+
+// ShowSkipPermissionsEnabled warns that claude runs without permission prompts
+func (p *Printer) ShowSkipPermissionsEnabled() {
+	p.Warning("⚠ Permission checks are disabled (--dangerously-skip-permissions)\n")
 }
 
 // ShowConfigError shows a configuration error message
@@ -69,35 +476,191 @@ func (p *Printer) ShowConfigError() {
 	p.Print("\n")
 	p.Print("Please set allowed directories using one of these methods:\n")
 	p.Print("\n")
-	p.Print("1. Environment variable (colon-separated):\n")
-	p.Print("   export CLAUDE_SAFE_DIRS=\"$HOME/projects:$HOME/work\"\n")
+	if runtime.GOOS == "windows" {
+		p.Print("1. Environment variable (semicolon-separated, PowerShell):\n")
+		p.Print("   $env:CLAUDE_SAFE_DIRS = \"$HOME\\projects;$HOME\\work\"\n")
+	} else {
+		p.Print("1. Environment variable (colon-separated):\n")
+		p.Print("   export CLAUDE_SAFE_DIRS=\"$HOME/projects:$HOME/work\"\n")
+	}
 	p.Print("\n")
 	p.Print("2. Create ~/.config/claude-launcher/config.json:\n")
 	p.Print("   {\"allowedDirs\": [\"/home/user/projects\"]}\n")
 	p.Print("\n")
 }
 
+// ShowClaudeNotFound shows that none of the claude binary candidates exist and how to install it
+func (p *Printer) ShowClaudeNotFound(candidates []string) {
+	if len(candidates) > 1 {
+		p.Error("✗ claude not found (tried %s)\n", strings.Join(candidates, ", "))
+	} else {
+		p.Error("✗ claude not found in PATH\n")
+	}
+	p.Print("\n")
+	p.Print("Install Claude Code with:\n")
+	p.Print("  npm install -g @anthropic-ai/claude-code\n")
+	p.Print("\n")
+}
+
+// ShowToolNotFound shows that the binary of a non-default tool is not in PATH
+func (p *Printer) ShowToolNotFound(name string) {
+	p.Error("✗ %s not found in PATH\n", name)
+	p.Print("\n")
+}
+
+// ShowToolIgnoresConfigDir warns that the selected account's config directory does not apply to tool
+func (p *Printer) ShowToolIgnoresConfigDir(name string) {
+	p.Warning("⚠")
+	p.Print(" %s has no config directory setting; the account's configDir is ignored\n", name)
+	p.Print("\n")
+}
+
+// ShowClaudeTooOld shows that the installed claude is older than required
+func (p *Printer) ShowClaudeTooOld(found, minimum string) {
+	p.Error("✗ claude %s is older than the required minimum %s\n", found, minimum)
+	p.Print("\n")
+	p.Print("Update Claude Code with:\n")
+	p.Print("  claude update\n")
+	p.Print("\n")
+}
+
+// ShowRequirementFailures lists the requirements of a project that are not met, with their hints
+func (p *Printer) ShowRequirementFailures(failures []launcher.RequirementFailure) {
+	p.Error("✗ The environment of this project is not ready:\n")
+	for _, f := range failures {
+		req := f.Requirement.String()
+		switch {
+		case f.Problem == launcher.ProblemNotFound:
+			p.Print("  - %s: not found on PATH\n", req)
+		case f.Problem == launcher.ProblemTooOld:
+			p.Print("  - %s: found %s\n", req, f.Detail)
+		case f.Problem == launcher.ProblemMissing:
+			p.Print("  - %s: missing\n", req)
+		case f.Problem == launcher.ProblemNoVersion:
+			p.Print("  - %s: could not read its version\n", req)
+		case f.Detail != "":
+			p.Print("  - %s: failed (%s)\n", req, f.Detail)
+		default:
+			p.Print("  - %s: failed\n", req)
+		}
+		if f.Requirement.Hint != "" {
+			p.Print("    %s\n", f.Requirement.Hint)
+		}
+	}
+	p.Print("\n")
+}
+
+// ShowBranchPreset shows the permission preset a branch rule applies
+func (p *Printer) ShowBranchPreset(branch, preset string) {
+	if quiet {
+		return
+	}
+	p.Success("✓")
+	p.Print(" Branch %s: %s preset\n", branch, preset)
+}
+
 // ShowDirectoryAllowed shows that the directory check passed
 func (p *Printer) ShowDirectoryAllowed() {
+	if quiet {
+		return
+	}
 	p.Success("✓")
 	p.Print(" Directory allowed\n")
 	p.Print("\n")
 }
 
+// ShowExplanation shows the reasons --explain gives for the step shown before. Unlike the
+// status lines it is shown with --quiet too, as it was asked for.
+func (p *Printer) ShowExplanation(reasons []string) {
+	for _, reason := range reasons {
+		p.Print("  ▸ %s\n", reason)
+	}
+	p.Print("\n")
+}
+
+// ShowFirstLaunch summarizes what Claude will be able to access before the first launch in dir.
+// complete is false when files stopped at a limit.
+func (p *Printer) ShowFirstLaunch(dir, remote string, files int, complete bool) {
+	p.Warning("⚠ First launch in this directory\n")
+	p.Print("  Path:       %s\n", dir)
+	if remote != "" {
+		p.Print("  Git remote: %s\n", remote)
+	} else {
+		p.Print("  Git remote: (none)\n")
+	}
+	if complete {
+		p.Print("  Files:      %d\n", files)
+	} else {
+		p.Print("  Files:      %d+\n", files)
+	}
+	p.Print("\n")
+}
+
+// WorkTree is the state of a git working tree checked before a launch (gitCheck)
+type WorkTree struct {
+	Root      string
+	Changes   int    // Uncommitted changes, untracked files included
+	Detached  bool   // HEAD is not on a branch
+	Operation string // Unfinished operation, e.g. "rebase" or "merge"; "" when none
+}
+
+// Clean reports whether the working tree has nothing to warn about
+func (w WorkTree) Clean() bool {
+	return w.Changes == 0 && !w.Detached && w.Operation == ""
+}
+
+// ShowWorkTree lists what is unclean about a working tree, as a warning or, when blocked, as the
+// reason the launch is refused
+func (p *Printer) ShowWorkTree(w WorkTree, blocked bool) {
+	if blocked {
+		p.Error("✗ The git working tree of %s is not clean (gitCheck: block)\n", w.Root)
+	} else {
+		p.Warning("⚠ The git working tree of %s is not clean\n", w.Root)
+	}
+	if w.Changes > 0 {
+		p.Print("  - %d uncommitted change(s)\n", w.Changes)
+	}
+	if w.Detached {
+		p.Print("  - HEAD is detached\n")
+	}
+	if w.Operation != "" {
+		p.Print("  - A %s is in progress\n", w.Operation)
+	}
+	p.Print("\n")
+}
+
 // ShowContinuingSession shows that we're continuing the previous session
 func (p *Printer) ShowContinuingSession() {
+	if quiet {
+		return
+	}
 	p.Success("→")
 	p.Print(" Continuing previous session...\n")
 }
 
+// ShowPickingSession shows that the user chooses the session to resume
+func (p *Printer) ShowPickingSession() {
+	if quiet {
+		return
+	}
+	p.Success("→")
+	p.Print(" Opening the session picker...\n")
+}
+
 // ShowStartingNewSession shows that we're starting a new session
 func (p *Printer) ShowStartingNewSession() {
+	if quiet {
+		return
+	}
 	p.Success("→")
 	p.Print(" Starting new session...\n")
 }
 
 // ShowAccountSelected shows that an account was selected
 func (p *Printer) ShowAccountSelected(name string, configDir string) {
+	if quiet {
+		return
+	}
 	p.Success("✓")
 	p.Print(" Account: %s (%s)\n", name, configDir)
 	p.Print("\n")
@@ -105,6 +668,9 @@ func (p *Printer) ShowAccountSelected(name string, configDir string) {
 
 // ShowNoAccountsConfigured shows that no accounts are configured (using default)
 func (p *Printer) ShowNoAccountsConfigured() {
+	if quiet {
+		return
+	}
 	p.Print("Using default Claude configuration\n")
 	p.Print("\n")
 }
@@ -115,3 +681,329 @@ func (p *Printer) ShowAccountNotFound(name string) {
 	p.Print(" Account '%s' not found in configuration\n", name)
 	p.Print("\n")
 }
+
+// ShowSessionDetached shows how to reattach to a detached session
+func (p *Printer) ShowSessionDetached(id string) {
+	p.Success("→")
+	p.Print(" Detached session %s\n", id)
+	p.Print("  Reattach with: claude-launcher attach %s\n", id)
+}
+
+// ShowAttaching shows that we're attaching to a detached session
+func (p *Printer) ShowAttaching(id string) {
+	if quiet {
+		return
+	}
+	p.Success("→")
+	p.Print(" Attaching to session %s (press Ctrl-\\ to detach)...\n", id)
+}
+
+// ShowDevcontainerLaunch shows that claude is being launched in a devcontainer
+func (p *Printer) ShowDevcontainerLaunch(workspace string) {
+	if quiet {
+		return
+	}
+	p.Success("→")
+	p.Print(" Launching in the devcontainer of %s...\n", workspace)
+}
+
+// ShowRemoteLaunch shows that claude is being launched on an SSH host
+func (p *Printer) ShowRemoteLaunch(dir, host string) {
+	if quiet {
+		return
+	}
+	p.Success("→")
+	p.Print(" Launching in %s on %s...\n", dir, host)
+}
+
+// SessionSummary describes a finished session for ShowSessionSummary
+type SessionSummary struct {
+	Duration     time.Duration
+	ExitCode     *int
+	Git          bool // The directory is in a git repository, so changes were counted
+	FilesChanged int
+	Insertions   int
+	Deletions    int
+	Usage        *launcher.Tokens // Nil when no usage was recorded
+	Resume       string           // Command continuing the session
+}
+
+// ShowSessionSummary shows how a foreground session went once claude exits
+func (p *Printer) ShowSessionSummary(s SessionSummary) {
+	if quiet {
+		return
+	}
+	duration := s.Duration.Round(time.Second).String()
+	p.Print("\n")
+	switch {
+	case s.ExitCode != nil && *s.ExitCode == 0:
+		p.Success("✓")
+		p.Print(" Session ended after %s\n", duration)
+	case s.ExitCode != nil:
+		p.Warning("⚠ Session ended after %s with exit code %d\n", duration, *s.ExitCode)
+	default:
+		p.Warning("⚠ Session ended after %s\n", duration)
+	}
+	if s.Git {
+		p.Print("  Files changed: %d (+%d -%d)\n", s.FilesChanged, s.Insertions, s.Deletions)
+	}
+	if s.Usage != nil {
+		p.Print("  Tokens: %s ($%.2f estimated)\n", FormatTokens(s.Usage.Total()), s.Usage.CostUSD)
+	}
+	p.Print("  Resume: %s\n", s.Resume)
+}
+
+// ShowSessionEnded shows that a detached session has finished
+func (p *Printer) ShowSessionEnded(id string) {
+	if quiet {
+		return
+	}
+	p.Print("\n")
+	p.Success("✓")
+	p.Print(" Session %s ended\n", id)
+}
+
+// ShowNoDetachedSession shows that no matching detached session was found
+func (p *Printer) ShowNoDetachedSession(id string) {
+	if id != "" {
+		p.Error("✗ No detached session with ID '%s'\n", id)
+		return
+	}
+	p.Error("✗ No single detached session found; specify an ID\n")
+}
+
+// ShowBinaryVersion shows the version reported by the resolved binary and, when set, the
+// command that actually runs it (e.g. node and the script behind an npm shim)
+func (p *Printer) ShowBinaryVersion(tool, version, command string) {
+	if version == "" {
+		p.Warning("⚠ %s did not report a version\n", tool)
+	} else if !quiet {
+		p.Print("%s %s\n", tool, version)
+	}
+	if command != "" && !quiet {
+		p.Print("Runs: %s\n", command)
+	}
+}
+
+// ShowInstances displays running Claude sessions as a table
+func (p *Printer) ShowInstances(instances []state.Instance, now time.Time) {
+	if len(instances) == 0 {
+		p.Print("No running Claude sessions\n")
+		return
+	}
+
+	rows := [][]string{strings.Split(i18n.T("ID\tPID\tMODE\tSTARTED\tACCOUNT\tDIRECTORY"), "\t")}
+	for _, inst := range instances {
+		mode := i18n.T("foreground")
+		if inst.Socket != "" {
+			mode = i18n.T("detached")
+		}
+		accountName := inst.Account
+		if accountName == "" {
+			accountName = "-"
+		}
+		rows = append(rows, []string{
+			inst.ID, strconv.Itoa(inst.PID), mode, FormatAge(now.Sub(inst.StartedAt)), accountName, inst.Dir,
+		})
+	}
+	for _, line := range AlignColumns(rows, p.lineWidth()) {
+		p.Print("%s\n", line)
+	}
+}
+
+// ShowSnapshots displays snapshots as a table, newest first
+func (p *Printer) ShowSnapshots(snapshots []snapshot.Snapshot, now time.Time) {
+	if len(snapshots) == 0 {
+		p.Print("No snapshots\n")
+		return
+	}
+
+	rows := [][]string{strings.Split(i18n.T("ID\tMODE\tTAKEN\tBRANCH\tDIRECTORY"), "\t")}
+	for _, s := range slices.Backward(snapshots) {
+		branch := s.Branch
+		if branch == "" {
+			branch = "-"
+		}
+		rows = append(rows, []string{s.ID, s.Mode, FormatAge(now.Sub(s.CreatedAt)), branch, s.Root})
+	}
+	for _, line := range AlignColumns(rows, p.lineWidth()) {
+		p.Print("%s\n", line)
+	}
+}
+
+// ShowSnapshotTaken shows that a snapshot was taken before a launch
+func (p *Printer) ShowSnapshotTaken(id string) {
+	if quiet {
+		return
+	}
+	p.Success("✓ Snapshot %s taken ('claude-launcher rollback' restores it)\n", id)
+}
+
+// AccountUsage is the recent use of one account, shown by ShowAccountUsage
+type AccountUsage struct {
+	Name         string
+	WindowTokens int       // Tokens used in the rolling usage window (5 hours)
+	WeekTokens   int       // Tokens used in the last 7 days
+	LastUsed     time.Time // Zero when unused for 7 days
+	LimitReached time.Time // Latest usage limit error in the last 7 days, or zero
+}
+
+// ShowAccountUsage displays the recent use of the accounts as a table
+func (p *Printer) ShowAccountUsage(usages []AccountUsage, now time.Time) {
+	if len(usages) == 0 {
+		p.Print("No accounts configured\n")
+		return
+	}
+
+	orDash := func(s string, ok bool) string {
+		if !ok {
+			return "-"
+		}
+		return s
+	}
+	rows := [][]string{strings.Split(i18n.T("ACCOUNT\tLAST 5H\tLAST 7 DAYS\tLAST USED\tLIMIT REACHED"), "\t")}
+	for _, u := range usages {
+		rows = append(rows, []string{
+			u.Name,
+			orDash(FormatTokens(u.WindowTokens), u.WindowTokens > 0),
+			orDash(FormatTokens(u.WeekTokens), u.WeekTokens > 0),
+			orDash(FormatAge(now.Sub(u.LastUsed)), !u.LastUsed.IsZero()),
+			orDash(FormatAge(now.Sub(u.LimitReached)), !u.LimitReached.IsZero()),
+		})
+	}
+	for _, line := range AlignColumns(rows, p.lineWidth()) {
+		p.Print("%s\n", line)
+	}
+}
+
+// ShowTiming shows how long each phase of the startup took (--timing), apart from the time
+// spent waiting for answers
+func (p *Printer) ShowTiming(phases []timing.Phase, total time.Duration) {
+	ms := func(d time.Duration) string { return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond)) }
+
+	p.Print("Startup timing:\n")
+	rows := make([][]string, 0, len(phases)+1)
+	for _, phase := range phases {
+		row := []string{"  " + phase.Name, ms(phase.Duration)}
+		if waited := phase.Waited.Round(time.Millisecond); waited > 0 {
+			row = append(row, fmt.Sprintf(i18n.T("(+%s waiting for input)"), waited))
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows, []string{"  " + i18n.T("total"), ms(total)})
+	for _, line := range AlignColumns(rows, p.lineWidth()) {
+		p.Print("%s\n", line)
+	}
+	p.Print("\n")
+}
+
+// statsTop is how many entries of each list ShowStats prints
+const statsTop = 10
+
+// ShowStats summarizes the launch history for `stats`
+func (p *Printer) ShowStats(s launcher.Stats) {
+	if s.Launches == 0 {
+		p.Print("No launches recorded yet\n")
+		return
+	}
+
+	p.Print("Launches: %d\n", s.Launches)
+	p.Print("Session time: %.1fh this week, %.1fh this month\n", s.WeekTime.Hours(), s.MonthTime.Hours())
+
+	p.showCounts("Projects:\n", s.Projects)
+	p.showCounts("Accounts:\n", s.Accounts)
+	p.showCounts("Presets:\n", s.Presets)
+}
+
+// showCounts prints the most used entries of counts under title
+func (p *Printer) showCounts(title string, counts []launcher.Count) {
+	if len(counts) == 0 {
+		return
+	}
+
+	p.Print("\n")
+	p.Print(title)
+	rows := make([][]string, 0, statsTop)
+	for _, c := range counts[:min(len(counts), statsTop)] {
+		name := c.Name
+		if name == "" {
+			name = i18n.T("(default)")
+		}
+		rows = append(rows, []string{fmt.Sprintf("%6d", c.Launches), name})
+	}
+	for _, line := range AlignColumns(rows, p.lineWidth()) {
+		p.Print("%s\n", line)
+	}
+	if rest := len(counts) - statsTop; rest > 0 {
+		p.Print("  ... and %d more\n", rest)
+	}
+}
+
+// ShowCostStats summarizes the token usage and estimated cost of the launch history for `stats --cost`
+func (p *Printer) ShowCostStats(c launcher.CostStats) {
+	if c.Sessions == 0 {
+		p.Print("No token usage recorded yet\n")
+		return
+	}
+
+	p.Print("Sessions with usage: %d\n", c.Sessions)
+	p.Print("Total: %s tokens, $%.2f (estimated)\n", FormatTokens(c.Total.Total()), c.Total.CostUSD)
+
+	p.showTokenCounts("Projects:\n", c.Projects)
+	p.showTokenCounts("Accounts:\n", c.Accounts)
+}
+
+// showTokenCounts prints the most expensive entries of counts under title
+func (p *Printer) showTokenCounts(title string, counts []launcher.TokenCount) {
+	p.Print("\n")
+	p.Print(title)
+	rows := make([][]string, 0, statsTop)
+	for _, c := range counts[:min(len(counts), statsTop)] {
+		name := c.Name
+		if name == "" {
+			name = i18n.T("(default)")
+		}
+		rows = append(rows, []string{fmt.Sprintf("%8s", "$"+strconv.FormatFloat(c.CostUSD, 'f', 2, 64)), fmt.Sprintf("%6s", FormatTokens(c.Total())), name})
+	}
+	for _, line := range AlignColumns(rows, p.lineWidth()) {
+		p.Print("%s\n", line)
+	}
+	if rest := len(counts) - statsTop; rest > 0 {
+		p.Print("  ... and %d more\n", rest)
+	}
+}
+
+// ShowSessionKilled shows that a session was asked to stop
+func (p *Printer) ShowSessionKilled(id string, pid int) {
+	if quiet {
+		return
+	}
+	p.Success("✓")
+	p.Print(" Sent termination signal to session %s (PID %d)\n", id, pid)
+}
+
+// FormatTokens formats a token count briefly (e.g. "950", "12k", "1.2M")
+func FormatTokens(n int) string {
+	switch {
+	case n < 1000:
+		return strconv.Itoa(n)
+	case n < 1000000:
+		return fmt.Sprintf("%dk", n/1000)
+	default:
+		return fmt.Sprintf("%.1fM", float64(n)/1000000)
+	}
+}
+
+// FormatAge formats a duration as a short human-readable age (e.g. "5m ago")
+func FormatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return i18n.T("just now")
+	case d < time.Hour:
+		return fmt.Sprintf(i18n.T("%dm ago"), int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf(i18n.T("%dh ago"), int(d.Hours()))
+	default:
+		return fmt.Sprintf(i18n.T("%dd ago"), int(d.Hours()/24))
+	}
+}
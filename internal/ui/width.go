@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/23prime/claude-launcher/internal/log"
+)
+
+const (
+	// ellipsis replaces the part of a path that does not fit
+	ellipsis      = "…"
+	asciiEllipsis = "..."
+	// columnGap is the space between aligned columns
+	columnGap = 2
+	// minPathWidth keeps truncated paths readable on very narrow terminals
+	minPathWidth = 20
+)
+
+// TerminalWidth returns the width of the terminal w writes to, or 0 when w is not a
+// terminal (output is then never truncated). COLUMNS overrides the detected width.
+func TerminalWidth(w io.Writer) int {
+	f, ok := w.(*os.File)
+	if !ok || !isTerminal(f) {
+		return 0
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	width, _, err := term.GetSize(f.Fd())
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// TruncatePath shortens path to at most width columns by replacing its beginning with an
+// ellipsis, keeping whole trailing components where possible ("…/work/api"). A width of
+// 0 or less means no limit.
+func TruncatePath(path string, width int) string {
+	if width <= 0 || runewidth.StringWidth(path) <= width {
+		return path
+	}
+	mark := ellipsis
+	if useASCII() {
+		mark = asciiEllipsis
+	}
+	width = max(width, minPathWidth) - runewidth.StringWidth(mark)
+
+	// Prefer cutting at a separator so the visible part starts with a whole component
+	sep := string(filepath.Separator)
+	tail := path
+	for runewidth.StringWidth(tail) > width {
+		_, rest, found := strings.Cut(strings.TrimPrefix(tail, sep), sep)
+		if !found {
+			break
+		}
+		tail = sep + rest
+	}
+	if runewidth.StringWidth(tail) > width {
+		tail = runewidth.TruncateLeft(tail, runewidth.StringWidth(tail)-width, "")
+	}
+	return mark + tail
+}
+
+// lineWidth returns the width available to the printer's lists, or 0 for no limit.
+// Paths are never shortened for JSON or accessible output, or with --verbose.
+func (p *Printer) lineWidth() int {
+	if log.DebugEnabled() {
+		return 0
+	}
+	switch p.backend().(type) {
+	case *ColorBackend, *PlainBackend:
+		return TerminalWidth(p.Writer)
+	default:
+		return 0
+	}
+}
+
+// menuIndent is the room taken by the cursor or number in front of menu items
+const menuIndent = 6
+
+// MenuWidth returns the width available to the items of Select, or 0 for no limit
+func MenuWidth() int {
+	if accessible || log.DebugEnabled() {
+		return 0
+	}
+	if width := TerminalWidth(os.Stderr); width > menuIndent {
+		return width - menuIndent
+	}
+	return 0
+}
+
+// fitPath shortens path to the rest of a line that already holds indent columns
+func (p *Printer) fitPath(path string, indent int) string {
+	width := p.lineWidth()
+	if width == 0 {
+		return path
+	}
+	return TruncatePath(path, width-indent)
+}
+
+// AlignColumns pads every column but the last to the widest cell in it and returns
+// one line per row. The last column (usually a path) is shortened to fit width;
+// a width of 0 or less means no limit.
+func AlignColumns(rows [][]string, width int) []string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row[:max(len(row)-1, 0)] {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], runewidth.StringWidth(cell))
+		}
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			if i == len(row)-1 {
+				last := cell
+				if width > 0 {
+					last = TruncatePath(cell, width-runewidth.StringWidth(b.String()))
+				}
+				b.WriteString(last)
+				break
+			}
+			b.WriteString(runewidth.FillRight(cell, widths[i]+columnGap))
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	return lines
+}
@@ -0,0 +1,120 @@
+// Package update checks whether a newer claude-launcher release exists, so that users do not
+// keep running old directory checks without knowing. Checks are rate-limited through a cache
+// in the state directory.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// ReleasesURL is the GitHub API endpoint of the latest release
+const ReleasesURL = "https://api.github.com/repos/23prime/claude-launcher/releases/latest"
+
+// ReleasesPage is where users download releases
+const ReleasesPage = "https://github.com/23prime/claude-launcher/releases"
+
+// DisableEnvVar turns the check off when set to a non-empty value, like updateCheck: false
+const DisableEnvVar = "CLAUDE_LAUNCHER_NO_UPDATE_CHECK"
+
+// Interval is how long a check result is reused before the releases feed is asked again
+const Interval = 24 * time.Hour
+
+// timeout bounds the request, so an unreachable network never delays the exit
+const timeout = 5 * time.Second
+
+// cacheName is the cache file in the state directory
+const cacheName = "update-check.json"
+
+// cache is the result of the last check
+type cache struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	Latest    string    `json:"latest"`
+}
+
+// Checker finds the latest release
+type Checker struct {
+	Store  *state.Store
+	URL    string // Defaults to ReleasesURL
+	Client *http.Client
+	Now    func() time.Time // Defaults to time.Now
+}
+
+// Latest returns the version of the latest release. It reuses the cached result when it is
+// younger than Interval, and caches a fresh one otherwise.
+func (c *Checker) Latest(ctx context.Context) (string, error) {
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+
+	// A missing or unreadable cache only means checking again
+	var cached cache
+	if err := c.Store.ReadJSON(cacheName, &cached); err == nil && now().Sub(cached.CheckedAt) < Interval {
+		return cached.Latest, nil
+	}
+
+	// Failures are cached too, so an offline machine is not asked again on every launch
+	latest, err := c.fetch(ctx)
+	if err != nil {
+		_ = c.Store.WriteJSON(cacheName, cache{CheckedAt: now(), Latest: cached.Latest}) //nolint:errcheck // the check failed anyway
+		return "", err
+	}
+	if err := c.Store.WriteJSON(cacheName, cache{CheckedAt: now(), Latest: latest}); err != nil {
+		return "", err
+	}
+	return latest, nil
+}
+
+// fetch asks the releases feed for the tag of the latest release
+func (c *Checker) fetch(ctx context.Context) (string, error) {
+	url := c.URL
+	if url == "" {
+		url = ReleasesURL
+	}
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: timeout}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only body
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to check for updates: %s", resp.Status)
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("failed to parse the latest release: %w", err)
+	}
+	if release.TagName == "" {
+		return "", errors.New("failed to parse the latest release: no tag_name")
+	}
+	return release.TagName, nil
+}
+
+// Newer reports whether latest is a newer version than current. Development builds ("dev")
+// are never outdated.
+func Newer(current, latest string) bool {
+	if current == "" || current == "dev" {
+		return false
+	}
+	return launcher.CompareVersions(latest, current) > 0
+}
@@ -0,0 +1,73 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+func TestCheckerLatest(t *testing.T) {
+	requests := 0
+	tag := "v1.4.0"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if tag == "" {
+			http.Error(w, "rate limited", http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"tag_name": "` + tag + `", "name": "Release"}`)) //nolint:errcheck // test server
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	c := &Checker{Store: &state.Store{Dir: t.TempDir()}, URL: server.URL, Now: func() time.Time { return now }}
+
+	if latest, err := c.Latest(context.Background()); err != nil || latest != "v1.4.0" {
+		t.Fatalf("Latest() = %q, %v", latest, err)
+	}
+
+	// Within the interval the cached result is reused
+	tag = "v1.5.0"
+	now = now.Add(Interval - time.Minute)
+	if latest, err := c.Latest(context.Background()); err != nil || latest != "v1.4.0" || requests != 1 {
+		t.Errorf("Latest() = %q, %v after %d requests, expected the cached v1.4.0", latest, err, requests)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if latest, err := c.Latest(context.Background()); err != nil || latest != "v1.5.0" || requests != 2 {
+		t.Errorf("Latest() = %q, %v after %d requests, expected a fresh v1.5.0", latest, err, requests)
+	}
+
+	// A failed check is not retried before the interval either
+	tag = ""
+	now = now.Add(Interval)
+	if _, err := c.Latest(context.Background()); err == nil {
+		t.Error("Latest() should fail when the feed does")
+	}
+	if latest, err := c.Latest(context.Background()); err != nil || latest != "v1.5.0" || requests != 3 {
+		t.Errorf("Latest() = %q, %v after %d requests, expected the previous result", latest, err, requests)
+	}
+}
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.2.0", "v1.3.0", true},
+		{"1.2.0", "v1.2.1", true},
+		{"v1.3.0", "v1.3.0", false},
+		{"v1.4.0", "v1.3.0", false},
+		{"dev", "v9.0.0", false},
+		{"v1.2.0", "", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/23prime/claude-launcher/internal/account"
+)
+
+// ErrAccountNotFound is wrapped by the error of AccountByName when no account has the name
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrAccountRequired is returned by AccountByName("") when several accounts are configured
+var ErrAccountRequired = errors.New("several accounts configured; select one by name")
+
+// Account is a Claude account: a config directory with its own default model. Accounts loaded
+// by DefaultAccounts also keep their other settings (type, API key, proxy, backend, env).
+type Account struct {
+	Name      string
+	ConfigDir string // CLAUDE_CONFIG_DIR of the account's launches
+	Model     string // Optional: default model of the account
+
+	file *account.Account
+}
+
+// newAccount returns the Account of a loaded account
+func newAccount(a account.Account) Account {
+	return Account{Name: a.Name, ConfigDir: a.ConfigDir, Model: a.Model, file: &a}
+}
+
+// internal returns the account with the fields of a applied over the loaded settings
+func (a *Account) internal() account.Account {
+	var acc account.Account
+	if a.file != nil {
+		acc = *a.file
+	}
+	acc.Name = a.Name
+	acc.ConfigDir = a.ConfigDir
+	acc.Model = a.Model
+	return acc
+}
+
+// AccountSource lists the configured accounts
+type AccountSource interface {
+	Accounts(ctx context.Context) ([]Account, error)
+}
+
+// AccountSourceFunc adapts a function to AccountSource
+type AccountSourceFunc func(ctx context.Context) ([]Account, error)
+
+// Accounts implements AccountSource
+func (f AccountSourceFunc) Accounts(ctx context.Context) ([]Account, error) { return f(ctx) }
+
+// DefaultAccounts reads the accounts like the claude-launcher command: from CLAUDE_ACCOUNTS,
+// then from config.json. No accounts is not an error.
+var DefaultAccounts AccountSource = AccountSourceFunc(func(ctx context.Context) ([]Account, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	cfg, err := account.LoadAccountConfig()
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	accounts := make([]Account, 0, len(cfg.Accounts))
+	for _, a := range cfg.Accounts {
+		accounts = append(accounts, newAccount(a))
+	}
+	return accounts, nil
+})
+
+// AccountSelector picks the account of a launch
+type AccountSelector interface {
+	// Select returns the account to use among accounts, or nil for Claude's default
+	// configuration
+	Select(ctx context.Context, accounts []Account) (*Account, error)
+}
+
+// AccountByName selects the account with this name. The empty name selects the only account,
+// or none when no accounts are configured.
+type AccountByName string
+
+// Select implements AccountSelector
+func (n AccountByName) Select(_ context.Context, accounts []Account) (*Account, error) {
+	if n == "" {
+		switch len(accounts) {
+		case 0:
+			return nil, nil
+		case 1:
+			return &accounts[0], nil
+		default:
+			return nil, ErrAccountRequired
+		}
+	}
+	for i := range accounts {
+		if accounts[i].Name == string(n) {
+			return &accounts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, string(n))
+}
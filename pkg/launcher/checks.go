@@ -0,0 +1,76 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/23prime/claude-launcher/internal/config"
+	core "github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// errSkipPermissionsRefused is wrapped with ErrNotAllowed when --dangerously-skip-permissions is
+// refused in an allowed directory, which the audit log records
+var errSkipPermissionsRefused = errors.New("--dangerously-skip-permissions not allowed")
+
+// runningAsRoot reports whether the process runs with an effective UID of 0.
+// Always false on Windows, where Geteuid returns -1.
+var runningAsRoot = func() bool { return os.Geteuid() == 0 }
+
+// checkRoot refuses a launch as root unless allowRoot is set, as the command does without
+// --allow-root. Both outcomes are written to the audit log.
+func checkRoot(cfg *config.Config, dir string, args []string) error {
+	if !runningAsRoot() {
+		return nil
+	}
+	if !cfg.AllowRoot {
+		_ = audit(state.AuditRootDenied, dir, "", args) //nolint:errcheck // the launch is refused anyway
+		return fmt.Errorf("%w: running as root without allowRoot", ErrNotAllowed)
+	}
+	return audit(state.AuditRoot, dir, "", args)
+}
+
+// branchPreset enforces the branch rules of project on a launch in dir and returns the permission
+// preset to launch with, as the command does: a deny rule refuses the launch and a preset rule
+// applies its preset, refusing another one and --dangerously-skip-permissions
+func branchPreset(ctx context.Context, project *config.Project, dir, preset string, args []string) (string, error) {
+	if project == nil || len(project.Branches) == 0 {
+		return preset, nil
+	}
+	// Outside git repositories and on a detached HEAD no rule applies
+	cmd := exec.CommandContext(ctx, "git", "symbolic-ref", "--quiet", "--short", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return preset, ctx.Err()
+	}
+	branch := strings.TrimSpace(string(out))
+	rule := config.BranchRuleFor(project, branch)
+	switch {
+	case rule == nil:
+		return preset, nil
+	case rule.Deny:
+		return "", fmt.Errorf("%w: launches on branch %s of %s", ErrNotAllowed, branch, project.Path)
+	case preset != "" && preset != rule.Preset:
+		return "", fmt.Errorf("%w: branch %s requires the %s preset, not %s", ErrNotAllowed, branch, rule.Preset, preset)
+	case core.HasSkipPermissions(args):
+		return "", fmt.Errorf("%w: --dangerously-skip-permissions on branch %s", ErrNotAllowed, branch)
+	}
+	return rule.Preset, nil
+}
+
+// audit appends an entry to the audit log of the claude-launcher command
+func audit(event, dir, accountName string, args []string) error {
+	store, err := state.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := store.AppendAudit(state.AuditEntry{Event: event, Dir: dir, Account: accountName, Args: args}); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/23prime/claude-launcher/internal/config"
+)
+
+// Config is the launcher configuration. A Config loaded from config.json (DefaultConfig,
+// ConfigFile) also keeps the settings that have no field here, such as env, permission presets
+// and branch rules; a Config built in code has only these fields.
+type Config struct {
+	AllowedDirs     []string  // Directories, with their subdirectories, where Claude may run
+	YoloAllowedDirs []string  // Directories where --dangerously-skip-permissions is allowed
+	AllowRoot       bool      // Launch even when running as root
+	Projects        []Project // Settings of the launches inside each project's Path
+
+	file *config.Config
+}
+
+// Project holds the settings of launches inside Path. Projects loaded from config.json keep
+// their other settings (env, permissions, branch rules, ...) as well.
+type Project struct {
+	Path       string
+	Model      string               // Optional: default model of the project
+	Preset     string               // Optional: permission preset applied when the request has none
+	LinkedDirs []string             // Optional: directories passed to claude with --add-dir
+	MCPServers map[string]MCPServer // Optional: merged over the global MCP servers
+
+	file *config.Project
+}
+
+// MCPServer is an MCP server passed to claude with --mcp-config: a command (stdio) or a URL
+type MCPServer struct {
+	Type    string
+	Command string
+	Args    []string
+	Env     map[string]string
+	URL     string
+	Headers map[string]string
+}
+
+// ConfigLoader loads the configuration of a launch
+type ConfigLoader interface {
+	Load(ctx context.Context) (*Config, error)
+}
+
+// ConfigLoaderFunc adapts a function to ConfigLoader
+type ConfigLoaderFunc func(ctx context.Context) (*Config, error)
+
+// Load implements ConfigLoader
+func (f ConfigLoaderFunc) Load(ctx context.Context) (*Config, error) { return f(ctx) }
+
+// DefaultConfig loads the configuration like the claude-launcher command: the allowed
+// directories from CLAUDE_LAUNCHER_CI_ALLOW (in CI) or CLAUDE_SAFE_DIRS when set, everything
+// else from ~/.config/claude-launcher/config.json
+var DefaultConfig ConfigLoader = ConfigLoaderFunc(func(ctx context.Context) (*Config, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return newConfig(cfg), nil
+})
+
+// ConfigFile loads the configuration from the config.json at path only
+func ConfigFile(path string) ConfigLoader {
+	return ConfigLoaderFunc(func(ctx context.Context) (*Config, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cfg, err := (&config.FileLoader{Path: path}).Load()
+		if err != nil {
+			return nil, err
+		}
+		if len(cfg.AllowedDirs) == 0 {
+			return nil, fmt.Errorf("no allowedDirs found in %s", path)
+		}
+		return newConfig(cfg), nil
+	})
+}
+
+// newConfig returns the Config of a loaded configuration
+func newConfig(cfg *config.Config) *Config {
+	c := &Config{
+		AllowedDirs:     cfg.AllowedDirs,
+		YoloAllowedDirs: cfg.YoloAllowedDirs,
+		AllowRoot:       cfg.AllowRoot,
+		file:            cfg,
+	}
+	for i := range cfg.Projects {
+		p := &cfg.Projects[i]
+		servers := make(map[string]MCPServer, len(p.MCPServers))
+		for name, server := range p.MCPServers {
+			servers[name] = MCPServer(server)
+		}
+		c.Projects = append(c.Projects, Project{
+			Path:       p.Path,
+			Model:      p.Model,
+			Preset:     p.PermissionPreset,
+			LinkedDirs: p.LinkedDirs,
+			MCPServers: servers,
+			file:       p,
+		})
+	}
+	return c
+}
+
+// internal returns the configuration with the fields of c applied over the loaded settings
+func (c *Config) internal() *config.Config {
+	var cfg config.Config
+	if c.file != nil {
+		cfg = *c.file
+	}
+	cfg.AllowedDirs = c.AllowedDirs
+	cfg.YoloAllowedDirs = c.YoloAllowedDirs
+	cfg.AllowRoot = c.AllowRoot
+	cfg.Projects = make([]config.Project, 0, len(c.Projects))
+	for _, p := range c.Projects {
+		cfg.Projects = append(cfg.Projects, p.internal())
+	}
+	return &cfg
+}
+
+// internal returns the project with the fields of p applied over the loaded settings
+func (p Project) internal() config.Project {
+	var project config.Project
+	if p.file != nil {
+		project = *p.file
+	}
+	project.Path = p.Path
+	project.Model = p.Model
+	project.PermissionPreset = p.Preset
+	project.LinkedDirs = p.LinkedDirs
+	project.MCPServers = make(map[string]config.MCPServer, len(p.MCPServers))
+	for name, server := range p.MCPServers {
+		project.MCPServers[name] = config.MCPServer(server)
+	}
+	return project
+}
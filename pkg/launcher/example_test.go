@@ -0,0 +1,22 @@
+package launcher_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/23prime/claude-launcher/pkg/launcher"
+)
+
+// An editor plugin running a prompt in the user's project with the Work account
+func ExampleLauncher_Run() {
+	l := launcher.Launcher{Selector: launcher.AccountByName("Work")}
+
+	_, err := l.Run(context.Background(), launcher.Request{
+		Dir:  "/home/me/projects/api",
+		Args: []string{"-p", "summarize the open TODOs"},
+	})
+	if errors.Is(err, launcher.ErrNotAllowed) {
+		fmt.Println("Claude is not allowed in this project")
+	}
+}
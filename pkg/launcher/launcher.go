@@ -0,0 +1,367 @@
+// Package launcher is the public Go API of claude-launcher. It lets other tools (editor
+// plugins, team CLIs) apply the same directory policy, account selection and launch settings as
+// the claude-launcher command before starting Claude Code.
+//
+// A zero Launcher behaves like the command without prompts: it loads the configuration from
+// the environment and ~/.config/claude-launcher/config.json, refuses directories outside the
+// allowed ones, uses the only configured account (if any) and applies the project's model,
+// permission preset, MCP servers and environment:
+//
+//	var l launcher.Launcher
+//	launch, err := l.Run(ctx, launcher.Request{Dir: dir, Args: []string{"-p", "hello"}})
+//	if errors.Is(err, launcher.ErrNotAllowed) {
+//		// refused by the policy
+//	}
+//
+// Every step can be replaced through the Config, Policy, Accounts, Selector and Runner fields.
+//
+// Besides the directory check, Prepare applies the checks of the command that need no answer
+// from a user: launches as root are refused without allowRoot, the branch rules of the project
+// apply, and launches as root and with --dangerously-skip-permissions (forwarded or refused) are
+// written to the command's audit log. A custom Policy only replaces the directory check.
+//
+// The rest of the command's launch flow is left to the caller, and these checks are not made:
+//   - the config and launch hooks of plugins, and the launchScript: they run programs that
+//     expect the command's flags and output, so directories plugins add to allowedDirs are
+//     not allowed here
+//   - Landlock, --read-only and resource limits: they wrap the claude process, which the
+//     caller's Runner starts
+//   - confirmNewDirs, gitCheck, snapshots, requirements and the warning about a session
+//     already running in the directory, which ask the user or change the working tree
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+
+	"github.com/23prime/claude-launcher/internal/account"
+	"github.com/23prime/claude-launcher/internal/config"
+	core "github.com/23prime/claude-launcher/internal/launcher"
+	"github.com/23prime/claude-launcher/internal/secrets"
+	"github.com/23prime/claude-launcher/internal/security"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// ErrNotAllowed is wrapped by the errors of a Policy refusing a directory, and of the checks
+// refusing a launch as root or on a branch
+var ErrNotAllowed = errors.New("directory not allowed")
+
+// Policy decides whether Claude may be launched in a directory
+type Policy interface {
+	// Check returns the rule that allows launching claude with args in dir (e.g. the allowed
+	// directory containing it), or an error wrapping ErrNotAllowed
+	Check(ctx context.Context, dir string, args []string) (rule string, err error)
+}
+
+// DirectoryChecker is the policy of the claude-launcher command: a directory is allowed when it
+// is one of AllowedDirs or inside one, after resolving symlinks. --dangerously-skip-permissions
+// is only allowed inside YoloAllowedDirs.
+type DirectoryChecker struct {
+	AllowedDirs     []string
+	YoloAllowedDirs []string
+}
+
+// NewDirectoryChecker creates a DirectoryChecker without any yoloAllowedDirs
+func NewDirectoryChecker(allowedDirs []string) *DirectoryChecker {
+	return &DirectoryChecker{AllowedDirs: allowedDirs}
+}
+
+// Check implements Policy
+func (c *DirectoryChecker) Check(ctx context.Context, dir string, args []string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	rule, err := security.NewDirectoryChecker(c.AllowedDirs).Match(dir)
+	if err != nil {
+		return "", err
+	}
+	if rule == "" {
+		return "", fmt.Errorf("%w: %s", ErrNotAllowed, dir)
+	}
+	if core.HasSkipPermissions(args) {
+		yolo, err := security.NewDirectoryChecker(c.YoloAllowedDirs).IsAllowed(dir)
+		if err != nil {
+			return "", err
+		}
+		if !yolo {
+			return "", fmt.Errorf("%w: %w in %s", ErrNotAllowed, errSkipPermissionsRefused, dir)
+		}
+	}
+	return rule, nil
+}
+
+// Runner runs a prepared claude command
+type Runner interface {
+	Run(ctx context.Context, cmd *exec.Cmd) error
+}
+
+// ExecRunner runs the command and waits for it to exit. The command is stopped when ctx is done.
+type ExecRunner struct{}
+
+// Run implements Runner
+func (ExecRunner) Run(_ context.Context, cmd *exec.Cmd) error {
+	return cmd.Run()
+}
+
+// Launcher launches Claude Code with the policy and settings of claude-launcher.
+// The zero value uses the defaults of the claude-launcher command.
+type Launcher struct {
+	Config     ConfigLoader    // Optional: defaults to DefaultConfig
+	Policy     Policy          // Optional: defaults to a DirectoryChecker over the allowed directories
+	Accounts   AccountSource   // Optional: defaults to DefaultAccounts
+	Selector   AccountSelector // Optional: defaults to AccountByName("")
+	Runner     Runner          // Optional: defaults to ExecRunner
+	ClaudePath string          // Optional: the claude binary (defaults to claudePath, then claude in PATH)
+}
+
+// Request describes one launch
+type Request struct {
+	Dir      string   // Optional: defaults to the current directory
+	Args     []string // Passed to claude after the launcher's own arguments
+	Continue bool     // Continue the previous session
+	Model    string   // Optional: overrides the project and account defaults
+	Preset   string   // Optional: permission preset from permissionPresets
+
+	// Standard streams of claude; they default to the launcher process's own
+	Stdin          io.Reader
+	Stdout, Stderr io.Writer
+}
+
+// Prepared is a launch that passed the policy, ready to run
+type Prepared struct {
+	Dir     string   // Absolute launch directory
+	Rule    string   // What allowed the directory, as returned by the Policy
+	Account *Account // The selected account, nil for Claude's default configuration
+	Cmd     *exec.Cmd
+
+	command *core.Command
+}
+
+// Close removes the temporary files of the launch (permission settings, MCP config). Call it
+// once claude has exited.
+func (p *Prepared) Close() {
+	p.command.Cleanup()
+}
+
+// Prepare loads the configuration, checks the directory against the policy, applies the root
+// check and the branch rules, selects the account and builds the claude command without running
+// it. The caller must Close the result.
+func (l *Launcher) Prepare(ctx context.Context, req Request) (*Prepared, error) {
+	loaded, err := l.configLoader().Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg := loaded.internal()
+
+	dir := req.Dir
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return nil, err
+	}
+
+	policy := l.Policy
+	if policy == nil {
+		policy = &DirectoryChecker{AllowedDirs: cfg.AllowedDirs, YoloAllowedDirs: cfg.YoloAllowedDirs}
+	}
+	rule, err := policy.Check(ctx, dir, req.Args)
+	if err != nil {
+		if errors.Is(err, errSkipPermissionsRefused) {
+			_ = audit(state.AuditSkipPermissionsDenied, dir, "", req.Args) //nolint:errcheck // the launch is refused anyway
+		}
+		return nil, err
+	}
+	if err := checkRoot(cfg, dir, req.Args); err != nil {
+		return nil, err
+	}
+	project := cfg.FindProject(dir)
+	if req.Preset, err = branchPreset(ctx, project, dir, req.Preset, req.Args); err != nil {
+		return nil, err
+	}
+
+	accounts, err := l.accountSource().Accounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load accounts: %w", err)
+	}
+	selected, err := l.selector().Select(ctx, accounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select account: %w", err)
+	}
+
+	opts, err := launchOptions(cfg, project, dir, rule, selected, req)
+	if err != nil {
+		return nil, err
+	}
+
+	cl := core.NewLauncher()
+	cl.EnvPolicy = core.EnvPolicy{Passthrough: cfg.Env.Passthrough, Block: cfg.Env.Block}
+	cl.Candidates = cfg.ClaudePath
+	if l.ClaudePath != "" {
+		cl.Candidates = []string{l.ClaudePath}
+	}
+	if _, err := cl.Preflight(cfg.MinClaudeVersion); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	command, err := cl.Prepare(opts)
+	if err != nil {
+		return nil, err
+	}
+	if core.HasSkipPermissions(opts.Args) {
+		if err := audit(state.AuditSkipPermissions, dir, opts.Account, opts.Args); err != nil {
+			command.Cleanup()
+			return nil, err
+		}
+	}
+	// #nosec G204 -- the resolved claude binary with the caller's arguments
+	cmd := exec.CommandContext(ctx, command.Path, command.Args...)
+	cmd.Dir, cmd.Env = command.Dir, command.Env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = req.Stdin, req.Stdout, req.Stderr
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+
+	return &Prepared{Dir: dir, Rule: rule, Account: selected, Cmd: cmd, command: command}, nil
+}
+
+// Run prepares the launch and runs claude until it exits. A non-zero exit is reported as an
+// *exec.ExitError; the prepared launch is returned whenever claude was run.
+func (l *Launcher) Run(ctx context.Context, req Request) (*Prepared, error) {
+	p, err := l.Prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer p.Close()
+
+	runner := l.Runner
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	return p, runner.Run(ctx, p.Cmd)
+}
+
+// launchOptions applies the project and account settings to req, as the command does
+func launchOptions(cfg *config.Config, project *config.Project, dir, rule string, selected *Account, req Request) (core.LaunchOptions, error) {
+	permissions, err := cfg.PermissionsFor(project, req.Preset)
+	if err != nil {
+		return core.LaunchOptions{}, err
+	}
+
+	env := cfg.EnvFor(project)
+	otelEnv := maps.Clone(cfg.OtelEnv)
+	if otelEnv == nil {
+		otelEnv = map[string]string{}
+	}
+	model := req.Model
+	if model == "" && project != nil {
+		model = project.Model
+	}
+	var accountName, configDir string
+	var acc account.Account
+	if selected != nil {
+		acc = selected.internal()
+		accountName, configDir = acc.Name, acc.ConfigDir
+		if err := acc.Prepare(); err != nil {
+			return core.LaunchOptions{}, err
+		}
+		maps.Copy(env, acc.LaunchEnv())
+		maps.Copy(otelEnv, acc.OtelEnv)
+		if model == "" {
+			model = acc.Model
+		}
+	}
+	secretEnv, err := secrets.ResolveEnv(env)
+	if err != nil {
+		return core.LaunchOptions{}, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+	linkedDirs, err := linkedDirsFor(cfg, project)
+	if err != nil {
+		return core.LaunchOptions{}, err
+	}
+	if selected != nil && acc.Type == account.TypeAPIKey && !slices.Contains(secretEnv, "ANTHROPIC_API_KEY") {
+		secretEnv = append(secretEnv, "ANTHROPIC_API_KEY")
+	}
+
+	return core.LaunchOptions{
+		Mode:        core.ModeForeground,
+		Account:     accountName,
+		Preset:      config.PresetFor(project, req.Preset),
+		Rule:        rule,
+		Continue:    req.Continue,
+		Dir:         dir,
+		Model:       model,
+		AddDirs:     slices.Concat(linkedDirs, cfg.SharedDirsFor(dir)),
+		Args:        slices.Concat(cfg.ExtraArgsFor(project), req.Args),
+		ConfigDir:   configDir,
+		OtelEnv:     otelEnv,
+		Env:         env,
+		SecretEnv:   secretEnv,
+		MCPServers:  cfg.MCPServersFor(project),
+		Permissions: permissions,
+	}, nil
+}
+
+// linkedDirsFor returns the existing linkedDirs of project, or ErrNotAllowed when one of them is
+// outside the allowed directories
+func linkedDirsFor(cfg *config.Config, project *config.Project) ([]string, error) {
+	if project == nil {
+		return nil, nil
+	}
+	checker := security.NewDirectoryChecker(cfg.AllowedDirs)
+	var dirs []string
+	for _, dir := range project.LinkedDirs {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if allowed, err := checker.IsAllowed(dir); err != nil || !allowed {
+			return nil, fmt.Errorf("%w: linked directory %s", ErrNotAllowed, dir)
+		}
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
+
+// configLoader returns the ConfigLoader to use
+func (l *Launcher) configLoader() ConfigLoader {
+	if l.Config == nil {
+		return DefaultConfig
+	}
+	return l.Config
+}
+
+// accountSource returns the AccountSource to use
+func (l *Launcher) accountSource() AccountSource {
+	if l.Accounts == nil {
+		return DefaultAccounts
+	}
+	return l.Accounts
+}
+
+// selector returns the AccountSelector to use
+func (l *Launcher) selector() AccountSelector {
+	if l.Selector == nil {
+		return AccountByName("")
+	}
+	return l.Selector
+}
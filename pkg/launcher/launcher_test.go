@@ -0,0 +1,230 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/23prime/claude-launcher/internal/home"
+	"github.com/23prime/claude-launcher/internal/state"
+)
+
+// recordingRunner records the command instead of running it
+type recordingRunner struct {
+	cmd *exec.Cmd
+}
+
+func (r *recordingRunner) Run(_ context.Context, cmd *exec.Cmd) error {
+	r.cmd = cmd
+	return nil
+}
+
+// fakeClaude writes an executable standing in for claude
+func fakeClaude(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts")
+	}
+	path := filepath.Join(t.TempDir(), "claude")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil { // #nosec G306 -- test script
+		t.Fatal(err)
+	}
+	return path
+}
+
+// asUser runs the test as a user other than root, with its own audit log
+func asUser(t *testing.T, root bool) {
+	t.Helper()
+	t.Setenv(home.EnvVar, t.TempDir())
+	saved := runningAsRoot
+	runningAsRoot = func() bool { return root }
+	t.Cleanup(func() { runningAsRoot = saved })
+}
+
+// auditEvents returns the events of the audit log
+func auditEvents(t *testing.T) []string {
+	t.Helper()
+	store, err := state.NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := store.ReadAudit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []string
+	for _, e := range entries {
+		events = append(events, e.Event)
+	}
+	return events
+}
+
+// staticConfig loads cfg
+func staticConfig(cfg *Config) ConfigLoader {
+	return ConfigLoaderFunc(func(context.Context) (*Config, error) { return cfg, nil })
+}
+
+// staticAccounts lists accounts
+func staticAccounts(accounts ...Account) AccountSource {
+	return AccountSourceFunc(func(context.Context) ([]Account, error) { return accounts, nil })
+}
+
+func TestLauncherRun(t *testing.T) {
+	asUser(t, false)
+	root := t.TempDir()
+	project := filepath.Join(root, "api")
+	if err := os.Mkdir(project, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &recordingRunner{}
+	l := Launcher{
+		Config: staticConfig(&Config{
+			AllowedDirs: []string{root},
+			Projects:    []Project{{Path: project, Model: "opus", MCPServers: map[string]MCPServer{"db": {URL: "http://localhost/mcp"}}}},
+		}),
+		Accounts:   staticAccounts(Account{Name: "Personal", ConfigDir: "/home/me/.claude"}, Account{Name: "Work", ConfigDir: "/home/me/.claude-work"}),
+		Selector:   AccountByName("Work"),
+		Runner:     runner,
+		ClaudePath: fakeClaude(t),
+	}
+
+	launch, err := l.Run(context.Background(), Request{Dir: project, Args: []string{"-p", "hi"}})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if launch.Rule != root || launch.Account.Name != "Work" {
+		t.Errorf("Run() = rule %q, account %+v", launch.Rule, launch.Account)
+	}
+
+	cmd := runner.cmd
+	if cmd.Dir != project || !slices.Contains(cmd.Env, "CLAUDE_CONFIG_DIR=/home/me/.claude-work") {
+		t.Errorf("cmd = dir %q, env %v", cmd.Dir, cmd.Env)
+	}
+	args := strings.Join(cmd.Args, " ")
+	if !strings.Contains(args, "--mcp-config") || !strings.Contains(args, "--model opus") || !strings.HasSuffix(args, "-p hi") {
+		t.Errorf("cmd.Args = %q, expected the project's MCP servers and model", cmd.Args)
+	}
+}
+
+func TestLauncherPolicy(t *testing.T) {
+	asUser(t, false)
+	allowed, outside := t.TempDir(), t.TempDir()
+	runner := &recordingRunner{}
+	l := Launcher{
+		Config:     staticConfig(&Config{AllowedDirs: []string{allowed}}),
+		Accounts:   staticAccounts(),
+		Runner:     runner,
+		ClaudePath: fakeClaude(t),
+	}
+
+	if _, err := l.Run(context.Background(), Request{Dir: outside}); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Run() error = %v, expected ErrNotAllowed", err)
+	}
+	if _, err := l.Run(context.Background(), Request{Dir: allowed, Args: []string{"--dangerously-skip-permissions"}}); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Run() error = %v, expected ErrNotAllowed outside yoloAllowedDirs", err)
+	}
+	if runner.cmd != nil {
+		t.Error("a refused launch must not run")
+	}
+	if events := auditEvents(t); !slices.Equal(events, []string{state.AuditSkipPermissionsDenied}) {
+		t.Errorf("audit log = %v, expected the refused --dangerously-skip-permissions", events)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Run(ctx, Request{Dir: allowed}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, expected the context error", err)
+	}
+
+	// A custom policy replaces the directory check
+	l.Policy = policyFunc(func(context.Context, string, []string) (string, error) { return "team policy", nil })
+	launch, err := l.Run(context.Background(), Request{Dir: outside})
+	if err != nil || launch.Rule != "team policy" {
+		t.Errorf("Run() = %+v, %v, expected the custom policy to allow it", launch, err)
+	}
+}
+
+func TestLauncherRoot(t *testing.T) {
+	asUser(t, true)
+	allowed := t.TempDir()
+	runner := &recordingRunner{}
+	cfg := &Config{AllowedDirs: []string{allowed}, YoloAllowedDirs: []string{allowed}}
+	l := Launcher{Config: staticConfig(cfg), Accounts: staticAccounts(), Runner: runner, ClaudePath: fakeClaude(t)}
+
+	if _, err := l.Run(context.Background(), Request{Dir: allowed}); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Run() as root error = %v, expected ErrNotAllowed", err)
+	}
+	if runner.cmd != nil {
+		t.Error("a launch as root without allowRoot must not run")
+	}
+
+	cfg.AllowRoot = true
+	if _, err := l.Run(context.Background(), Request{Dir: allowed, Args: []string{"--dangerously-skip-permissions"}}); err != nil {
+		t.Fatalf("Run() with allowRoot error = %v", err)
+	}
+	expected := []string{state.AuditRootDenied, state.AuditRoot, state.AuditSkipPermissions}
+	if events := auditEvents(t); !slices.Equal(events, expected) {
+		t.Errorf("audit log = %v, expected %v", events, expected)
+	}
+}
+
+func TestLauncherBranchRules(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	asUser(t, false)
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", "-b", "main", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"allowedDirs": [` + strconv.Quote(repo) + `], "projects": [{"path": ` + strconv.Quote(repo) + `, "branches": [{"pattern": "main", "deny": true}]}]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &recordingRunner{}
+	l := Launcher{Config: ConfigFile(path), Accounts: staticAccounts(), Runner: runner, ClaudePath: fakeClaude(t)}
+	if _, err := l.Run(context.Background(), Request{Dir: repo}); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Run() on a denied branch error = %v, expected ErrNotAllowed", err)
+	}
+	if runner.cmd != nil {
+		t.Error("a launch on a denied branch must not run")
+	}
+}
+
+// policyFunc adapts a function to Policy
+type policyFunc func(ctx context.Context, dir string, args []string) (string, error)
+
+func (f policyFunc) Check(ctx context.Context, dir string, args []string) (string, error) {
+	return f(ctx, dir, args)
+}
+
+func TestAccountByName(t *testing.T) {
+	accounts := []Account{{Name: "Personal"}, {Name: "Work"}}
+	ctx := context.Background()
+
+	if acc, err := AccountByName("Work").Select(ctx, accounts); err != nil || acc.Name != "Work" {
+		t.Errorf("Select() = %+v, %v", acc, err)
+	}
+	if _, err := AccountByName("Other").Select(ctx, accounts); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Select() error = %v, expected ErrAccountNotFound", err)
+	}
+	if _, err := AccountByName("").Select(ctx, accounts); !errors.Is(err, ErrAccountRequired) {
+		t.Errorf("Select() error = %v, expected ErrAccountRequired", err)
+	}
+	if acc, err := AccountByName("").Select(ctx, accounts[:1]); err != nil || acc.Name != "Personal" {
+		t.Errorf("Select() = %+v, %v, expected the only account", acc, err)
+	}
+	if acc, err := AccountByName("").Select(ctx, nil); err != nil || acc != nil {
+		t.Errorf("Select() = %+v, %v, expected the default configuration", acc, err)
+	}
+}